@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+	"github.com/madr/backend/internal/config"
+	"github.com/madr/backend/internal/router"
+	"github.com/madr/backend/internal/scheduler"
+	"github.com/madr/backend/pkg/database"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/migrate"
+	"github.com/madr/backend/pkg/money"
+)
+
+func main() {
+	// Load configuration
+	if err := config.Load(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Initialize logger
+	logger.Init(config.AppConfig.Logging.Level, config.AppConfig.Logging.Format)
+
+	// Set Gin mode
+	gin.SetMode(config.AppConfig.Server.Mode)
+
+	// Choose how money amounts are encoded in JSON responses
+	money.EncodeAsString = config.AppConfig.Server.AmountsAsString
+
+	// Connect to database
+	if err := database.Connect(); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to connect to database")
+		os.Exit(1)
+	}
+	defer database.Close()
+
+	// Run pending migrations automatically in development mode
+	if config.AppConfig.Server.Mode == gin.DebugMode {
+		sqlDB, err := database.GetDB().DB()
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Failed to get database instance")
+			os.Exit(1)
+		}
+		if err := migrate.RunMigrations(sqlDB); err != nil {
+			logger.Warn().Err(err).Msg("Automatic migration failed, run cmd/migrate manually")
+		}
+	}
+
+	// Build router
+	handlers := router.NewHandlers()
+	r := router.Setup(handlers)
+
+	srv := &http.Server{
+		Addr:    config.AppConfig.Server.GetServerAddress(),
+		Handler: r,
+	}
+
+	// Listen for SIGINT/SIGTERM to trigger graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Background jobs stop with the server
+	waitJobs := scheduler.Start(ctx, handlers.JobStore, handlers.Jobs...)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info().
+			Str("address", srv.Addr).
+			Str("mode", config.AppConfig.Server.Mode).
+			Msg("Server starting")
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	// A failed listener shuts down the same way as a signal, so running
+	// jobs still drain
+	failed := false
+	select {
+	case err := <-serverErr:
+		logger.Error().Err(err).Msg("Server failed")
+		failed = true
+	case <-ctx.Done():
+	}
+	stop()
+
+	logger.Info().
+		Dur("timeout", config.AppConfig.Server.ShutdownTimeout).
+		Msg("Shutting down server, draining in-flight requests")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.AppConfig.Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error().Err(err).Msg("Server forced to shutdown")
+		failed = true
+	}
+
+	waitJobs()
+	if failed {
+		logger.Info().Msg("Server stopped")
+		return
+	}
+	logger.Info().Msg("Server stopped gracefully")
+}
@@ -15,18 +15,28 @@ type Config struct {
 	Server    ServerConfig
 	Database  DatabaseConfig
 	JWT       JWTConfig
+	Auth      AuthConfig
 	CORS      CORSConfig
 	RateLimit RateLimitConfig
 	Logging   LoggingConfig
 	Upload    UploadConfig
 	YouTube   YouTubeConfig
+	Payment   PaymentConfig
+	Notifier  NotifierConfig
+	Pledge    PledgeConfig
+	Campaign  CampaignConfig
+	Scheduler SchedulerConfig
+	Mail      MailConfig
 }
 
 // ServerConfig holds server-related configuration
 type ServerConfig struct {
-	Host string
-	Port string
-	Mode string
+	Host            string
+	Port            string
+	Mode            string
+	ShutdownTimeout time.Duration
+	AmountsAsString bool     // Encode money amounts as JSON strings
+	TrustedProxies  []string // Proxy IPs or CIDRs whose X-Forwarded-For is believed; none by default
 }
 
 // DatabaseConfig holds database-related configuration
@@ -46,6 +56,20 @@ type JWTConfig struct {
 	RefreshExpiry time.Duration
 }
 
+// AuthConfig holds account registration configuration
+type AuthConfig struct {
+	RegistrationMode    string        // closed, invite or open
+	InviteExpiry        time.Duration // Default lifetime of registration invitations
+	ResetExpiry         time.Duration // Lifetime of password reset links
+	VerifyExpiry        time.Duration // Lifetime of email verification links
+	MFARequiredForAdmin bool          // Administrators must use two-factor authentication
+	MFAIssuer           string        // Account issuer shown in authenticator apps
+	LoginMaxAttempts    int           // Failed logins per username before it is locked
+	LoginMaxAttemptsIP  int           // Failed logins per client IP before it is locked
+	LoginLockout        time.Duration // How long a lockout lasts
+	LoginBackoffBase    time.Duration // Wait after a failed login, doubled for each further one
+}
+
 // CORSConfig holds CORS-related configuration
 type CORSConfig struct {
 	AllowedOrigins []string
@@ -81,6 +105,54 @@ type YouTubeConfig struct {
 	APIURL    string
 }
 
+// PaymentConfig holds payment gateway configuration
+type PaymentConfig struct {
+	DefaultProvider   string
+	MidtransServerKey string
+	MidtransSnapURL   string
+	MidtransAPIURL    string
+	FakeEnabled       bool
+	FakeSecret        string
+	QRISMerchant      string
+}
+
+// NotifierConfig holds notification channel configuration
+type NotifierConfig struct {
+	Provider      string // log or webhook
+	WebhookURL    string
+	WebhookSecret string
+}
+
+// PledgeConfig holds recurring pledge scheduler configuration
+type PledgeConfig struct {
+	SchedulerEnabled  bool
+	SchedulerInterval time.Duration
+	GraceDays         int // Days after the due date before an unpaid installment is missed
+}
+
+// CampaignConfig holds fundraising campaign configuration
+type CampaignConfig struct {
+	CloseInterval time.Duration // How often campaigns past their end date are closed
+}
+
+// SchedulerConfig holds background job configuration
+type SchedulerConfig struct {
+	RefreshTokenCleanup string        // Schedule for deleting expired and revoked refresh tokens
+	RunRetention        time.Duration // How long job run history is kept
+}
+
+// MailConfig holds outgoing email configuration
+type MailConfig struct {
+	Provider     string // smtp or file
+	From         string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	FileDir      string // Where the file provider writes .eml files
+	AppURL       string // Frontend base URL used to build links in emails
+}
+
 var AppConfig *Config
 
 // Load loads configuration from environment variables
@@ -122,9 +194,12 @@ func Load() error {
 
 	AppConfig = &Config{
 		Server: ServerConfig{
-			Host: getEnv("SERVER_HOST", "localhost"),
-			Port: getEnv("SERVER_PORT", "8080"),
-			Mode: getEnv("SERVER_MODE", "debug"),
+			Host:            getEnv("SERVER_HOST", "localhost"),
+			Port:            getEnv("SERVER_PORT", "8080"),
+			Mode:            getEnv("SERVER_MODE", "debug"),
+			ShutdownTimeout: parseDuration(getEnv("SERVER_SHUTDOWN_TIMEOUT", "15s")),
+			AmountsAsString: getEnvBool("JSON_AMOUNTS_AS_STRING", false),
+			TrustedProxies:  getEnvSlice("TRUSTED_PROXIES", nil),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -139,6 +214,18 @@ func Load() error {
 			AccessExpiry:  parseDuration(getEnv("JWT_ACCESS_EXPIRY", "15m")),
 			RefreshExpiry: parseDuration(getEnv("JWT_REFRESH_EXPIRY", "7d")),
 		},
+		Auth: AuthConfig{
+			RegistrationMode:    getEnv("REGISTRATION_MODE", "invite"),
+			InviteExpiry:        parseDuration(getEnv("INVITE_EXPIRY", "168h")),
+			ResetExpiry:         parseDuration(getEnv("PASSWORD_RESET_EXPIRY", "1h")),
+			VerifyExpiry:        parseDuration(getEnv("EMAIL_VERIFY_EXPIRY", "48h")),
+			MFARequiredForAdmin: getEnvBool("MFA_REQUIRED_FOR_ADMIN", false),
+			MFAIssuer:           getEnv("MFA_ISSUER", "MADR"),
+			LoginMaxAttempts:    getEnvInt("LOGIN_MAX_ATTEMPTS", 5),
+			LoginMaxAttemptsIP:  getEnvInt("LOGIN_MAX_ATTEMPTS_PER_IP", 20),
+			LoginLockout:        parseDuration(getEnv("LOGIN_LOCKOUT_DURATION", "15m")),
+			LoginBackoffBase:    parseDuration(getEnv("LOGIN_BACKOFF_BASE", "1s")),
+		},
 		CORS: CORSConfig{
 			AllowedOrigins: getEnvSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:3000","http://localhost:3001"}),
 			AllowedMethods: getEnvSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"}),
@@ -164,6 +251,42 @@ func Load() error {
 			ChannelID: getEnv("YOUTUBE_CHANNEL_ID", ""),
 			APIURL:    getEnv("YOUTUBE_API_URL", "https://www.googleapis.com/youtube/v3/search"),
 		},
+		Payment: PaymentConfig{
+			DefaultProvider:   getEnv("PAYMENT_DEFAULT_PROVIDER", "midtrans"),
+			MidtransServerKey: getEnv("MIDTRANS_SERVER_KEY", ""),
+			MidtransSnapURL:   getEnv("MIDTRANS_SNAP_URL", "https://app.sandbox.midtrans.com/snap/v1/transactions"),
+			MidtransAPIURL:    getEnv("MIDTRANS_API_URL", "https://api.sandbox.midtrans.com/v2"),
+			FakeEnabled:       getEnvBool("PAYMENT_FAKE_ENABLED", false),
+			FakeSecret:        getEnv("PAYMENT_FAKE_SECRET", "fake-payment-secret"),
+			QRISMerchant:      getEnv("QRIS_MERCHANT_PAYLOAD", ""),
+		},
+		Notifier: NotifierConfig{
+			Provider:      getEnv("NOTIFIER_PROVIDER", "log"),
+			WebhookURL:    getEnv("NOTIFIER_WEBHOOK_URL", ""),
+			WebhookSecret: getEnv("NOTIFIER_WEBHOOK_SECRET", ""),
+		},
+		Pledge: PledgeConfig{
+			SchedulerEnabled:  getEnvBool("PLEDGE_SCHEDULER_ENABLED", true),
+			SchedulerInterval: parseDuration(getEnv("PLEDGE_SCHEDULER_INTERVAL", "1h")),
+			GraceDays:         getEnvInt("PLEDGE_GRACE_DAYS", 7),
+		},
+		Campaign: CampaignConfig{
+			CloseInterval: parseDuration(getEnv("CAMPAIGN_CLOSE_INTERVAL", "15m")),
+		},
+		Scheduler: SchedulerConfig{
+			RefreshTokenCleanup: getEnv("REFRESH_TOKEN_CLEANUP_SCHEDULE", "0 3 * * *"),
+			RunRetention:        parseDuration(getEnv("JOB_RUN_RETENTION", "720h")),
+		},
+		Mail: MailConfig{
+			Provider:     getEnv("MAIL_PROVIDER", "file"),
+			From:         getEnv("MAIL_FROM", "MADR <no-reply@madr.local>"),
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			FileDir:      getEnv("MAIL_FILE_DIR", "./mail"),
+			AppURL:       getEnv("APP_URL", "http://localhost:3000"),
+		},
 	}
 
 	// Fallback: Try to read directly from environment if not loaded from .env
@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/madr/backend/internal/domain/models"
+)
+
+// Outcome tells whether an audited request succeeded
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+)
+
+// IsValid reports whether o is a known outcome
+func (o Outcome) IsValid() bool {
+	return o == OutcomeSuccess || o == OutcomeFailure
+}
+
+// Actions derived from the HTTP method. Routes with a trailing segment after
+// the entity ID, like /mustahik/:id/verification, use that segment instead.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Log is an append-only record of one admin mutation
+type Log struct {
+	ID         uint        `gorm:"primarykey" json:"id"`
+	ActorID    *uint       `json:"actor_id"`
+	ActorName  *string     `gorm:"->;-:migration" json:"actor_name,omitempty"`
+	IP         string      `gorm:"type:varchar(45)" json:"ip,omitempty"`
+	Method     string      `gorm:"type:varchar(10);not null" json:"method"`
+	Route      string      `gorm:"type:varchar(255);not null" json:"route"` // Route pattern, e.g. /api/v1/admin/events/:id
+	Path       string      `gorm:"type:varchar(255);not null" json:"path"`
+	EntityType string      `gorm:"type:varchar(100);not null" json:"entity_type"`
+	EntityID   *uint       `json:"entity_id,omitempty"`
+	Action     string      `gorm:"type:varchar(50);not null" json:"action"`
+	StatusCode int         `gorm:"not null" json:"status_code"`
+	Outcome    Outcome     `gorm:"type:varchar(10);not null" json:"outcome"`
+	Error      *string     `gorm:"type:text" json:"error,omitempty"`
+	Before     models.JSON `gorm:"type:jsonb" json:"before"`
+	After      models.JSON `gorm:"type:jsonb" json:"after"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Log) TableName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,63 @@
+package campaign
+
+import (
+	"time"
+
+	"github.com/madr/backend/internal/domain/models"
+	"github.com/madr/backend/pkg/money"
+)
+
+// Status is the stored state of a campaign
+type Status string
+
+const (
+	StatusActive Status = "active"
+	StatusClosed Status = "closed" // Closed by an admin or after the end date
+)
+
+// IsValid reports whether s is a known status
+func (s Status) IsValid() bool {
+	return s == StatusActive || s == StatusClosed
+}
+
+// Phase is the state of a campaign as seen by donors on a given day
+type Phase string
+
+const (
+	PhaseUpcoming Phase = "upcoming"
+	PhaseOpen     Phase = "open"
+	PhaseClosed   Phase = "closed"
+)
+
+// Campaign is a fundraising target collected through one donation category
+type Campaign struct {
+	models.BaseModel
+	CategoryID   uint        `gorm:"not null" json:"category_id"`
+	Title        string      `gorm:"type:varchar(255);not null" json:"title"`
+	Description  string      `gorm:"type:text" json:"description"`
+	TargetAmount money.Money `gorm:"type:decimal(15,2);not null" json:"target_amount"`
+	StartDate    time.Time   `gorm:"type:date;not null" json:"start_date"`
+	EndDate      time.Time   `gorm:"type:date;not null" json:"end_date"`
+	CoverImage   *string     `gorm:"type:varchar(500)" json:"cover_image"` // File name in the upload directory
+	Status       Status      `gorm:"type:varchar(20);not null;default:'active'" json:"status"`
+	ClosedAt     *time.Time  `gorm:"type:timestamptz" json:"closed_at"`
+	CategoryName string      `gorm:"->;-:migration" json:"category_name,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (Campaign) TableName() string {
+	return "campaigns"
+}
+
+// PhaseOn returns the phase of the campaign on the given Jakarta day. The
+// end date is the last day donations are accepted.
+func (c *Campaign) PhaseOn(today time.Time) Phase {
+	switch {
+	case c.Status == StatusClosed || today.After(c.EndDate):
+		return PhaseClosed
+	case today.Before(c.StartDate):
+		return PhaseUpcoming
+	default:
+		return PhaseOpen
+	}
+}
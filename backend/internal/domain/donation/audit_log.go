@@ -0,0 +1,145 @@
+package donation
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/madr/backend/internal/domain/models"
+	"github.com/madr/backend/pkg/money"
+)
+
+// AuditAction is the kind of change recorded in the audit trail
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+)
+
+// Actor identifies who made a change. A nil actor is the system itself,
+// e.g. a payment webhook or the pledge scheduler.
+type Actor struct {
+	UserID *uint
+	IP     string
+}
+
+// AuditLog is an append-only record of one change to a donation
+type AuditLog struct {
+	ID         uint        `gorm:"primarykey" json:"id"`
+	DonationID uint        `gorm:"not null;index" json:"donation_id"`
+	Action     AuditAction `gorm:"type:varchar(10);not null" json:"action"`
+	ActorID    *uint       `json:"actor_id"`
+	ActorName  *string     `gorm:"->;-:migration" json:"actor_name,omitempty"`
+	IP         string      `gorm:"type:varchar(45)" json:"ip,omitempty"`
+	Before     models.JSON `gorm:"type:jsonb" json:"before"`
+	After      models.JSON `gorm:"type:jsonb" json:"after"`
+	Changes    models.JSON `gorm:"type:jsonb" json:"changes"` // Field name to {from, to}
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (AuditLog) TableName() string {
+	return "donation_audit_logs"
+}
+
+// FieldChange is the old and new value of one changed field
+type FieldChange struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+// auditSnapshot holds the fields of a donation kept in the audit trail
+type auditSnapshot struct {
+	CategoryID       uint          `json:"category_id"`
+	DonorName        *string       `json:"donor_name"`
+	UserID           *uint         `json:"user_id"`
+	Amount           money.Money   `json:"amount"`
+	Message          string        `json:"message"`
+	PaymentStatus    PaymentStatus `json:"payment_status"`
+	PaymentProvider  *string       `json:"payment_provider"`
+	PaymentReference *string       `json:"payment_reference"`
+	TransactionID    *string       `json:"transaction_id"`
+	PaidAt           *time.Time    `json:"paid_at"`
+}
+
+// NewAuditLog builds the audit entry of a change from the donation before and
+// after it. Before is nil for a create and after is nil for a delete.
+func NewAuditLog(action AuditAction, before, after *Donation, actor *Actor) (*AuditLog, error) {
+	entry := &AuditLog{Action: action}
+	if actor != nil {
+		entry.ActorID = actor.UserID
+		entry.IP = actor.IP
+	}
+
+	var err error
+	var beforeFields, afterFields map[string]json.RawMessage
+	if before != nil {
+		entry.DonationID = before.ID
+		if entry.Before, beforeFields, err = snapshot(before); err != nil {
+			return nil, err
+		}
+	}
+	if after != nil {
+		entry.DonationID = after.ID
+		if entry.After, afterFields, err = snapshot(after); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := make(map[string]FieldChange)
+	for name, value := range afterFields {
+		if old, ok := beforeFields[name]; !ok || !bytes.Equal(old, value) {
+			changes[name] = FieldChange{From: orNull(old), To: value}
+		}
+	}
+	for name, old := range beforeFields {
+		if _, ok := afterFields[name]; !ok {
+			changes[name] = FieldChange{From: old, To: orNull(nil)}
+		}
+	}
+	if entry.Changes, err = json.Marshal(changes); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// snapshot encodes the audited fields of a donation, both as a document and
+// field by field for diffing
+func snapshot(don *Donation) (models.JSON, map[string]json.RawMessage, error) {
+	snap := auditSnapshot{
+		CategoryID:       don.CategoryID,
+		DonorName:        don.DonorName,
+		UserID:           don.UserID,
+		Amount:           don.Amount,
+		Message:          don.Message,
+		PaymentStatus:    don.PaymentStatus,
+		PaymentProvider:  don.PaymentProvider,
+		PaymentReference: don.PaymentReference,
+		TransactionID:    don.TransactionID,
+	}
+	if don.PaidAt != nil {
+		// Compare instants, not the zone or precision the value was read with
+		paidAt := don.PaidAt.UTC().Truncate(time.Microsecond)
+		snap.PaidAt = &paidAt
+	}
+
+	doc, err := json.Marshal(snap)
+	if err != nil {
+		return nil, nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &fields); err != nil {
+		return nil, nil, err
+	}
+	return doc, fields, nil
+}
+
+func orNull(value json.RawMessage) json.RawMessage {
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	return value
+}
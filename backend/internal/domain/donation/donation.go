@@ -1,7 +1,10 @@
 package donation
 
 import (
+	"time"
+
 	"github.com/madr/backend/internal/domain/models"
+	"github.com/madr/backend/pkg/money"
 )
 
 // PaymentStatus represents payment status type
@@ -18,16 +21,41 @@ type DonationCategoryInfo struct {
 	ID          uint   `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	FundType    string `json:"fund_type"`
 }
 
 // Donation represents a donation entity
 type Donation struct {
 	models.BaseModel
-	CategoryID    uint          `gorm:"not null;index" json:"category_id" binding:"required"`
-	DonorName     *string       `gorm:"type:varchar(255)" json:"donor_name"` // Nullable for anonymous
-	Amount        float64       `gorm:"type:decimal(15,2);not null" json:"amount" binding:"required,gt=0"`
-	Message       string        `gorm:"type:text" json:"message"`
-	PaymentStatus PaymentStatus `gorm:"type:varchar(20);default:'pending'" json:"payment_status"`
-	Category      *DonationCategoryInfo `gorm:"-" json:"category,omitempty"` // Will be loaded via Preload
+	CategoryID       uint                  `gorm:"not null;index" json:"category_id" binding:"required"`
+	DonorName        *string               `gorm:"type:varchar(255)" json:"donor_name"` // Nullable for anonymous
+	UserID           *uint                 `gorm:"index" json:"user_id,omitempty"`      // Donor account, nil for anonymous giving
+	Amount           money.Money           `gorm:"type:decimal(15,2);not null" json:"amount" binding:"required,gt=0"`
+	Message          string                `gorm:"type:text" json:"message"`
+	PaymentStatus    PaymentStatus         `gorm:"type:varchar(20);default:'pending'" json:"payment_status"`
+	PaymentProvider  *string               `gorm:"type:varchar(50)" json:"payment_provider,omitempty"`
+	PaymentReference *string               `gorm:"type:varchar(100);uniqueIndex" json:"payment_reference,omitempty"` // Order ID sent to the gateway
+	TransactionID    *string               `gorm:"type:varchar(255)" json:"transaction_id,omitempty"`                // Provider transaction ID
+	PaymentPayload   *string               `gorm:"type:text" json:"-"`                                               // Raw callback payload
+	PaidAt           *time.Time            `gorm:"type:timestamptz" json:"paid_at,omitempty"`
+	Category         *DonationCategoryInfo `gorm:"-" json:"category,omitempty"` // Will be loaded via Preload
 }
 
+// ReportPeriod is the bucket size of a donation report
+type ReportPeriod string
+
+const (
+	PeriodDay   ReportPeriod = "day"
+	PeriodWeek  ReportPeriod = "week"
+	PeriodMonth ReportPeriod = "month"
+	PeriodYear  ReportPeriod = "year"
+)
+
+// IsValid reports whether p is a supported report period
+func (p ReportPeriod) IsValid() bool {
+	switch p {
+	case PeriodDay, PeriodWeek, PeriodMonth, PeriodYear:
+		return true
+	}
+	return false
+}
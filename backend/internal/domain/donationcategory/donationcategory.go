@@ -4,15 +4,39 @@ import (
 	"github.com/madr/backend/internal/domain/models"
 )
 
+// FundType tells which fund the donations of a category belong to. Zakat must
+// be distributed to the eight asnaf and is kept apart from general infaq.
+type FundType string
+
+const (
+	FundInfaq       FundType = "infaq"
+	FundZakatFitrah FundType = "zakat_fitrah"
+	FundZakatMal    FundType = "zakat_mal"
+)
+
+// IsValid reports whether f is a known fund type
+func (f FundType) IsValid() bool {
+	switch f {
+	case FundInfaq, FundZakatFitrah, FundZakatMal:
+		return true
+	}
+	return false
+}
+
+// IsZakat reports whether f is one of the zakat funds
+func (f FundType) IsZakat() bool {
+	return f == FundZakatFitrah || f == FundZakatMal
+}
+
 // DonationCategory represents a donation category entity
 type DonationCategory struct {
 	models.BaseModel
-	Name        string `gorm:"type:varchar(255);not null;uniqueIndex" json:"name" binding:"required"`
-	Description string `gorm:"type:text" json:"description"`
+	Name        string   `gorm:"type:varchar(255);not null;uniqueIndex" json:"name" binding:"required"`
+	Description string   `gorm:"type:text" json:"description"`
+	FundType    FundType `gorm:"type:varchar(20);not null;default:'infaq'" json:"fund_type"`
 }
 
 // TableName specifies the table name for GORM
 func (DonationCategory) TableName() string {
 	return "donation_categories"
 }
-
@@ -0,0 +1,51 @@
+package invitation
+
+import "time"
+
+// Status describes where an invitation is in its lifecycle
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusUsed    Status = "used"
+	StatusRevoked Status = "revoked"
+	StatusExpired Status = "expired"
+)
+
+// Invitation lets one person register with the role chosen by the admin who
+// issued it. The signed token itself is only shown once; TokenHash keeps its
+// SHA-256 digest.
+type Invitation struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	TokenHash string     `gorm:"type:char(64);uniqueIndex;not null" json:"-"`
+	Email     string     `gorm:"type:varchar(255)" json:"email"` // Empty means any email
+	RoleID    uint       `gorm:"not null" json:"role_id"`
+	RoleName  string     `gorm:"->;-:migration" json:"role_name"`
+	CreatedBy *uint      `json:"created_by"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	UsedBy    *uint      `json:"used_by"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+
+	Status Status `gorm:"-" json:"status"` // Filled by the use case
+}
+
+// TableName specifies the table name for GORM
+func (Invitation) TableName() string {
+	return "invitations"
+}
+
+// StatusAt reports the invitation status at the given time
+func (i *Invitation) StatusAt(now time.Time) Status {
+	switch {
+	case i.UsedAt != nil:
+		return StatusUsed
+	case i.RevokedAt != nil:
+		return StatusRevoked
+	case !now.Before(i.ExpiresAt):
+		return StatusExpired
+	default:
+		return StatusPending
+	}
+}
@@ -0,0 +1,48 @@
+package jobrun
+
+import "time"
+
+// Status is the state of a background job run
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// IsValid reports whether s is a known status
+func (s Status) IsValid() bool {
+	return s == StatusRunning || s == StatusSucceeded || s == StatusFailed
+}
+
+// JobRun records one run of a background job
+type JobRun struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	JobName     string     `gorm:"type:varchar(100);not null;index" json:"job_name"`
+	Status      Status     `gorm:"type:varchar(20);not null" json:"status"`
+	Instance    string     `gorm:"type:varchar(255);not null;default:''" json:"instance"` // Host that ran the job
+	ScheduledAt time.Time  `gorm:"not null" json:"scheduled_at"`
+	StartedAt   time.Time  `gorm:"not null" json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	DurationMs  *int64     `json:"duration_ms"`
+	Error       *string    `gorm:"type:text" json:"error,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (JobRun) TableName() string {
+	return "job_runs"
+}
+
+// Finish records the outcome of the run
+func (r *JobRun) Finish(at time.Time, err error) {
+	duration := at.Sub(r.StartedAt).Milliseconds()
+	r.FinishedAt = &at
+	r.DurationMs = &duration
+	r.Status = StatusSucceeded
+	if err != nil {
+		message := err.Error()
+		r.Status = StatusFailed
+		r.Error = &message
+	}
+}
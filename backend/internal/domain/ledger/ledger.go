@@ -0,0 +1,78 @@
+package ledger
+
+import (
+	"time"
+
+	"github.com/madr/backend/internal/domain/models"
+	"github.com/madr/backend/pkg/money"
+)
+
+// EntryType represents the direction of a cash ledger entry
+type EntryType string
+
+const (
+	EntryTypeIncome  EntryType = "income"
+	EntryTypeExpense EntryType = "expense"
+)
+
+// IsValid reports whether t is a known entry type
+func (t EntryType) IsValid() bool {
+	return t == EntryTypeIncome || t == EntryTypeExpense
+}
+
+// Account represents a place the mosque keeps money, e.g. the cash box or a bank account
+type Account struct {
+	models.BaseModel
+	Name        string `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`
+	Description string `gorm:"type:text" json:"description"`
+	IsDefault   bool   `gorm:"not null;default:false" json:"is_default"` // Receives donation income
+}
+
+// TableName specifies the table name for GORM
+func (Account) TableName() string {
+	return "ledger_accounts"
+}
+
+// Category groups entries for reporting, e.g. electricity or imam honorarium
+type Category struct {
+	models.BaseModel
+	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
+	Type        EntryType `gorm:"type:varchar(10);not null" json:"type"`
+	Description string    `gorm:"type:text" json:"description"`
+	System      bool      `gorm:"not null;default:false" json:"system"` // Used for automatic postings; cannot be changed
+}
+
+// TableName specifies the table name for GORM
+func (Category) TableName() string {
+	return "ledger_categories"
+}
+
+// Entry represents a single income or expense. Amount is always positive; the
+// type decides whether it adds to or subtracts from the balance.
+type Entry struct {
+	models.BaseModel
+	AccountID     uint         `gorm:"not null;index" json:"account_id"`
+	CategoryID    uint         `gorm:"not null;index" json:"category_id"`
+	Type          EntryType    `gorm:"type:varchar(10);not null" json:"type"`
+	Amount        money.Money  `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Description   string       `gorm:"type:text" json:"description"`
+	EntryDate     time.Time    `gorm:"type:date;not null" json:"entry_date"`
+	AttachmentURL *string      `gorm:"type:varchar(500)" json:"attachment_url,omitempty"` // Receipt or invoice scan
+	DonationID    *uint        `gorm:"uniqueIndex" json:"donation_id,omitempty"`          // Set for entries posted from donations
+	AccountName   string       `gorm:"->;-:migration" json:"account_name,omitempty"`
+	CategoryName  string       `gorm:"->;-:migration" json:"category_name,omitempty"`
+	Balance       *money.Money `gorm:"->;-:migration" json:"balance,omitempty"` // Running balance after this entry
+}
+
+// TableName specifies the table name for GORM
+func (Entry) TableName() string {
+	return "ledger_entries"
+}
+
+// SignedAmount returns the amount as a balance change
+func (e *Entry) SignedAmount() money.Money {
+	if e.Type == EntryTypeExpense {
+		return money.Money(0).Sub(e.Amount)
+	}
+	return e.Amount
+}
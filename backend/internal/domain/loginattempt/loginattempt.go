@@ -0,0 +1,30 @@
+package loginattempt
+
+import "time"
+
+// Scope tells what a login attempt counter is keyed by
+type Scope string
+
+const (
+	ScopeUsername Scope = "username"
+	ScopeIP       Scope = "ip"
+)
+
+// LoginAttempt counts the recent failed logins of a username or client IP
+type LoginAttempt struct {
+	Scope        Scope      `gorm:"type:varchar(10);primarykey" json:"scope"`
+	Key          string     `gorm:"type:varchar(255);primarykey" json:"key"`
+	Failures     int        `gorm:"not null;default:0" json:"failures"`
+	LastFailedAt time.Time  `gorm:"not null" json:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until"`
+}
+
+// TableName specifies the table name for GORM
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// IsLockedAt reports whether logins are locked out at the given time
+func (a *LoginAttempt) IsLockedAt(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
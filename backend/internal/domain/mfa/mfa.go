@@ -0,0 +1,39 @@
+package mfa
+
+import "time"
+
+// TOTP is the authenticator app a user enrolled for two-factor
+// authentication. It only protects logins once ConfirmedAt is set.
+type TOTP struct {
+	UserID       uint       `gorm:"primarykey;autoIncrement:false" json:"user_id"`
+	Secret       string     `gorm:"type:varchar(64);not null" json:"-"`
+	ConfirmedAt  *time.Time `json:"confirmed_at"`
+	LastUsedStep int64      `gorm:"not null;default:0" json:"-"` // Time step of the last accepted code
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (TOTP) TableName() string {
+	return "user_totp"
+}
+
+// IsEnabled reports whether the authenticator was confirmed
+func (t *TOTP) IsEnabled() bool {
+	return t.ConfirmedAt != nil
+}
+
+// RecoveryCode is a one-time code that replaces an authenticator code. The
+// code itself is only shown once; CodeHash keeps its SHA-256 digest.
+type RecoveryCode struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"type:char(64);not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (RecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
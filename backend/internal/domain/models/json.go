@@ -0,0 +1,50 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+)
+
+// JSON is a raw JSON document stored in a JSONB column
+type JSON []byte
+
+// Value stores the document as text so the driver casts it to JSONB
+func (j JSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	return string(j), nil
+}
+
+// Scan reads a JSONB column
+func (j *JSON) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*j = nil
+	case []byte:
+		*j = append((*j)[:0], v...)
+	case string:
+		*j = JSON(v)
+	default:
+		return errors.New("unsupported JSON column type")
+	}
+	return nil
+}
+
+// MarshalJSON embeds the document as is, or null when empty
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+// UnmarshalJSON keeps a copy of the raw document
+func (j *JSON) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*j = nil
+		return nil
+	}
+	*j = append((*j)[:0], data...)
+	return nil
+}
@@ -0,0 +1,91 @@
+package mustahik
+
+import (
+	"time"
+
+	"github.com/madr/backend/internal/domain/models"
+	"github.com/madr/backend/pkg/money"
+)
+
+// Asnaf is one of the eight groups entitled to receive zakat (QS. At-Taubah: 60)
+type Asnaf string
+
+const (
+	AsnafFakir        Asnaf = "fakir"
+	AsnafMiskin       Asnaf = "miskin"
+	AsnafAmil         Asnaf = "amil"
+	AsnafMuallaf      Asnaf = "muallaf"
+	AsnafRiqab        Asnaf = "riqab"
+	AsnafGharimin     Asnaf = "gharimin"
+	AsnafFisabilillah Asnaf = "fisabilillah"
+	AsnafIbnuSabil    Asnaf = "ibnu_sabil"
+)
+
+// AllAsnaf lists the asnaf in their Quranic order
+var AllAsnaf = []Asnaf{
+	AsnafFakir, AsnafMiskin, AsnafAmil, AsnafMuallaf,
+	AsnafRiqab, AsnafGharimin, AsnafFisabilillah, AsnafIbnuSabil,
+}
+
+// IsValid reports whether a is a known asnaf
+func (a Asnaf) IsValid() bool {
+	for _, known := range AllAsnaf {
+		if a == known {
+			return true
+		}
+	}
+	return false
+}
+
+// VerificationStatus tracks whether a mustahik has been checked by the amil
+type VerificationStatus string
+
+const (
+	VerificationPending  VerificationStatus = "pending"
+	VerificationVerified VerificationStatus = "verified"
+	VerificationRejected VerificationStatus = "rejected"
+)
+
+// IsValid reports whether s is a known verification status
+func (s VerificationStatus) IsValid() bool {
+	return s == VerificationPending || s == VerificationVerified || s == VerificationRejected
+}
+
+// Mustahik is a household eligible to receive zakat and aid
+type Mustahik struct {
+	models.BaseModel
+	HeadName           string             `gorm:"type:varchar(255);not null" json:"head_name"`
+	Asnaf              Asnaf              `gorm:"type:varchar(20);not null" json:"asnaf"`
+	Phone              *string            `gorm:"type:varchar(30)" json:"phone"`
+	Address            string             `gorm:"type:text" json:"address"`
+	HouseholdSize      int                `gorm:"not null;default:1" json:"household_size"`
+	VerificationStatus VerificationStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"verification_status"`
+	VerifiedAt         *time.Time         `gorm:"type:timestamptz" json:"verified_at"`
+	VerifiedBy         *uint              `json:"verified_by"`
+	Notes              string             `gorm:"type:text" json:"notes"`
+}
+
+// TableName specifies the table name for GORM
+func (Mustahik) TableName() string {
+	return "mustahik"
+}
+
+// Distribution is aid paid to a mustahik out of the funds of one donation category
+type Distribution struct {
+	ID            uint        `gorm:"primaryKey" json:"id"`
+	MustahikID    uint        `gorm:"not null;index" json:"mustahik_id"`
+	CategoryID    uint        `gorm:"not null;index" json:"category_id"`
+	Amount        money.Money `gorm:"type:decimal(15,2);not null" json:"amount"`
+	DistributedOn time.Time   `gorm:"type:date;not null" json:"distributed_on"`
+	Description   string      `gorm:"type:text" json:"description"`
+	CreatedAt     time.Time   `gorm:"type:timestamptz" json:"created_at"`
+	UpdatedAt     time.Time   `gorm:"type:timestamptz" json:"updated_at"`
+	MustahikName  string      `gorm:"->;-:migration" json:"mustahik_name,omitempty"`
+	Asnaf         Asnaf       `gorm:"->;-:migration" json:"asnaf,omitempty"`
+	CategoryName  string      `gorm:"->;-:migration" json:"category_name,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (Distribution) TableName() string {
+	return "distributions"
+}
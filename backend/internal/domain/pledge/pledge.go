@@ -0,0 +1,122 @@
+package pledge
+
+import (
+	"time"
+
+	"github.com/madr/backend/internal/domain/models"
+	"github.com/madr/backend/pkg/money"
+)
+
+// Frequency is how often a pledge falls due
+type Frequency string
+
+const (
+	FrequencyWeekly  Frequency = "weekly"
+	FrequencyMonthly Frequency = "monthly"
+	FrequencyYearly  Frequency = "yearly"
+)
+
+// IsValid reports whether f is a known frequency
+func (f Frequency) IsValid() bool {
+	return f == FrequencyWeekly || f == FrequencyMonthly || f == FrequencyYearly
+}
+
+// DueDate returns the due date of the given installment, counting the start
+// date as installment 1. Monthly and yearly dates keep the day of the start
+// date, clamped to the end of shorter months.
+func (f Frequency) DueDate(start time.Time, sequence int) time.Time {
+	n := sequence - 1
+	switch f {
+	case FrequencyWeekly:
+		return start.AddDate(0, 0, 7*n)
+	case FrequencyYearly:
+		return addMonthsClamped(start, 12*n)
+	default:
+		return addMonthsClamped(start, n)
+	}
+}
+
+func addMonthsClamped(start time.Time, months int) time.Time {
+	first := time.Date(start.Year(), start.Month()+time.Month(months), 1, 0, 0, 0, 0, start.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := start.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(first.Year(), first.Month(), day, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+}
+
+// Status is the lifecycle state of a pledge
+type Status string
+
+const (
+	StatusActive    Status = "active"
+	StatusPaused    Status = "paused"
+	StatusCancelled Status = "cancelled"
+	StatusCompleted Status = "completed" // The end date has passed
+)
+
+// IsValid reports whether s is a known status
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusActive, StatusPaused, StatusCancelled, StatusCompleted:
+		return true
+	}
+	return false
+}
+
+// Pledge is a donor's commitment to give an amount on a regular schedule
+type Pledge struct {
+	models.BaseModel
+	DonorName             string      `gorm:"type:varchar(255);not null" json:"donor_name"`
+	Phone                 *string     `gorm:"type:varchar(30)" json:"phone"`
+	Email                 *string     `gorm:"type:varchar(255)" json:"email"`
+	CategoryID            uint        `gorm:"not null;index" json:"category_id"`
+	Amount                money.Money `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Frequency             Frequency   `gorm:"type:varchar(10);not null" json:"frequency"`
+	StartDate             time.Time   `gorm:"type:date;not null" json:"start_date"`
+	EndDate               *time.Time  `gorm:"type:date" json:"end_date"`
+	Status                Status      `gorm:"type:varchar(20);not null;default:'active'" json:"status"`
+	InstallmentsGenerated int         `gorm:"not null;default:0" json:"installments_generated"`
+	NextDueDate           time.Time   `gorm:"type:date;not null" json:"next_due_date"`
+	Notes                 string      `gorm:"type:text" json:"notes"`
+	CategoryName          string      `gorm:"->;-:migration" json:"category_name,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (Pledge) TableName() string {
+	return "pledges"
+}
+
+// InstallmentStatus tracks whether an installment was paid
+type InstallmentStatus string
+
+const (
+	InstallmentPending   InstallmentStatus = "pending"
+	InstallmentFulfilled InstallmentStatus = "fulfilled"
+	InstallmentMissed    InstallmentStatus = "missed"
+)
+
+// IsValid reports whether s is a known installment status
+func (s InstallmentStatus) IsValid() bool {
+	return s == InstallmentPending || s == InstallmentFulfilled || s == InstallmentMissed
+}
+
+// Installment is one due date of a pledge with the pending donation created for it
+type Installment struct {
+	ID             uint              `gorm:"primaryKey" json:"id"`
+	PledgeID       uint              `gorm:"not null;index" json:"pledge_id"`
+	Sequence       int               `gorm:"not null" json:"sequence"`
+	DueDate        time.Time         `gorm:"type:date;not null" json:"due_date"`
+	Amount         money.Money       `gorm:"type:decimal(15,2);not null" json:"amount"`
+	DonationID     *uint             `gorm:"uniqueIndex" json:"donation_id"`
+	Status         InstallmentStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	ReminderSentAt *time.Time        `gorm:"type:timestamptz" json:"reminder_sent_at"`
+	CreatedAt      time.Time         `gorm:"type:timestamptz" json:"created_at"`
+	UpdatedAt      time.Time         `gorm:"type:timestamptz" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Installment) TableName() string {
+	return "pledge_installments"
+}
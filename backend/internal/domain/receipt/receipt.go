@@ -0,0 +1,33 @@
+package receipt
+
+import (
+	"fmt"
+	"time"
+)
+
+// NumberPrefix is the prefix of every receipt (kwitansi) number
+const NumberPrefix = "KW"
+
+// Receipt represents an official receipt issued for a successful donation.
+// Receipts are never deleted, so numbers stay gap-free within a year.
+type Receipt struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	DonationID uint      `gorm:"not null;uniqueIndex" json:"donation_id"`
+	Year       int       `gorm:"not null" json:"year"`
+	Sequence   int       `gorm:"not null" json:"sequence"`
+	Number     string    `gorm:"type:varchar(50);not null;uniqueIndex" json:"number"`
+	FileName   *string   `gorm:"type:varchar(255)" json:"-"` // Stored PDF in the upload directory
+	IssuedAt   time.Time `gorm:"type:timestamp;not null" json:"issued_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Receipt) TableName() string {
+	return "donation_receipts"
+}
+
+// FormatNumber builds a receipt number such as KW-2025-000042
+func FormatNumber(year, sequence int) string {
+	return fmt.Sprintf("%s-%d-%06d", NumberPrefix, year, sequence)
+}
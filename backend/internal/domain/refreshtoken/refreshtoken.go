@@ -6,16 +6,20 @@ import (
 	"github.com/madr/backend/internal/domain/models"
 )
 
-// RefreshToken represents a refresh token entity
+// RefreshToken represents a refresh token entity. The token itself is only
+// sent to the client; TokenHash keeps its SHA-256 digest. Tokens rotated from
+// the same login share a FamilyID.
 type RefreshToken struct {
 	models.BaseModel
-	Token     string    `gorm:"type:varchar(500);uniqueIndex;not null" json:"token"`
-	UserID    uint      `gorm:"not null;index" json:"user_id"`
-	ExpiresAt time.Time `gorm:"type:timestamp;not null" json:"expires_at"`
-	IsRevoked bool      `gorm:"default:false" json:"is_revoked"`
-	RevokedAt *time.Time `gorm:"type:timestamp" json:"revoked_at,omitempty"`
-	UserAgent string    `gorm:"type:varchar(255)" json:"user_agent,omitempty"`
-	IPAddress string    `gorm:"type:varchar(45)" json:"ip_address,omitempty"`
+	TokenHash  string     `gorm:"type:char(64);uniqueIndex;not null" json:"-"`
+	FamilyID   string     `gorm:"type:varchar(64);index;not null" json:"family_id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	ExpiresAt  time.Time  `gorm:"type:timestamp;not null" json:"expires_at"`
+	IsRevoked  bool       `gorm:"default:false" json:"is_revoked"`
+	RevokedAt  *time.Time `gorm:"type:timestamp" json:"revoked_at,omitempty"`
+	UserAgent  string     `gorm:"type:varchar(255)" json:"user_agent,omitempty"`
+	IPAddress  string     `gorm:"type:varchar(45)" json:"ip_address,omitempty"`
+	SignedInAt time.Time  `gorm:"type:timestamptz;not null" json:"signed_in_at"` // Login that started the family
 }
 
 // TableName specifies the table name for GORM
@@ -32,4 +36,3 @@ func (rt *RefreshToken) IsExpired() bool {
 func (rt *RefreshToken) IsValid() bool {
 	return !rt.IsExpired() && !rt.IsRevoked
 }
-
@@ -0,0 +1,39 @@
+package role
+
+import "time"
+
+// AdminRoleName is the system role granted every permission
+const AdminRoleName = "admin"
+
+// Permission is a single action a role may grant, coded as resource:action
+// (e.g. donation:write)
+type Permission struct {
+	ID          uint   `gorm:"primarykey" json:"id"`
+	Code        string `gorm:"type:varchar(100);uniqueIndex;not null" json:"code"`
+	Description string `gorm:"type:text" json:"description"`
+}
+
+// Role groups permissions and is assigned to users
+type Role struct {
+	ID          uint         `gorm:"primarykey" json:"id"`
+	Name        string       `gorm:"type:varchar(100);uniqueIndex;not null" json:"name"`
+	Description string       `gorm:"type:text" json:"description"`
+	IsSystem    bool         `gorm:"not null;default:false" json:"is_system"` // Cannot be deleted
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// IsAdmin reports whether r is the admin system role
+func (r *Role) IsAdmin() bool {
+	return r.IsSystem && r.Name == AdminRoleName
+}
+
+// PermissionCodes lists the codes of the permissions granted by r
+func (r *Role) PermissionCodes() []string {
+	codes := make([]string, len(r.Permissions))
+	for i, p := range r.Permissions {
+		codes[i] = p.Code
+	}
+	return codes
+}
@@ -24,6 +24,7 @@ type User struct {
 	Role     UserRole `gorm:"type:varchar(20);default:'user'" json:"role"`
 	IsActive bool     `gorm:"default:true" json:"is_active"`
 	LastLogin *time.Time `gorm:"type:timestamp" json:"last_login,omitempty"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"` // Nil until the user follows the verification link
 }
 
 // TableName specifies the table name for GORM
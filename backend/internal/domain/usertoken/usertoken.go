@@ -0,0 +1,33 @@
+package usertoken
+
+import "time"
+
+// Purpose tells what a user token may be used for
+type Purpose string
+
+const (
+	PurposePasswordReset     Purpose = "password_reset"
+	PurposeEmailVerification Purpose = "email_verification"
+)
+
+// UserToken is a single-use secret emailed to a user. The token itself is
+// only sent once; TokenHash keeps its SHA-256 digest.
+type UserToken struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	Purpose   Purpose    `gorm:"type:varchar(30);not null" json:"purpose"`
+	TokenHash string     `gorm:"type:char(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
+
+// IsValidAt reports whether the token can still be used at the given time
+func (t *UserToken) IsValidAt(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
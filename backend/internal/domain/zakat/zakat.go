@@ -0,0 +1,89 @@
+package zakat
+
+import (
+	"time"
+
+	"github.com/madr/backend/internal/domain/models"
+	"github.com/madr/backend/pkg/money"
+)
+
+// Type is the kind of zakat paid
+type Type string
+
+const (
+	TypeFitrah Type = "fitrah"
+	TypeMal    Type = "mal"
+)
+
+// IsValid reports whether t is a known zakat type
+func (t Type) IsValid() bool {
+	return t == TypeFitrah || t == TypeMal
+}
+
+// PaymentForm is what the zakat was paid in
+type PaymentForm string
+
+const (
+	FormRice PaymentForm = "rice"
+	FormCash PaymentForm = "cash"
+)
+
+// IsValid reports whether f is a known payment form
+func (f PaymentForm) IsValid() bool {
+	return f == FormRice || f == FormCash
+}
+
+// Setting holds the configurable zakat rates. There is exactly one row.
+type Setting struct {
+	ID                  uint        `gorm:"primaryKey" json:"-"`
+	GoldPricePerGram    money.Money `gorm:"type:decimal(15,2);not null" json:"gold_price_per_gram"`
+	NisabGoldGrams      float64     `gorm:"type:decimal(8,3);not null" json:"nisab_gold_grams"`
+	FitrahRiceKg        float64     `gorm:"type:decimal(6,2);not null" json:"fitrah_rice_kg"`          // Per person
+	FitrahCashPerPerson money.Money `gorm:"type:decimal(15,2);not null" json:"fitrah_cash_per_person"` // Rice equivalent in cash
+	UpdatedAt           time.Time   `gorm:"type:timestamptz" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Setting) TableName() string {
+	return "zakat_settings"
+}
+
+// Muzakki is a household paying zakat
+type Muzakki struct {
+	models.BaseModel
+	HeadName      string  `gorm:"type:varchar(255);not null" json:"head_name"`
+	Phone         *string `gorm:"type:varchar(30)" json:"phone"`
+	Email         *string `gorm:"type:varchar(255)" json:"email"`
+	Address       string  `gorm:"type:text" json:"address"`
+	HouseholdSize int     `gorm:"not null;default:1" json:"household_size"`
+	Notes         string  `gorm:"type:text" json:"notes"`
+}
+
+// TableName specifies the table name for GORM
+func (Muzakki) TableName() string {
+	return "muzakki"
+}
+
+// Payment is a zakat payment of a household. Cash payments are also recorded
+// as a successful donation so they reach the ledger and donation reports.
+type Payment struct {
+	ID          uint        `gorm:"primaryKey" json:"id"`
+	MuzakkiID   uint        `gorm:"not null;index" json:"muzakki_id"`
+	ZakatType   Type        `gorm:"type:varchar(10);not null" json:"zakat_type"`
+	HijriYear   int         `gorm:"not null" json:"hijri_year"`
+	Persons     int         `gorm:"not null;default:0" json:"persons"`
+	Form        PaymentForm `gorm:"type:varchar(10);not null" json:"form"`
+	RiceKg      float64     `gorm:"type:decimal(8,2);not null;default:0" json:"rice_kg"`
+	Amount      money.Money `gorm:"type:decimal(15,2);not null;default:0" json:"amount"`
+	DonationID  *uint       `gorm:"uniqueIndex" json:"donation_id,omitempty"`
+	PaidOn      time.Time   `gorm:"type:date;not null" json:"paid_on"`
+	Notes       string      `gorm:"type:text" json:"notes"`
+	CreatedAt   time.Time   `gorm:"type:timestamptz" json:"created_at"`
+	UpdatedAt   time.Time   `gorm:"type:timestamptz" json:"updated_at"`
+	MuzakkiName string      `gorm:"->;-:migration" json:"muzakki_name,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (Payment) TableName() string {
+	return "zakat_payments"
+}
@@ -0,0 +1,116 @@
+package audit
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	auditDomain "github.com/madr/backend/internal/domain/audit"
+	auditUsecase "github.com/madr/backend/internal/usecase/audit"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// Handler handles HTTP requests for the admin audit log
+type Handler struct {
+	useCase auditUsecase.UseCase
+}
+
+// NewHandler creates a new audit log handler
+func NewHandler(useCase auditUsecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// GetAll handles GET /admin/audit-logs
+func (h *Handler) GetAll(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	filter, err := parseListFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.useCase.GetAll(limit, offset, filter)
+	if err != nil {
+		if err.Error() == "invalid date range" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		logger.Error().Err(err).Msg("Failed to get audit logs")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get audit logs",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseListFilter reads the optional actor_id, entity_type, entity_id,
+// action, outcome, from and to query parameters
+func parseListFilter(c *gin.Context) (*auditUsecase.ListFilter, error) {
+	filter := &auditUsecase.ListFilter{
+		EntityType: c.Query("entity_type"),
+		Action:     c.Query("action"),
+	}
+
+	var err error
+	if filter.ActorID, err = queryID(c, "actor_id"); err != nil {
+		return nil, err
+	}
+	if filter.EntityID, err = queryID(c, "entity_id"); err != nil {
+		return nil, err
+	}
+
+	if raw := c.Query("outcome"); raw != "" {
+		outcome := auditDomain.Outcome(raw)
+		if !outcome.IsValid() {
+			return nil, fmt.Errorf("invalid outcome %q, expected success or failure", raw)
+		}
+		filter.Outcome = &outcome
+	}
+
+	if filter.From, err = queryDate(c, "from"); err != nil {
+		return nil, err
+	}
+	if filter.To, err = queryDate(c, "to"); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func queryID(c *gin.Context, name string) (*uint, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q", name, raw)
+	}
+	value := uint(id)
+	return &value, nil
+}
+
+func queryDate(c *gin.Context, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	date, err := utils.ParseDate(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &date, nil
+}
@@ -1,22 +1,31 @@
 package auth
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/madr/backend/internal/middleware"
+	"github.com/madr/backend/internal/usecase/account"
 	"github.com/madr/backend/internal/usecase/auth"
+	"github.com/madr/backend/internal/usecase/lockout"
 	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/password"
 )
 
 // Handler handles HTTP requests for authentication
 type Handler struct {
 	useCase auth.UseCase
+	account account.UseCase
 }
 
 // NewHandler creates a new auth handler
-func NewHandler(useCase auth.UseCase) *Handler {
+func NewHandler(useCase auth.UseCase, accountUseCase account.UseCase) *Handler {
 	return &Handler{
 		useCase: useCase,
+		account: accountUseCase,
 	}
 }
 
@@ -34,11 +43,29 @@ func (h *Handler) Register(c *gin.Context) {
 
 	response, err := h.useCase.Register(&req)
 	if err != nil {
-		if err.Error() == "username already exists" || err.Error() == "email already exists" {
+		if password.IsPolicyError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		switch err.Error() {
+		case "username already exists", "email already exists":
 			c.JSON(http.StatusConflict, gin.H{
 				"error": err.Error(),
 			})
 			return
+		case "registration is closed", "invitation required":
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+			})
+			return
+		case "invalid invitation", "invitation has expired", "invitation has already been used",
+			"invitation has been revoked", "email does not match the invitation":
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
 		}
 		logger.Error().Err(err).Msg("Failed to register user")
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -47,6 +74,11 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 
+	// The account exists either way; the user can ask for another link
+	if err := h.account.SendVerification(response.User.ID); err != nil {
+		logger.Warn().Err(err).Uint("user_id", response.User.ID).Msg("Failed to send verification email after registration")
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "User registered successfully",
 		"data":    response,
@@ -71,6 +103,9 @@ func (h *Handler) Login(c *gin.Context) {
 
 	response, err := h.useCase.Login(&req, userAgent, ipAddress)
 	if err != nil {
+		if writeThrottled(c, err) {
+			return
+		}
 		if err.Error() == "invalid credentials" {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error": "Invalid credentials",
@@ -90,12 +125,74 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
+	message := "Login successful"
+	if response.MFARequired || response.MFASetupRequired {
+		message = "Two-factor authentication required"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    response,
+	})
+}
+
+// LoginMFA handles POST /auth/login/2fa
+func (h *Handler) LoginMFA(c *gin.Context) {
+	var req auth.MFALoginRequest
+	if !bindJSON(c, &req, "Invalid two-factor login request body") {
+		return
+	}
+
+	response, err := h.useCase.LoginMFA(&req, c.GetHeader("User-Agent"), c.ClientIP())
+	if err != nil {
+		writeAccountError(c, err, "Failed to login")
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Login successful",
 		"data":    response,
 	})
 }
 
+// SetupMFA handles POST /auth/2fa/setup
+func (h *Handler) SetupMFA(c *gin.Context) {
+	var req auth.MFASetupRequest
+	if !bindJSON(c, &req, "Invalid two-factor setup request body") {
+		return
+	}
+
+	response, err := h.useCase.SetupMFA(&req)
+	if err != nil {
+		writeAccountError(c, err, "Failed to enroll two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Scan the QR code with an authenticator app, then confirm with a code",
+		"data":    response,
+	})
+}
+
+// ConfirmMFASetup handles POST /auth/2fa/setup/confirm
+func (h *Handler) ConfirmMFASetup(c *gin.Context) {
+	var req auth.MFALoginRequest
+	if !bindJSON(c, &req, "Invalid two-factor setup request body") {
+		return
+	}
+
+	response, err := h.useCase.ConfirmMFASetup(&req, c.GetHeader("User-Agent"), c.ClientIP())
+	if err != nil {
+		writeAccountError(c, err, "Failed to confirm two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Two-factor authentication enabled, store the recovery codes somewhere safe",
+		"data":    response,
+	})
+}
+
 // RefreshToken handles POST /auth/refresh
 func (h *Handler) RefreshToken(c *gin.Context) {
 	var req auth.RefreshTokenRequest
@@ -175,6 +272,69 @@ func (h *Handler) GetMe(c *gin.Context) {
 	})
 }
 
+// UpdateMe handles PUT /auth/me (protected route)
+func (h *Handler) UpdateMe(c *gin.Context) {
+	uid, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	var req auth.UpdateMeRequest
+	if !bindJSON(c, &req, "Invalid update profile request body") {
+		return
+	}
+
+	response, err := h.useCase.UpdateMe(uid, &req, c.ClientIP())
+	if err != nil {
+		writeAccountError(c, err, "Failed to update profile")
+		return
+	}
+
+	if response.EmailChanged {
+		if err := h.account.SendVerification(uid); err != nil {
+			logger.Warn().Err(err).Uint("user_id", uid).Msg("Failed to send verification email after email change")
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Profile updated successfully",
+		"data":    response,
+	})
+}
+
+// ChangePassword handles POST /auth/change-password (protected route)
+func (h *Handler) ChangePassword(c *gin.Context) {
+	uid, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	var req auth.ChangePasswordRequest
+	if !bindJSON(c, &req, "Invalid change password request body") {
+		return
+	}
+
+	response, err := h.useCase.ChangePassword(uid, &req, c.GetHeader("User-Agent"), c.ClientIP())
+	if err != nil {
+		writeAccountError(c, err, "Failed to change password")
+		return
+	}
+
+	body := gin.H{
+		"message": "Password changed successfully",
+	}
+	if response.RefreshTokenResponse != nil {
+		body["data"] = response.RefreshTokenResponse
+	}
+	c.JSON(http.StatusOK, body)
+}
+
 // Logout handles POST /auth/logout
 func (h *Handler) Logout(c *gin.Context) {
 	var req auth.RefreshTokenRequest
@@ -233,3 +393,150 @@ func (h *Handler) LogoutAll(c *gin.Context) {
 	})
 }
 
+// ForgotPassword handles POST /auth/forgot-password
+func (h *Handler) ForgotPassword(c *gin.Context) {
+	var req account.ForgotPasswordRequest
+	if !bindJSON(c, &req, "Invalid forgot password request body") {
+		return
+	}
+
+	if err := h.account.ForgotPassword(&req); err != nil {
+		writeAccountError(c, err, "Failed to request password reset")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "If the email is registered, a password reset link has been sent",
+	})
+}
+
+// ResetPassword handles POST /auth/reset-password
+func (h *Handler) ResetPassword(c *gin.Context) {
+	var req account.ResetPasswordRequest
+	if !bindJSON(c, &req, "Invalid reset password request body") {
+		return
+	}
+
+	if err := h.account.ResetPassword(&req); err != nil {
+		writeAccountError(c, err, "Failed to reset password")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password reset successfully, please log in again",
+	})
+}
+
+// VerifyEmail handles POST /auth/verify-email
+func (h *Handler) VerifyEmail(c *gin.Context) {
+	var req account.VerifyEmailRequest
+	if !bindJSON(c, &req, "Invalid verify email request body") {
+		return
+	}
+
+	if err := h.account.VerifyEmail(&req); err != nil {
+		writeAccountError(c, err, "Failed to verify email")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email verified successfully",
+	})
+}
+
+// ResendVerification handles POST /auth/resend-verification (protected route)
+func (h *Handler) ResendVerification(c *gin.Context) {
+	uid, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	if err := h.account.SendVerification(uid); err != nil {
+		writeAccountError(c, err, "Failed to send verification email")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Verification email sent",
+	})
+}
+
+// bindJSON binds the request body and writes a 400 response when it is invalid
+func bindJSON(c *gin.Context, req interface{}, logMessage string) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		logger.Warn().Err(err).Msg(logMessage)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// writeAccountError maps errors from the account, profile and two-factor
+// login flows to HTTP responses
+func writeAccountError(c *gin.Context, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+
+	if password.IsPolicyError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if writeThrottled(c, err) {
+		return
+	}
+
+	switch err.Error() {
+	case "invalid or expired token", "new password must be different from the current password":
+		status, message = http.StatusBadRequest, err.Error()
+	case "invalid current password":
+		status, message = http.StatusForbidden, err.Error()
+	case "invalid or expired mfa token", "invalid code":
+		status, message = http.StatusUnauthorized, err.Error()
+	case "two-factor authentication has not been set up":
+		status, message = http.StatusBadRequest, err.Error()
+	case "email already exists", "two-factor authentication is already enabled":
+		status, message = http.StatusConflict, err.Error()
+	case "account is inactive":
+		status, message = http.StatusForbidden, "Account is inactive"
+	case "user not found":
+		status, message = http.StatusNotFound, "User not found"
+	case "email already verified":
+		status, message = http.StatusConflict, err.Error()
+	default:
+		logger.Error().Err(err).Msg(fallback)
+	}
+
+	c.JSON(status, gin.H{
+		"error": message,
+	})
+}
+
+// writeThrottled responds 423 to a locked account and 429 to a client that
+// has to back off, telling it when to retry. It reports whether err was a
+// throttling error.
+func writeThrottled(c *gin.Context, err error) bool {
+	var throttled *lockout.ThrottledError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+
+	status := http.StatusTooManyRequests
+	if throttled.Locked {
+		status = http.StatusLocked
+	}
+	retryAfter := int64(math.Ceil(throttled.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+	c.JSON(status, gin.H{
+		"error":       throttled.Error(),
+		"retry_after": retryAfter,
+	})
+	return true
+}
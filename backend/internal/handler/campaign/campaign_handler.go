@@ -0,0 +1,172 @@
+package campaign
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	campaignDomain "github.com/madr/backend/internal/domain/campaign"
+	campaignRepo "github.com/madr/backend/internal/repository/campaign"
+	campaignUsecase "github.com/madr/backend/internal/usecase/campaign"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// Handler handles HTTP requests for fundraising campaigns
+type Handler struct {
+	useCase campaignUsecase.UseCase
+}
+
+// NewHandler creates a new campaign handler
+func NewHandler(useCase campaignUsecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// GetAll handles GET /campaigns and GET /admin/campaigns
+func (h *Handler) GetAll(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	filter := &campaignRepo.Filter{Search: c.Query("search")}
+	if raw := c.Query("status"); raw != "" {
+		status := campaignDomain.Status(raw)
+		if !status.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid status, expected active or closed",
+			})
+			return
+		}
+		filter.Status = &status
+	}
+
+	response, err := h.useCase.GetAll(limit, offset, filter)
+	if err != nil {
+		writeError(c, err, "Failed to get campaigns")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetByID handles GET /campaigns/:id and GET /admin/campaigns/:id
+func (h *Handler) GetByID(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	campaign, err := h.useCase.GetByID(id)
+	if err != nil {
+		writeError(c, err, "Failed to get campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": campaign,
+	})
+}
+
+// Create handles POST /admin/campaigns
+func (h *Handler) Create(c *gin.Context) {
+	var req campaignUsecase.CreateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	campaign, err := h.useCase.Create(&req)
+	if err != nil {
+		writeError(c, err, "Failed to create campaign")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Campaign created successfully",
+		"data":    campaign,
+	})
+}
+
+// Update handles PUT /admin/campaigns/:id
+func (h *Handler) Update(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req campaignUsecase.UpdateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	campaign, err := h.useCase.Update(id, &req)
+	if err != nil {
+		writeError(c, err, "Failed to update campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Campaign updated successfully",
+		"data":    campaign,
+	})
+}
+
+// Delete handles DELETE /admin/campaigns/:id
+func (h *Handler) Delete(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.Delete(id); err != nil {
+		writeError(c, err, "Failed to delete campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Campaign deleted successfully",
+	})
+}
+
+// writeError maps use case errors to HTTP responses
+func writeError(c *gin.Context, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+
+	switch err.Error() {
+	case "campaign not found", "donation category not found":
+		status, message = http.StatusNotFound, err.Error()
+	case "category already has a campaign", "campaign end date has passed":
+		status, message = http.StatusConflict, err.Error()
+	case "invalid start date", "invalid end date", "end date is before start date", "cover image not found":
+		status, message = http.StatusBadRequest, err.Error()
+	default:
+		logger.Error().Err(err).Msg(fallback)
+	}
+
+	c.JSON(status, gin.H{
+		"error": message,
+	})
+}
+
+func bindJSON(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		logger.Warn().Err(err).Str("path", c.FullPath()).Msg("Invalid campaign request body")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid campaign ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -1,12 +1,17 @@
 package donation
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	donationUsecase "github.com/madr/backend/internal/usecase/donation"
 	donationDomain "github.com/madr/backend/internal/domain/donation"
+	"github.com/madr/backend/internal/middleware"
+	"github.com/madr/backend/internal/utils"
 	"github.com/madr/backend/pkg/logger"
 )
 
@@ -34,7 +39,7 @@ func (h *Handler) Create(c *gin.Context) {
 		return
 	}
 
-	don, err := h.useCase.Create(&req)
+	don, err := h.useCase.Create(&req, actor(c))
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to create donation")
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -84,20 +89,23 @@ func (h *Handler) GetAll(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 	
-	// Optional status filter
-	statusStr := c.Query("status")
-	var status *donationDomain.PaymentStatus
-	if statusStr != "" {
-		ps := donationDomain.PaymentStatus(statusStr)
-		if ps == donationDomain.PaymentStatusPending || 
-		   ps == donationDomain.PaymentStatusSuccess || 
-		   ps == donationDomain.PaymentStatusFailed {
-			status = &ps
-		}
+	filter, err := parseListFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter",
+			"details": err.Error(),
+		})
+		return
 	}
 
-	response, err := h.useCase.GetAll(limit, offset, status)
+	response, err := h.useCase.GetAll(limit, offset, filter)
 	if err != nil {
+		if err.Error() == "invalid date range" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid date range",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to get donations",
 		})
@@ -107,6 +115,164 @@ func (h *Handler) GetAll(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GetReport handles GET /admin/donations/reports
+func (h *Handler) GetReport(c *gin.Context) {
+	categoryID, from, to, err := parseReportFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	report, err := h.useCase.GetReport(&donationUsecase.ReportRequest{
+		Period:     donationDomain.ReportPeriod(c.Query("period")),
+		From:       from,
+		To:         to,
+		CategoryID: categoryID,
+	})
+	if err != nil {
+		switch err.Error() {
+		case "invalid report period", "invalid date range", "date range too large":
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to get donation report",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// Export handles GET /admin/donations/export
+func (h *Handler) Export(c *gin.Context) {
+	h.export(c, "donasi", h.useCase.ExportDonations)
+}
+
+// ExportSummary handles GET /admin/donations/summary/export
+func (h *Handler) ExportSummary(c *gin.Context) {
+	h.export(c, "ringkasan-donasi", h.useCase.ExportSummary)
+}
+
+// export streams a donation export as a file download
+func (h *Handler) export(c *gin.Context, name string, write func(io.Writer, donationUsecase.ExportFormat, *donationUsecase.ListFilter) error) {
+	format := donationUsecase.ExportFormat(c.DefaultQuery("format", "csv"))
+	if !format.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid export format, expected csv or xlsx",
+		})
+		return
+	}
+
+	filter, err := parseListFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	fileName := exportFileName(name, filter, format)
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Header("Cache-Control", "no-store")
+
+	if err := write(c.Writer, format, filter); err != nil {
+		if c.Writer.Written() {
+			// Headers are already sent; the client receives a truncated file
+			logger.Error().Err(err).Str("file", fileName).Msg("Donation export interrupted")
+			return
+		}
+		c.Header("Content-Disposition", "")
+		c.Header("Cache-Control", "")
+		if err.Error() == "invalid date range" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid date range",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to export donations",
+		})
+	}
+}
+
+// exportFileName names an export after its date range, e.g. donasi-2025-01-01_2025-01-31.csv
+func exportFileName(name string, filter *donationUsecase.ListFilter, format donationUsecase.ExportFormat) string {
+	if filter.From != nil {
+		name += "-" + filter.From.Format(utils.DateLayout)
+	}
+	if filter.To != nil {
+		name += "_" + filter.To.Format(utils.DateLayout)
+	}
+	if filter.From == nil && filter.To == nil {
+		name += "-" + time.Now().In(utils.Jakarta).Format("20060102")
+	}
+	return name + "." + string(format)
+}
+
+// parseListFilter reads the optional status, category_id, from and to query parameters.
+// Unknown statuses are ignored.
+func parseListFilter(c *gin.Context) (*donationUsecase.ListFilter, error) {
+	var status *donationDomain.PaymentStatus
+	if statusStr := c.Query("status"); statusStr != "" {
+		ps := donationDomain.PaymentStatus(statusStr)
+		if ps == donationDomain.PaymentStatusPending ||
+			ps == donationDomain.PaymentStatusSuccess ||
+			ps == donationDomain.PaymentStatusFailed {
+			status = &ps
+		}
+	}
+
+	categoryID, from, to, err := parseReportFilter(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &donationUsecase.ListFilter{
+		Status:     status,
+		CategoryID: categoryID,
+		From:       from,
+		To:         to,
+	}, nil
+}
+
+// parseReportFilter reads the optional category_id, from and to query parameters
+func parseReportFilter(c *gin.Context) (*uint, *time.Time, *time.Time, error) {
+	var categoryID *uint
+	if raw := c.Query("category_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid category_id %q", raw)
+		}
+		value := uint(id)
+		categoryID = &value
+	}
+
+	var dates [2]*time.Time
+	for i, name := range []string{"from", "to"} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		date, err := utils.ParseDate(raw)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		dates[i] = &date
+	}
+
+	return categoryID, dates[0], dates[1], nil
+}
+
 // Update handles PUT /donations/:id
 func (h *Handler) Update(c *gin.Context) {
 	idStr := c.Param("id")
@@ -128,7 +294,7 @@ func (h *Handler) Update(c *gin.Context) {
 		return
 	}
 
-	don, err := h.useCase.Update(uint(id), &req)
+	don, err := h.useCase.Update(uint(id), &req, actor(c))
 	if err != nil {
 		if err.Error() == "donation not found" {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -159,7 +325,7 @@ func (h *Handler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.useCase.Delete(uint(id)); err != nil {
+	if err := h.useCase.Delete(uint(id), actor(c)); err != nil {
 		if err.Error() == "donation not found" {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error": "Donation not found",
@@ -177,6 +343,36 @@ func (h *Handler) Delete(c *gin.Context) {
 	})
 }
 
+// GetHistory handles GET /admin/donations/:id/history
+func (h *Handler) GetHistory(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid donation ID",
+		})
+		return
+	}
+
+	logs, err := h.useCase.GetHistory(uint(id))
+	if err != nil {
+		if err.Error() == "donation not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Donation not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get donation history",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": logs,
+	})
+}
+
 // GetSummary handles GET /donations/summary (Public endpoint)
 func (h *Handler) GetSummary(c *gin.Context) {
 	summary, err := h.useCase.GetSummary()
@@ -193,3 +389,213 @@ func (h *Handler) GetSummary(c *gin.Context) {
 	})
 }
 
+
+// Checkout handles POST /donations/checkout (Public endpoint)
+func (h *Handler) Checkout(c *gin.Context) {
+	var req donationUsecase.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn().Err(err).Msg("Invalid checkout request body")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if userID, err := middleware.GetUserIDFromContext(c); err == nil {
+		req.UserID = &userID
+	}
+
+	response, err := h.useCase.Checkout(&req)
+	if err != nil {
+		if err.Error() == "payment provider is not available" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Payment provider is not available",
+			})
+			return
+		}
+		if err.Error() == "campaign is closed" || err.Error() == "campaign has not started" {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create payment",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Donation checkout created successfully",
+		"data":    response,
+	})
+}
+
+// GetMyDonations handles GET /me/donations, the signed-in donor's history
+func (h *Handler) GetMyDonations(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	filter, err := parseListFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.useCase.GetDonorHistory(userID, limit, offset, filter)
+	if err != nil {
+		if err.Error() == "invalid date range" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid date range",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get donations",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ClaimDonation handles POST /me/donations/claim
+func (h *Handler) ClaimDonation(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	var req donationUsecase.ClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	don, err := h.useCase.ClaimDonation(userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "donation not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Donation not found",
+			})
+		case "donation already claimed":
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Donation already claimed by another account",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to claim donation",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Donation claimed successfully",
+		"data":    don,
+	})
+}
+
+// Webhook handles POST /donations/webhook/:provider (Public endpoint, signature verified)
+func (h *Handler) Webhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	don, err := h.useCase.HandleWebhook(c.Param("provider"), c.Request.Header, body)
+	if err != nil {
+		switch err.Error() {
+		case "unknown payment provider", "donation not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+			})
+		case "invalid webhook signature":
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid signature",
+			})
+		case "invalid webhook payload", "payment amount mismatch":
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			logger.Error().Err(err).Msg("Failed to process payment webhook")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to process webhook",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Webhook processed",
+		"payment_status": don.PaymentStatus,
+	})
+}
+
+// SyncPayment handles POST /donations/:id/sync-payment
+func (h *Handler) SyncPayment(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid donation ID",
+		})
+		return
+	}
+
+	don, err := h.useCase.SyncPaymentStatus(uint(id))
+	if err != nil {
+		switch err.Error() {
+		case "donation not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Donation not found",
+			})
+		case "donation has no payment reference", "unknown payment provider":
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error": "Failed to sync payment status",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Payment status synced",
+		"data":    don,
+	})
+}
+
+// actor identifies the signed-in admin and client address for the audit trail
+func actor(c *gin.Context) *donationDomain.Actor {
+	a := &donationDomain.Actor{IP: c.ClientIP()}
+	if userID, err := middleware.GetUserIDFromContext(c); err == nil {
+		a.UserID = &userID
+	}
+	return a
+}
@@ -0,0 +1,105 @@
+package invitation
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/madr/backend/internal/middleware"
+	invitationUsecase "github.com/madr/backend/internal/usecase/invitation"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// Handler handles HTTP requests for registration invitations
+type Handler struct {
+	useCase invitationUsecase.UseCase
+}
+
+// NewHandler creates a new invitation handler
+func NewHandler(useCase invitationUsecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// GetAll handles GET /admin/invitations
+func (h *Handler) GetAll(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	response, err := h.useCase.GetAll(limit, offset)
+	if err != nil {
+		writeError(c, err, "Failed to get invitations")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Create handles POST /admin/invitations
+func (h *Handler) Create(c *gin.Context) {
+	var req invitationUsecase.CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn().Err(err).Msg("Invalid create invitation request body")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	createdBy, _ := middleware.GetUserIDFromContext(c)
+	response, err := h.useCase.Create(&req, createdBy)
+	if err != nil {
+		writeError(c, err, "Failed to create invitation")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Invitation created successfully",
+		"data":    response,
+	})
+}
+
+// Revoke handles DELETE /admin/invitations/:id
+func (h *Handler) Revoke(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid invitation ID",
+		})
+		return
+	}
+
+	if err := h.useCase.Revoke(uint(id)); err != nil {
+		writeError(c, err, "Failed to revoke invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Invitation revoked successfully",
+	})
+}
+
+// writeError maps use case errors to HTTP responses
+func writeError(c *gin.Context, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+
+	switch err.Error() {
+	case "invitation not found", "role not found":
+		status, message = http.StatusNotFound, err.Error()
+	case "invitation is no longer pending":
+		status, message = http.StatusConflict, err.Error()
+	case "invitation expiry is too long":
+		status, message = http.StatusBadRequest, err.Error()
+	case "cannot grant permissions you do not have", "only administrators can assign the admin role":
+		status, message = http.StatusForbidden, err.Error()
+	default:
+		logger.Error().Err(err).Msg(fallback)
+	}
+
+	c.JSON(status, gin.H{
+		"error": message,
+	})
+}
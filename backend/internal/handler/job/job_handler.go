@@ -0,0 +1,68 @@
+package job
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	jobRunDomain "github.com/madr/backend/internal/domain/jobrun"
+	jobRunRepo "github.com/madr/backend/internal/repository/jobrun"
+	jobUsecase "github.com/madr/backend/internal/usecase/job"
+)
+
+// Handler handles HTTP requests for background jobs
+type Handler struct {
+	useCase jobUsecase.UseCase
+}
+
+// NewHandler creates a new background job handler
+func NewHandler(useCase jobUsecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// GetAll handles GET /admin/jobs
+func (h *Handler) GetAll(c *gin.Context) {
+	jobs, err := h.useCase.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get jobs",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": jobs,
+	})
+}
+
+// GetRuns handles GET /admin/jobs/runs
+func (h *Handler) GetRuns(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	filter := &jobRunRepo.Filter{
+		JobName: c.Query("job"),
+	}
+	if raw := c.Query("status"); raw != "" {
+		status := jobRunDomain.Status(raw)
+		if !status.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid status, expected running, succeeded or failed",
+			})
+			return
+		}
+		filter.Status = &status
+	}
+
+	response, err := h.useCase.GetRuns(limit, offset, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get job runs",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,439 @@
+package ledger
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	ledgerDomain "github.com/madr/backend/internal/domain/ledger"
+	ledgerRepo "github.com/madr/backend/internal/repository/ledger"
+	ledgerUsecase "github.com/madr/backend/internal/usecase/ledger"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// Handler handles HTTP requests for the cash ledger
+type Handler struct {
+	useCase ledgerUsecase.UseCase
+}
+
+// NewHandler creates a new cash ledger handler
+func NewHandler(useCase ledgerUsecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// GetAccounts handles GET /admin/ledger/accounts
+func (h *Handler) GetAccounts(c *gin.Context) {
+	accounts, err := h.useCase.GetAccounts()
+	if err != nil {
+		writeError(c, err, "Failed to get ledger accounts")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": accounts,
+	})
+}
+
+// CreateAccount handles POST /admin/ledger/accounts
+func (h *Handler) CreateAccount(c *gin.Context) {
+	var req ledgerUsecase.CreateAccountRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	acc, err := h.useCase.CreateAccount(&req)
+	if err != nil {
+		writeError(c, err, "Failed to create ledger account")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Ledger account created successfully",
+		"data":    acc,
+	})
+}
+
+// UpdateAccount handles PUT /admin/ledger/accounts/:id
+func (h *Handler) UpdateAccount(c *gin.Context) {
+	id, ok := parseID(c, "Invalid account ID")
+	if !ok {
+		return
+	}
+
+	var req ledgerUsecase.UpdateAccountRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	acc, err := h.useCase.UpdateAccount(id, &req)
+	if err != nil {
+		writeError(c, err, "Failed to update ledger account")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ledger account updated successfully",
+		"data":    acc,
+	})
+}
+
+// DeleteAccount handles DELETE /admin/ledger/accounts/:id
+func (h *Handler) DeleteAccount(c *gin.Context) {
+	id, ok := parseID(c, "Invalid account ID")
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.DeleteAccount(id); err != nil {
+		writeError(c, err, "Failed to delete ledger account")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ledger account deleted successfully",
+	})
+}
+
+// GetCategories handles GET /admin/ledger/categories
+func (h *Handler) GetCategories(c *gin.Context) {
+	var entryType *ledgerDomain.EntryType
+	if raw := c.Query("type"); raw != "" {
+		t := ledgerDomain.EntryType(raw)
+		if !t.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid type, expected income or expense",
+			})
+			return
+		}
+		entryType = &t
+	}
+
+	categories, err := h.useCase.GetCategories(entryType)
+	if err != nil {
+		writeError(c, err, "Failed to get ledger categories")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": categories,
+	})
+}
+
+// CreateCategory handles POST /admin/ledger/categories
+func (h *Handler) CreateCategory(c *gin.Context) {
+	var req ledgerUsecase.CreateCategoryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	cat, err := h.useCase.CreateCategory(&req)
+	if err != nil {
+		writeError(c, err, "Failed to create ledger category")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Ledger category created successfully",
+		"data":    cat,
+	})
+}
+
+// UpdateCategory handles PUT /admin/ledger/categories/:id
+func (h *Handler) UpdateCategory(c *gin.Context) {
+	id, ok := parseID(c, "Invalid category ID")
+	if !ok {
+		return
+	}
+
+	var req ledgerUsecase.UpdateCategoryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	cat, err := h.useCase.UpdateCategory(id, &req)
+	if err != nil {
+		writeError(c, err, "Failed to update ledger category")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ledger category updated successfully",
+		"data":    cat,
+	})
+}
+
+// DeleteCategory handles DELETE /admin/ledger/categories/:id
+func (h *Handler) DeleteCategory(c *gin.Context) {
+	id, ok := parseID(c, "Invalid category ID")
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.DeleteCategory(id); err != nil {
+		writeError(c, err, "Failed to delete ledger category")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ledger category deleted successfully",
+	})
+}
+
+// GetEntries handles GET /admin/ledger/entries
+func (h *Handler) GetEntries(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	filter, err := parseEntryFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.useCase.GetEntries(limit, offset, filter)
+	if err != nil {
+		writeError(c, err, "Failed to get ledger entries")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetEntry handles GET /admin/ledger/entries/:id
+func (h *Handler) GetEntry(c *gin.Context) {
+	id, ok := parseID(c, "Invalid entry ID")
+	if !ok {
+		return
+	}
+
+	entry, err := h.useCase.GetEntry(id)
+	if err != nil {
+		writeError(c, err, "Failed to get ledger entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": entry,
+	})
+}
+
+// CreateEntry handles POST /admin/ledger/entries
+func (h *Handler) CreateEntry(c *gin.Context) {
+	var req ledgerUsecase.CreateEntryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	entry, err := h.useCase.CreateEntry(&req)
+	if err != nil {
+		writeError(c, err, "Failed to create ledger entry")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Ledger entry created successfully",
+		"data":    entry,
+	})
+}
+
+// UpdateEntry handles PUT /admin/ledger/entries/:id
+func (h *Handler) UpdateEntry(c *gin.Context) {
+	id, ok := parseID(c, "Invalid entry ID")
+	if !ok {
+		return
+	}
+
+	var req ledgerUsecase.UpdateEntryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	entry, err := h.useCase.UpdateEntry(id, &req)
+	if err != nil {
+		writeError(c, err, "Failed to update ledger entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ledger entry updated successfully",
+		"data":    entry,
+	})
+}
+
+// DeleteEntry handles DELETE /admin/ledger/entries/:id
+func (h *Handler) DeleteEntry(c *gin.Context) {
+	id, ok := parseID(c, "Invalid entry ID")
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.DeleteEntry(id); err != nil {
+		writeError(c, err, "Failed to delete ledger entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ledger entry deleted successfully",
+	})
+}
+
+// GetBalance handles GET /admin/ledger/balance
+func (h *Handler) GetBalance(c *gin.Context) {
+	accountID, date, err := parseAccountAndDate(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	balance, err := h.useCase.GetBalance(accountID, date)
+	if err != nil {
+		writeError(c, err, "Failed to get ledger balance")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": balance,
+	})
+}
+
+// GetWeeklyReport handles GET /ledger/weekly-report (Public endpoint)
+func (h *Handler) GetWeeklyReport(c *gin.Context) {
+	accountID, date, err := parseAccountAndDate(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	report, err := h.useCase.GetWeeklyReport(date, accountID)
+	if err != nil {
+		writeError(c, err, "Failed to get weekly report")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// writeError maps use case errors to HTTP responses
+func writeError(c *gin.Context, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+
+	switch err.Error() {
+	case "ledger account not found", "ledger category not found", "ledger entry not found":
+		status, message = http.StatusNotFound, err.Error()
+	case "account name already exists", "category name already exists",
+		"account has entries", "category is in use", "default account required",
+		"system category cannot be changed", "entry is posted from a donation":
+		status, message = http.StatusConflict, err.Error()
+	case "invalid entry date", "invalid date range":
+		status, message = http.StatusBadRequest, err.Error()
+	default:
+		logger.Error().Err(err).Msg(fallback)
+	}
+
+	c.JSON(status, gin.H{
+		"error": message,
+	})
+}
+
+func bindJSON(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		logger.Warn().Err(err).Str("path", c.FullPath()).Msg("Invalid ledger request body")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func parseID(c *gin.Context, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": message,
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parseOptionalID reads an optional numeric query parameter
+func parseOptionalID(c *gin.Context, name string) (*uint, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q", name, raw)
+	}
+	value := uint(id)
+	return &value, nil
+}
+
+// parseOptionalDate reads an optional YYYY-MM-DD query parameter
+func parseOptionalDate(c *gin.Context, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	date, err := utils.ParseDate(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &date, nil
+}
+
+func parseAccountAndDate(c *gin.Context) (*uint, *time.Time, error) {
+	accountID, err := parseOptionalID(c, "account_id")
+	if err != nil {
+		return nil, nil, err
+	}
+	date, err := parseOptionalDate(c, "date")
+	if err != nil {
+		return nil, nil, err
+	}
+	return accountID, date, nil
+}
+
+func parseEntryFilter(c *gin.Context) (*ledgerRepo.EntryFilter, error) {
+	filter := &ledgerRepo.EntryFilter{}
+	var err error
+
+	if filter.AccountID, err = parseOptionalID(c, "account_id"); err != nil {
+		return nil, err
+	}
+	if filter.CategoryID, err = parseOptionalID(c, "category_id"); err != nil {
+		return nil, err
+	}
+	if raw := c.Query("type"); raw != "" {
+		t := ledgerDomain.EntryType(raw)
+		if !t.IsValid() {
+			return nil, fmt.Errorf("invalid type %q", raw)
+		}
+		filter.Type = &t
+	}
+	if filter.From, err = parseOptionalDate(c, "from"); err != nil {
+		return nil, err
+	}
+	if filter.To, err = parseOptionalDate(c, "to"); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
@@ -0,0 +1,197 @@
+package mfa
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/madr/backend/internal/middleware"
+	"github.com/madr/backend/internal/usecase/lockout"
+	mfaUsecase "github.com/madr/backend/internal/usecase/mfa"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// Handler handles HTTP requests for managing two-factor authentication
+type Handler struct {
+	useCase mfaUsecase.UseCase
+}
+
+// NewHandler creates a new two-factor authentication handler
+func NewHandler(useCase mfaUsecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// GetStatus handles GET /auth/2fa
+func (h *Handler) GetStatus(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.useCase.GetStatus(userID)
+	if err != nil {
+		writeError(c, err, "Failed to get two-factor authentication status")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": response,
+	})
+}
+
+// Enroll handles POST /auth/2fa/enroll
+func (h *Handler) Enroll(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.useCase.Enroll(userID)
+	if err != nil {
+		writeError(c, err, "Failed to enroll two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Scan the QR code with an authenticator app, then confirm with a code",
+		"data":    response,
+	})
+}
+
+// Confirm handles POST /auth/2fa/confirm
+func (h *Handler) Confirm(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req mfaUsecase.CodeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	response, err := h.useCase.Confirm(userID, req.Code)
+	if err != nil {
+		writeError(c, err, "Failed to confirm two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Two-factor authentication enabled, store the recovery codes somewhere safe",
+		"data":    response,
+	})
+}
+
+// Disable handles POST /auth/2fa/disable
+func (h *Handler) Disable(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req mfaUsecase.DisableRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.useCase.Disable(userID, &req, c.ClientIP()); err != nil {
+		writeError(c, err, "Failed to disable two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes handles POST /auth/2fa/recovery-codes
+func (h *Handler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req mfaUsecase.CodeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	response, err := h.useCase.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		writeError(c, err, "Failed to regenerate recovery codes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Recovery codes regenerated, the previous codes no longer work",
+		"data":    response,
+	})
+}
+
+// currentUser reads the authenticated user and writes a 401 response when
+// there is none
+func currentUser(c *gin.Context) (uint, bool) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return 0, false
+	}
+	return userID, true
+}
+
+// bindJSON binds the request body and writes a 400 response when it is invalid
+func bindJSON(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		logger.Warn().Err(err).Msg("Invalid two-factor authentication request body")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// writeError maps use case errors to HTTP responses
+func writeError(c *gin.Context, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+
+	var throttled *lockout.ThrottledError
+	if errors.As(err, &throttled) {
+		status = http.StatusTooManyRequests
+		if throttled.Locked {
+			status = http.StatusLocked
+		}
+		retryAfter := int64(math.Ceil(throttled.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+		c.JSON(status, gin.H{
+			"error":       throttled.Error(),
+			"retry_after": retryAfter,
+		})
+		return
+	}
+
+	switch err.Error() {
+	case "user not found":
+		status, message = http.StatusNotFound, err.Error()
+	case "invalid code", "two-factor authentication has not been set up", "two-factor authentication is not enabled":
+		status, message = http.StatusBadRequest, err.Error()
+	case "invalid current password", "two-factor authentication is required for your role":
+		status, message = http.StatusForbidden, err.Error()
+	case "two-factor authentication is already enabled":
+		status, message = http.StatusConflict, err.Error()
+	default:
+		logger.Error().Err(err).Msg(fallback)
+	}
+
+	c.JSON(status, gin.H{
+		"error": message,
+	})
+}
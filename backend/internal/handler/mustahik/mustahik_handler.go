@@ -0,0 +1,378 @@
+package mustahik
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mustahikDomain "github.com/madr/backend/internal/domain/mustahik"
+	mustahikRepo "github.com/madr/backend/internal/repository/mustahik"
+	mustahikUsecase "github.com/madr/backend/internal/usecase/mustahik"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// Handler handles HTTP requests for mustahik and distributions
+type Handler struct {
+	useCase mustahikUsecase.UseCase
+}
+
+// NewHandler creates a new mustahik handler
+func NewHandler(useCase mustahikUsecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// GetMustahikList handles GET /admin/mustahik
+func (h *Handler) GetMustahikList(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	filter := &mustahikRepo.MustahikFilter{Search: c.Query("search")}
+	if raw := c.Query("asnaf"); raw != "" {
+		asnaf := mustahikDomain.Asnaf(raw)
+		if !asnaf.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid asnaf",
+			})
+			return
+		}
+		filter.Asnaf = &asnaf
+	}
+	if raw := c.Query("status"); raw != "" {
+		status := mustahikDomain.VerificationStatus(raw)
+		if !status.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid status, expected pending, verified or rejected",
+			})
+			return
+		}
+		filter.Status = &status
+	}
+
+	response, err := h.useCase.GetMustahikList(limit, offset, filter)
+	if err != nil {
+		writeError(c, err, "Failed to get mustahik")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetMustahik handles GET /admin/mustahik/:id
+func (h *Handler) GetMustahik(c *gin.Context) {
+	id, ok := parseID(c, "Invalid mustahik ID")
+	if !ok {
+		return
+	}
+
+	m, err := h.useCase.GetMustahik(id)
+	if err != nil {
+		writeError(c, err, "Failed to get mustahik")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": m,
+	})
+}
+
+// CreateMustahik handles POST /admin/mustahik
+func (h *Handler) CreateMustahik(c *gin.Context) {
+	var req mustahikUsecase.MustahikRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	m, err := h.useCase.CreateMustahik(&req)
+	if err != nil {
+		writeError(c, err, "Failed to create mustahik")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Mustahik created successfully",
+		"data":    m,
+	})
+}
+
+// UpdateMustahik handles PUT /admin/mustahik/:id
+func (h *Handler) UpdateMustahik(c *gin.Context) {
+	id, ok := parseID(c, "Invalid mustahik ID")
+	if !ok {
+		return
+	}
+
+	var req mustahikUsecase.MustahikRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	m, err := h.useCase.UpdateMustahik(id, &req)
+	if err != nil {
+		writeError(c, err, "Failed to update mustahik")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Mustahik updated successfully",
+		"data":    m,
+	})
+}
+
+// VerifyMustahik handles PUT /admin/mustahik/:id/verification
+func (h *Handler) VerifyMustahik(c *gin.Context) {
+	id, ok := parseID(c, "Invalid mustahik ID")
+	if !ok {
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	uid, isUint := userID.(uint)
+	if !exists || !isUint {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	var req mustahikUsecase.VerifyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	m, err := h.useCase.VerifyMustahik(id, &req, uid)
+	if err != nil {
+		writeError(c, err, "Failed to verify mustahik")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Mustahik verification updated successfully",
+		"data":    m,
+	})
+}
+
+// DeleteMustahik handles DELETE /admin/mustahik/:id
+func (h *Handler) DeleteMustahik(c *gin.Context) {
+	id, ok := parseID(c, "Invalid mustahik ID")
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.DeleteMustahik(id); err != nil {
+		writeError(c, err, "Failed to delete mustahik")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Mustahik deleted successfully",
+	})
+}
+
+// GetDistributions handles GET /admin/distributions
+func (h *Handler) GetDistributions(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	filter, err := parseDistributionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.useCase.GetDistributions(limit, offset, filter)
+	if err != nil {
+		writeError(c, err, "Failed to get distributions")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetDistribution handles GET /admin/distributions/:id
+func (h *Handler) GetDistribution(c *gin.Context) {
+	id, ok := parseID(c, "Invalid distribution ID")
+	if !ok {
+		return
+	}
+
+	d, err := h.useCase.GetDistribution(id)
+	if err != nil {
+		writeError(c, err, "Failed to get distribution")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": d,
+	})
+}
+
+// CreateDistribution handles POST /admin/distributions
+func (h *Handler) CreateDistribution(c *gin.Context) {
+	var req mustahikUsecase.CreateDistributionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	d, err := h.useCase.CreateDistribution(&req)
+	if err != nil {
+		writeError(c, err, "Failed to create distribution")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Distribution recorded successfully",
+		"data":    d,
+	})
+}
+
+// DeleteDistribution handles DELETE /admin/distributions/:id
+func (h *Handler) DeleteDistribution(c *gin.Context) {
+	id, ok := parseID(c, "Invalid distribution ID")
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.DeleteDistribution(id); err != nil {
+		writeError(c, err, "Failed to delete distribution")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Distribution deleted successfully",
+	})
+}
+
+// GetReport handles GET /admin/distributions/report
+func (h *Handler) GetReport(c *gin.Context) {
+	from, err := parseOptionalDate(c, "from")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+	to, err := parseOptionalDate(c, "to")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	report, err := h.useCase.GetReport(from, to)
+	if err != nil {
+		writeError(c, err, "Failed to get distribution report")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// writeError maps use case errors to HTTP responses
+func writeError(c *gin.Context, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+
+	switch err.Error() {
+	case "mustahik not found", "distribution not found", "donation category not found":
+		status, message = http.StatusNotFound, err.Error()
+	case "mustahik has distributions", "mustahik is not verified", "insufficient category balance":
+		status, message = http.StatusConflict, err.Error()
+	case "invalid distribution date", "invalid date range":
+		status, message = http.StatusBadRequest, err.Error()
+	default:
+		logger.Error().Err(err).Msg(fallback)
+	}
+
+	c.JSON(status, gin.H{
+		"error": message,
+	})
+}
+
+func bindJSON(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		logger.Warn().Err(err).Str("path", c.FullPath()).Msg("Invalid mustahik request body")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func parseID(c *gin.Context, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": message,
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parseOptionalID reads an optional numeric query parameter
+func parseOptionalID(c *gin.Context, name string) (*uint, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q", name, raw)
+	}
+	value := uint(id)
+	return &value, nil
+}
+
+// parseOptionalDate reads an optional YYYY-MM-DD query parameter
+func parseOptionalDate(c *gin.Context, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	date, err := utils.ParseDate(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &date, nil
+}
+
+func parseDistributionFilter(c *gin.Context) (*mustahikRepo.DistributionFilter, error) {
+	filter := &mustahikRepo.DistributionFilter{}
+	var err error
+
+	if filter.MustahikID, err = parseOptionalID(c, "mustahik_id"); err != nil {
+		return nil, err
+	}
+	if filter.CategoryID, err = parseOptionalID(c, "category_id"); err != nil {
+		return nil, err
+	}
+	if raw := c.Query("asnaf"); raw != "" {
+		asnaf := mustahikDomain.Asnaf(raw)
+		if !asnaf.IsValid() {
+			return nil, fmt.Errorf("invalid asnaf %q", raw)
+		}
+		filter.Asnaf = &asnaf
+	}
+	if filter.From, err = parseOptionalDate(c, "from"); err != nil {
+		return nil, err
+	}
+	if filter.To, err = parseOptionalDate(c, "to"); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
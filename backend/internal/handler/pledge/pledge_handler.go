@@ -0,0 +1,216 @@
+package pledge
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	pledgeDomain "github.com/madr/backend/internal/domain/pledge"
+	pledgeRepo "github.com/madr/backend/internal/repository/pledge"
+	pledgeUsecase "github.com/madr/backend/internal/usecase/pledge"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// Handler handles HTTP requests for recurring donation pledges
+type Handler struct {
+	useCase pledgeUsecase.UseCase
+}
+
+// NewHandler creates a new pledge handler
+func NewHandler(useCase pledgeUsecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// GetAll handles GET /admin/pledges
+func (h *Handler) GetAll(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	filter := &pledgeRepo.Filter{Search: c.Query("search")}
+	if raw := c.Query("status"); raw != "" {
+		status := pledgeDomain.Status(raw)
+		if !status.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid status, expected active, paused, cancelled or completed",
+			})
+			return
+		}
+		filter.Status = &status
+	}
+	if raw := c.Query("category_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid category ID",
+			})
+			return
+		}
+		categoryID := uint(id)
+		filter.CategoryID = &categoryID
+	}
+
+	response, err := h.useCase.GetAll(limit, offset, filter)
+	if err != nil {
+		writeError(c, err, "Failed to get pledges")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetByID handles GET /admin/pledges/:id
+func (h *Handler) GetByID(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	p, err := h.useCase.GetByID(id)
+	if err != nil {
+		writeError(c, err, "Failed to get pledge")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": p,
+	})
+}
+
+// GetInstallments handles GET /admin/pledges/:id/installments
+func (h *Handler) GetInstallments(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	installments, err := h.useCase.GetInstallments(id)
+	if err != nil {
+		writeError(c, err, "Failed to get pledge installments")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": installments,
+	})
+}
+
+// Create handles POST /admin/pledges
+func (h *Handler) Create(c *gin.Context) {
+	var req pledgeUsecase.CreateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	p, err := h.useCase.Create(&req)
+	if err != nil {
+		writeError(c, err, "Failed to create pledge")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Pledge created successfully",
+		"data":    p,
+	})
+}
+
+// Update handles PUT /admin/pledges/:id
+func (h *Handler) Update(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req pledgeUsecase.UpdateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	p, err := h.useCase.Update(id, &req)
+	if err != nil {
+		writeError(c, err, "Failed to update pledge")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Pledge updated successfully",
+		"data":    p,
+	})
+}
+
+// Delete handles DELETE /admin/pledges/:id
+func (h *Handler) Delete(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.Delete(id); err != nil {
+		writeError(c, err, "Failed to delete pledge")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Pledge deleted successfully",
+	})
+}
+
+// RunScheduler handles POST /admin/pledges/run, running the scheduler now
+func (h *Handler) RunScheduler(c *gin.Context) {
+	result, err := h.useCase.RunScheduler(time.Now())
+	if err != nil {
+		writeError(c, err, "Failed to run pledge scheduler")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Pledge scheduler finished",
+		"data":    result,
+	})
+}
+
+// writeError maps use case errors to HTTP responses
+func writeError(c *gin.Context, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+
+	switch err.Error() {
+	case "pledge not found", "donation category not found":
+		status, message = http.StatusNotFound, err.Error()
+	case "pledge is closed", "pledge has installments", "pledge schedule cannot change after installments":
+		status, message = http.StatusConflict, err.Error()
+	case "invalid start date", "invalid end date", "end date is before start date":
+		status, message = http.StatusBadRequest, err.Error()
+	default:
+		logger.Error().Err(err).Msg(fallback)
+	}
+
+	c.JSON(status, gin.H{
+		"error": message,
+	})
+}
+
+func bindJSON(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		logger.Warn().Err(err).Str("path", c.FullPath()).Msg("Invalid pledge request body")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid pledge ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
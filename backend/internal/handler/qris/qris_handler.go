@@ -0,0 +1,144 @@
+package qris
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/madr/backend/internal/middleware"
+	qrisUsecase "github.com/madr/backend/internal/usecase/qris"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/qris"
+)
+
+const (
+	defaultImageSize = 320
+	maxImageSize     = 1024
+)
+
+// Handler handles HTTP requests for QRIS donations
+type Handler struct {
+	useCase qrisUsecase.UseCase
+}
+
+// NewHandler creates a new QRIS handler
+func NewHandler(useCase qrisUsecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// Generate handles POST /donations/qris (Public endpoint)
+func (h *Handler) Generate(c *gin.Context) {
+	var req qrisUsecase.GenerateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn().Err(err).Msg("Invalid QRIS request body")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if userID, err := middleware.GetUserIDFromContext(c); err == nil {
+		req.UserID = &userID
+	}
+
+	response, err := h.useCase.Generate(&req)
+	if err != nil {
+		switch err.Error() {
+		case "donation category not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Donation category not found",
+			})
+		case "QRIS is not configured":
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "QRIS is not available",
+			})
+		case "campaign is closed", "campaign has not started":
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to generate QRIS",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "QRIS generated successfully",
+		"data": gin.H{
+			"donation":  response.Donation,
+			"reference": response.Reference,
+			"qris":      response.Payload,
+			"image_url": "/api/v1/donations/qris/" + response.Reference + "?format=png",
+		},
+	})
+}
+
+// GetImage handles GET /donations/qris/:reference?format=png|svg&size=320 (Public endpoint)
+func (h *Handler) GetImage(c *gin.Context) {
+	size, err := strconv.Atoi(c.DefaultQuery("size", strconv.Itoa(defaultImageSize)))
+	if err != nil || size <= 0 || size > maxImageSize {
+		size = defaultImageSize
+	}
+
+	format := c.DefaultQuery("format", "png")
+	if format != "png" && format != "svg" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Format must be either 'png' or 'svg'",
+		})
+		return
+	}
+
+	payload, err := h.useCase.GetPayload(c.Param("reference"))
+	if err != nil {
+		switch err.Error() {
+		case "donation not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Donation not found",
+			})
+		case "donation is no longer pending":
+			c.JSON(http.StatusGone, gin.H{
+				"error": "Donation is no longer pending",
+			})
+		case "QRIS is not configured":
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "QRIS is not available",
+			})
+		case "campaign is closed", "campaign has not started":
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to generate QRIS",
+			})
+		}
+		return
+	}
+
+	var (
+		image       []byte
+		contentType string
+	)
+	if format == "svg" {
+		image, err = qris.SVG(payload, size)
+		contentType = "image/svg+xml"
+	} else {
+		image, err = qris.PNG(payload, size)
+		contentType = "image/png"
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to render QRIS image")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to render QRIS",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, contentType, image)
+}
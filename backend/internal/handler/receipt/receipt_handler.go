@@ -0,0 +1,80 @@
+package receipt
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	receiptUsecase "github.com/madr/backend/internal/usecase/receipt"
+)
+
+// Handler handles HTTP requests for donation receipts
+type Handler struct {
+	useCase receiptUsecase.UseCase
+}
+
+// NewHandler creates a new donation receipt handler
+func NewHandler(useCase receiptUsecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// Download handles GET /donations/:id/receipt?reference=DON-... (Public endpoint).
+// The payment reference proves the caller owns the donation.
+func (h *Handler) Download(c *gin.Context) {
+	id, ok := parseDonationID(c)
+	if !ok {
+		return
+	}
+
+	file, err := h.useCase.GetPDFByReference(id, c.Query("reference"))
+	h.respond(c, file, err)
+}
+
+// AdminDownload handles GET /admin/donations/:id/receipt (Admin only)
+func (h *Handler) AdminDownload(c *gin.Context) {
+	id, ok := parseDonationID(c)
+	if !ok {
+		return
+	}
+
+	file, err := h.useCase.GetPDF(id)
+	h.respond(c, file, err)
+}
+
+func (h *Handler) respond(c *gin.Context, file *receiptUsecase.File, err error) {
+	if err != nil {
+		switch err.Error() {
+		case "donation not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Donation not found",
+			})
+		case "donation is not paid":
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Receipt is only available for successful donations",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to get receipt",
+			})
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="kwitansi-%s.pdf"`, file.Receipt.Number))
+	c.Header("X-Receipt-Number", file.Receipt.Number)
+	c.Data(http.StatusOK, "application/pdf", file.Content)
+}
+
+func parseDonationID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid donation ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
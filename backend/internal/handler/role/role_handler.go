@@ -0,0 +1,219 @@
+package role
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/madr/backend/internal/middleware"
+	roleUsecase "github.com/madr/backend/internal/usecase/role"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// Handler handles HTTP requests for roles, permissions and role assignments
+type Handler struct {
+	useCase roleUsecase.UseCase
+}
+
+// NewHandler creates a new role handler
+func NewHandler(useCase roleUsecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// GetPermissions handles GET /admin/permissions
+func (h *Handler) GetPermissions(c *gin.Context) {
+	permissions, err := h.useCase.GetPermissions()
+	if err != nil {
+		writeError(c, err, "Failed to get permissions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": permissions,
+	})
+}
+
+// GetAll handles GET /admin/roles
+func (h *Handler) GetAll(c *gin.Context) {
+	roles, err := h.useCase.GetAll()
+	if err != nil {
+		writeError(c, err, "Failed to get roles")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": roles,
+	})
+}
+
+// GetByID handles GET /admin/roles/:id
+func (h *Handler) GetByID(c *gin.Context) {
+	id, ok := parseID(c, "Invalid role ID")
+	if !ok {
+		return
+	}
+
+	role, err := h.useCase.GetByID(id)
+	if err != nil {
+		writeError(c, err, "Failed to get role")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": role,
+	})
+}
+
+// Create handles POST /admin/roles
+func (h *Handler) Create(c *gin.Context) {
+	var req roleUsecase.CreateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	actorID, _ := middleware.GetUserIDFromContext(c)
+	role, err := h.useCase.Create(&req, actorID)
+	if err != nil {
+		writeError(c, err, "Failed to create role")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Role created successfully",
+		"data":    role,
+	})
+}
+
+// Update handles PUT /admin/roles/:id
+func (h *Handler) Update(c *gin.Context) {
+	id, ok := parseID(c, "Invalid role ID")
+	if !ok {
+		return
+	}
+
+	var req roleUsecase.UpdateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	actorID, _ := middleware.GetUserIDFromContext(c)
+	role, err := h.useCase.Update(id, &req, actorID)
+	if err != nil {
+		writeError(c, err, "Failed to update role")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Role updated successfully",
+		"data":    role,
+	})
+}
+
+// Delete handles DELETE /admin/roles/:id
+func (h *Handler) Delete(c *gin.Context) {
+	id, ok := parseID(c, "Invalid role ID")
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.Delete(id); err != nil {
+		writeError(c, err, "Failed to delete role")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Role deleted successfully",
+	})
+}
+
+// GetUserRoles handles GET /admin/users/:id/roles
+func (h *Handler) GetUserRoles(c *gin.Context) {
+	userID, ok := parseID(c, "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	roles, err := h.useCase.GetUserRoles(userID)
+	if err != nil {
+		writeError(c, err, "Failed to get user roles")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": roles,
+	})
+}
+
+// SetUserRoles handles PUT /admin/users/:id/roles
+func (h *Handler) SetUserRoles(c *gin.Context) {
+	userID, ok := parseID(c, "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	var req roleUsecase.AssignRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	actorID, _ := middleware.GetUserIDFromContext(c)
+	roles, err := h.useCase.SetUserRoles(userID, &req, actorID)
+	if err != nil {
+		writeError(c, err, "Failed to assign roles")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User roles updated successfully",
+		"data":    roles,
+	})
+}
+
+// writeError maps use case errors to HTTP responses
+func writeError(c *gin.Context, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+
+	switch err.Error() {
+	case "role not found", "user not found":
+		status, message = http.StatusNotFound, err.Error()
+	case "role name already exists", "system role cannot be renamed", "system role cannot be deleted",
+		"admin role permissions cannot be changed", "cannot remove the last administrator":
+		status, message = http.StatusConflict, err.Error()
+	case "unknown permission":
+		status, message = http.StatusBadRequest, err.Error()
+	case "cannot grant permissions you do not have", "only administrators can assign the admin role":
+		status, message = http.StatusForbidden, err.Error()
+	default:
+		logger.Error().Err(err).Msg(fallback)
+	}
+
+	c.JSON(status, gin.H{
+		"error": message,
+	})
+}
+
+func bindJSON(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		logger.Warn().Err(err).Str("path", c.FullPath()).Msg("Invalid role request body")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func parseID(c *gin.Context, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": message,
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,102 @@
+package session
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/madr/backend/internal/middleware"
+	sessionUsecase "github.com/madr/backend/internal/usecase/session"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// Handler handles HTTP requests for login sessions
+type Handler struct {
+	useCase sessionUsecase.UseCase
+}
+
+// NewHandler creates a new session handler
+func NewHandler(useCase sessionUsecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// List handles GET /auth/sessions
+func (h *Handler) List(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	sessions, err := h.useCase.List(userID, middleware.GetSessionIDFromContext(c))
+	if err != nil {
+		writeError(c, err, "Failed to get sessions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": sessions,
+	})
+}
+
+// Revoke handles DELETE /auth/sessions/:id
+func (h *Handler) Revoke(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	if err := h.useCase.Revoke(userID, c.Param("id")); err != nil {
+		writeError(c, err, "Failed to revoke session")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Session revoked successfully",
+	})
+}
+
+// GetByUser handles GET /admin/users/:id/sessions
+func (h *Handler) GetByUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return
+	}
+
+	sessions, err := h.useCase.List(uint(id), "")
+	if err != nil {
+		writeError(c, err, "Failed to get sessions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": sessions,
+	})
+}
+
+// writeError maps use case errors to HTTP responses
+func writeError(c *gin.Context, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+
+	switch err.Error() {
+	case "user not found", "session not found":
+		status, message = http.StatusNotFound, err.Error()
+	default:
+		logger.Error().Err(err).Msg(fallback)
+	}
+
+	c.JSON(status, gin.H{
+		"error": message,
+	})
+}
@@ -0,0 +1,263 @@
+package user
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/madr/backend/internal/middleware"
+	userRepo "github.com/madr/backend/internal/repository/user"
+	userUsecase "github.com/madr/backend/internal/usecase/user"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/password"
+)
+
+// Handler handles HTTP requests for admin user management
+type Handler struct {
+	useCase userUsecase.UseCase
+}
+
+// NewHandler creates a new user handler
+func NewHandler(useCase userUsecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// GetAll handles GET /admin/users
+func (h *Handler) GetAll(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	filter := &userRepo.Filter{Search: c.Query("search")}
+	if raw := c.Query("is_active"); raw != "" {
+		active, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid is_active, expected true or false",
+			})
+			return
+		}
+		filter.IsActive = &active
+	}
+	if raw := c.Query("role_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid role_id",
+			})
+			return
+		}
+		roleID := uint(id)
+		filter.RoleID = &roleID
+	}
+
+	response, err := h.useCase.GetAll(limit, offset, filter)
+	if err != nil {
+		writeError(c, err, "Failed to get users")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetByID handles GET /admin/users/:id
+func (h *Handler) GetByID(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	usr, err := h.useCase.GetByID(id)
+	if err != nil {
+		writeError(c, err, "Failed to get user")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": usr,
+	})
+}
+
+// Create handles POST /admin/users
+func (h *Handler) Create(c *gin.Context) {
+	var req userUsecase.CreateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	actorID, _ := middleware.GetUserIDFromContext(c)
+	usr, err := h.useCase.Create(&req, actorID)
+	if err != nil {
+		writeError(c, err, "Failed to create user")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "User created successfully",
+		"data":    usr,
+	})
+}
+
+// Update handles PUT /admin/users/:id
+func (h *Handler) Update(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req userUsecase.UpdateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	actorID, _ := middleware.GetUserIDFromContext(c)
+	usr, err := h.useCase.Update(id, &req, actorID)
+	if err != nil {
+		writeError(c, err, "Failed to update user")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User updated successfully",
+		"data":    usr,
+	})
+}
+
+// SetStatus handles PUT /admin/users/:id/status
+func (h *Handler) SetStatus(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req userUsecase.StatusRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	actorID, _ := middleware.GetUserIDFromContext(c)
+	usr, err := h.useCase.SetActive(id, actorID, &req)
+	if err != nil {
+		writeError(c, err, "Failed to update user status")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User status updated successfully",
+		"data":    usr,
+	})
+}
+
+// ResetPassword handles POST /admin/users/:id/reset-password
+func (h *Handler) ResetPassword(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req userUsecase.ResetPasswordRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	actorID, _ := middleware.GetUserIDFromContext(c)
+	response, err := h.useCase.ResetPassword(id, actorID, &req)
+	if err != nil {
+		writeError(c, err, "Failed to reset password")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password reset successfully",
+		"data":    response,
+	})
+}
+
+// Unlock handles POST /admin/users/:id/unlock
+func (h *Handler) Unlock(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	actorID, _ := middleware.GetUserIDFromContext(c)
+	if err := h.useCase.Unlock(id, actorID); err != nil {
+		writeError(c, err, "Failed to unlock user")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User unlocked successfully",
+	})
+}
+
+// Delete handles DELETE /admin/users/:id
+func (h *Handler) Delete(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	actorID, _ := middleware.GetUserIDFromContext(c)
+	if err := h.useCase.Delete(id, actorID); err != nil {
+		writeError(c, err, "Failed to delete user")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User deleted successfully",
+	})
+}
+
+// writeError maps use case errors to HTTP responses
+func writeError(c *gin.Context, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+
+	if password.IsPolicyError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	switch err.Error() {
+	case "user not found", "role not found":
+		status, message = http.StatusNotFound, err.Error()
+	case "username already exists", "email already exists", "cannot remove the last administrator",
+		"cannot deactivate your own account", "cannot delete your own account":
+		status, message = http.StatusConflict, err.Error()
+	case "cannot grant permissions you do not have", "only administrators can assign the admin role":
+		status, message = http.StatusForbidden, err.Error()
+	default:
+		logger.Error().Err(err).Msg(fallback)
+	}
+
+	c.JSON(status, gin.H{
+		"error": message,
+	})
+}
+
+func bindJSON(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		logger.Warn().Err(err).Str("path", c.FullPath()).Msg("Invalid user request body")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
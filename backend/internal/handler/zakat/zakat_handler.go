@@ -0,0 +1,373 @@
+package zakat
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	zakatDomain "github.com/madr/backend/internal/domain/zakat"
+	zakatRepo "github.com/madr/backend/internal/repository/zakat"
+	zakatUsecase "github.com/madr/backend/internal/usecase/zakat"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// Handler handles HTTP requests for zakat
+type Handler struct {
+	useCase zakatUsecase.UseCase
+}
+
+// NewHandler creates a new zakat handler
+func NewHandler(useCase zakatUsecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// GetRates handles GET /zakat/rates (Public endpoint)
+func (h *Handler) GetRates(c *gin.Context) {
+	rates, err := h.useCase.GetRates()
+	if err != nil {
+		writeError(c, err, "Failed to get zakat rates")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": rates,
+	})
+}
+
+// UpdateSetting handles PUT /admin/zakat/settings
+func (h *Handler) UpdateSetting(c *gin.Context) {
+	var req zakatUsecase.UpdateSettingRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rates, err := h.useCase.UpdateSetting(&req)
+	if err != nil {
+		writeError(c, err, "Failed to update zakat settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Zakat settings updated successfully",
+		"data":    rates,
+	})
+}
+
+// CalculateMal handles POST /zakat/calculator/mal (Public endpoint)
+func (h *Handler) CalculateMal(c *gin.Context) {
+	var req zakatUsecase.MalRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.useCase.CalculateMal(&req)
+	if err != nil {
+		writeError(c, err, "Failed to calculate zakat mal")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
+
+// CalculateFitrah handles GET /zakat/calculator/fitrah (Public endpoint)
+func (h *Handler) CalculateFitrah(c *gin.Context) {
+	persons, err := strconv.Atoi(c.DefaultQuery("persons", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid number of persons",
+		})
+		return
+	}
+
+	result, err := h.useCase.CalculateFitrah(persons)
+	if err != nil {
+		writeError(c, err, "Failed to calculate zakat fitrah")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
+
+// GetMuzakkiList handles GET /admin/zakat/muzakki
+func (h *Handler) GetMuzakkiList(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	response, err := h.useCase.GetMuzakkiList(limit, offset, c.Query("search"))
+	if err != nil {
+		writeError(c, err, "Failed to get muzakki")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetMuzakki handles GET /admin/zakat/muzakki/:id
+func (h *Handler) GetMuzakki(c *gin.Context) {
+	id, ok := parseID(c, "Invalid muzakki ID")
+	if !ok {
+		return
+	}
+
+	m, err := h.useCase.GetMuzakki(id)
+	if err != nil {
+		writeError(c, err, "Failed to get muzakki")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": m,
+	})
+}
+
+// CreateMuzakki handles POST /admin/zakat/muzakki
+func (h *Handler) CreateMuzakki(c *gin.Context) {
+	var req zakatUsecase.MuzakkiRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	m, err := h.useCase.CreateMuzakki(&req)
+	if err != nil {
+		writeError(c, err, "Failed to create muzakki")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Muzakki created successfully",
+		"data":    m,
+	})
+}
+
+// UpdateMuzakki handles PUT /admin/zakat/muzakki/:id
+func (h *Handler) UpdateMuzakki(c *gin.Context) {
+	id, ok := parseID(c, "Invalid muzakki ID")
+	if !ok {
+		return
+	}
+
+	var req zakatUsecase.MuzakkiRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	m, err := h.useCase.UpdateMuzakki(id, &req)
+	if err != nil {
+		writeError(c, err, "Failed to update muzakki")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Muzakki updated successfully",
+		"data":    m,
+	})
+}
+
+// DeleteMuzakki handles DELETE /admin/zakat/muzakki/:id
+func (h *Handler) DeleteMuzakki(c *gin.Context) {
+	id, ok := parseID(c, "Invalid muzakki ID")
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.DeleteMuzakki(id); err != nil {
+		writeError(c, err, "Failed to delete muzakki")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Muzakki deleted successfully",
+	})
+}
+
+// GetPayments handles GET /admin/zakat/payments
+func (h *Handler) GetPayments(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	filter, err := parsePaymentFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.useCase.GetPayments(limit, offset, filter)
+	if err != nil {
+		writeError(c, err, "Failed to get zakat payments")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RecordPayment handles POST /admin/zakat/payments
+func (h *Handler) RecordPayment(c *gin.Context) {
+	var req zakatUsecase.RecordPaymentRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	payment, err := h.useCase.RecordPayment(&req)
+	if err != nil {
+		writeError(c, err, "Failed to record zakat payment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Zakat payment recorded successfully",
+		"data":    payment,
+	})
+}
+
+// DeletePayment handles DELETE /admin/zakat/payments/:id
+func (h *Handler) DeletePayment(c *gin.Context) {
+	id, ok := parseID(c, "Invalid payment ID")
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.DeletePayment(id); err != nil {
+		writeError(c, err, "Failed to delete zakat payment")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Zakat payment deleted successfully",
+	})
+}
+
+// GetReport handles GET /admin/zakat/report
+func (h *Handler) GetReport(c *gin.Context) {
+	from, err := parseOptionalDate(c, "from")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+	to, err := parseOptionalDate(c, "to")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	report, err := h.useCase.GetReport(from, to)
+	if err != nil {
+		writeError(c, err, "Failed to get zakat report")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// writeError maps use case errors to HTTP responses
+func writeError(c *gin.Context, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+
+	switch err.Error() {
+	case "muzakki not found", "zakat payment not found", "donation category not found":
+		status, message = http.StatusNotFound, err.Error()
+	case "muzakki has payments", "zakat category not configured":
+		status, message = http.StatusConflict, err.Error()
+	case "invalid number of persons", "invalid payment date", "invalid date range",
+		"zakat mal must be paid in cash", "amount is required for zakat mal",
+		"payment is below the fitrah rate", "donation category does not match zakat type":
+		status, message = http.StatusBadRequest, err.Error()
+	default:
+		logger.Error().Err(err).Msg(fallback)
+	}
+
+	c.JSON(status, gin.H{
+		"error": message,
+	})
+}
+
+func bindJSON(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		logger.Warn().Err(err).Str("path", c.FullPath()).Msg("Invalid zakat request body")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func parseID(c *gin.Context, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": message,
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parseOptionalDate reads an optional YYYY-MM-DD query parameter
+func parseOptionalDate(c *gin.Context, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	date, err := utils.ParseDate(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &date, nil
+}
+
+func parsePaymentFilter(c *gin.Context) (*zakatRepo.PaymentFilter, error) {
+	filter := &zakatRepo.PaymentFilter{}
+	var err error
+
+	if raw := c.Query("muzakki_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid muzakki_id %q", raw)
+		}
+		muzakkiID := uint(id)
+		filter.MuzakkiID = &muzakkiID
+	}
+	if raw := c.Query("zakat_type"); raw != "" {
+		zakatType := zakatDomain.Type(raw)
+		if !zakatType.IsValid() {
+			return nil, fmt.Errorf("invalid zakat_type %q", raw)
+		}
+		filter.ZakatType = &zakatType
+	}
+	if raw := c.Query("hijri_year"); raw != "" {
+		year, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hijri_year %q", raw)
+		}
+		filter.HijriYear = &year
+	}
+	if filter.From, err = parseOptionalDate(c, "from"); err != nil {
+		return nil, err
+	}
+	if filter.To, err = parseOptionalDate(c, "to"); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
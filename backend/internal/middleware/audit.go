@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	auditDomain "github.com/madr/backend/internal/domain/audit"
+	"github.com/madr/backend/internal/domain/models"
+)
+
+// maxAuditBody caps how much of a response is kept to find the created entity
+const maxAuditBody = 64 << 10
+
+// Auditor stores audit log entries and snapshots the audited entities
+type Auditor interface {
+	Snapshot(entityType string, id uint) models.JSON
+	Record(entry *auditDomain.Log)
+}
+
+// AuditMiddleware records every mutating request of the group it guards:
+// who made it, the route, the entity it targeted, the outcome and the
+// entity before and after. It must run after AuthMiddleware.
+func AuditMiddleware(auditor Auditor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		entry := &auditDomain.Log{
+			IP:     c.ClientIP(),
+			Method: c.Request.Method,
+			Route:  c.FullPath(),
+			Path:   c.Request.URL.Path,
+		}
+		entry.EntityType, entry.Action = AuditTarget(entry.Route, entry.Method)
+		if id, err := strconv.ParseUint(c.Param("id"), 10, 32); err == nil {
+			entityID := uint(id)
+			entry.EntityID = &entityID
+		}
+
+		if entry.Action != auditDomain.ActionCreate {
+			entry.Before = auditor.Snapshot(entry.EntityType, entityIDOrZero(entry.EntityID))
+		}
+
+		recorder := &auditBodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if userID, err := GetUserIDFromContext(c); err == nil {
+			entry.ActorID = &userID
+		}
+		entry.StatusCode = c.Writer.Status()
+
+		var response struct {
+			Data  json.RawMessage `json:"data"`
+			Error string          `json:"error"`
+		}
+		_ = json.Unmarshal(recorder.body.Bytes(), &response)
+
+		if entry.StatusCode >= http.StatusBadRequest {
+			entry.Outcome = auditDomain.OutcomeFailure
+			if response.Error != "" {
+				entry.Error = &response.Error
+			}
+			auditor.Record(entry)
+			return
+		}
+
+		entry.Outcome = auditDomain.OutcomeSuccess
+		if entry.EntityID == nil {
+			// Created entities are only known from the response
+			var data struct {
+				ID *uint `json:"id"`
+			}
+			if json.Unmarshal(response.Data, &data) == nil {
+				entry.EntityID = data.ID
+			}
+		}
+		if entry.Action != auditDomain.ActionDelete {
+			entry.After = auditor.Snapshot(entry.EntityType, entityIDOrZero(entry.EntityID))
+			if entry.After == nil && len(response.Data) > 0 && string(response.Data) != "null" {
+				entry.After = models.JSON(response.Data)
+			}
+		}
+		auditor.Record(entry)
+	}
+}
+
+// AuditTarget derives the entity type and action of an admin route. The
+// entity type is made of the segments after /admin up to the first parameter,
+// e.g. ledger.entries for /admin/ledger/entries/:id. Segments after the
+// parameter name the action, e.g. verification for /admin/mustahik/:id/verification;
+// otherwise the action follows the HTTP method.
+func AuditTarget(route, method string) (entityType, action string) {
+	if i := strings.Index(route, "/admin/"); i >= 0 {
+		route = route[i+len("/admin/"):]
+	}
+
+	var entity, rest []string
+	for _, segment := range strings.Split(strings.Trim(route, "/"), "/") {
+		switch {
+		case strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*"):
+			rest = []string{}
+		case rest != nil:
+			rest = append(rest, segment)
+		default:
+			entity = append(entity, segment)
+		}
+	}
+
+	entityType = strings.Join(entity, ".")
+	if len(rest) > 0 {
+		return entityType, strings.Join(rest, ".")
+	}
+
+	switch method {
+	case http.MethodPost:
+		return entityType, auditDomain.ActionCreate
+	case http.MethodDelete:
+		return entityType, auditDomain.ActionDelete
+	default:
+		return entityType, auditDomain.ActionUpdate
+	}
+}
+
+func entityIDOrZero(id *uint) uint {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+// auditBodyRecorder keeps the start of the response body while writing it
+type auditBodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditBodyRecorder) Write(b []byte) (int, error) {
+	w.keep(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *auditBodyRecorder) WriteString(s string) (int, error) {
+	w.keep([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *auditBodyRecorder) keep(b []byte) {
+	if room := maxAuditBody - w.body.Len(); room > 0 {
+		if len(b) > room {
+			b = b[:room]
+		}
+		w.body.Write(b)
+	}
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	auditDomain "github.com/madr/backend/internal/domain/audit"
+	"github.com/madr/backend/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuditor keeps entries in memory and snapshots from a map keyed by ID
+type fakeAuditor struct {
+	state   map[uint]string
+	entries []*auditDomain.Log
+}
+
+func (a *fakeAuditor) Snapshot(entityType string, id uint) models.JSON {
+	if doc, ok := a.state[id]; ok && entityType == "events" {
+		return models.JSON(doc)
+	}
+	return nil
+}
+
+func (a *fakeAuditor) Record(entry *auditDomain.Log) {
+	a.entries = append(a.entries, entry)
+}
+
+func newAuditedRouter(auditor *fakeAuditor) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	admin := r.Group("/api/v1/admin", func(c *gin.Context) {
+		c.Set("user_id", uint(3))
+	}, AuditMiddleware(auditor))
+
+	admin.GET("/events", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": []string{}})
+	})
+	admin.POST("/events", func(c *gin.Context) {
+		auditor.state[9] = `{"id":9,"title":"Kajian"}`
+		c.JSON(http.StatusCreated, gin.H{"data": gin.H{"id": 9, "title": "Kajian"}})
+	})
+	admin.PUT("/events/:id", func(c *gin.Context) {
+		auditor.state[9] = `{"id":9,"title":"Kajian Ahad"}`
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"id": 9}})
+	})
+	admin.DELETE("/events/:id", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+	})
+	admin.PUT("/mustahik/:id/verification", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"id": 4, "verification_status": "verified"}})
+	})
+	return r
+}
+
+// TestAuditMiddleware tests the entries recorded for reads, creates, updates,
+// failed requests and routes without a snapshot hook
+func TestAuditMiddleware(t *testing.T) {
+	auditor := &fakeAuditor{state: map[uint]string{}}
+	r := newAuditedRouter(auditor)
+
+	serve := func(method, path string) {
+		req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+		req.RemoteAddr = "10.0.0.5:1234"
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	serve(http.MethodGet, "/api/v1/admin/events")
+	assert.Empty(t, auditor.entries, "reads are not audited")
+
+	serve(http.MethodPost, "/api/v1/admin/events")
+	serve(http.MethodPut, "/api/v1/admin/events/9")
+	serve(http.MethodDelete, "/api/v1/admin/events/10")
+	serve(http.MethodPut, "/api/v1/admin/mustahik/4/verification")
+	require.Len(t, auditor.entries, 4)
+
+	created := auditor.entries[0]
+	assert.Equal(t, "events", created.EntityType)
+	assert.Equal(t, auditDomain.ActionCreate, created.Action)
+	require.NotNil(t, created.EntityID, "created ID is read from the response")
+	assert.Equal(t, uint(9), *created.EntityID)
+	assert.Equal(t, uint(3), *created.ActorID)
+	assert.Equal(t, "10.0.0.5", created.IP)
+	assert.Equal(t, "/api/v1/admin/events", created.Route)
+	assert.Equal(t, auditDomain.OutcomeSuccess, created.Outcome)
+	assert.Nil(t, created.Before)
+	assert.JSONEq(t, `{"id":9,"title":"Kajian"}`, string(created.After))
+
+	updated := auditor.entries[1]
+	assert.Equal(t, auditDomain.ActionUpdate, updated.Action)
+	assert.Equal(t, "/api/v1/admin/events/:id", updated.Route)
+	assert.Equal(t, "/api/v1/admin/events/9", updated.Path)
+	assert.JSONEq(t, `{"id":9,"title":"Kajian"}`, string(updated.Before))
+	assert.JSONEq(t, `{"id":9,"title":"Kajian Ahad"}`, string(updated.After))
+
+	failed := auditor.entries[2]
+	assert.Equal(t, auditDomain.ActionDelete, failed.Action)
+	assert.Equal(t, http.StatusNotFound, failed.StatusCode)
+	assert.Equal(t, auditDomain.OutcomeFailure, failed.Outcome)
+	require.NotNil(t, failed.Error)
+	assert.Equal(t, "Event not found", *failed.Error)
+	assert.Nil(t, failed.After)
+
+	verified := auditor.entries[3]
+	assert.Equal(t, "mustahik", verified.EntityType)
+	assert.Equal(t, "verification", verified.Action)
+	assert.Nil(t, verified.Before)
+	assert.JSONEq(t, `{"id":4,"verification_status":"verified"}`, string(verified.After), "response data is kept without a hook")
+}
+
+// TestAuditTarget tests deriving the entity type and action from routes
+func TestAuditTarget(t *testing.T) {
+	cases := []struct {
+		route, method, entity, action string
+	}{
+		{"/api/v1/admin/announcements", http.MethodPost, "announcements", "create"},
+		{"/api/v1/admin/ledger/entries/:id", http.MethodPut, "ledger.entries", "update"},
+		{"/api/v1/admin/zakat/muzakki/:id", http.MethodDelete, "zakat.muzakki", "delete"},
+		{"/api/v1/admin/donations/:id/sync-payment", http.MethodPost, "donations", "sync-payment"},
+		{"/api/v1/admin/about", http.MethodPut, "about", "update"},
+	}
+	for _, tc := range cases {
+		entity, action := AuditTarget(tc.route, tc.method)
+		assert.Equal(t, tc.entity, entity, tc.route)
+		assert.Equal(t, tc.action, action, tc.route)
+	}
+}
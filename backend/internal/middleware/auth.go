@@ -53,12 +53,56 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("role_ids", claims.RoleIDs)
+		c.Set("session_id", claims.SessionID)
 
 		// Continue to next handler
 		c.Next()
 	}
 }
 
+// OptionalAuthMiddleware sets the user context when a valid token is sent and
+// lets anonymous requests through. An invalid token is still rejected so
+// clients notice they need to refresh it.
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.Next()
+			return
+		}
+
+		tokenString, err := jwt.ExtractTokenFromHeader(authHeader)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		claims, err := jwt.ValidateToken(tokenString)
+		if err != nil {
+			message := "Invalid token"
+			if err == jwt.ErrExpiredToken {
+				message = "Token is expired"
+			}
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": message,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", claims.UserID)
+		c.Set("username", claims.Username)
+		c.Set("role", claims.Role)
+		c.Set("role_ids", claims.RoleIDs)
+		c.Set("session_id", claims.SessionID)
+		c.Next()
+	}
+}
+
 // RoleMiddleware checks if user has required role
 func RoleMiddleware(requiredRole string) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -99,6 +143,12 @@ func RoleMiddleware(requiredRole string) gin.HandlerFunc {
 	}
 }
 
+// GetSessionIDFromContext returns the session the access token was issued
+// for, or an empty string for tokens issued before sessions were tracked
+func GetSessionIDFromContext(c *gin.Context) string {
+	return c.GetString("session_id")
+}
+
 // GetUserIDFromContext extracts user ID from context
 func GetUserIDFromContext(c *gin.Context) (uint, error) {
 	userID, exists := c.Get("user_id")
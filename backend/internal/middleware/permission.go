@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// PermissionChecker resolves whether a set of roles grants a permission
+type PermissionChecker interface {
+	HasPermission(roleIDs []uint, permission string) (bool, error)
+}
+
+// PermissionMiddleware allows the request only when one of the user's roles
+// grants the permission. It must run after AuthMiddleware.
+func PermissionMiddleware(checker PermissionChecker, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get("user_id"); !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Unauthorized",
+			})
+			c.Abort()
+			return
+		}
+
+		allowed, err := checker.HasPermission(GetRoleIDsFromContext(c), permission)
+		if err != nil {
+			logger.Error().Err(err).Str("permission", permission).Msg("Failed to check permission")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to check permissions",
+			})
+			c.Abort()
+			return
+		}
+
+		if !allowed {
+			logger.Warn().
+				Str("permission", permission).
+				Interface("user_id", c.Value("user_id")).
+				Msg("Access denied: insufficient permissions")
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Insufficient permissions",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetRoleIDsFromContext extracts the RBAC role IDs set by AuthMiddleware
+func GetRoleIDsFromContext(c *gin.Context) []uint {
+	roleIDs, _ := c.Value("role_ids").([]uint)
+	return roleIDs
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeChecker grants permissions from a map keyed by role ID
+type fakeChecker struct {
+	grants map[uint][]string
+	err    error
+}
+
+func (f *fakeChecker) HasPermission(roleIDs []uint, permission string) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	for _, id := range roleIDs {
+		for _, code := range f.grants[id] {
+			if code == permission {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// TestPermissionMiddleware tests the statuses for granted, missing and failed checks
+func TestPermissionMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		roleIDs []uint
+		err     error
+		want    int
+	}{
+		{name: "granted", roleIDs: []uint{2}, want: http.StatusOK},
+		{name: "granted by any role", roleIDs: []uint{1, 2}, want: http.StatusOK},
+		{name: "missing permission", roleIDs: []uint{1}, want: http.StatusForbidden},
+		{name: "no roles", want: http.StatusForbidden},
+		{name: "checker failure", roleIDs: []uint{2}, err: errors.New("db down"), want: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &fakeChecker{
+				grants: map[uint][]string{1: {"event:write"}, 2: {"donation:write"}},
+				err:    tt.err,
+			}
+			r := gin.New()
+			r.POST("/donations", func(c *gin.Context) {
+				c.Set("user_id", uint(3))
+				c.Set("role_ids", tt.roleIDs)
+			}, PermissionMiddleware(checker, "donation:write"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/donations", nil))
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
+// TestPermissionMiddleware_Unauthenticated tests requests without a user
+func TestPermissionMiddleware_Unauthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/roles", PermissionMiddleware(&fakeChecker{}, "role:read"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/roles", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"time"
+
+	auditDomain "github.com/madr/backend/internal/domain/audit"
+	"github.com/madr/backend/pkg/database"
+	"gorm.io/gorm"
+)
+
+// Repository defines the interface for audit log repository
+type Repository interface {
+	Create(entry *auditDomain.Log) error
+	GetAll(limit, offset int, filter *Filter) ([]auditDomain.Log, int64, error)
+}
+
+// Filter narrows audit log queries. From is inclusive and To is exclusive.
+type Filter struct {
+	ActorID    *uint
+	EntityType string
+	EntityID   *uint
+	Action     string
+	Outcome    *auditDomain.Outcome
+	From       *time.Time
+	To         *time.Time
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new audit log repository
+func NewRepository() Repository {
+	return &repository{
+		db: database.GetDB(),
+	}
+}
+
+// Create appends an audit log entry
+func (r *repository) Create(entry *auditDomain.Log) error {
+	return r.db.Create(entry).Error
+}
+
+// GetAll retrieves audit log entries with the actor name, latest first
+func (r *repository) GetAll(limit, offset int, filter *Filter) ([]auditDomain.Log, int64, error) {
+	var logs []auditDomain.Log
+	var total int64
+
+	query := filter.apply(r.db.Model(&auditDomain.Log{}))
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Select("audit_logs.*, users.name AS actor_name").
+		Joins("LEFT JOIN users ON audit_logs.actor_id = users.id").
+		Order("audit_logs.created_at DESC, audit_logs.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
+
+// apply adds the filter conditions to an audit logs query
+func (f *Filter) apply(query *gorm.DB) *gorm.DB {
+	if f == nil {
+		return query
+	}
+	if f.ActorID != nil {
+		query = query.Where("audit_logs.actor_id = ?", *f.ActorID)
+	}
+	if f.EntityType != "" {
+		query = query.Where("audit_logs.entity_type = ?", f.EntityType)
+	}
+	if f.EntityID != nil {
+		query = query.Where("audit_logs.entity_id = ?", *f.EntityID)
+	}
+	if f.Action != "" {
+		query = query.Where("audit_logs.action = ?", f.Action)
+	}
+	if f.Outcome != nil {
+		query = query.Where("audit_logs.outcome = ?", *f.Outcome)
+	}
+	if f.From != nil {
+		query = query.Where("audit_logs.created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("audit_logs.created_at < ?", *f.To)
+	}
+	return query
+}
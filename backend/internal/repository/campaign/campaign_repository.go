@@ -0,0 +1,192 @@
+package campaign
+
+import (
+	"errors"
+	"time"
+
+	campaignDomain "github.com/madr/backend/internal/domain/campaign"
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	"github.com/madr/backend/pkg/database"
+	"github.com/madr/backend/pkg/money"
+	"gorm.io/gorm"
+)
+
+// Repository defines the interface for campaign repository
+type Repository interface {
+	Create(c *campaignDomain.Campaign) error
+	GetByID(id uint) (*campaignDomain.Campaign, error)
+	GetByCategoryID(categoryID uint) (*campaignDomain.Campaign, error)
+	GetAll(limit, offset int, filter *Filter) ([]campaignDomain.Campaign, int64, error)
+	Update(c *campaignDomain.Campaign) error
+	Delete(id uint) error
+	ExistsForCategory(categoryID, excludeID uint) (bool, error)
+
+	CloseExpired(today time.Time, at time.Time) (int64, error)
+	GetProgress(ids []uint) (map[uint]Progress, error)
+}
+
+// Filter narrows campaign queries
+type Filter struct {
+	Status *campaignDomain.Status
+	Search string // Title
+}
+
+// Progress sums the successful donations made to a campaign while it ran
+type Progress struct {
+	CampaignID uint
+	Collected  money.Money
+	Donations  int64
+	Donors     int64 // Distinct donor names; each anonymous donation counts once
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new campaign repository
+func NewRepository() Repository {
+	return &repository{
+		db: database.GetDB(),
+	}
+}
+
+// Create creates a new campaign
+func (r *repository) Create(c *campaignDomain.Campaign) error {
+	return r.db.Create(c).Error
+}
+
+// GetByID retrieves a campaign by ID with its category name
+func (r *repository) GetByID(id uint) (*campaignDomain.Campaign, error) {
+	var c campaignDomain.Campaign
+	if err := r.withCategory(r.db.Model(&campaignDomain.Campaign{})).
+		Select(campaignColumns).
+		Where("campaigns.id = ?", id).
+		First(&c).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("campaign not found")
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetByCategoryID retrieves the campaign collecting through a category
+func (r *repository) GetByCategoryID(categoryID uint) (*campaignDomain.Campaign, error) {
+	var c campaignDomain.Campaign
+	if err := r.db.Where("category_id = ?", categoryID).First(&c).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("campaign not found")
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetAll retrieves campaigns with pagination, latest deadline first
+func (r *repository) GetAll(limit, offset int, filter *Filter) ([]campaignDomain.Campaign, int64, error) {
+	var campaigns []campaignDomain.Campaign
+	var total int64
+
+	query := r.withCategory(r.db.Model(&campaignDomain.Campaign{}))
+	if filter != nil {
+		if filter.Status != nil {
+			query = query.Where("campaigns.status = ?", *filter.Status)
+		}
+		if filter.Search != "" {
+			query = query.Where("campaigns.title ILIKE ?", "%"+filter.Search+"%")
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Select(campaignColumns).
+		Order("campaigns.end_date DESC, campaigns.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&campaigns).Error; err != nil {
+		return nil, 0, err
+	}
+	return campaigns, total, nil
+}
+
+// Update updates a campaign
+func (r *repository) Update(c *campaignDomain.Campaign) error {
+	return r.db.Save(c).Error
+}
+
+// Delete soft deletes a campaign
+func (r *repository) Delete(id uint) error {
+	return r.db.Delete(&campaignDomain.Campaign{}, id).Error
+}
+
+// ExistsForCategory checks if a campaign already collects through the category
+func (r *repository) ExistsForCategory(categoryID, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&campaignDomain.Campaign{}).Where("category_id = ?", categoryID)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CloseExpired closes the active campaigns whose end date is before today
+func (r *repository) CloseExpired(today time.Time, at time.Time) (int64, error) {
+	result := r.db.Model(&campaignDomain.Campaign{}).
+		Where("status = ? AND end_date < ?", campaignDomain.StatusActive, today).
+		Updates(map[string]interface{}{
+			"status":    campaignDomain.StatusClosed,
+			"closed_at": at,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// GetProgress sums the successful donations made to each campaign's category
+// between its start date and the end of its end date, in Jakarta time
+func (r *repository) GetProgress(ids []uint) (map[uint]Progress, error) {
+	progress := make(map[uint]Progress, len(ids))
+	if len(ids) == 0 {
+		return progress, nil
+	}
+
+	var rows []Progress
+	if err := r.db.Table("campaigns").
+		Select(`
+			campaigns.id AS campaign_id,
+			COALESCE(SUM(donations.amount), 0) AS collected,
+			COUNT(donations.id) AS donations,
+			COUNT(DISTINCT CASE WHEN donations.id IS NOT NULL THEN
+				COALESCE(NULLIF(NULLIF(LOWER(TRIM(donations.donor_name)), ''), 'hamba allah'), 'anonymous-' || donations.id)
+			END) AS donors
+		`).
+		Joins(`LEFT JOIN donations ON donations.category_id = campaigns.category_id
+			AND donations.payment_status = ?
+			AND donations.deleted_at IS NULL
+			AND donations.created_at >= campaigns.start_date::timestamp AT TIME ZONE ?
+			AND donations.created_at < (campaigns.end_date + 1)::timestamp AT TIME ZONE ?`,
+			donationDomain.PaymentStatusSuccess, campaignTimeZone, campaignTimeZone).
+		Where("campaigns.id IN ?", ids).
+		Group("campaigns.id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		progress[row.CampaignID] = row
+	}
+	return progress, nil
+}
+
+// campaignTimeZone is the zone campaign dates are read in
+const campaignTimeZone = "Asia/Jakarta"
+
+// campaignColumns selects campaigns with their category name
+const campaignColumns = "campaigns.*, donation_categories.name AS category_name"
+
+// withCategory joins the donation category of campaigns
+func (r *repository) withCategory(query *gorm.DB) *gorm.DB {
+	return query.Joins("LEFT JOIN donation_categories ON campaigns.category_id = donation_categories.id")
+}
@@ -2,9 +2,12 @@ package donation
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	donationDomain "github.com/madr/backend/internal/domain/donation"
 	"github.com/madr/backend/pkg/database"
+	"github.com/madr/backend/pkg/money"
 	"gorm.io/gorm"
 )
 
@@ -12,19 +15,76 @@ import (
 type Repository interface {
 	Create(don *donationDomain.Donation) error
 	GetByID(id uint) (*donationDomain.Donation, error)
-	GetAll(limit, offset int, status *donationDomain.PaymentStatus) ([]donationDomain.Donation, int64, error)
+	GetAll(limit, offset int, filter *Filter) ([]donationDomain.Donation, int64, error)
 	Update(don *donationDomain.Donation) error
 	Delete(id uint) error
-	GetTotalAmount(status *donationDomain.PaymentStatus) (float64, error)
+	GetTotalAmount(status *donationDomain.PaymentStatus) (money.Money, error)
 	GetTotalTransactions(status *donationDomain.PaymentStatus) (int64, error)
-	GetAmountPerCategory(status *donationDomain.PaymentStatus) ([]CategoryAmount, error)
+	GetAmountPerCategory(filter *Filter) ([]CategoryAmount, error)
+	GetByPaymentReference(reference string) (*donationDomain.Donation, error)
+	ApplyPaymentResult(id uint, result *PaymentResult) (bool, error)
+	GetTotals(filter *Filter) (*Totals, error)
+	GetPeriodAmounts(period donationDomain.ReportPeriod, filter *Filter) ([]PeriodAmount, error)
+	FindInBatches(filter *Filter, batchSize int, fn func(batch []donationDomain.Donation) error) error
+	GetYearlyTotals(filter *Filter) ([]YearTotal, error)
+	Claim(id, userID uint) (bool, error)
+	Audited(fn func(tx Repository) (*donationDomain.AuditLog, error)) error
+	GetAuditLogs(donationID uint) ([]donationDomain.AuditLog, error)
+}
+
+// Filter narrows donation queries. From is inclusive and To is exclusive;
+// both are compared with the time the donation was paid (or created when unpaid).
+type Filter struct {
+	Status     *donationDomain.PaymentStatus
+	CategoryID *uint
+	UserID     *uint
+	From       *time.Time
+	To         *time.Time
+}
+
+// Totals represents the aggregate of the donations matching a filter
+type Totals struct {
+	Amount       money.Money
+	Transactions int64
+}
+
+// YearTotal represents the donations of one Jakarta calendar year
+type YearTotal struct {
+	Year         int         `json:"year"`
+	Amount       money.Money `json:"amount"`
+	Transactions int64       `json:"transactions"`
+}
+
+// PeriodAmount represents the donations of one category within one period
+type PeriodAmount struct {
+	PeriodStart  time.Time
+	CategoryID   uint
+	CategoryName string
+	Amount       money.Money
+	Transactions int64
+}
+
+// reportTimeZone is the zone report periods are aligned to
+const reportTimeZone = "Asia/Jakarta"
+
+// donationTime is the moment a donation is reported at
+const donationTime = "COALESCE(donations.paid_at, donations.created_at)"
+
+// PaymentResult represents a payment outcome reported by a gateway
+type PaymentResult struct {
+	Status        donationDomain.PaymentStatus
+	TransactionID string
+	Payload       string
+	PaidAt        *time.Time
 }
 
 // CategoryAmount represents donation amount per category
 type CategoryAmount struct {
-	CategoryID   uint    `json:"category_id"`
-	CategoryName string  `json:"category_name"`
-	Amount       float64 `json:"amount"`
+	CategoryID   uint        `json:"category_id"`
+	CategoryName string      `json:"category_name"`
+	FundType     string      `json:"fund_type"`
+	Amount       money.Money `json:"amount"`
+	Transactions int64       `json:"transactions"`
 }
 
 type repository struct {
@@ -49,24 +109,24 @@ func (r *repository) Create(don *donationDomain.Donation) error {
 // GetByID retrieves a donation by ID with category
 func (r *repository) GetByID(id uint) (*donationDomain.Donation, error) {
 	var don donationDomain.Donation
-	if err := r.db.Preload("Category").First(&don, id).Error; err != nil {
+	if err := r.db.First(&don, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("donation not found")
 		}
 		return nil, err
 	}
-	return &don, nil
+
+	donations := []donationDomain.Donation{don}
+	r.attachCategories(donations)
+	return &donations[0], nil
 }
 
-// GetAll retrieves all donations with pagination and optional status filter
-func (r *repository) GetAll(limit, offset int, status *donationDomain.PaymentStatus) ([]donationDomain.Donation, int64, error) {
+// GetAll retrieves all donations with pagination and optional filter
+func (r *repository) GetAll(limit, offset int, filter *Filter) ([]donationDomain.Donation, int64, error) {
 	var donations []donationDomain.Donation
 	var total int64
 
-	query := r.db.Model(&donationDomain.Donation{})
-	if status != nil {
-		query = query.Where("payment_status = ?", *status)
-	}
+	query := filter.apply(r.db.Model(&donationDomain.Donation{}))
 
 	// Count total records
 	if err := query.Count(&total).Error; err != nil {
@@ -81,39 +141,7 @@ func (r *repository) GetAll(limit, offset int, status *donationDomain.PaymentSta
 		return nil, 0, err
 	}
 
-	// Load categories for all donations
-	categoryIDs := make([]uint, 0, len(donations))
-	for _, d := range donations {
-		categoryIDs = append(categoryIDs, d.CategoryID)
-	}
-	
-	if len(categoryIDs) > 0 {
-		var categories []struct {
-			ID          uint
-			Name        string
-			Description string
-		}
-		r.db.Table("donation_categories").
-			Select("id, name, description").
-			Where("id IN ?", categoryIDs).
-			Find(&categories)
-		
-		// Map categories to donations
-		categoryMap := make(map[uint]*donationDomain.DonationCategoryInfo)
-		for _, c := range categories {
-			categoryMap[c.ID] = &donationDomain.DonationCategoryInfo{
-				ID:          c.ID,
-				Name:        c.Name,
-				Description: c.Description,
-			}
-		}
-		
-		for i := range donations {
-			if cat, ok := categoryMap[donations[i].CategoryID]; ok {
-				donations[i].Category = cat
-			}
-		}
-	}
+	r.attachCategories(donations)
 
 	return donations, total, nil
 }
@@ -135,10 +163,10 @@ func (r *repository) Delete(id uint) error {
 }
 
 // GetTotalAmount calculates total donation amount (only success status by default)
-func (r *repository) GetTotalAmount(status *donationDomain.PaymentStatus) (float64, error) {
-	var total float64
+func (r *repository) GetTotalAmount(status *donationDomain.PaymentStatus) (money.Money, error) {
+	var total money.Money
 	query := r.db.Model(&donationDomain.Donation{})
-	
+
 	if status != nil {
 		query = query.Where("payment_status = ?", *status)
 	} else {
@@ -156,7 +184,7 @@ func (r *repository) GetTotalAmount(status *donationDomain.PaymentStatus) (float
 func (r *repository) GetTotalTransactions(status *donationDomain.PaymentStatus) (int64, error) {
 	var total int64
 	query := r.db.Model(&donationDomain.Donation{})
-	
+
 	if status != nil {
 		query = query.Where("payment_status = ?", *status)
 	} else {
@@ -170,22 +198,23 @@ func (r *repository) GetTotalTransactions(status *donationDomain.PaymentStatus)
 	return total, nil
 }
 
-// GetAmountPerCategory calculates donation amount per category (optimized SQL)
-func (r *repository) GetAmountPerCategory(status *donationDomain.PaymentStatus) ([]CategoryAmount, error) {
+// GetAmountPerCategory calculates donation amount per category (optimized SQL).
+// Only successful donations are counted unless the filter sets a status.
+func (r *repository) GetAmountPerCategory(filter *Filter) ([]CategoryAmount, error) {
 	var results []CategoryAmount
-	
-	query := r.db.Model(&donationDomain.Donation{}).
+
+	query := filter.apply(r.db.Model(&donationDomain.Donation{})).
 		Select(`
 			donations.category_id,
 			donation_categories.name as category_name,
-			COALESCE(SUM(donations.amount), 0) as amount
+			donation_categories.fund_type,
+			COALESCE(SUM(donations.amount), 0) as amount,
+			COUNT(*) as transactions
 		`).
 		Joins("LEFT JOIN donation_categories ON donations.category_id = donation_categories.id").
-		Group("donations.category_id, donation_categories.name")
-	
-	if status != nil {
-		query = query.Where("donations.payment_status = ?", *status)
-	} else {
+		Group("donations.category_id, donation_categories.name, donation_categories.fund_type")
+
+	if filter == nil || filter.Status == nil {
 		// Default to success only
 		query = query.Where("donations.payment_status = ?", donationDomain.PaymentStatusSuccess)
 	}
@@ -193,7 +222,205 @@ func (r *repository) GetAmountPerCategory(status *donationDomain.PaymentStatus)
 	if err := query.Order("amount DESC").Scan(&results).Error; err != nil {
 		return nil, err
 	}
-	
+
+	return results, nil
+}
+
+// GetByPaymentReference retrieves a donation by the order ID sent to the gateway
+func (r *repository) GetByPaymentReference(reference string) (*donationDomain.Donation, error) {
+	var don donationDomain.Donation
+	if err := r.db.Where("payment_reference = ?", reference).First(&don).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("donation not found")
+		}
+		return nil, err
+	}
+	return &don, nil
+}
+
+// ApplyPaymentResult stores a gateway result while the donation is still pending.
+// The conditional update makes repeated or concurrent callbacks idempotent: once a
+// donation leaves pending it is never changed again. Returns whether a row changed.
+func (r *repository) ApplyPaymentResult(id uint, result *PaymentResult) (bool, error) {
+	updates := map[string]interface{}{
+		"payment_status": result.Status,
+		"paid_at":        result.PaidAt,
+	}
+	if result.TransactionID != "" {
+		updates["transaction_id"] = result.TransactionID
+	}
+	if result.Payload != "" {
+		updates["payment_payload"] = result.Payload
+	}
+
+	tx := r.db.Model(&donationDomain.Donation{}).
+		Where("id = ? AND payment_status = ?", id, donationDomain.PaymentStatusPending).
+		Updates(updates)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected > 0, nil
+}
+
+// GetTotals sums the donations matching the filter
+func (r *repository) GetTotals(filter *Filter) (*Totals, error) {
+	var totals Totals
+	query := filter.apply(r.db.Model(&donationDomain.Donation{})).
+		Select("COALESCE(SUM(donations.amount), 0) AS amount, COUNT(*) AS transactions")
+
+	if err := query.Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	return &totals, nil
+}
+
+// GetPeriodAmounts sums the donations matching the filter per period and category.
+// Periods are truncated in Jakarta time; weeks start on Monday.
+func (r *repository) GetPeriodAmounts(period donationDomain.ReportPeriod, filter *Filter) ([]PeriodAmount, error) {
+	if !period.IsValid() {
+		return nil, errors.New("invalid report period")
+	}
+
+	// The period is validated above, so it is safe to inline in the expression
+	bucket := fmt.Sprintf("date_trunc('%s', %s AT TIME ZONE '%s')", period, donationTime, reportTimeZone)
+
+	var results []PeriodAmount
+	query := filter.apply(r.db.Model(&donationDomain.Donation{})).
+		Select(fmt.Sprintf(`
+			%s AT TIME ZONE '%s' AS period_start,
+			donations.category_id,
+			donation_categories.name AS category_name,
+			COALESCE(SUM(donations.amount), 0) AS amount,
+			COUNT(*) AS transactions
+		`, bucket, reportTimeZone)).
+		Joins("LEFT JOIN donation_categories ON donations.category_id = donation_categories.id").
+		Group("period_start, donations.category_id, donation_categories.name").
+		Order("period_start, amount DESC")
+
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, err
+	}
 	return results, nil
 }
 
+// FindInBatches walks the donations matching the filter in ID order, passing
+// at most batchSize donations with their categories to fn at a time
+func (r *repository) FindInBatches(filter *Filter, batchSize int, fn func(batch []donationDomain.Donation) error) error {
+	var batch []donationDomain.Donation
+	return filter.apply(r.db.Model(&donationDomain.Donation{})).
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			r.attachCategories(batch)
+			return fn(batch)
+		}).Error
+}
+
+// GetYearlyTotals sums the donations matching the filter per year, latest first
+func (r *repository) GetYearlyTotals(filter *Filter) ([]YearTotal, error) {
+	var totals []YearTotal
+	year := fmt.Sprintf("EXTRACT(YEAR FROM %s AT TIME ZONE '%s')::int", donationTime, reportTimeZone)
+	if err := filter.apply(r.db.Model(&donationDomain.Donation{})).
+		Select(year + " AS year, COALESCE(SUM(donations.amount), 0) AS amount, COUNT(*) AS transactions").
+		Group("year").
+		Order("year DESC").
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// Claim links an unclaimed donation to a donor account. Returns false when
+// the donation already belongs to an account.
+func (r *repository) Claim(id, userID uint) (bool, error) {
+	result := r.db.Model(&donationDomain.Donation{}).
+		Where("id = ? AND user_id IS NULL", id).
+		Update("user_id", userID)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Audited runs fn in a transaction and appends the audit entry it returns, so a
+// change is never stored without its history. A nil entry records nothing.
+func (r *repository) Audited(fn func(tx Repository) (*donationDomain.AuditLog, error)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		entry, err := fn(&repository{db: tx})
+		if err != nil || entry == nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+// GetAuditLogs retrieves the change history of a donation, oldest first
+func (r *repository) GetAuditLogs(donationID uint) ([]donationDomain.AuditLog, error) {
+	var logs []donationDomain.AuditLog
+	if err := r.db.Model(&donationDomain.AuditLog{}).
+		Select("donation_audit_logs.*, users.name AS actor_name").
+		Joins("LEFT JOIN users ON donation_audit_logs.actor_id = users.id").
+		Where("donation_audit_logs.donation_id = ?", donationID).
+		Order("donation_audit_logs.created_at, donation_audit_logs.id").
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// apply adds the filter conditions to a donations query
+func (f *Filter) apply(query *gorm.DB) *gorm.DB {
+	if f == nil {
+		return query
+	}
+	if f.Status != nil {
+		query = query.Where("donations.payment_status = ?", *f.Status)
+	}
+	if f.CategoryID != nil {
+		query = query.Where("donations.category_id = ?", *f.CategoryID)
+	}
+	if f.UserID != nil {
+		query = query.Where("donations.user_id = ?", *f.UserID)
+	}
+	if f.From != nil {
+		query = query.Where(donationTime+" >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where(donationTime+" < ?", *f.To)
+	}
+	return query
+}
+
+// attachCategories loads category information for the given donations.
+// Category is not a GORM relation, so it cannot be preloaded.
+func (r *repository) attachCategories(donations []donationDomain.Donation) {
+	categoryIDs := make([]uint, 0, len(donations))
+	for _, d := range donations {
+		categoryIDs = append(categoryIDs, d.CategoryID)
+	}
+
+	if len(categoryIDs) > 0 {
+		var categories []struct {
+			ID          uint
+			Name        string
+			Description string
+			FundType    string
+		}
+		r.db.Table("donation_categories").
+			Select("id, name, description, fund_type").
+			Where("id IN ?", categoryIDs).
+			Find(&categories)
+
+		// Map categories to donations
+		categoryMap := make(map[uint]*donationDomain.DonationCategoryInfo)
+		for _, c := range categories {
+			categoryMap[c.ID] = &donationDomain.DonationCategoryInfo{
+				ID:          c.ID,
+				Name:        c.Name,
+				Description: c.Description,
+				FundType:    c.FundType,
+			}
+		}
+
+		for i := range donations {
+			if cat, ok := categoryMap[donations[i].CategoryID]; ok {
+				donations[i].Category = cat
+			}
+		}
+	}
+}
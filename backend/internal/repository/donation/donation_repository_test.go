@@ -0,0 +1,117 @@
+package donation
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+	"time"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	donationCategoryDomain "github.com/madr/backend/internal/domain/donationcategory"
+	"github.com/madr/backend/internal/testutil"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/database"
+	"github.com/madr/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProperty_SummaryEqualsExactSumOfRows inserts random amounts and checks that
+// the database aggregates match the exact sum of the inserted rows
+func TestProperty_SummaryEqualsExactSumOfRows(t *testing.T) {
+	testutil.SetupDatabase(t)
+	db := database.GetDB()
+	repo := NewRepository()
+
+	var categories []donationCategoryDomain.DonationCategory
+	require.NoError(t, db.Order("id").Limit(3).Find(&categories).Error)
+	require.NotEmpty(t, categories)
+
+	// Rows use a dedicated status filter so existing data does not interfere
+	status := donationDomain.PaymentStatusFailed
+	clean := func() {
+		db.Unscoped().Where("payment_status = ?", status).Delete(&donationDomain.Donation{})
+	}
+	clean()
+	t.Cleanup(clean)
+
+	property := func(seed int64) bool {
+		clean()
+		r := rand.New(rand.NewSource(seed))
+
+		var expected money.Money
+		perCategory := make(map[uint]money.Money)
+		for i := 0; i < 1+r.Intn(40); i++ {
+			// Sen-level amounts such as 0.10 and 0.20 would drift as float64
+			amount := money.FromSen(1 + r.Int63n(10_000_000_00))
+			category := categories[r.Intn(len(categories))]
+			if err := db.Create(&donationDomain.Donation{
+				CategoryID:    category.ID,
+				Amount:        amount,
+				PaymentStatus: status,
+			}).Error; err != nil {
+				t.Log(err)
+				return false
+			}
+			expected = expected.Add(amount)
+			perCategory[category.ID] = perCategory[category.ID].Add(amount)
+		}
+
+		total, err := repo.GetTotalAmount(&status)
+		if err != nil || total != expected {
+			t.Logf("total %s, expected %s, err %v", total, expected, err)
+			return false
+		}
+
+		amounts, err := repo.GetAmountPerCategory(&Filter{Status: &status})
+		if err != nil || len(amounts) != len(perCategory) {
+			return false
+		}
+		for _, ca := range amounts {
+			if ca.Amount != perCategory[ca.CategoryID] {
+				t.Logf("category %d: %s, expected %s", ca.CategoryID, ca.Amount, perCategory[ca.CategoryID])
+				return false
+			}
+		}
+		return true
+	}
+	assert.NoError(t, quick.Check(property, &quick.Config{MaxCount: 25}))
+}
+
+// TestGetPeriodAmounts_BucketsInJakartaTime checks that a donation paid late in
+// the evening UTC is counted in the next Jakarta day and month
+func TestGetPeriodAmounts_BucketsInJakartaTime(t *testing.T) {
+	testutil.SetupDatabase(t)
+	db := database.GetDB()
+	repo := NewRepository()
+
+	var category donationCategoryDomain.DonationCategory
+	require.NoError(t, db.First(&category).Error)
+
+	// 2999-01-31 18:30 UTC is 2999-02-01 01:30 in Jakarta
+	paidAt := time.Date(2999, 1, 31, 18, 30, 0, 0, time.UTC)
+	don := &donationDomain.Donation{
+		CategoryID:    category.ID,
+		Amount:        money.MustParse("12500.75"),
+		PaymentStatus: donationDomain.PaymentStatusSuccess,
+		PaidAt:        &paidAt,
+	}
+	require.NoError(t, db.Create(don).Error)
+	t.Cleanup(func() { db.Unscoped().Delete(&donationDomain.Donation{}, don.ID) })
+
+	from := time.Date(2999, 1, 1, 0, 0, 0, 0, utils.Jakarta)
+	to := time.Date(3000, 1, 1, 0, 0, 0, 0, utils.Jakarta)
+	filter := &Filter{CategoryID: &category.ID, From: &from, To: &to}
+
+	amounts, err := repo.GetPeriodAmounts(donationDomain.PeriodMonth, filter)
+	require.NoError(t, err)
+	require.Len(t, amounts, 1)
+	assert.True(t, amounts[0].PeriodStart.Equal(time.Date(2999, 2, 1, 0, 0, 0, 0, utils.Jakarta)), "got %s", amounts[0].PeriodStart)
+	assert.Equal(t, don.Amount, amounts[0].Amount)
+	assert.Equal(t, int64(1), amounts[0].Transactions)
+
+	totals, err := repo.GetTotals(filter)
+	require.NoError(t, err)
+	assert.Equal(t, don.Amount, totals.Amount)
+	assert.Equal(t, int64(1), totals.Transactions)
+}
@@ -16,6 +16,7 @@ type Repository interface {
 	Update(cat *donationcategory.DonationCategory) error
 	Delete(id uint) error
 	ExistsByName(name string, excludeID uint) (bool, error)
+	FindByFundType(fundType donationcategory.FundType) (*donationcategory.DonationCategory, error)
 }
 
 type repository struct {
@@ -87,3 +88,15 @@ func (r *repository) ExistsByName(name string, excludeID uint) (bool, error) {
 	return count > 0, nil
 }
 
+
+// FindByFundType retrieves the oldest category of the given fund
+func (r *repository) FindByFundType(fundType donationcategory.FundType) (*donationcategory.DonationCategory, error) {
+	var cat donationcategory.DonationCategory
+	if err := r.db.Where("fund_type = ?", fundType).Order("id ASC").First(&cat).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("donation category not found")
+		}
+		return nil, err
+	}
+	return &cat, nil
+}
@@ -1,6 +1,8 @@
 package gallery
 
 import (
+	"errors"
+
 	"github.com/madr/backend/internal/domain/gallery"
 	"github.com/madr/backend/pkg/database"
 	"gorm.io/gorm"
@@ -9,6 +11,7 @@ import (
 // Repository defines the interface for gallery repository
 type Repository interface {
 	Create(gal *gallery.Gallery) error
+	GetByID(id uint) (*gallery.Gallery, error)
 	GetAll(limit, offset int) ([]gallery.Gallery, int64, error)
 	Delete(id uint) error
 }
@@ -32,6 +35,18 @@ func (r *repository) Create(gal *gallery.Gallery) error {
 	return nil
 }
 
+// GetByID retrieves a gallery item by ID
+func (r *repository) GetByID(id uint) (*gallery.Gallery, error) {
+	var gal gallery.Gallery
+	if err := r.db.First(&gal, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("gallery item not found")
+		}
+		return nil, err
+	}
+	return &gal, nil
+}
+
 // GetAll retrieves all gallery items with pagination
 func (r *repository) GetAll(limit, offset int) ([]gallery.Gallery, int64, error) {
 	var items []gallery.Gallery
@@ -0,0 +1,149 @@
+package invitation
+
+import (
+	"errors"
+	"time"
+
+	invitationDomain "github.com/madr/backend/internal/domain/invitation"
+	userDomain "github.com/madr/backend/internal/domain/user"
+	"github.com/madr/backend/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrUnavailable is returned when an invitation was used, revoked or expired
+// before it could be accepted
+var ErrUnavailable = errors.New("invitation is no longer valid")
+
+// Repository defines the interface for invitation repository
+type Repository interface {
+	Create(inv *invitationDomain.Invitation) error
+	GetByID(id uint) (*invitationDomain.Invitation, error)
+	GetByTokenHash(tokenHash string) (*invitationDomain.Invitation, error)
+	GetAll(limit, offset int) ([]invitationDomain.Invitation, int64, error)
+	Revoke(id uint, at time.Time) error
+	Accept(id uint, usr *userDomain.User, at time.Time) error
+}
+
+// userRole is a row of the user_roles join table
+type userRole struct {
+	UserID uint
+	RoleID uint
+}
+
+func (userRole) TableName() string {
+	return "user_roles"
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new invitation repository
+func NewRepository() Repository {
+	return &repository{
+		db: database.GetDB(),
+	}
+}
+
+// Create creates a new invitation
+func (r *repository) Create(inv *invitationDomain.Invitation) error {
+	return r.db.Create(inv).Error
+}
+
+// GetByID retrieves an invitation by ID with its role name
+func (r *repository) GetByID(id uint) (*invitationDomain.Invitation, error) {
+	var inv invitationDomain.Invitation
+	if err := r.withRole().Where("invitations.id = ?", id).First(&inv).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invitation not found")
+		}
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// GetByTokenHash retrieves the invitation issued with a token
+func (r *repository) GetByTokenHash(tokenHash string) (*invitationDomain.Invitation, error) {
+	var inv invitationDomain.Invitation
+	if err := r.withRole().Where("invitations.token_hash = ?", tokenHash).First(&inv).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invitation not found")
+		}
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// GetAll retrieves invitations, newest first
+func (r *repository) GetAll(limit, offset int) ([]invitationDomain.Invitation, int64, error) {
+	var invitations []invitationDomain.Invitation
+	var total int64
+
+	if err := r.db.Model(&invitationDomain.Invitation{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := r.withRole().
+		Order("invitations.created_at DESC, invitations.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&invitations).Error; err != nil {
+		return nil, 0, err
+	}
+	return invitations, total, nil
+}
+
+// Revoke revokes a pending invitation
+func (r *repository) Revoke(id uint, at time.Time) error {
+	result := r.db.Model(&invitationDomain.Invitation{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// Accept creates the invited user, grants the invitation role and marks the
+// invitation used in one transaction. Concurrent attempts with the same
+// invitation cannot both succeed.
+func (r *repository) Accept(id uint, usr *userDomain.User, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var inv invitationDomain.Invitation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND used_at IS NULL AND revoked_at IS NULL AND expires_at > ?", id, at).
+			First(&inv).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUnavailable
+			}
+			return err
+		}
+
+		if err := tx.Create(usr).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&userRole{UserID: usr.ID, RoleID: inv.RoleID}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&invitationDomain.Invitation{}).
+			Where("id = ? AND used_at IS NULL", id).
+			Updates(map[string]interface{}{"used_at": at, "used_by": usr.ID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUnavailable
+		}
+		return nil
+	})
+}
+
+func (r *repository) withRole() *gorm.DB {
+	return r.db.Model(&invitationDomain.Invitation{}).
+		Select("invitations.*, roles.name AS role_name").
+		Joins("LEFT JOIN roles ON roles.id = invitations.role_id")
+}
@@ -0,0 +1,138 @@
+package jobrun
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	jobRunDomain "github.com/madr/backend/internal/domain/jobrun"
+	"github.com/madr/backend/pkg/database"
+	"github.com/madr/backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// Repository defines the interface for job run repository. It also provides
+// the cross-instance lock that keeps a job from running on two replicas.
+type Repository interface {
+	TryLock(ctx context.Context, job string) (unlock func(), acquired bool, err error)
+	LastStartedAt(job string) (time.Time, error)
+	Create(run *jobRunDomain.JobRun) error
+	Update(run *jobRunDomain.JobRun) error
+	GetAll(limit, offset int, filter *Filter) ([]jobRunDomain.JobRun, int64, error)
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+// Filter narrows job run queries
+type Filter struct {
+	JobName string
+	Status  *jobRunDomain.Status
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new job run repository
+func NewRepository() Repository {
+	return &repository{
+		db: database.GetDB(),
+	}
+}
+
+// TryLock takes a Postgres session advisory lock for the job without
+// waiting. The lock lives on a dedicated connection that unlock releases.
+func (r *repository) TryLock(ctx context.Context, job string) (func(), bool, error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", lockKey(job)).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", lockKey(job)); err != nil {
+			logger.Error().Err(err).Str("job", job).Msg("Failed to release job lock")
+			// Discard the connection so the lock is not left held in the pool
+			_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}
+	return unlock, true, nil
+}
+
+func lockKey(job string) string {
+	return "scheduler:" + job
+}
+
+// LastStartedAt returns when the job last started on any instance, or the
+// zero time if it never ran
+func (r *repository) LastStartedAt(job string) (time.Time, error) {
+	var last sql.NullTime
+	if err := r.db.Model(&jobRunDomain.JobRun{}).
+		Select("MAX(started_at)").
+		Where("job_name = ?", job).
+		Row().
+		Scan(&last); err != nil {
+		return time.Time{}, err
+	}
+	return last.Time, nil
+}
+
+// Create records a job run
+func (r *repository) Create(run *jobRunDomain.JobRun) error {
+	return r.db.Create(run).Error
+}
+
+// Update saves the outcome of a job run
+func (r *repository) Update(run *jobRunDomain.JobRun) error {
+	return r.db.Save(run).Error
+}
+
+// GetAll retrieves job runs, latest first
+func (r *repository) GetAll(limit, offset int, filter *Filter) ([]jobRunDomain.JobRun, int64, error) {
+	var runs []jobRunDomain.JobRun
+	var total int64
+
+	query := r.db.Model(&jobRunDomain.JobRun{})
+	if filter != nil {
+		if filter.JobName != "" {
+			query = query.Where("job_name = ?", filter.JobName)
+		}
+		if filter.Status != nil {
+			query = query.Where("status = ?", *filter.Status)
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("started_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return runs, total, nil
+}
+
+// DeleteBefore deletes runs started before the given time and returns how
+// many were removed
+func (r *repository) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Where("started_at < ?", before).Delete(&jobRunDomain.JobRun{})
+	return result.RowsAffected, result.Error
+}
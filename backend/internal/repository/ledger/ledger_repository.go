@@ -0,0 +1,366 @@
+package ledger
+
+import (
+	"errors"
+	"time"
+
+	ledgerDomain "github.com/madr/backend/internal/domain/ledger"
+	"github.com/madr/backend/pkg/database"
+	"github.com/madr/backend/pkg/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository defines the interface for cash ledger repository
+type Repository interface {
+	CreateAccount(acc *ledgerDomain.Account) error
+	GetAccountByID(id uint) (*ledgerDomain.Account, error)
+	GetDefaultAccount() (*ledgerDomain.Account, error)
+	GetAccounts() ([]ledgerDomain.Account, error)
+	UpdateAccount(acc *ledgerDomain.Account) error
+	DeleteAccount(id uint) error
+	AccountNameExists(name string, excludeID uint) (bool, error)
+
+	CreateCategory(cat *ledgerDomain.Category) error
+	GetCategoryByID(id uint) (*ledgerDomain.Category, error)
+	GetDonationCategory() (*ledgerDomain.Category, error)
+	GetCategories(entryType *ledgerDomain.EntryType) ([]ledgerDomain.Category, error)
+	UpdateCategory(cat *ledgerDomain.Category) error
+	DeleteCategory(id uint) error
+	CategoryNameExists(name string, entryType ledgerDomain.EntryType, excludeID uint) (bool, error)
+
+	CreateEntry(entry *ledgerDomain.Entry) error
+	GetEntryByID(id uint) (*ledgerDomain.Entry, error)
+	GetEntries(limit, offset int, filter *EntryFilter) ([]ledgerDomain.Entry, int64, error)
+	UpdateEntry(entry *ledgerDomain.Entry) error
+	DeleteEntry(id uint) error
+	CountEntries(filter *EntryFilter) (int64, error)
+
+	UpsertDonationEntry(entry *ledgerDomain.Entry) error
+	DeleteDonationEntry(donationID uint) error
+
+	GetBalance(accountID *uint, before time.Time) (money.Money, error)
+	GetCategoryTotals(filter *EntryFilter) ([]CategoryTotal, error)
+}
+
+// EntryFilter narrows entry queries. From and To are inclusive dates.
+type EntryFilter struct {
+	AccountID  *uint
+	CategoryID *uint
+	Type       *ledgerDomain.EntryType
+	From       *time.Time
+	To         *time.Time
+}
+
+// CategoryTotal represents the sum of entries of one category
+type CategoryTotal struct {
+	CategoryID   uint                   `json:"category_id"`
+	CategoryName string                 `json:"category"`
+	Type         ledgerDomain.EntryType `json:"type"`
+	Amount       money.Money            `json:"amount"`
+	Entries      int64                  `json:"entries"`
+}
+
+// signedAmount is the balance change of an entry
+const signedAmount = "CASE WHEN ledger_entries.type = 'expense' THEN -ledger_entries.amount ELSE ledger_entries.amount END"
+
+// entryColumns selects entries with their account and category names
+const entryColumns = "ledger_entries.*, ledger_accounts.name AS account_name, ledger_categories.name AS category_name"
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new cash ledger repository
+func NewRepository() Repository {
+	return &repository{
+		db: database.GetDB(),
+	}
+}
+
+// CreateAccount creates a new ledger account
+func (r *repository) CreateAccount(acc *ledgerDomain.Account) error {
+	return r.db.Create(acc).Error
+}
+
+// GetAccountByID retrieves a ledger account by ID
+func (r *repository) GetAccountByID(id uint) (*ledgerDomain.Account, error) {
+	var acc ledgerDomain.Account
+	if err := r.db.First(&acc, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ledger account not found")
+		}
+		return nil, err
+	}
+	return &acc, nil
+}
+
+// GetDefaultAccount retrieves the account that receives donation income
+func (r *repository) GetDefaultAccount() (*ledgerDomain.Account, error) {
+	var acc ledgerDomain.Account
+	if err := r.db.Where("is_default = ?", true).First(&acc).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("default ledger account not found")
+		}
+		return nil, err
+	}
+	return &acc, nil
+}
+
+// GetAccounts retrieves all ledger accounts
+func (r *repository) GetAccounts() ([]ledgerDomain.Account, error) {
+	var accounts []ledgerDomain.Account
+	if err := r.db.Order("is_default DESC, name ASC").Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// UpdateAccount updates a ledger account. Making it the default clears the
+// flag on the previous default account in the same transaction.
+func (r *repository) UpdateAccount(acc *ledgerDomain.Account) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if acc.IsDefault {
+			if err := tx.Model(&ledgerDomain.Account{}).
+				Where("is_default = ? AND id <> ?", true, acc.ID).
+				Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Save(acc).Error
+	})
+}
+
+// DeleteAccount soft deletes a ledger account
+func (r *repository) DeleteAccount(id uint) error {
+	return r.db.Delete(&ledgerDomain.Account{}, id).Error
+}
+
+// AccountNameExists checks if an account with the given name exists
+func (r *repository) AccountNameExists(name string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&ledgerDomain.Account{}).Where("name = ?", name)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CreateCategory creates a new ledger category
+func (r *repository) CreateCategory(cat *ledgerDomain.Category) error {
+	return r.db.Create(cat).Error
+}
+
+// GetCategoryByID retrieves a ledger category by ID
+func (r *repository) GetCategoryByID(id uint) (*ledgerDomain.Category, error) {
+	var cat ledgerDomain.Category
+	if err := r.db.First(&cat, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ledger category not found")
+		}
+		return nil, err
+	}
+	return &cat, nil
+}
+
+// GetDonationCategory retrieves the system income category used for donations
+func (r *repository) GetDonationCategory() (*ledgerDomain.Category, error) {
+	var cat ledgerDomain.Category
+	if err := r.db.Where("system = ? AND type = ?", true, ledgerDomain.EntryTypeIncome).
+		Order("id").First(&cat).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("donation ledger category not found")
+		}
+		return nil, err
+	}
+	return &cat, nil
+}
+
+// GetCategories retrieves ledger categories, optionally of one type
+func (r *repository) GetCategories(entryType *ledgerDomain.EntryType) ([]ledgerDomain.Category, error) {
+	var categories []ledgerDomain.Category
+	query := r.db.Order("type ASC, name ASC")
+	if entryType != nil {
+		query = query.Where("type = ?", *entryType)
+	}
+	if err := query.Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// UpdateCategory updates a ledger category
+func (r *repository) UpdateCategory(cat *ledgerDomain.Category) error {
+	return r.db.Save(cat).Error
+}
+
+// DeleteCategory soft deletes a ledger category
+func (r *repository) DeleteCategory(id uint) error {
+	return r.db.Delete(&ledgerDomain.Category{}, id).Error
+}
+
+// CategoryNameExists checks if a category with the given name and type exists
+func (r *repository) CategoryNameExists(name string, entryType ledgerDomain.EntryType, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&ledgerDomain.Category{}).Where("name = ? AND type = ?", name, entryType)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CreateEntry creates a new ledger entry
+func (r *repository) CreateEntry(entry *ledgerDomain.Entry) error {
+	return r.db.Create(entry).Error
+}
+
+// GetEntryByID retrieves a ledger entry by ID with account and category names
+func (r *repository) GetEntryByID(id uint) (*ledgerDomain.Entry, error) {
+	var entry ledgerDomain.Entry
+	if err := r.withNames(r.db.Model(&ledgerDomain.Entry{})).
+		Select(entryColumns).
+		Where("ledger_entries.id = ?", id).
+		First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ledger entry not found")
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetEntries retrieves entries newest first with the running balance after
+// each entry. The balance covers the whole history of the filtered account
+// (or of all accounts), independent of the other filters and pagination.
+func (r *repository) GetEntries(limit, offset int, filter *EntryFilter) ([]ledgerDomain.Entry, int64, error) {
+	history := r.db.Model(&ledgerDomain.Entry{}).
+		Select("ledger_entries.*, SUM(" + signedAmount + ") OVER (ORDER BY ledger_entries.entry_date, ledger_entries.id) AS balance")
+	if filter != nil && filter.AccountID != nil {
+		history = history.Where("ledger_entries.account_id = ?", *filter.AccountID)
+	}
+
+	query := r.withNames(r.db.Table("(?) AS ledger_entries", history))
+	query = filter.apply(query)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []ledgerDomain.Entry
+	if err := query.Select(entryColumns).
+		Order("ledger_entries.entry_date DESC, ledger_entries.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// UpdateEntry updates an existing ledger entry
+func (r *repository) UpdateEntry(entry *ledgerDomain.Entry) error {
+	return r.db.Save(entry).Error
+}
+
+// DeleteEntry soft deletes a ledger entry
+func (r *repository) DeleteEntry(id uint) error {
+	return r.db.Delete(&ledgerDomain.Entry{}, id).Error
+}
+
+// CountEntries counts the entries matching the filter
+func (r *repository) CountEntries(filter *EntryFilter) (int64, error) {
+	var count int64
+	if err := filter.apply(r.db.Model(&ledgerDomain.Entry{})).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// UpsertDonationEntry posts a donation, updating its existing entry if the
+// donation was posted before. Repeated postings never create duplicates.
+func (r *repository) UpsertDonationEntry(entry *ledgerDomain.Entry) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "donation_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"amount", "description", "entry_date", "updated_at", "deleted_at"}),
+	}).
+		Create(entry).Error
+}
+
+// DeleteDonationEntry permanently removes the entry posted for a donation
+func (r *repository) DeleteDonationEntry(donationID uint) error {
+	return r.db.Unscoped().Where("donation_id = ?", donationID).Delete(&ledgerDomain.Entry{}).Error
+}
+
+// GetBalance sums all entries dated before the given day
+func (r *repository) GetBalance(accountID *uint, before time.Time) (money.Money, error) {
+	var balance money.Money
+	query := r.db.Model(&ledgerDomain.Entry{}).
+		Select("COALESCE(SUM("+signedAmount+"), 0)").
+		Where("ledger_entries.entry_date < ?", before)
+	if accountID != nil {
+		query = query.Where("ledger_entries.account_id = ?", *accountID)
+	}
+	if err := query.Scan(&balance).Error; err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+// GetCategoryTotals sums the entries matching the filter per category
+func (r *repository) GetCategoryTotals(filter *EntryFilter) ([]CategoryTotal, error) {
+	var results []CategoryTotal
+	query := filter.apply(r.db.Model(&ledgerDomain.Entry{})).
+		Select(`
+			ledger_entries.category_id,
+			ledger_categories.name AS category_name,
+			ledger_entries.type,
+			COALESCE(SUM(ledger_entries.amount), 0) AS amount,
+			COUNT(*) AS entries
+		`).
+		Joins("LEFT JOIN ledger_categories ON ledger_entries.category_id = ledger_categories.id").
+		Group("ledger_entries.category_id, ledger_categories.name, ledger_entries.type").
+		Order("ledger_entries.type DESC, amount DESC")
+
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// withNames joins the account and category names of entries
+func (r *repository) withNames(query *gorm.DB) *gorm.DB {
+	return query.
+		Joins("LEFT JOIN ledger_accounts ON ledger_entries.account_id = ledger_accounts.id").
+		Joins("LEFT JOIN ledger_categories ON ledger_entries.category_id = ledger_categories.id")
+}
+
+// apply adds the filter conditions to an entries query
+func (f *EntryFilter) apply(query *gorm.DB) *gorm.DB {
+	if f == nil {
+		return query
+	}
+	if f.AccountID != nil {
+		query = query.Where("ledger_entries.account_id = ?", *f.AccountID)
+	}
+	if f.CategoryID != nil {
+		query = query.Where("ledger_entries.category_id = ?", *f.CategoryID)
+	}
+	if f.Type != nil {
+		query = query.Where("ledger_entries.type = ?", *f.Type)
+	}
+	if f.From != nil {
+		query = query.Where("ledger_entries.entry_date >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("ledger_entries.entry_date <= ?", *f.To)
+	}
+	return query
+}
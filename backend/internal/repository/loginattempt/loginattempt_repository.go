@@ -0,0 +1,79 @@
+package loginattempt
+
+import (
+	"errors"
+	"time"
+
+	"github.com/madr/backend/internal/domain/loginattempt"
+	"github.com/madr/backend/pkg/database"
+	"gorm.io/gorm"
+)
+
+// Repository defines the interface for login attempt repository
+type Repository interface {
+	Get(scope loginattempt.Scope, key string) (*loginattempt.LoginAttempt, error)
+	RecordFailure(scope loginattempt.Scope, key string, at, since time.Time) (*loginattempt.LoginAttempt, error)
+	Lock(scope loginattempt.Scope, key string, until time.Time) error
+	Reset(scope loginattempt.Scope, key string) error
+	DeleteStale(before time.Time) (int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new login attempt repository
+func NewRepository() Repository {
+	return &repository{
+		db: database.GetDB(),
+	}
+}
+
+// Get retrieves the failed login counter of a username or client IP
+func (r *repository) Get(scope loginattempt.Scope, key string) (*loginattempt.LoginAttempt, error) {
+	var attempt loginattempt.LoginAttempt
+	if err := r.db.Where("scope = ? AND key = ?", scope, key).First(&attempt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("login attempt not found")
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// RecordFailure counts a failed login and returns the updated counter.
+// Failures from before since are forgotten, starting the count over.
+func (r *repository) RecordFailure(scope loginattempt.Scope, key string, at, since time.Time) (*loginattempt.LoginAttempt, error) {
+	var attempt loginattempt.LoginAttempt
+	if err := r.db.Raw(`
+		INSERT INTO login_attempts (scope, key, failures, last_failed_at) VALUES (?, ?, 1, ?)
+		ON CONFLICT (scope, key) DO UPDATE SET
+			failures = CASE WHEN login_attempts.last_failed_at <= ? THEN 1 ELSE login_attempts.failures + 1 END,
+			locked_until = CASE WHEN login_attempts.last_failed_at <= ? THEN NULL ELSE login_attempts.locked_until END,
+			last_failed_at = EXCLUDED.last_failed_at
+		RETURNING *`, scope, key, at, since, since).
+		Scan(&attempt).Error; err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// Lock locks out logins for a username or client IP until the given time
+func (r *repository) Lock(scope loginattempt.Scope, key string, until time.Time) error {
+	return r.db.Model(&loginattempt.LoginAttempt{}).
+		Where("scope = ? AND key = ?", scope, key).
+		Update("locked_until", until).Error
+}
+
+// Reset forgets the failed logins of a username or client IP, lifting any lockout
+func (r *repository) Reset(scope loginattempt.Scope, key string) error {
+	return r.db.Where("scope = ? AND key = ?", scope, key).Delete(&loginattempt.LoginAttempt{}).Error
+}
+
+// DeleteStale deletes counters whose last failure and lockout ended before
+// the given time and returns how many were removed
+func (r *repository) DeleteStale(before time.Time) (int64, error) {
+	result := r.db.Where("last_failed_at < ? AND (locked_until IS NULL OR locked_until < ?)", before, before).
+		Delete(&loginattempt.LoginAttempt{})
+	return result.RowsAffected, result.Error
+}
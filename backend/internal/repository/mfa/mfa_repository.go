@@ -0,0 +1,136 @@
+package mfa
+
+import (
+	"errors"
+	"time"
+
+	mfaDomain "github.com/madr/backend/internal/domain/mfa"
+	"github.com/madr/backend/pkg/database"
+	"gorm.io/gorm"
+)
+
+// ErrUnavailable is returned when a code was already used
+var ErrUnavailable = errors.New("code was already used")
+
+// Repository defines the interface for two-factor authentication repository
+type Repository interface {
+	GetTOTP(userID uint) (*mfaDomain.TOTP, error)
+	SaveTOTP(totp *mfaDomain.TOTP) error
+	Enable(userID uint, step int64, at time.Time, codeHashes []string) error
+	Disable(userID uint) error
+	UseStep(userID uint, step int64) error
+	ReplaceRecoveryCodes(userID uint, codeHashes []string) error
+	UseRecoveryCode(userID uint, codeHash string, at time.Time) error
+	CountRecoveryCodes(userID uint) (int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new two-factor authentication repository
+func NewRepository() Repository {
+	return &repository{
+		db: database.GetDB(),
+	}
+}
+
+// GetTOTP retrieves the authenticator of a user
+func (r *repository) GetTOTP(userID uint) (*mfaDomain.TOTP, error) {
+	var totp mfaDomain.TOTP
+	if err := r.db.Where("user_id = ?", userID).First(&totp).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("totp not found")
+		}
+		return nil, err
+	}
+	return &totp, nil
+}
+
+// SaveTOTP creates or replaces the authenticator of a user
+func (r *repository) SaveTOTP(totp *mfaDomain.TOTP) error {
+	return r.db.Save(totp).Error
+}
+
+// Enable confirms a pending authenticator with the step of the code that
+// proved it and stores fresh recovery codes, in one transaction
+func (r *repository) Enable(userID uint, step int64, at time.Time, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&mfaDomain.TOTP{}).
+			Where("user_id = ? AND confirmed_at IS NULL", userID).
+			Updates(map[string]interface{}{"confirmed_at": at, "last_used_step": step, "updated_at": at})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUnavailable
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// Disable removes the authenticator and recovery codes of a user
+func (r *repository) Disable(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&mfaDomain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&mfaDomain.TOTP{}).Error
+	})
+}
+
+// UseStep records the step of an accepted code. A code from the same or an
+// earlier step cannot be used again.
+func (r *repository) UseStep(userID uint, step int64) error {
+	result := r.db.Model(&mfaDomain.TOTP{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes discards the recovery codes of a user for new ones
+func (r *repository) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// UseRecoveryCode marks an unused recovery code as used
+func (r *repository) UseRecoveryCode(userID uint, codeHash string, at time.Time) error {
+	result := r.db.Model(&mfaDomain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// CountRecoveryCodes counts the unused recovery codes of a user
+func (r *repository) CountRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&mfaDomain.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&mfaDomain.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]mfaDomain.RecoveryCode, len(codeHashes))
+	for i, hash := range codeHashes {
+		codes[i] = mfaDomain.RecoveryCode{UserID: userID, CodeHash: hash}
+	}
+	return tx.Create(&codes).Error
+}
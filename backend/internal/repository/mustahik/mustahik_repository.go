@@ -0,0 +1,306 @@
+package mustahik
+
+import (
+	"errors"
+	"time"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	mustahikDomain "github.com/madr/backend/internal/domain/mustahik"
+	"github.com/madr/backend/pkg/database"
+	"github.com/madr/backend/pkg/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository defines the interface for mustahik repository
+type Repository interface {
+	CreateMustahik(m *mustahikDomain.Mustahik) error
+	GetMustahikByID(id uint) (*mustahikDomain.Mustahik, error)
+	GetMustahik(limit, offset int, filter *MustahikFilter) ([]mustahikDomain.Mustahik, int64, error)
+	UpdateMustahik(m *mustahikDomain.Mustahik) error
+	DeleteMustahik(id uint) error
+
+	CreateDistribution(d *mustahikDomain.Distribution) error
+	GetDistributionByID(id uint) (*mustahikDomain.Distribution, error)
+	GetDistributions(limit, offset int, filter *DistributionFilter) ([]mustahikDomain.Distribution, int64, error)
+	DeleteDistribution(id uint) error
+	CountDistributions(filter *DistributionFilter) (int64, error)
+	GetCategoryBalance(categoryID uint) (money.Money, error)
+	GetDistributedPerCategory(filter *DistributionFilter) ([]CategoryDistributed, error)
+	GetDistributedPerAsnaf(filter *DistributionFilter) ([]AsnafDistributed, error)
+}
+
+// MustahikFilter narrows mustahik queries
+type MustahikFilter struct {
+	Search string // Head of household name or phone
+	Asnaf  *mustahikDomain.Asnaf
+	Status *mustahikDomain.VerificationStatus
+}
+
+// DistributionFilter narrows distribution queries. From and To are inclusive dates.
+type DistributionFilter struct {
+	MustahikID *uint
+	CategoryID *uint
+	Asnaf      *mustahikDomain.Asnaf
+	From       *time.Time
+	To         *time.Time
+}
+
+// CategoryDistributed sums the distributions paid out of one donation category
+type CategoryDistributed struct {
+	CategoryID    uint        `json:"category_id"`
+	Amount        money.Money `json:"amount"`
+	Distributions int64       `json:"distributions"`
+}
+
+// AsnafDistributed sums the distributions received by one asnaf
+type AsnafDistributed struct {
+	Asnaf         mustahikDomain.Asnaf `json:"asnaf"`
+	Amount        money.Money          `json:"amount"`
+	Distributions int64                `json:"distributions"`
+	Households    int64                `json:"households"`
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new mustahik repository
+func NewRepository() Repository {
+	return &repository{
+		db: database.GetDB(),
+	}
+}
+
+// CreateMustahik creates a new mustahik household
+func (r *repository) CreateMustahik(m *mustahikDomain.Mustahik) error {
+	return r.db.Create(m).Error
+}
+
+// GetMustahikByID retrieves a mustahik household by ID
+func (r *repository) GetMustahikByID(id uint) (*mustahikDomain.Mustahik, error) {
+	var m mustahikDomain.Mustahik
+	if err := r.db.First(&m, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("mustahik not found")
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// GetMustahik retrieves mustahik households with pagination
+func (r *repository) GetMustahik(limit, offset int, filter *MustahikFilter) ([]mustahikDomain.Mustahik, int64, error) {
+	var list []mustahikDomain.Mustahik
+	var total int64
+
+	query := r.db.Model(&mustahikDomain.Mustahik{})
+	if filter != nil {
+		if filter.Search != "" {
+			pattern := "%" + filter.Search + "%"
+			query = query.Where("(head_name ILIKE ? OR phone ILIKE ?)", pattern, pattern)
+		}
+		if filter.Asnaf != nil {
+			query = query.Where("asnaf = ?", *filter.Asnaf)
+		}
+		if filter.Status != nil {
+			query = query.Where("verification_status = ?", *filter.Status)
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("head_name ASC, id ASC").Limit(limit).Offset(offset).Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// UpdateMustahik updates a mustahik household
+func (r *repository) UpdateMustahik(m *mustahikDomain.Mustahik) error {
+	return r.db.Save(m).Error
+}
+
+// DeleteMustahik soft deletes a mustahik household
+func (r *repository) DeleteMustahik(id uint) error {
+	return r.db.Delete(&mustahikDomain.Mustahik{}, id).Error
+}
+
+// CreateDistribution records a distribution if the category balance covers it.
+// The category row lock serializes distributions of one category so two
+// concurrent distributions cannot both spend the same balance.
+func (r *repository) CreateDistribution(d *mustahikDomain.Distribution) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var locked struct{ ID uint }
+		if err := tx.Table("donation_categories").
+			Select("id").
+			Where("id = ? AND deleted_at IS NULL", d.CategoryID).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Take(&locked).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("donation category not found")
+			}
+			return err
+		}
+
+		balance, err := categoryBalance(tx, d.CategoryID)
+		if err != nil {
+			return err
+		}
+		if d.Amount > balance {
+			return errors.New("insufficient category balance")
+		}
+
+		return tx.Create(d).Error
+	})
+}
+
+// GetDistributionByID retrieves a distribution by ID
+func (r *repository) GetDistributionByID(id uint) (*mustahikDomain.Distribution, error) {
+	var d mustahikDomain.Distribution
+	if err := r.withNames(r.db.Model(&mustahikDomain.Distribution{})).
+		Select(distributionColumns).
+		Where("distributions.id = ?", id).
+		First(&d).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("distribution not found")
+		}
+		return nil, err
+	}
+	return &d, nil
+}
+
+// GetDistributions retrieves distributions with pagination, newest first
+func (r *repository) GetDistributions(limit, offset int, filter *DistributionFilter) ([]mustahikDomain.Distribution, int64, error) {
+	var list []mustahikDomain.Distribution
+	var total int64
+
+	query := filter.apply(r.withNames(r.db.Model(&mustahikDomain.Distribution{})))
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Select(distributionColumns).
+		Order("distributions.distributed_on DESC, distributions.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// DeleteDistribution permanently deletes a distribution, returning its
+// amount to the category balance
+func (r *repository) DeleteDistribution(id uint) error {
+	return r.db.Delete(&mustahikDomain.Distribution{}, id).Error
+}
+
+// CountDistributions counts the distributions matching the filter
+func (r *repository) CountDistributions(filter *DistributionFilter) (int64, error) {
+	var count int64
+	query := filter.apply(r.db.Model(&mustahikDomain.Distribution{}).
+		Joins("LEFT JOIN mustahik ON distributions.mustahik_id = mustahik.id"))
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetCategoryBalance returns the successful donations of a category less
+// everything distributed from it
+func (r *repository) GetCategoryBalance(categoryID uint) (money.Money, error) {
+	return categoryBalance(r.db, categoryID)
+}
+
+// GetDistributedPerCategory sums the distributions matching the filter per category
+func (r *repository) GetDistributedPerCategory(filter *DistributionFilter) ([]CategoryDistributed, error) {
+	var results []CategoryDistributed
+	query := filter.apply(r.db.Model(&mustahikDomain.Distribution{}).
+		Joins("LEFT JOIN mustahik ON distributions.mustahik_id = mustahik.id")).
+		Select(`
+			distributions.category_id,
+			COALESCE(SUM(distributions.amount), 0) AS amount,
+			COUNT(*) AS distributions
+		`).
+		Group("distributions.category_id").
+		Order("distributions.category_id ASC")
+
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetDistributedPerAsnaf sums the distributions matching the filter per asnaf
+func (r *repository) GetDistributedPerAsnaf(filter *DistributionFilter) ([]AsnafDistributed, error) {
+	var results []AsnafDistributed
+	query := filter.apply(r.db.Model(&mustahikDomain.Distribution{}).
+		Joins("LEFT JOIN mustahik ON distributions.mustahik_id = mustahik.id")).
+		Select(`
+			mustahik.asnaf,
+			COALESCE(SUM(distributions.amount), 0) AS amount,
+			COUNT(*) AS distributions,
+			COUNT(DISTINCT distributions.mustahik_id) AS households
+		`).
+		Group("mustahik.asnaf").
+		Order("mustahik.asnaf ASC")
+
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// distributionColumns selects distributions with the mustahik and category names
+const distributionColumns = `distributions.*, mustahik.head_name AS mustahik_name,
+	mustahik.asnaf, donation_categories.name AS category_name`
+
+// withNames joins the mustahik and donation category of distributions
+func (r *repository) withNames(query *gorm.DB) *gorm.DB {
+	return query.
+		Joins("LEFT JOIN mustahik ON distributions.mustahik_id = mustahik.id").
+		Joins("LEFT JOIN donation_categories ON distributions.category_id = donation_categories.id")
+}
+
+// categoryBalance computes the balance of a category on the given connection
+func categoryBalance(db *gorm.DB, categoryID uint) (money.Money, error) {
+	var collected, distributed money.Money
+	if err := db.Table("donations").
+		Select("COALESCE(SUM(amount), 0)").
+		Where("category_id = ? AND payment_status = ? AND deleted_at IS NULL",
+			categoryID, donationDomain.PaymentStatusSuccess).
+		Scan(&collected).Error; err != nil {
+		return 0, err
+	}
+	if err := db.Table("distributions").
+		Select("COALESCE(SUM(amount), 0)").
+		Where("category_id = ?", categoryID).
+		Scan(&distributed).Error; err != nil {
+		return 0, err
+	}
+	return collected.Sub(distributed), nil
+}
+
+// apply adds the filter conditions to a distributions query joined with mustahik
+func (f *DistributionFilter) apply(query *gorm.DB) *gorm.DB {
+	if f == nil {
+		return query
+	}
+	if f.MustahikID != nil {
+		query = query.Where("distributions.mustahik_id = ?", *f.MustahikID)
+	}
+	if f.CategoryID != nil {
+		query = query.Where("distributions.category_id = ?", *f.CategoryID)
+	}
+	if f.Asnaf != nil {
+		query = query.Where("mustahik.asnaf = ?", *f.Asnaf)
+	}
+	if f.From != nil {
+		query = query.Where("distributions.distributed_on >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("distributions.distributed_on <= ?", *f.To)
+	}
+	return query
+}
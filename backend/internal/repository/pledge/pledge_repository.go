@@ -0,0 +1,333 @@
+package pledge
+
+import (
+	"errors"
+	"time"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	pledgeDomain "github.com/madr/backend/internal/domain/pledge"
+	"github.com/madr/backend/pkg/database"
+	"github.com/madr/backend/pkg/money"
+	"gorm.io/gorm"
+)
+
+// Repository defines the interface for pledge repository
+type Repository interface {
+	Create(p *pledgeDomain.Pledge) error
+	GetByID(id uint) (*pledgeDomain.Pledge, error)
+	GetAll(limit, offset int, filter *Filter) ([]pledgeDomain.Pledge, int64, error)
+	Update(p *pledgeDomain.Pledge) error
+	Delete(id uint) error
+
+	GetDue(today time.Time) ([]pledgeDomain.Pledge, error)
+	ClaimInstallment(p *pledgeDomain.Pledge, inst *pledgeDomain.Installment, nextDue time.Time) (bool, error)
+	Complete(id uint) error
+	GetInstallmentsWithoutDonation() ([]pledgeDomain.Installment, error)
+	AttachDonation(installmentID, donationID uint) (bool, error)
+	RefreshInstallmentStatuses(missedBefore time.Time) (fulfilled, missed int64, err error)
+	GetReminderDue() ([]ReminderDue, error)
+	MarkReminded(installmentID uint, at time.Time) error
+
+	GetInstallments(pledgeID uint) ([]pledgeDomain.Installment, error)
+	CountInstallments(pledgeID uint) (int64, error)
+	GetInstallmentTotals(pledgeID uint) ([]InstallmentTotal, error)
+}
+
+// Filter narrows pledge queries
+type Filter struct {
+	Status     *pledgeDomain.Status
+	CategoryID *uint
+	Search     string // Donor name, phone or email
+}
+
+// ReminderDue is a pending installment whose donor has not been reminded yet
+type ReminderDue struct {
+	InstallmentID uint
+	PledgeID      uint
+	Sequence      int
+	DueDate       time.Time
+	Amount        money.Money
+	DonationID    uint
+	DonorName     string
+	Phone         *string
+	Email         *string
+	CategoryName  string
+}
+
+// InstallmentTotal sums the installments of a pledge in one status
+type InstallmentTotal struct {
+	Status       pledgeDomain.InstallmentStatus `json:"status"`
+	Installments int64                          `json:"installments"`
+	Amount       money.Money                    `json:"amount"`
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new pledge repository
+func NewRepository() Repository {
+	return &repository{
+		db: database.GetDB(),
+	}
+}
+
+// Create creates a new pledge
+func (r *repository) Create(p *pledgeDomain.Pledge) error {
+	return r.db.Create(p).Error
+}
+
+// GetByID retrieves a pledge by ID with its category name
+func (r *repository) GetByID(id uint) (*pledgeDomain.Pledge, error) {
+	var p pledgeDomain.Pledge
+	if err := r.withCategory(r.db.Model(&pledgeDomain.Pledge{})).
+		Select(pledgeColumns).
+		Where("pledges.id = ?", id).
+		First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pledge not found")
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetAll retrieves pledges with pagination, soonest due first
+func (r *repository) GetAll(limit, offset int, filter *Filter) ([]pledgeDomain.Pledge, int64, error) {
+	var pledges []pledgeDomain.Pledge
+	var total int64
+
+	query := r.withCategory(r.db.Model(&pledgeDomain.Pledge{}))
+	if filter != nil {
+		if filter.Status != nil {
+			query = query.Where("pledges.status = ?", *filter.Status)
+		}
+		if filter.CategoryID != nil {
+			query = query.Where("pledges.category_id = ?", *filter.CategoryID)
+		}
+		if filter.Search != "" {
+			pattern := "%" + filter.Search + "%"
+			query = query.Where("(pledges.donor_name ILIKE ? OR pledges.phone ILIKE ? OR pledges.email ILIKE ?)",
+				pattern, pattern, pattern)
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Select(pledgeColumns).
+		Order("pledges.next_due_date ASC, pledges.id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&pledges).Error; err != nil {
+		return nil, 0, err
+	}
+	return pledges, total, nil
+}
+
+// Update updates a pledge
+func (r *repository) Update(p *pledgeDomain.Pledge) error {
+	return r.db.Save(p).Error
+}
+
+// Delete soft deletes a pledge
+func (r *repository) Delete(id uint) error {
+	return r.db.Delete(&pledgeDomain.Pledge{}, id).Error
+}
+
+// GetDue retrieves active pledges with an installment due on or before today
+func (r *repository) GetDue(today time.Time) ([]pledgeDomain.Pledge, error) {
+	var pledges []pledgeDomain.Pledge
+	if err := r.db.
+		Where("status = ? AND next_due_date <= ?", pledgeDomain.StatusActive, today).
+		Order("next_due_date ASC, id ASC").
+		Find(&pledges).Error; err != nil {
+		return nil, err
+	}
+	return pledges, nil
+}
+
+// ClaimInstallment advances the schedule of a pledge past inst and stores
+// inst in one transaction. The update is conditional on the installment count
+// read by the caller, so when two scheduler runs race only one claims the
+// installment. Returns whether this call claimed it.
+func (r *repository) ClaimInstallment(p *pledgeDomain.Pledge, inst *pledgeDomain.Installment, nextDue time.Time) (bool, error) {
+	claimed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&pledgeDomain.Pledge{}).
+			Where("id = ? AND installments_generated = ? AND status = ?",
+				p.ID, p.InstallmentsGenerated, pledgeDomain.StatusActive).
+			Updates(map[string]interface{}{
+				"installments_generated": gorm.Expr("installments_generated + 1"),
+				"next_due_date":          nextDue,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Create(inst).Error; err != nil {
+			return err
+		}
+		claimed = true
+		return nil
+	})
+	return claimed, err
+}
+
+// Complete marks an active pledge completed
+func (r *repository) Complete(id uint) error {
+	return r.db.Model(&pledgeDomain.Pledge{}).
+		Where("id = ? AND status = ?", id, pledgeDomain.StatusActive).
+		Update("status", pledgeDomain.StatusCompleted).Error
+}
+
+// GetInstallmentsWithoutDonation retrieves pending installments still
+// waiting for their donation
+func (r *repository) GetInstallmentsWithoutDonation() ([]pledgeDomain.Installment, error) {
+	var installments []pledgeDomain.Installment
+	if err := r.db.
+		Select("pledge_installments.*").
+		Joins("JOIN pledges ON pledge_installments.pledge_id = pledges.id").
+		Where("pledge_installments.donation_id IS NULL AND pledge_installments.status = ?", pledgeDomain.InstallmentPending).
+		Where("pledges.deleted_at IS NULL AND pledges.status <> ?", pledgeDomain.StatusCancelled).
+		Order("pledge_installments.due_date ASC, pledge_installments.id ASC").
+		Find(&installments).Error; err != nil {
+		return nil, err
+	}
+	return installments, nil
+}
+
+// AttachDonation links the donation created for an installment. Returns false
+// when another run attached a donation first.
+func (r *repository) AttachDonation(installmentID, donationID uint) (bool, error) {
+	result := r.db.Model(&pledgeDomain.Installment{}).
+		Where("id = ? AND donation_id IS NULL", installmentID).
+		Update("donation_id", donationID)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RefreshInstallmentStatuses derives installment statuses from their
+// donations: a successful donation fulfils the installment; a failed or
+// deleted donation, or none paid before missedBefore, misses it. A missed
+// installment is fulfilled again if its donation is paid late.
+func (r *repository) RefreshInstallmentStatuses(missedBefore time.Time) (int64, int64, error) {
+	paid := `EXISTS (
+		SELECT 1 FROM donations
+		WHERE donations.id = pledge_installments.donation_id
+			AND donations.payment_status = ? AND donations.deleted_at IS NULL)`
+	lost := `(pledge_installments.due_date < ? OR pledge_installments.donation_id IS NULL
+		OR EXISTS (
+			SELECT 1 FROM donations
+			WHERE donations.id = pledge_installments.donation_id
+				AND (donations.payment_status = ? OR donations.deleted_at IS NOT NULL)))`
+
+	var fulfilled, missed int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&pledgeDomain.Installment{}).
+			Where("status <> ?", pledgeDomain.InstallmentFulfilled).
+			Where(paid, donationDomain.PaymentStatusSuccess).
+			Update("status", pledgeDomain.InstallmentFulfilled)
+		if result.Error != nil {
+			return result.Error
+		}
+		fulfilled = result.RowsAffected
+
+		// An installment without a donation yet is only missed once it is late
+		result = tx.Model(&pledgeDomain.Installment{}).
+			Where("status <> ?", pledgeDomain.InstallmentMissed).
+			Where("NOT "+paid, donationDomain.PaymentStatusSuccess).
+			Where("(pledge_installments.donation_id IS NOT NULL OR pledge_installments.due_date < ?)", missedBefore).
+			Where(lost, missedBefore, donationDomain.PaymentStatusFailed).
+			Update("status", pledgeDomain.InstallmentMissed)
+		if result.Error != nil {
+			return result.Error
+		}
+		missed = result.RowsAffected
+		return nil
+	})
+	return fulfilled, missed, err
+}
+
+// GetReminderDue retrieves pending installments with a donation whose donor
+// has a contact and has not been reminded
+func (r *repository) GetReminderDue() ([]ReminderDue, error) {
+	var results []ReminderDue
+	if err := r.db.Model(&pledgeDomain.Installment{}).
+		Select(`
+			pledge_installments.id AS installment_id,
+			pledge_installments.pledge_id,
+			pledge_installments.sequence,
+			pledge_installments.due_date,
+			pledge_installments.amount,
+			pledge_installments.donation_id,
+			pledges.donor_name,
+			pledges.phone,
+			pledges.email,
+			donation_categories.name AS category_name
+		`).
+		Joins("JOIN pledges ON pledge_installments.pledge_id = pledges.id").
+		Joins("LEFT JOIN donation_categories ON pledges.category_id = donation_categories.id").
+		Where("pledge_installments.status = ? AND pledge_installments.reminder_sent_at IS NULL", pledgeDomain.InstallmentPending).
+		Where("pledge_installments.donation_id IS NOT NULL").
+		Where("pledges.deleted_at IS NULL AND pledges.status IN ?",
+			[]pledgeDomain.Status{pledgeDomain.StatusActive, pledgeDomain.StatusCompleted}).
+		Where("(pledges.phone IS NOT NULL OR pledges.email IS NOT NULL)").
+		Order("pledge_installments.due_date ASC, pledge_installments.id ASC").
+		Scan(&results).Error; err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// MarkReminded records that the donor was reminded of an installment
+func (r *repository) MarkReminded(installmentID uint, at time.Time) error {
+	return r.db.Model(&pledgeDomain.Installment{}).
+		Where("id = ?", installmentID).
+		Update("reminder_sent_at", at).Error
+}
+
+// GetInstallments retrieves every installment of a pledge, newest first
+func (r *repository) GetInstallments(pledgeID uint) ([]pledgeDomain.Installment, error) {
+	var installments []pledgeDomain.Installment
+	if err := r.db.Where("pledge_id = ?", pledgeID).
+		Order("sequence DESC").
+		Find(&installments).Error; err != nil {
+		return nil, err
+	}
+	return installments, nil
+}
+
+// CountInstallments counts the installments of a pledge
+func (r *repository) CountInstallments(pledgeID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&pledgeDomain.Installment{}).Where("pledge_id = ?", pledgeID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetInstallmentTotals sums the installments of a pledge per status
+func (r *repository) GetInstallmentTotals(pledgeID uint) ([]InstallmentTotal, error) {
+	var results []InstallmentTotal
+	if err := r.db.Model(&pledgeDomain.Installment{}).
+		Select("status, COUNT(*) AS installments, COALESCE(SUM(amount), 0) AS amount").
+		Where("pledge_id = ?", pledgeID).
+		Group("status").
+		Scan(&results).Error; err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// pledgeColumns selects pledges with their category name
+const pledgeColumns = "pledges.*, donation_categories.name AS category_name"
+
+// withCategory joins the donation category of pledges
+func (r *repository) withCategory(query *gorm.DB) *gorm.DB {
+	return query.Joins("LEFT JOIN donation_categories ON pledges.category_id = donation_categories.id")
+}
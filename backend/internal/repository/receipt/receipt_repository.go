@@ -0,0 +1,95 @@
+package receipt
+
+import (
+	"errors"
+	"time"
+
+	receiptDomain "github.com/madr/backend/internal/domain/receipt"
+	"github.com/madr/backend/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository defines the interface for donation receipt repository
+type Repository interface {
+	Issue(donationID uint, issuedAt time.Time) (*receiptDomain.Receipt, bool, error)
+	UpdateFileName(id uint, fileName string) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new donation receipt repository
+func NewRepository() Repository {
+	return &repository{
+		db: database.GetDB(),
+	}
+}
+
+// Issue allocates the next number of the issue year and stores the receipt in one
+// transaction. The donation row lock makes issuing idempotent per donation and the
+// counter row lock serializes allocation within a year; because the counter is a
+// plain row rather than a database sequence, a rolled back transaction releases
+// its number instead of leaving a gap. Returns whether a new receipt was created.
+func (r *repository) Issue(donationID uint, issuedAt time.Time) (*receiptDomain.Receipt, bool, error) {
+	var rcpt receiptDomain.Receipt
+	created := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var locked struct{ ID uint }
+		if err := tx.Table("donations").
+			Select("id").
+			Where("id = ? AND deleted_at IS NULL", donationID).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Take(&locked).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("donation not found")
+			}
+			return err
+		}
+
+		err := tx.Where("donation_id = ?", donationID).First(&rcpt).Error
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		year := issuedAt.Year()
+		var sequence int
+		if err := tx.Raw(`
+			INSERT INTO receipt_sequences (year, last_number) VALUES (?, 1)
+			ON CONFLICT (year) DO UPDATE SET last_number = receipt_sequences.last_number + 1
+			RETURNING last_number`, year).
+			Scan(&sequence).Error; err != nil {
+			return err
+		}
+
+		rcpt = receiptDomain.Receipt{
+			DonationID: donationID,
+			Year:       year,
+			Sequence:   sequence,
+			Number:     receiptDomain.FormatNumber(year, sequence),
+			IssuedAt:   issuedAt,
+		}
+		if err := tx.Create(&rcpt).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &rcpt, created, nil
+}
+
+// UpdateFileName records where the receipt PDF is stored
+func (r *repository) UpdateFileName(id uint, fileName string) error {
+	return r.db.Model(&receiptDomain.Receipt{}).
+		Where("id = ?", id).
+		Update("file_name", fileName).Error
+}
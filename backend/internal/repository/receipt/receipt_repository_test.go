@@ -0,0 +1,89 @@
+package receipt
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	donationCategoryDomain "github.com/madr/backend/internal/domain/donationcategory"
+	"github.com/madr/backend/internal/testutil"
+	"github.com/madr/backend/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIssue_ConcurrentNumbersAreGapFree issues receipts for many donations at
+// once, including duplicate confirmations, and checks the numbers are sequential
+func TestIssue_ConcurrentNumbersAreGapFree(t *testing.T) {
+	testutil.SetupDatabase(t)
+	db := database.GetDB()
+	repo := NewRepository()
+
+	var category donationCategoryDomain.DonationCategory
+	require.NoError(t, db.First(&category).Error)
+
+	// Use a far-future year so existing receipts do not interfere
+	issuedAt := time.Date(2999, 6, 1, 10, 0, 0, 0, time.UTC)
+	db.Exec("DELETE FROM donation_receipts WHERE year = ?", issuedAt.Year())
+	db.Exec("DELETE FROM receipt_sequences WHERE year = ?", issuedAt.Year())
+
+	const count = 20
+	ids := make([]uint, count)
+	for i := range ids {
+		don := &donationDomain.Donation{
+			CategoryID:    category.ID,
+			Amount:        10000,
+			PaymentStatus: donationDomain.PaymentStatusSuccess,
+		}
+		require.NoError(t, db.Create(don).Error)
+		ids[i] = don.ID
+	}
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM donation_receipts WHERE donation_id IN ?", ids)
+		db.Exec("DELETE FROM receipt_sequences WHERE year = ?", issuedAt.Year())
+		db.Unscoped().Delete(&donationDomain.Donation{}, ids)
+	})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	numbers := make(map[uint]map[string]bool)
+	for _, id := range ids {
+		for attempt := 0; attempt < 2; attempt++ {
+			wg.Add(1)
+			go func(id uint) {
+				defer wg.Done()
+				rcpt, isNew, err := repo.Issue(id, issuedAt)
+				if !assert.NoError(t, err) {
+					return
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if isNew {
+					created++
+				}
+				if numbers[id] == nil {
+					numbers[id] = make(map[string]bool)
+				}
+				numbers[id][rcpt.Number] = true
+			}(id)
+		}
+	}
+	wg.Wait()
+
+	assert.Equal(t, count, created)
+	for _, id := range ids {
+		assert.Len(t, numbers[id], 1, "donation %d got more than one number", id)
+	}
+
+	var sequences []int
+	require.NoError(t, db.Table("donation_receipts").
+		Where("year = ?", issuedAt.Year()).
+		Order("sequence").
+		Pluck("sequence", &sequences).Error)
+	require.Len(t, sequences, count)
+	for i, seq := range sequences {
+		assert.Equal(t, i+1, seq)
+	}
+}
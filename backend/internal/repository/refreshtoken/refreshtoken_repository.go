@@ -9,14 +9,20 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrUnavailable is returned when a token was revoked before it could be rotated
+var ErrUnavailable = errors.New("refresh token is no longer valid")
+
 // Repository defines the interface for refresh token repository
 type Repository interface {
 	Create(rt *refreshtoken.RefreshToken) error
-	GetByToken(token string) (*refreshtoken.RefreshToken, error)
+	GetByTokenHash(tokenHash string) (*refreshtoken.RefreshToken, error)
 	GetByUserID(userID uint) ([]refreshtoken.RefreshToken, error)
-	Revoke(token string) error
+	GetActiveByUserID(userID uint, now time.Time) ([]refreshtoken.RefreshToken, error)
+	Rotate(id uint, next *refreshtoken.RefreshToken) error
+	Revoke(tokenHash string) error
+	RevokeFamily(familyID string) (int64, error)
 	RevokeAllByUserID(userID uint) error
-	DeleteExpired() error
+	DeleteInactive(now time.Time) (int64, error)
 	Delete(id uint) error
 }
 
@@ -39,10 +45,10 @@ func (r *repository) Create(rt *refreshtoken.RefreshToken) error {
 	return nil
 }
 
-// GetByToken retrieves a refresh token by token string
-func (r *repository) GetByToken(token string) (*refreshtoken.RefreshToken, error) {
+// GetByTokenHash retrieves a refresh token by the hash of its value
+func (r *repository) GetByTokenHash(tokenHash string) (*refreshtoken.RefreshToken, error) {
 	var rt refreshtoken.RefreshToken
-	if err := r.db.Where("token = ?", token).First(&rt).Error; err != nil {
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&rt).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("refresh token not found")
 		}
@@ -60,11 +66,44 @@ func (r *repository) GetByUserID(userID uint) ([]refreshtoken.RefreshToken, erro
 	return tokens, nil
 }
 
+// GetActiveByUserID retrieves the unrevoked, unexpired refresh tokens of a
+// user, most recently issued first. Each is the latest token of its family.
+func (r *repository) GetActiveByUserID(userID uint, now time.Time) ([]refreshtoken.RefreshToken, error) {
+	var tokens []refreshtoken.RefreshToken
+	if err := r.db.Where("user_id = ? AND is_revoked = ? AND expires_at > ?", userID, false, now).
+		Order("created_at DESC").
+		Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Rotate revokes a refresh token and stores its replacement in one
+// transaction. Only one of several concurrent rotations of the same token
+// succeeds; the others get ErrUnavailable.
+func (r *repository) Rotate(id uint, next *refreshtoken.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&refreshtoken.RefreshToken{}).
+			Where("id = ? AND is_revoked = ?", id, false).
+			Updates(map[string]interface{}{
+				"is_revoked": true,
+				"revoked_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUnavailable
+		}
+		return tx.Create(next).Error
+	})
+}
+
 // Revoke revokes a refresh token
-func (r *repository) Revoke(token string) error {
+func (r *repository) Revoke(tokenHash string) error {
 	now := time.Now()
 	if err := r.db.Model(&refreshtoken.RefreshToken{}).
-		Where("token = ?", token).
+		Where("token_hash = ?", tokenHash).
 		Updates(map[string]interface{}{
 			"is_revoked": true,
 			"revoked_at": now,
@@ -74,6 +113,18 @@ func (r *repository) Revoke(token string) error {
 	return nil
 }
 
+// RevokeFamily revokes every token rotated from the same login and returns
+// how many were still active
+func (r *repository) RevokeFamily(familyID string) (int64, error) {
+	result := r.db.Model(&refreshtoken.RefreshToken{}).
+		Where("family_id = ? AND is_revoked = ?", familyID, false).
+		Updates(map[string]interface{}{
+			"is_revoked": true,
+			"revoked_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
 // RevokeAllByUserID revokes all refresh tokens for a user
 func (r *repository) RevokeAllByUserID(userID uint) error {
 	now := time.Now()
@@ -88,13 +139,20 @@ func (r *repository) RevokeAllByUserID(userID uint) error {
 	return nil
 }
 
-// DeleteExpired deletes expired refresh tokens
-func (r *repository) DeleteExpired() error {
-	now := time.Now()
-	if err := r.db.Where("expires_at < ?", now).Delete(&refreshtoken.RefreshToken{}).Error; err != nil {
-		return err
-	}
-	return nil
+// DeleteInactive permanently deletes tokens that have expired, and revoked
+// tokens of families without an active token. Revoked tokens of an active
+// family are kept so their reuse still revokes the family. Returns how many
+// were removed.
+func (r *repository) DeleteInactive(now time.Time) (int64, error) {
+	result := r.db.Unscoped().
+		Where("expires_at <= ?", now).
+		Or(`is_revoked = ? AND NOT EXISTS (
+			SELECT 1 FROM refresh_tokens active
+			WHERE active.family_id = refresh_tokens.family_id
+			AND active.is_revoked = ? AND active.expires_at > ? AND active.deleted_at IS NULL
+		)`, true, false, now).
+		Delete(&refreshtoken.RefreshToken{})
+	return result.RowsAffected, result.Error
 }
 
 // Delete deletes a refresh token
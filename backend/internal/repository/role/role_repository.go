@@ -0,0 +1,193 @@
+package role
+
+import (
+	"errors"
+
+	roleDomain "github.com/madr/backend/internal/domain/role"
+	"github.com/madr/backend/pkg/database"
+	"gorm.io/gorm"
+)
+
+// Repository defines the interface for role repository
+type Repository interface {
+	GetPermissions() ([]roleDomain.Permission, error)
+	GetPermissionsByCodes(codes []string) ([]roleDomain.Permission, error)
+
+	Create(r *roleDomain.Role) error
+	GetByID(id uint) (*roleDomain.Role, error)
+	GetByName(name string) (*roleDomain.Role, error)
+	GetByIDs(ids []uint) ([]roleDomain.Role, error)
+	GetAll() ([]roleDomain.Role, error)
+	Update(r *roleDomain.Role) error
+	Delete(id uint) error
+	ExistsByName(name string, excludeID uint) (bool, error)
+
+	GetUserRoles(userID uint) ([]roleDomain.Role, error)
+	SetUserRoles(userID uint, roleIDs []uint) error
+	CountActiveUsers(roleID uint) (int64, error)
+}
+
+// userRole is a row of the user_roles join table
+type userRole struct {
+	UserID uint
+	RoleID uint
+}
+
+func (userRole) TableName() string {
+	return "user_roles"
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new role repository
+func NewRepository() Repository {
+	return &repository{
+		db: database.GetDB(),
+	}
+}
+
+// GetPermissions retrieves every known permission ordered by code
+func (r *repository) GetPermissions() ([]roleDomain.Permission, error) {
+	var permissions []roleDomain.Permission
+	if err := r.db.Order("code").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// GetPermissionsByCodes retrieves the permissions with the given codes
+func (r *repository) GetPermissionsByCodes(codes []string) ([]roleDomain.Permission, error) {
+	var permissions []roleDomain.Permission
+	if len(codes) == 0 {
+		return permissions, nil
+	}
+	if err := r.db.Where("code IN ?", codes).Order("code").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// Create creates a role with its permissions
+func (r *repository) Create(role *roleDomain.Role) error {
+	return r.db.Omit("Permissions.*").Create(role).Error
+}
+
+// GetByID retrieves a role by ID with its permissions
+func (r *repository) GetByID(id uint) (*roleDomain.Role, error) {
+	var role roleDomain.Role
+	if err := r.withPermissions().First(&role, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("role not found")
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+// GetByName retrieves a role by name with its permissions
+func (r *repository) GetByName(name string) (*roleDomain.Role, error) {
+	var role roleDomain.Role
+	if err := r.withPermissions().Where("name = ?", name).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("role not found")
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+// GetByIDs retrieves the roles with the given IDs and their permissions
+func (r *repository) GetByIDs(ids []uint) ([]roleDomain.Role, error) {
+	var roles []roleDomain.Role
+	if len(ids) == 0 {
+		return roles, nil
+	}
+	if err := r.withPermissions().Where("id IN ?", ids).Order("name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// GetAll retrieves every role with its permissions ordered by name
+func (r *repository) GetAll() ([]roleDomain.Role, error) {
+	var roles []roleDomain.Role
+	if err := r.withPermissions().Order("name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// Update saves a role and replaces its permissions
+func (r *repository) Update(role *roleDomain.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Save(role).Error; err != nil {
+			return err
+		}
+		return tx.Model(role).Omit("Permissions.*").Association("Permissions").Replace(role.Permissions)
+	})
+}
+
+// Delete deletes a role; its grants and assignments are removed by cascade
+func (r *repository) Delete(id uint) error {
+	return r.db.Delete(&roleDomain.Role{}, id).Error
+}
+
+// ExistsByName checks whether another role already uses the name
+func (r *repository) ExistsByName(name string, excludeID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&roleDomain.Role{}).
+		Where("LOWER(name) = LOWER(?) AND id <> ?", name, excludeID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetUserRoles retrieves the roles assigned to a user with their permissions
+func (r *repository) GetUserRoles(userID uint) ([]roleDomain.Role, error) {
+	var roles []roleDomain.Role
+	if err := r.withPermissions().
+		Where("id IN (?)", r.db.Model(&userRole{}).Select("role_id").Where("user_id = ?", userID)).
+		Order("name").
+		Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// SetUserRoles replaces the roles assigned to a user
+func (r *repository) SetUserRoles(userID uint, roleIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&userRole{}).Error; err != nil {
+			return err
+		}
+		if len(roleIDs) == 0 {
+			return nil
+		}
+		rows := make([]userRole, len(roleIDs))
+		for i, id := range roleIDs {
+			rows[i] = userRole{UserID: userID, RoleID: id}
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// CountActiveUsers counts the active, not deleted users holding a role
+func (r *repository) CountActiveUsers(roleID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&userRole{}).
+		Joins("JOIN users ON users.id = user_roles.user_id").
+		Where("user_roles.role_id = ? AND users.is_active AND users.deleted_at IS NULL", roleID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *repository) withPermissions() *gorm.DB {
+	return r.db.Preload("Permissions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("permissions.code")
+	})
+}
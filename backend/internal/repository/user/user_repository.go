@@ -15,6 +15,7 @@ type Repository interface {
 	GetByID(id uint) (*user.User, error)
 	GetByUsername(username string) (*user.User, error)
 	GetByEmail(email string) (*user.User, error)
+	GetAll(limit, offset int, filter *Filter) ([]user.User, int64, error)
 	Update(usr *user.User) error
 	UpdateLastLogin(id uint) error
 	Delete(id uint) error
@@ -22,6 +23,13 @@ type Repository interface {
 	ExistsByEmail(email string) (bool, error)
 }
 
+// Filter narrows user queries
+type Filter struct {
+	Search   string // Username, email or name
+	IsActive *bool
+	RoleID   *uint // Assigned RBAC role
+}
+
 type repository struct {
 	db *gorm.DB
 }
@@ -77,6 +85,34 @@ func (r *repository) GetByEmail(email string) (*user.User, error) {
 	return &usr, nil
 }
 
+// GetAll retrieves users ordered by username
+func (r *repository) GetAll(limit, offset int, filter *Filter) ([]user.User, int64, error) {
+	var users []user.User
+	var total int64
+
+	query := r.db.Model(&user.User{})
+	if filter != nil {
+		if filter.Search != "" {
+			search := "%" + filter.Search + "%"
+			query = query.Where("username ILIKE ? OR email ILIKE ? OR name ILIKE ?", search, search, search)
+		}
+		if filter.IsActive != nil {
+			query = query.Where("is_active = ?", *filter.IsActive)
+		}
+		if filter.RoleID != nil {
+			query = query.Where("id IN (SELECT user_id FROM user_roles WHERE role_id = ?)", *filter.RoleID)
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("username").Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
 // Update updates an existing user
 func (r *repository) Update(usr *user.User) error {
 	if err := r.db.Save(usr).Error; err != nil {
@@ -0,0 +1,80 @@
+package usertoken
+
+import (
+	"errors"
+	"time"
+
+	"github.com/madr/backend/internal/domain/usertoken"
+	"github.com/madr/backend/pkg/database"
+	"gorm.io/gorm"
+)
+
+// ErrUnavailable is returned when a token was used or expired before it
+// could be consumed
+var ErrUnavailable = errors.New("token is no longer valid")
+
+// Repository defines the interface for user token repository
+type Repository interface {
+	Create(token *usertoken.UserToken) error
+	GetByHash(purpose usertoken.Purpose, tokenHash string) (*usertoken.UserToken, error)
+	Consume(id uint, at time.Time) error
+	InvalidateForUser(userID uint, purpose usertoken.Purpose, at time.Time) error
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new user token repository
+func NewRepository() Repository {
+	return &repository{
+		db: database.GetDB(),
+	}
+}
+
+// Create creates a new user token
+func (r *repository) Create(token *usertoken.UserToken) error {
+	return r.db.Create(token).Error
+}
+
+// GetByHash retrieves a token by purpose and hash
+func (r *repository) GetByHash(purpose usertoken.Purpose, tokenHash string) (*usertoken.UserToken, error) {
+	var token usertoken.UserToken
+	if err := r.db.Where("purpose = ? AND token_hash = ?", purpose, tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("token not found")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Consume marks an unused, unexpired token as used. Concurrent attempts with
+// the same token cannot both succeed.
+func (r *repository) Consume(id uint, at time.Time) error {
+	result := r.db.Model(&usertoken.UserToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, at).
+		Update("used_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// InvalidateForUser uses up every outstanding token a user has for a purpose
+func (r *repository) InvalidateForUser(userID uint, purpose usertoken.Purpose, at time.Time) error {
+	return r.db.Model(&usertoken.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", at).Error
+}
+
+// DeleteExpired deletes tokens that expired before the given time and
+// returns how many were removed
+func (r *repository) DeleteExpired(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&usertoken.UserToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,237 @@
+package zakat
+
+import (
+	"errors"
+	"time"
+
+	zakatDomain "github.com/madr/backend/internal/domain/zakat"
+	"github.com/madr/backend/pkg/database"
+	"github.com/madr/backend/pkg/money"
+	"gorm.io/gorm"
+)
+
+// Repository defines the interface for zakat repository
+type Repository interface {
+	GetSetting() (*zakatDomain.Setting, error)
+	UpdateSetting(setting *zakatDomain.Setting) error
+
+	CreateMuzakki(m *zakatDomain.Muzakki) error
+	GetMuzakkiByID(id uint) (*zakatDomain.Muzakki, error)
+	GetMuzakki(limit, offset int, search string) ([]zakatDomain.Muzakki, int64, error)
+	UpdateMuzakki(m *zakatDomain.Muzakki) error
+	DeleteMuzakki(id uint) error
+
+	CreatePayment(payment *zakatDomain.Payment) error
+	GetPaymentByID(id uint) (*zakatDomain.Payment, error)
+	GetPayments(limit, offset int, filter *PaymentFilter) ([]zakatDomain.Payment, int64, error)
+	DeletePayment(id uint) error
+	CountPayments(filter *PaymentFilter) (int64, error)
+	GetPaymentTotals(filter *PaymentFilter) ([]PaymentTotal, error)
+}
+
+// PaymentFilter narrows payment queries. From and To are inclusive dates.
+type PaymentFilter struct {
+	MuzakkiID *uint
+	ZakatType *zakatDomain.Type
+	HijriYear *int
+	From      *time.Time
+	To        *time.Time
+}
+
+// PaymentTotal sums payments of one zakat type and form
+type PaymentTotal struct {
+	ZakatType  zakatDomain.Type        `json:"zakat_type"`
+	Form       zakatDomain.PaymentForm `json:"form"`
+	Payments   int64                   `json:"payments"`
+	Households int64                   `json:"households"`
+	Persons    int64                   `json:"persons"`
+	RiceKg     float64                 `json:"rice_kg"`
+	Amount     money.Money             `json:"amount"`
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new zakat repository
+func NewRepository() Repository {
+	return &repository{
+		db: database.GetDB(),
+	}
+}
+
+// GetSetting retrieves the zakat settings
+func (r *repository) GetSetting() (*zakatDomain.Setting, error) {
+	var setting zakatDomain.Setting
+	if err := r.db.First(&setting, 1).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("zakat settings not found")
+		}
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// UpdateSetting saves the zakat settings
+func (r *repository) UpdateSetting(setting *zakatDomain.Setting) error {
+	setting.ID = 1
+	return r.db.Save(setting).Error
+}
+
+// CreateMuzakki creates a new muzakki household
+func (r *repository) CreateMuzakki(m *zakatDomain.Muzakki) error {
+	return r.db.Create(m).Error
+}
+
+// GetMuzakkiByID retrieves a muzakki household by ID
+func (r *repository) GetMuzakkiByID(id uint) (*zakatDomain.Muzakki, error) {
+	var m zakatDomain.Muzakki
+	if err := r.db.First(&m, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("muzakki not found")
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// GetMuzakki retrieves muzakki households with pagination, optionally
+// searching the head of household name and phone
+func (r *repository) GetMuzakki(limit, offset int, search string) ([]zakatDomain.Muzakki, int64, error) {
+	var list []zakatDomain.Muzakki
+	var total int64
+
+	query := r.db.Model(&zakatDomain.Muzakki{})
+	if search != "" {
+		pattern := "%" + search + "%"
+		query = query.Where("(head_name ILIKE ? OR phone ILIKE ?)", pattern, pattern)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("head_name ASC, id ASC").Limit(limit).Offset(offset).Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// UpdateMuzakki updates a muzakki household
+func (r *repository) UpdateMuzakki(m *zakatDomain.Muzakki) error {
+	return r.db.Save(m).Error
+}
+
+// DeleteMuzakki soft deletes a muzakki household
+func (r *repository) DeleteMuzakki(id uint) error {
+	return r.db.Delete(&zakatDomain.Muzakki{}, id).Error
+}
+
+// CreatePayment records a zakat payment
+func (r *repository) CreatePayment(payment *zakatDomain.Payment) error {
+	return r.db.Create(payment).Error
+}
+
+// GetPaymentByID retrieves a zakat payment by ID
+func (r *repository) GetPaymentByID(id uint) (*zakatDomain.Payment, error) {
+	var payment zakatDomain.Payment
+	if err := r.withMuzakki(r.db.Model(&zakatDomain.Payment{})).
+		Select(paymentColumns).
+		Where("zakat_payments.id = ?", id).
+		First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("zakat payment not found")
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// GetPayments retrieves zakat payments with pagination, newest first
+func (r *repository) GetPayments(limit, offset int, filter *PaymentFilter) ([]zakatDomain.Payment, int64, error) {
+	var payments []zakatDomain.Payment
+	var total int64
+
+	query := filter.apply(r.withMuzakki(r.db.Model(&zakatDomain.Payment{})))
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Select(paymentColumns).
+		Order("zakat_payments.paid_on DESC, zakat_payments.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&payments).Error; err != nil {
+		return nil, 0, err
+	}
+	return payments, total, nil
+}
+
+// DeletePayment permanently deletes a zakat payment
+func (r *repository) DeletePayment(id uint) error {
+	return r.db.Delete(&zakatDomain.Payment{}, id).Error
+}
+
+// CountPayments counts the payments matching the filter
+func (r *repository) CountPayments(filter *PaymentFilter) (int64, error) {
+	var count int64
+	if err := filter.apply(r.db.Model(&zakatDomain.Payment{})).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetPaymentTotals sums the payments matching the filter per zakat type and form
+func (r *repository) GetPaymentTotals(filter *PaymentFilter) ([]PaymentTotal, error) {
+	var results []PaymentTotal
+	query := filter.apply(r.db.Model(&zakatDomain.Payment{})).
+		Select(`
+			zakat_payments.zakat_type,
+			zakat_payments.form,
+			COUNT(*) AS payments,
+			COUNT(DISTINCT zakat_payments.muzakki_id) AS households,
+			COALESCE(SUM(zakat_payments.persons), 0) AS persons,
+			COALESCE(SUM(zakat_payments.rice_kg), 0) AS rice_kg,
+			COALESCE(SUM(zakat_payments.amount), 0) AS amount
+		`).
+		Group("zakat_payments.zakat_type, zakat_payments.form").
+		Order("zakat_payments.zakat_type ASC, zakat_payments.form ASC")
+
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// paymentColumns selects payments with the muzakki name
+const paymentColumns = "zakat_payments.*, muzakki.head_name AS muzakki_name"
+
+// withMuzakki joins the muzakki of payments
+func (r *repository) withMuzakki(query *gorm.DB) *gorm.DB {
+	return query.Joins("LEFT JOIN muzakki ON zakat_payments.muzakki_id = muzakki.id")
+}
+
+// apply adds the filter conditions to a payments query. Cash payments whose
+// donation was deleted are left out.
+func (f *PaymentFilter) apply(query *gorm.DB) *gorm.DB {
+	query = query.
+		Joins("LEFT JOIN donations ON zakat_payments.donation_id = donations.id").
+		Where("(zakat_payments.donation_id IS NULL OR donations.deleted_at IS NULL)")
+	if f == nil {
+		return query
+	}
+	if f.MuzakkiID != nil {
+		query = query.Where("zakat_payments.muzakki_id = ?", *f.MuzakkiID)
+	}
+	if f.ZakatType != nil {
+		query = query.Where("zakat_payments.zakat_type = ?", *f.ZakatType)
+	}
+	if f.HijriYear != nil {
+		query = query.Where("zakat_payments.hijri_year = ?", *f.HijriYear)
+	}
+	if f.From != nil {
+		query = query.Where("zakat_payments.paid_on >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("zakat_payments.paid_on <= ?", *f.To)
+	}
+	return query
+}
@@ -0,0 +1,597 @@
+package router
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/madr/backend/internal/config"
+	aboutHandler "github.com/madr/backend/internal/handler/about"
+	announcementHandler "github.com/madr/backend/internal/handler/announcement"
+	auditHandler "github.com/madr/backend/internal/handler/audit"
+	authHandler "github.com/madr/backend/internal/handler/auth"
+	bannerHandler "github.com/madr/backend/internal/handler/banner"
+	campaignHandler "github.com/madr/backend/internal/handler/campaign"
+	donationHandler "github.com/madr/backend/internal/handler/donation"
+	donationCategoryHandler "github.com/madr/backend/internal/handler/donationcategory"
+	eventHandler "github.com/madr/backend/internal/handler/event"
+	galleryHandler "github.com/madr/backend/internal/handler/gallery"
+	invitationHandler "github.com/madr/backend/internal/handler/invitation"
+	jobHandler "github.com/madr/backend/internal/handler/job"
+	kajianHandler "github.com/madr/backend/internal/handler/kajian"
+	ledgerHandler "github.com/madr/backend/internal/handler/ledger"
+	mfaHandler "github.com/madr/backend/internal/handler/mfa"
+	mustahikHandler "github.com/madr/backend/internal/handler/mustahik"
+	pledgeHandler "github.com/madr/backend/internal/handler/pledge"
+	qrisHandler "github.com/madr/backend/internal/handler/qris"
+	receiptHandler "github.com/madr/backend/internal/handler/receipt"
+	roleHandler "github.com/madr/backend/internal/handler/role"
+	sessionHandler "github.com/madr/backend/internal/handler/session"
+	uploadHandler "github.com/madr/backend/internal/handler/upload"
+	userHandler "github.com/madr/backend/internal/handler/user"
+	youtubeHandler "github.com/madr/backend/internal/handler/youtube"
+	zakatHandler "github.com/madr/backend/internal/handler/zakat"
+	"github.com/madr/backend/internal/middleware"
+	aboutRepo "github.com/madr/backend/internal/repository/about"
+	announcementRepo "github.com/madr/backend/internal/repository/announcement"
+	auditRepo "github.com/madr/backend/internal/repository/audit"
+	bannerRepo "github.com/madr/backend/internal/repository/banner"
+	campaignRepo "github.com/madr/backend/internal/repository/campaign"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	donationCategoryRepo "github.com/madr/backend/internal/repository/donationcategory"
+	eventRepo "github.com/madr/backend/internal/repository/event"
+	galleryRepo "github.com/madr/backend/internal/repository/gallery"
+	invitationRepo "github.com/madr/backend/internal/repository/invitation"
+	jobRunRepo "github.com/madr/backend/internal/repository/jobrun"
+	kajianRepo "github.com/madr/backend/internal/repository/kajian"
+	ledgerRepo "github.com/madr/backend/internal/repository/ledger"
+	loginAttemptRepo "github.com/madr/backend/internal/repository/loginattempt"
+	mfaRepo "github.com/madr/backend/internal/repository/mfa"
+	mustahikRepo "github.com/madr/backend/internal/repository/mustahik"
+	pledgeRepo "github.com/madr/backend/internal/repository/pledge"
+	receiptRepo "github.com/madr/backend/internal/repository/receipt"
+	refreshTokenRepo "github.com/madr/backend/internal/repository/refreshtoken"
+	roleRepo "github.com/madr/backend/internal/repository/role"
+	userRepo "github.com/madr/backend/internal/repository/user"
+	userTokenRepo "github.com/madr/backend/internal/repository/usertoken"
+	zakatRepo "github.com/madr/backend/internal/repository/zakat"
+	"github.com/madr/backend/internal/scheduler"
+	mailerService "github.com/madr/backend/internal/service/mailer"
+	notifierService "github.com/madr/backend/internal/service/notifier"
+	paymentService "github.com/madr/backend/internal/service/payment"
+	youtubeService "github.com/madr/backend/internal/service/youtube"
+	aboutUsecase "github.com/madr/backend/internal/usecase/about"
+	accountUsecase "github.com/madr/backend/internal/usecase/account"
+	announcementUsecase "github.com/madr/backend/internal/usecase/announcement"
+	auditUsecase "github.com/madr/backend/internal/usecase/audit"
+	authUsecase "github.com/madr/backend/internal/usecase/auth"
+	bannerUsecase "github.com/madr/backend/internal/usecase/banner"
+	campaignUsecase "github.com/madr/backend/internal/usecase/campaign"
+	donationUsecase "github.com/madr/backend/internal/usecase/donation"
+	donationCategoryUsecase "github.com/madr/backend/internal/usecase/donationcategory"
+	eventUsecase "github.com/madr/backend/internal/usecase/event"
+	galleryUsecase "github.com/madr/backend/internal/usecase/gallery"
+	invitationUsecase "github.com/madr/backend/internal/usecase/invitation"
+	jobUsecase "github.com/madr/backend/internal/usecase/job"
+	kajianUsecase "github.com/madr/backend/internal/usecase/kajian"
+	ledgerUsecase "github.com/madr/backend/internal/usecase/ledger"
+	lockoutUsecase "github.com/madr/backend/internal/usecase/lockout"
+	mfaUsecase "github.com/madr/backend/internal/usecase/mfa"
+	mustahikUsecase "github.com/madr/backend/internal/usecase/mustahik"
+	pledgeUsecase "github.com/madr/backend/internal/usecase/pledge"
+	qrisUsecase "github.com/madr/backend/internal/usecase/qris"
+	receiptUsecase "github.com/madr/backend/internal/usecase/receipt"
+	roleUsecase "github.com/madr/backend/internal/usecase/role"
+	sessionUsecase "github.com/madr/backend/internal/usecase/session"
+	userUsecase "github.com/madr/backend/internal/usecase/user"
+	zakatUsecase "github.com/madr/backend/internal/usecase/zakat"
+	"github.com/madr/backend/pkg/database"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// Handlers groups every HTTP handler served by the API
+type Handlers struct {
+	Auth             *authHandler.Handler
+	Announcement     *announcementHandler.Handler
+	Event            *eventHandler.Handler
+	Gallery          *galleryHandler.Handler
+	Banner           *bannerHandler.Handler
+	DonationCategory *donationCategoryHandler.Handler
+	Donation         *donationHandler.Handler
+	Campaign         *campaignHandler.Handler
+	QRIS             *qrisHandler.Handler
+	Receipt          *receiptHandler.Handler
+	Ledger           *ledgerHandler.Handler
+	Zakat            *zakatHandler.Handler
+	Mustahik         *mustahikHandler.Handler
+	Pledge           *pledgeHandler.Handler
+	About            *aboutHandler.Handler
+	Kajian           *kajianHandler.Handler
+	YouTube          *youtubeHandler.Handler
+	Upload           *uploadHandler.Handler
+	Audit            *auditHandler.Handler
+	Role             *roleHandler.Handler
+	User             *userHandler.Handler
+	Invitation       *invitationHandler.Handler
+	MFA              *mfaHandler.Handler
+	Session          *sessionHandler.Handler
+	Job              *jobHandler.Handler
+
+	// Permissions resolves the permissions granted by the roles in a token
+	Permissions middleware.PermissionChecker
+
+	// Auditor records the mutations made through the admin routes
+	Auditor middleware.Auditor
+
+	// Jobs are the background jobs to start alongside the server
+	Jobs []scheduler.Job
+
+	// JobStore records job runs and keeps replicas from running a job twice
+	JobStore scheduler.Store
+}
+
+// NewHandlers wires repositories, use cases and handlers together.
+// The database must be connected before calling this.
+func NewHandlers() *Handlers {
+	// Repositories
+	userRepository := userRepo.NewRepository()
+	refreshTokenRepository := refreshTokenRepo.NewRepository()
+	announcementRepository := announcementRepo.NewRepository()
+	eventRepository := eventRepo.NewRepository()
+	galleryRepository := galleryRepo.NewRepository()
+	bannerRepository := bannerRepo.NewRepository()
+	donationCategoryRepository := donationCategoryRepo.NewRepository()
+	donationRepository := donationRepo.NewRepository()
+	aboutRepository := aboutRepo.NewRepository()
+	kajianRepository := kajianRepo.NewRepository()
+	receiptRepository := receiptRepo.NewRepository()
+	ledgerRepository := ledgerRepo.NewRepository()
+	zakatRepository := zakatRepo.NewRepository()
+	mustahikRepository := mustahikRepo.NewRepository()
+	pledgeRepository := pledgeRepo.NewRepository()
+	campaignRepository := campaignRepo.NewRepository()
+	auditRepository := auditRepo.NewRepository()
+	roleRepository := roleRepo.NewRepository()
+	invitationRepository := invitationRepo.NewRepository()
+	userTokenRepository := userTokenRepo.NewRepository()
+	mfaRepository := mfaRepo.NewRepository()
+	loginAttemptRepository := loginAttemptRepo.NewRepository()
+	jobRunRepository := jobRunRepo.NewRepository()
+
+	// Services
+	ytService := youtubeService.NewService()
+	payments := newPaymentRegistry()
+	notifier := newNotifier()
+	mailer := newMailer()
+
+	// Use cases
+	roleUC := roleUsecase.NewUseCase(roleRepository, userRepository)
+	invitationUC := invitationUsecase.NewUseCase(invitationRepository, roleUC, config.AppConfig.Auth.InviteExpiry)
+	lockoutUC := lockoutUsecase.NewUseCase(loginAttemptRepository, lockoutUsecase.Options{
+		MaxAttempts:      config.AppConfig.Auth.LoginMaxAttempts,
+		MaxAttemptsPerIP: config.AppConfig.Auth.LoginMaxAttemptsIP,
+		LockoutDuration:  config.AppConfig.Auth.LoginLockout,
+		BackoffBase:      config.AppConfig.Auth.LoginBackoffBase,
+	})
+	mfaUC := mfaUsecase.NewUseCase(mfaRepository, userRepository, roleUC, lockoutUC, mfaUsecase.Options{
+		Issuer:           config.AppConfig.Auth.MFAIssuer,
+		RequiredForAdmin: config.AppConfig.Auth.MFARequiredForAdmin,
+	})
+	authUC := authUsecase.NewUseCase(userRepository, refreshTokenRepository, roleUC, invitationUC, mfaUC, lockoutUC, authUsecase.RegistrationMode(config.AppConfig.Auth.RegistrationMode))
+	accountUC := accountUsecase.NewUseCase(userRepository, userTokenRepository, refreshTokenRepository, mailer, accountUsecase.Options{
+		AppURL:       config.AppConfig.Mail.AppURL,
+		ResetExpiry:  config.AppConfig.Auth.ResetExpiry,
+		VerifyExpiry: config.AppConfig.Auth.VerifyExpiry,
+	})
+	sessionUC := sessionUsecase.NewUseCase(refreshTokenRepository, userRepository)
+	userUC := userUsecase.NewUseCase(userRepository, refreshTokenRepository, roleUC, lockoutUC)
+	announcementUC := announcementUsecase.NewUseCase(announcementRepository)
+	eventUC := eventUsecase.NewUseCase(eventRepository)
+	galleryUC := galleryUsecase.NewUseCase(galleryRepository)
+	bannerUC := bannerUsecase.NewUseCase(bannerRepository)
+	donationCategoryUC := donationCategoryUsecase.NewUseCase(donationCategoryRepository)
+	receiptUC := receiptUsecase.NewUseCase(receiptRepository, donationRepository, aboutRepository, receiptUsecase.NewUploadStorage())
+	ledgerUC := ledgerUsecase.NewUseCase(ledgerRepository)
+	campaignUC := campaignUsecase.NewUseCase(campaignRepository, donationCategoryRepository)
+	donationUC := donationUsecase.NewUseCase(donationRepository, payments, receiptUC, ledgerUC, campaignUC)
+	zakatUC := zakatUsecase.NewUseCase(zakatRepository, donationCategoryRepository, donationRepository, donationUC)
+	mustahikUC := mustahikUsecase.NewUseCase(mustahikRepository, donationCategoryRepository, donationRepository)
+	pledgeUC := pledgeUsecase.NewUseCase(pledgeRepository, donationCategoryRepository, donationUC, notifier, config.AppConfig.Pledge.GraceDays)
+	qrisUC := qrisUsecase.NewUseCase(donationUC, donationRepository, donationCategoryRepository, config.AppConfig.Payment.QRISMerchant)
+	aboutUC := aboutUsecase.NewUseCase(aboutRepository)
+	kajianUC := kajianUsecase.NewUseCase(kajianRepository, ytService)
+	auditUC := auditUsecase.NewUseCase(auditRepository, map[string]auditUsecase.SnapshotFunc{
+		"announcements":       auditUsecase.Snapshotter(announcementUC.GetByID),
+		"events":              auditUsecase.Snapshotter(eventUC.GetByID),
+		"gallery":             auditUsecase.Snapshotter(galleryUC.GetByID),
+		"banners":             auditUsecase.Snapshotter(bannerUC.GetByID),
+		"donation-categories": auditUsecase.Snapshotter(donationCategoryUC.GetByID),
+		"donations":           auditUsecase.Snapshotter(donationUC.GetByID),
+		"campaigns":           auditUsecase.Snapshotter(campaignUC.GetByID),
+		"ledger.entries":      auditUsecase.Snapshotter(ledgerUC.GetEntry),
+		"zakat.muzakki":       auditUsecase.Snapshotter(zakatUC.GetMuzakki),
+		"mustahik":            auditUsecase.Snapshotter(mustahikUC.GetMustahik),
+		"distributions":       auditUsecase.Snapshotter(mustahikUC.GetDistribution),
+		"pledges":             auditUsecase.Snapshotter(pledgeUC.GetByID),
+		"kajian":              auditUsecase.Snapshotter(kajianUC.GetByID),
+		"roles":               auditUsecase.Snapshotter(roleUC.GetByID),
+		"users":               auditUsecase.Snapshotter(userUC.GetByID),
+		"invitations":         auditUsecase.Snapshotter(invitationUC.GetByID),
+		"about": func(uint) (interface{}, error) {
+			return aboutUC.Get()
+		},
+	})
+
+	jobs := []scheduler.Job{{
+		Name:     "refresh-token-cleanup",
+		Schedule: config.AppConfig.Scheduler.RefreshTokenCleanup,
+		Run: func(now time.Time) error {
+			_, err := refreshTokenRepository.DeleteInactive(now)
+			return err
+		},
+	}, {
+		Name:     "job-run-cleanup",
+		Schedule: "@daily",
+		Run: func(now time.Time) error {
+			_, err := jobRunRepository.DeleteBefore(now.Add(-config.AppConfig.Scheduler.RunRetention))
+			return err
+		},
+	}, {
+		Name:     "campaign-close",
+		Interval: config.AppConfig.Campaign.CloseInterval,
+		Run: func(now time.Time) error {
+			_, err := campaignUC.CloseExpired(now)
+			return err
+		},
+	}, {
+		Name:     "user-token-cleanup",
+		Schedule: "@daily",
+		Run: func(now time.Time) error {
+			_, err := accountUC.DeleteExpired(now)
+			return err
+		},
+	}, {
+		Name:     "login-attempt-cleanup",
+		Interval: config.AppConfig.Auth.LoginLockout,
+		Run: func(now time.Time) error {
+			_, err := lockoutUC.DeleteStale(now)
+			return err
+		},
+	}}
+	if config.AppConfig.Pledge.SchedulerEnabled {
+		jobs = append(jobs, scheduler.Job{
+			Name:     "pledge",
+			Interval: config.AppConfig.Pledge.SchedulerInterval,
+			Run: func(now time.Time) error {
+				_, err := pledgeUC.RunScheduler(now)
+				return err
+			},
+		})
+	}
+	jobUC := jobUsecase.NewUseCase(jobRunRepository, jobs)
+
+	return &Handlers{
+		Auth:             authHandler.NewHandler(authUC, accountUC),
+		Announcement:     announcementHandler.NewHandler(announcementUC),
+		Event:            eventHandler.NewHandler(eventUC),
+		Gallery:          galleryHandler.NewHandler(galleryUC),
+		Banner:           bannerHandler.NewHandler(bannerUC),
+		DonationCategory: donationCategoryHandler.NewHandler(donationCategoryUC),
+		Donation:         donationHandler.NewHandler(donationUC),
+		Campaign:         campaignHandler.NewHandler(campaignUC),
+		QRIS:             qrisHandler.NewHandler(qrisUC),
+		Receipt:          receiptHandler.NewHandler(receiptUC),
+		Ledger:           ledgerHandler.NewHandler(ledgerUC),
+		Zakat:            zakatHandler.NewHandler(zakatUC),
+		Mustahik:         mustahikHandler.NewHandler(mustahikUC),
+		Pledge:           pledgeHandler.NewHandler(pledgeUC),
+		About:            aboutHandler.NewHandler(aboutUC),
+		Kajian:           kajianHandler.NewHandler(kajianUC),
+		YouTube:          youtubeHandler.NewHandler(),
+		Upload:           uploadHandler.NewHandler(),
+		Audit:            auditHandler.NewHandler(auditUC),
+		Role:             roleHandler.NewHandler(roleUC),
+		User:             userHandler.NewHandler(userUC),
+		Invitation:       invitationHandler.NewHandler(invitationUC),
+		MFA:              mfaHandler.NewHandler(mfaUC),
+		Session:          sessionHandler.NewHandler(sessionUC),
+		Job:              jobHandler.NewHandler(jobUC),
+		Permissions:      roleUC,
+		Auditor:          auditUC,
+		Jobs:             jobs,
+		JobStore:         jobRunRepository,
+	}
+}
+
+// Setup creates the Gin engine with global middleware and all API routes
+func Setup(h *Handlers) *gin.Engine {
+	r := newEngine(config.AppConfig.Server.TrustedProxies)
+
+	// Global middleware
+	r.Use(gin.Logger())
+	r.Use(middleware.ErrorHandler())
+	r.Use(corsMiddleware())
+	r.Use(middleware.RateLimiter())
+
+	// Health check
+	r.GET("/health", healthCheck)
+	r.GET("/api/health", healthCheck)
+
+	// Static file serving for uploaded media
+	r.Static("/uploads", config.AppConfig.Upload.UploadPath)
+
+	api := r.Group("/api/v1")
+
+	// Public routes
+	api.GET("/announcements/published", h.Announcement.GetPublished)
+	api.GET("/announcements/:id", h.Announcement.GetByID)
+	api.GET("/events", h.Event.GetAll)
+	api.GET("/events/:id", h.Event.GetByID)
+	api.GET("/gallery", h.Gallery.GetAll)
+	api.GET("/banners", h.Banner.GetAll)
+	api.GET("/banners/:id", h.Banner.GetByID)
+	api.GET("/donations/summary", h.Donation.GetSummary)
+	api.POST("/donations/checkout", middleware.OptionalAuthMiddleware(), h.Donation.Checkout)
+	api.POST("/donations/webhook/:provider", h.Donation.Webhook)
+	api.POST("/donations/qris", middleware.OptionalAuthMiddleware(), h.QRIS.Generate)
+	api.GET("/donations/qris/:reference", h.QRIS.GetImage)
+	api.GET("/donations/:id/receipt", h.Receipt.Download)
+	api.GET("/campaigns", h.Campaign.GetAll)
+	api.GET("/campaigns/:id", h.Campaign.GetByID)
+	api.GET("/ledger/weekly-report", h.Ledger.GetWeeklyReport)
+	api.GET("/zakat/rates", h.Zakat.GetRates)
+	api.GET("/zakat/calculator/fitrah", h.Zakat.CalculateFitrah)
+	api.POST("/zakat/calculator/mal", h.Zakat.CalculateMal)
+	api.GET("/about", h.About.Get)
+	api.GET("/kajian", h.Kajian.GetAll)
+	api.GET("/kajian/:id", h.Kajian.GetByID)
+	api.GET("/youtube/kajian", h.YouTube.GetKajianVideos)
+
+	// Auth routes
+	auth := api.Group("/auth")
+	{
+		auth.POST("/register", h.Auth.Register)
+		auth.POST("/login", h.Auth.Login)
+		auth.POST("/login/2fa", h.Auth.LoginMFA)
+		auth.POST("/2fa/setup", h.Auth.SetupMFA)
+		auth.POST("/2fa/setup/confirm", h.Auth.ConfirmMFASetup)
+		auth.POST("/refresh", h.Auth.RefreshToken)
+		auth.POST("/logout", h.Auth.Logout)
+		auth.POST("/forgot-password", h.Auth.ForgotPassword)
+		auth.POST("/reset-password", h.Auth.ResetPassword)
+		auth.POST("/verify-email", h.Auth.VerifyEmail)
+
+		protected := auth.Group("")
+		protected.Use(middleware.AuthMiddleware())
+		protected.GET("/me", h.Auth.GetMe)
+		protected.PUT("/me", h.Auth.UpdateMe)
+		protected.POST("/change-password", h.Auth.ChangePassword)
+		protected.POST("/logout-all", h.Auth.LogoutAll)
+		protected.POST("/resend-verification", h.Auth.ResendVerification)
+		protected.GET("/2fa", h.MFA.GetStatus)
+		protected.POST("/2fa/enroll", h.MFA.Enroll)
+		protected.POST("/2fa/confirm", h.MFA.Confirm)
+		protected.POST("/2fa/disable", h.MFA.Disable)
+		protected.POST("/2fa/recovery-codes", h.MFA.RegenerateRecoveryCodes)
+		protected.GET("/sessions", h.Session.List)
+		protected.DELETE("/sessions/:id", h.Session.Revoke)
+	}
+
+	// Donor routes (JWT)
+	me := api.Group("/me")
+	me.Use(middleware.AuthMiddleware())
+	{
+		me.GET("/donations", h.Donation.GetMyDonations)
+		me.POST("/donations/claim", h.Donation.ClaimDonation)
+	}
+
+	// Admin routes (JWT + role permissions)
+	admin := api.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.AuditMiddleware(h.Auditor))
+	can := func(permission string) gin.HandlerFunc {
+		return middleware.PermissionMiddleware(h.Permissions, permission)
+	}
+	{
+		admin.GET("/audit-logs", can("audit:read"), h.Audit.GetAll)
+
+		admin.GET("/jobs", can("job:read"), h.Job.GetAll)
+		admin.GET("/jobs/runs", can("job:read"), h.Job.GetRuns)
+
+		admin.GET("/permissions", can("role:read"), h.Role.GetPermissions)
+		admin.GET("/roles", can("role:read"), h.Role.GetAll)
+		admin.GET("/roles/:id", can("role:read"), h.Role.GetByID)
+		admin.POST("/roles", can("role:write"), h.Role.Create)
+		admin.PUT("/roles/:id", can("role:write"), h.Role.Update)
+		admin.DELETE("/roles/:id", can("role:delete"), h.Role.Delete)
+		admin.GET("/users", can("user:read"), h.User.GetAll)
+		admin.GET("/users/:id", can("user:read"), h.User.GetByID)
+		admin.POST("/users", can("user:write"), h.User.Create)
+		admin.PUT("/users/:id", can("user:write"), h.User.Update)
+		admin.PUT("/users/:id/status", can("user:write"), h.User.SetStatus)
+		admin.POST("/users/:id/reset-password", can("user:write"), h.User.ResetPassword)
+		admin.POST("/users/:id/unlock", can("user:write"), h.User.Unlock)
+		admin.DELETE("/users/:id", can("user:delete"), h.User.Delete)
+		admin.GET("/users/:id/roles", can("user:read"), h.Role.GetUserRoles)
+		admin.GET("/users/:id/sessions", can("user:read"), h.Session.GetByUser)
+		admin.PUT("/users/:id/roles", can("user:write"), h.Role.SetUserRoles)
+
+		admin.GET("/invitations", can("invitation:read"), h.Invitation.GetAll)
+		admin.POST("/invitations", can("invitation:write"), h.Invitation.Create)
+		admin.DELETE("/invitations/:id", can("invitation:write"), h.Invitation.Revoke)
+
+		admin.POST("/announcements", can("announcement:write"), h.Announcement.Create)
+		admin.GET("/announcements", can("announcement:read"), h.Announcement.GetAll)
+		admin.PUT("/announcements/:id", can("announcement:write"), h.Announcement.Update)
+		admin.DELETE("/announcements/:id", can("announcement:delete"), h.Announcement.Delete)
+
+		admin.POST("/events", can("event:write"), h.Event.Create)
+		admin.PUT("/events/:id", can("event:write"), h.Event.Update)
+		admin.DELETE("/events/:id", can("event:delete"), h.Event.Delete)
+
+		admin.POST("/gallery", can("gallery:write"), h.Gallery.Create)
+		admin.DELETE("/gallery/:id", can("gallery:delete"), h.Gallery.Delete)
+
+		admin.POST("/banners", can("banner:write"), h.Banner.Create)
+		admin.PUT("/banners/:id", can("banner:write"), h.Banner.Update)
+		admin.DELETE("/banners/:id", can("banner:delete"), h.Banner.Delete)
+
+		admin.POST("/upload", can("upload:write"), h.Upload.UploadFile)
+
+		admin.GET("/donation-categories", can("donation:read"), h.DonationCategory.GetAll)
+		admin.GET("/donation-categories/:id", can("donation:read"), h.DonationCategory.GetByID)
+		admin.POST("/donation-categories", can("donation:write"), h.DonationCategory.Create)
+		admin.PUT("/donation-categories/:id", can("donation:write"), h.DonationCategory.Update)
+		admin.DELETE("/donation-categories/:id", can("donation:delete"), h.DonationCategory.Delete)
+
+		admin.GET("/donations", can("donation:read"), h.Donation.GetAll)
+		admin.GET("/donations/reports", can("donation:read"), h.Donation.GetReport)
+		admin.GET("/donations/export", can("donation:read"), h.Donation.Export)
+		admin.GET("/donations/summary/export", can("donation:read"), h.Donation.ExportSummary)
+		admin.GET("/donations/:id", can("donation:read"), h.Donation.GetByID)
+		admin.GET("/donations/:id/history", can("donation:read"), h.Donation.GetHistory)
+		admin.POST("/donations", can("donation:write"), h.Donation.Create)
+		admin.PUT("/donations/:id", can("donation:write"), h.Donation.Update)
+		admin.DELETE("/donations/:id", can("donation:delete"), h.Donation.Delete)
+		admin.POST("/donations/:id/sync-payment", can("donation:write"), h.Donation.SyncPayment)
+		admin.GET("/donations/:id/receipt", can("donation:read"), h.Receipt.AdminDownload)
+
+		admin.GET("/campaigns", can("campaign:read"), h.Campaign.GetAll)
+		admin.GET("/campaigns/:id", can("campaign:read"), h.Campaign.GetByID)
+		admin.POST("/campaigns", can("campaign:write"), h.Campaign.Create)
+		admin.PUT("/campaigns/:id", can("campaign:write"), h.Campaign.Update)
+		admin.DELETE("/campaigns/:id", can("campaign:delete"), h.Campaign.Delete)
+
+		admin.GET("/ledger/accounts", can("ledger:read"), h.Ledger.GetAccounts)
+		admin.POST("/ledger/accounts", can("ledger:write"), h.Ledger.CreateAccount)
+		admin.PUT("/ledger/accounts/:id", can("ledger:write"), h.Ledger.UpdateAccount)
+		admin.DELETE("/ledger/accounts/:id", can("ledger:delete"), h.Ledger.DeleteAccount)
+		admin.GET("/ledger/categories", can("ledger:read"), h.Ledger.GetCategories)
+		admin.POST("/ledger/categories", can("ledger:write"), h.Ledger.CreateCategory)
+		admin.PUT("/ledger/categories/:id", can("ledger:write"), h.Ledger.UpdateCategory)
+		admin.DELETE("/ledger/categories/:id", can("ledger:delete"), h.Ledger.DeleteCategory)
+		admin.GET("/ledger/entries", can("ledger:read"), h.Ledger.GetEntries)
+		admin.GET("/ledger/entries/:id", can("ledger:read"), h.Ledger.GetEntry)
+		admin.POST("/ledger/entries", can("ledger:write"), h.Ledger.CreateEntry)
+		admin.PUT("/ledger/entries/:id", can("ledger:write"), h.Ledger.UpdateEntry)
+		admin.DELETE("/ledger/entries/:id", can("ledger:delete"), h.Ledger.DeleteEntry)
+		admin.GET("/ledger/balance", can("ledger:read"), h.Ledger.GetBalance)
+
+		admin.GET("/zakat/settings", can("zakat:read"), h.Zakat.GetRates)
+		admin.PUT("/zakat/settings", can("zakat:write"), h.Zakat.UpdateSetting)
+		admin.GET("/zakat/muzakki", can("zakat:read"), h.Zakat.GetMuzakkiList)
+		admin.GET("/zakat/muzakki/:id", can("zakat:read"), h.Zakat.GetMuzakki)
+		admin.POST("/zakat/muzakki", can("zakat:write"), h.Zakat.CreateMuzakki)
+		admin.PUT("/zakat/muzakki/:id", can("zakat:write"), h.Zakat.UpdateMuzakki)
+		admin.DELETE("/zakat/muzakki/:id", can("zakat:delete"), h.Zakat.DeleteMuzakki)
+		admin.GET("/zakat/payments", can("zakat:read"), h.Zakat.GetPayments)
+		admin.POST("/zakat/payments", can("zakat:write"), h.Zakat.RecordPayment)
+		admin.DELETE("/zakat/payments/:id", can("zakat:delete"), h.Zakat.DeletePayment)
+		admin.GET("/zakat/report", can("zakat:read"), h.Zakat.GetReport)
+
+		admin.GET("/mustahik", can("mustahik:read"), h.Mustahik.GetMustahikList)
+		admin.GET("/mustahik/:id", can("mustahik:read"), h.Mustahik.GetMustahik)
+		admin.POST("/mustahik", can("mustahik:write"), h.Mustahik.CreateMustahik)
+		admin.PUT("/mustahik/:id", can("mustahik:write"), h.Mustahik.UpdateMustahik)
+		admin.PUT("/mustahik/:id/verification", can("mustahik:write"), h.Mustahik.VerifyMustahik)
+		admin.DELETE("/mustahik/:id", can("mustahik:delete"), h.Mustahik.DeleteMustahik)
+		admin.GET("/distributions", can("mustahik:read"), h.Mustahik.GetDistributions)
+		admin.GET("/distributions/report", can("mustahik:read"), h.Mustahik.GetReport)
+		admin.GET("/distributions/:id", can("mustahik:read"), h.Mustahik.GetDistribution)
+		admin.POST("/distributions", can("mustahik:write"), h.Mustahik.CreateDistribution)
+		admin.DELETE("/distributions/:id", can("mustahik:delete"), h.Mustahik.DeleteDistribution)
+
+		admin.GET("/pledges", can("pledge:read"), h.Pledge.GetAll)
+		admin.POST("/pledges/run", can("pledge:write"), h.Pledge.RunScheduler)
+		admin.GET("/pledges/:id", can("pledge:read"), h.Pledge.GetByID)
+		admin.GET("/pledges/:id/installments", can("pledge:read"), h.Pledge.GetInstallments)
+		admin.POST("/pledges", can("pledge:write"), h.Pledge.Create)
+		admin.PUT("/pledges/:id", can("pledge:write"), h.Pledge.Update)
+		admin.DELETE("/pledges/:id", can("pledge:delete"), h.Pledge.Delete)
+
+		admin.GET("/about", can("about:read"), h.About.Get)
+		admin.PUT("/about", can("about:write"), h.About.Update)
+
+		admin.POST("/kajian/sync", can("kajian:write"), h.Kajian.SyncFromYouTube)
+		admin.DELETE("/kajian/:id", can("kajian:delete"), h.Kajian.Delete)
+	}
+
+	return r
+}
+
+// newPaymentRegistry registers the configured payment providers
+func newPaymentRegistry() *paymentService.Registry {
+	cfg := config.AppConfig.Payment
+	providers := []paymentService.Provider{paymentService.NewMidtransProvider()}
+	if cfg.FakeEnabled {
+		logger.Warn().Msg("Fake payment provider is enabled, do not use in production")
+		providers = append(providers, paymentService.NewFakeProvider(cfg.FakeSecret))
+	}
+	return paymentService.NewRegistry(cfg.DefaultProvider, providers...)
+}
+
+// newNotifier builds the configured notification channel
+func newNotifier() notifierService.Notifier {
+	cfg := config.AppConfig.Notifier
+	if cfg.Provider == notifierService.WebhookName {
+		return notifierService.NewWebhookNotifier(cfg.WebhookURL, cfg.WebhookSecret)
+	}
+	return notifierService.NewLogNotifier()
+}
+
+// newMailer builds the configured email transport
+func newMailer() mailerService.Mailer {
+	cfg := config.AppConfig.Mail
+	if cfg.Provider == mailerService.SMTPName {
+		return mailerService.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From)
+	}
+	return mailerService.NewFileMailer(cfg.FileDir, cfg.From)
+}
+
+// corsMiddleware builds the CORS middleware from configuration
+func corsMiddleware() gin.HandlerFunc {
+	cfg := config.AppConfig.CORS
+
+	logger.Info().
+		Strs("allowed_origins", cfg.AllowedOrigins).
+		Msg("CORS configuration loaded")
+
+	return cors.New(cors.Config{
+		AllowOrigins:     cfg.AllowedOrigins,
+		AllowMethods:     cfg.AllowedMethods,
+		AllowHeaders:     cfg.AllowedHeaders,
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition"},
+		AllowCredentials: true,
+	})
+}
+
+// healthCheck handles GET /health
+// newEngine creates a Gin engine that only believes X-Forwarded-For from the
+// given proxies. Otherwise clients could pick their own IP and dodge the per-IP
+// login lockout or lock out someone else.
+func newEngine(trustedProxies []string) *gin.Engine {
+	r := gin.New()
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		logger.Error().Err(err).Strs("trusted_proxies", trustedProxies).Msg("Invalid trusted proxies, trusting none")
+		_ = r.SetTrustedProxies(nil)
+	}
+	return r
+}
+
+func healthCheck(c *gin.Context) {
+	if err := database.HealthCheck(); err != nil {
+		logger.Error().Err(err).Msg("Health check failed")
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "unhealthy",
+			"service": "Masjid Management System API",
+			"version": "1.0.0",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "healthy",
+		"service": "Masjid Management System API",
+		"version": "1.0.0",
+	})
+}
@@ -0,0 +1,144 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/madr/backend/internal/config"
+	"github.com/madr/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestRouter boots the full router against the database named by
+// TEST_DB_NAME. The test is skipped when no test database is configured.
+func setupTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	t.Setenv("UPLOAD_PATH", t.TempDir())
+	testutil.SetupDatabase(t)
+	gin.SetMode(gin.TestMode)
+
+	return Setup(NewHandlers())
+}
+
+func doRequest(r http.Handler, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestRouter_EndToEnd exercises public, auth and admin routes through the real stack
+func TestRouter_EndToEnd(t *testing.T) {
+	r := setupTestRouter(t)
+
+	// Health check
+	w := doRequest(r, http.MethodGet, "/health", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = doRequest(r, http.MethodGet, "/api/health", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Public routes
+	w = doRequest(r, http.MethodGet, "/api/v1/donations/summary", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = doRequest(r, http.MethodGet, "/api/v1/announcements/published", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Admin routes require a token
+	w = doRequest(r, http.MethodGet, "/api/v1/admin/donations", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Login as the seeded admin
+	w = doRequest(r, http.MethodPost, "/api/v1/auth/login", "", map[string]string{
+		"username": "admin",
+		"password": "admin123",
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var login struct {
+		Data struct {
+			AccessToken string `json:"access_token"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &login))
+	require.NotEmpty(t, login.Data.AccessToken)
+
+	w = doRequest(r, http.MethodGet, "/api/v1/auth/me", login.Data.AccessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doRequest(r, http.MethodGet, "/api/v1/admin/donations", login.Data.AccessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doRequest(r, http.MethodGet, "/api/v1/admin/donation-categories", login.Data.AccessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doRequest(r, http.MethodGet, "/api/v1/admin/audit-logs?entity_type=donations", login.Data.AccessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doRequest(r, http.MethodGet, "/api/v1/admin/users?search=admin", login.Data.AccessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// TestRouter_ServesUploads checks that files in the upload path are served statically
+func TestRouter_ServesUploads(t *testing.T) {
+	r := setupTestRouter(t)
+
+	name := "e2e_test.txt"
+	require.NoError(t, os.WriteFile(filepath.Join(config.AppConfig.Upload.UploadPath, name), []byte("ok"), 0644))
+
+	w := doRequest(r, http.MethodGet, "/uploads/"+name, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", w.Body.String())
+}
+
+// TestNewEngine_ClientIP checks that X-Forwarded-For is only believed from trusted proxies
+func TestNewEngine_ClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clientIP := func(r *gin.Engine, remoteAddr, forwardedFor string) string {
+		r.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	// By default a forged header cannot move the client into another IP bucket
+	assert.Equal(t, "203.0.113.7", clientIP(newEngine(nil), "203.0.113.7:51000", "198.51.100.1"))
+
+	// Behind a configured proxy the forwarded client IP is used
+	assert.Equal(t, "198.51.100.1", clientIP(newEngine([]string{"10.0.0.0/8"}), "10.0.0.2:51000", "198.51.100.1"))
+	assert.Equal(t, "203.0.113.7", clientIP(newEngine([]string{"10.0.0.0/8"}), "203.0.113.7:51000", "198.51.100.1"))
+
+	// An invalid setting trusts no proxy
+	assert.Equal(t, "10.0.0.2", clientIP(newEngine([]string{"not-an-ip"}), "10.0.0.2:51000", "198.51.100.1"))
+}
+
+// TestNewHandlers_Jobs checks that every cleanup job is registered
+func TestNewHandlers_Jobs(t *testing.T) {
+	setupTestRouter(t)
+	handlers := NewHandlers()
+
+	names := make([]string, 0, len(handlers.Jobs))
+	for _, job := range handlers.Jobs {
+		names = append(names, job.Name)
+	}
+	assert.Subset(t, names, []string{"refresh-token-cleanup", "job-run-cleanup", "login-attempt-cleanup", "user-token-cleanup"})
+}
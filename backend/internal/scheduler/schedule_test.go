@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/madr/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func wib(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, utils.Jakarta)
+}
+
+// TestParseSchedule_Next tests the next run of cron expressions in Jakarta time
+func TestParseSchedule_Next(t *testing.T) {
+	// Friday, 1 March 2024 10:07:30 WIB
+	from := wib(2024, 3, 1, 10, 7).Add(30 * time.Second)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", wib(2024, 3, 1, 10, 8)},
+		{"*/15 * * * *", wib(2024, 3, 1, 10, 15)},
+		{"0 3 * * *", wib(2024, 3, 2, 3, 0)},
+		{"30 9-17 * * mon-fri", wib(2024, 3, 1, 10, 30)},
+		{"0 8 * * sun", wib(2024, 3, 3, 8, 0)},
+		{"0 8 * * 7", wib(2024, 3, 3, 8, 0)},
+		{"0 0 29 feb *", wib(2028, 2, 29, 0, 0)},
+		{"0 0 15 * 1", wib(2024, 3, 4, 0, 0)}, // Day of month or day of week
+		{"5,10 0 1 jan-mar *", wib(2025, 1, 1, 0, 5)},
+		{"@hourly", wib(2024, 3, 1, 11, 0)},
+		{"@daily", wib(2024, 3, 2, 0, 0)},
+		{"@monthly", wib(2024, 4, 1, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.spec)
+		require.NoError(t, err, tt.spec)
+		assert.True(t, tt.want.Equal(schedule.Next(from)), "%s: got %s, want %s", tt.spec, schedule.Next(from), tt.want)
+	}
+
+	never, err := ParseSchedule("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, never.Next(from).IsZero())
+}
+
+// TestParseSchedule_Every tests interval schedules aligned across instances
+func TestParseSchedule_Every(t *testing.T) {
+	schedule, err := ParseSchedule("@every 15m")
+	require.NoError(t, err)
+
+	from := time.Date(2024, 3, 1, 3, 7, 30, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 3, 1, 3, 15, 0, 0, time.UTC), schedule.Next(from))
+	assert.Equal(t, time.Date(2024, 3, 1, 3, 30, 0, 0, time.UTC), schedule.Next(schedule.Next(from)))
+}
+
+// TestParseSchedule_Invalid tests rejected expressions
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"@every",
+		"@every 500ms",
+		"@every soon",
+		"@sometimes",
+	} {
+		_, err := ParseSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+// TestJobSpec tests the schedule of jobs configured with an interval
+func TestJobSpec(t *testing.T) {
+	assert.Equal(t, "0 3 * * *", Job{Schedule: "0 3 * * *", Interval: time.Hour}.Spec())
+	assert.Equal(t, "@every 15m0s", Job{Interval: 15 * time.Minute}.Spec())
+	assert.Equal(t, "", Job{}.Spec())
+}
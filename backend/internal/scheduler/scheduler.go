@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	jobRunDomain "github.com/madr/backend/internal/domain/jobrun"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// Job is a task run periodically in the background
+type Job struct {
+	Name     string
+	Schedule string        // Cron expression or "@every <duration>", see ParseSchedule
+	Interval time.Duration // Used when Schedule is empty
+	Run      func(now time.Time) error
+}
+
+// Spec returns the schedule expression of the job
+func (j Job) Spec() string {
+	if j.Schedule != "" {
+		return j.Schedule
+	}
+	if j.Interval <= 0 {
+		return ""
+	}
+	return "@every " + j.Interval.String()
+}
+
+// Store keeps job run history and locks jobs across server instances
+type Store interface {
+	TryLock(ctx context.Context, job string) (unlock func(), acquired bool, err error)
+	LastStartedAt(job string) (time.Time, error)
+	Create(run *jobRunDomain.JobRun) error
+	Update(run *jobRunDomain.JobRun) error
+}
+
+// Start runs every job on its schedule until ctx is cancelled. A job missed
+// while no instance was running, for example across a deploy, runs right
+// away. The returned function waits for running jobs to finish.
+func Start(ctx context.Context, store Store, jobs ...Job) (wait func()) {
+	instance, _ := os.Hostname()
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		schedule, err := ParseSchedule(job.Spec())
+		if err != nil {
+			logger.Warn().Err(err).Str("job", job.Name).Msg("Job schedule is invalid, job disabled")
+			continue
+		}
+
+		r := &runner{job: job, schedule: schedule, store: store, instance: instance, now: time.Now}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.loop(ctx)
+		}()
+	}
+	return wg.Wait
+}
+
+// runner runs one job
+type runner struct {
+	job      Job
+	schedule Schedule
+	store    Store
+	instance string
+	now      func() time.Time
+}
+
+func (r *runner) loop(ctx context.Context) {
+	logger.Info().Str("job", r.job.Name).Str("schedule", r.job.Spec()).Msg("Background job started")
+
+	for {
+		r.attempt(ctx)
+
+		next := r.schedule.Next(r.now())
+		if next.IsZero() {
+			logger.Warn().Str("job", r.job.Name).Msg("Job schedule has no next run, job stopped")
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			logger.Info().Str("job", r.job.Name).Msg("Background job stopped")
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt runs the job if it is due and no other instance is running it.
+// The job is due when it never ran or its schedule fired since it last
+// started, so an instance firing just after another one finished skips it.
+func (r *runner) attempt(ctx context.Context) {
+	unlock, acquired, err := r.store.TryLock(ctx, r.job.Name)
+	if err != nil {
+		logger.Error().Err(err).Str("job", r.job.Name).Msg("Failed to lock background job")
+		return
+	}
+	if !acquired {
+		logger.Debug().Str("job", r.job.Name).Msg("Background job is running on another instance")
+		return
+	}
+	defer unlock()
+
+	last, err := r.store.LastStartedAt(r.job.Name)
+	if err != nil {
+		logger.Error().Err(err).Str("job", r.job.Name).Msg("Failed to get last job run")
+		return
+	}
+
+	started := r.now()
+	scheduled := started
+	if !last.IsZero() {
+		scheduled = r.schedule.Next(last)
+		if scheduled.IsZero() || scheduled.After(started) {
+			logger.Debug().Str("job", r.job.Name).Msg("Background job is not due")
+			return
+		}
+	}
+
+	run := &jobRunDomain.JobRun{
+		JobName:     r.job.Name,
+		Status:      jobRunDomain.StatusRunning,
+		Instance:    r.instance,
+		ScheduledAt: scheduled,
+		StartedAt:   started,
+	}
+	if err := r.store.Create(run); err != nil {
+		logger.Error().Err(err).Str("job", r.job.Name).Msg("Failed to record job run")
+		return
+	}
+
+	err = r.run(started)
+	run.Finish(r.now(), err)
+	if err != nil {
+		logger.Error().Err(err).Str("job", r.job.Name).Msg("Background job failed")
+	} else {
+		logger.Debug().Str("job", r.job.Name).Int64("duration_ms", *run.DurationMs).Msg("Background job finished")
+	}
+
+	if err := r.store.Update(run); err != nil {
+		logger.Error().Err(err).Str("job", r.job.Name).Msg("Failed to record job run")
+	}
+}
+
+// run executes the job, turning a panic into an error so the job keeps its schedule
+func (r *runner) run(now time.Time) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return r.job.Run(now)
+}
@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	jobRunDomain "github.com/madr/backend/internal/domain/jobrun"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore keeps job runs in memory. A lock is held by another instance
+// while lockedElsewhere is set.
+type fakeStore struct {
+	runs            []*jobRunDomain.JobRun
+	lockedElsewhere bool
+	locked          bool
+}
+
+func (s *fakeStore) TryLock(ctx context.Context, job string) (func(), bool, error) {
+	if s.lockedElsewhere || s.locked {
+		return nil, false, nil
+	}
+	s.locked = true
+	return func() { s.locked = false }, true, nil
+}
+
+func (s *fakeStore) LastStartedAt(job string) (time.Time, error) {
+	var last time.Time
+	for _, run := range s.runs {
+		if run.JobName == job && run.StartedAt.After(last) {
+			last = run.StartedAt
+		}
+	}
+	return last, nil
+}
+
+func (s *fakeStore) Create(run *jobRunDomain.JobRun) error {
+	run.ID = uint(len(s.runs) + 1)
+	clone := *run
+	s.runs = append(s.runs, &clone)
+	return nil
+}
+
+func (s *fakeStore) Update(run *jobRunDomain.JobRun) error {
+	clone := *run
+	s.runs[run.ID-1] = &clone
+	return nil
+}
+
+func newTestRunner(store *fakeStore, now *time.Time, run func(time.Time) error) *runner {
+	return &runner{
+		job:      Job{Name: "cleanup", Interval: 15 * time.Minute, Run: run},
+		schedule: Every(15 * time.Minute),
+		store:    store,
+		instance: "test",
+		now:      func() time.Time { return *now },
+	}
+}
+
+// TestAttempt_Due tests that a job runs once per scheduled time
+func TestAttempt_Due(t *testing.T) {
+	now := time.Date(2024, 3, 1, 3, 7, 0, 0, time.UTC)
+	store := &fakeStore{}
+	calls := 0
+	r := newTestRunner(store, &now, func(time.Time) error {
+		calls++
+		return nil
+	})
+
+	// Never ran, so it runs at startup
+	r.attempt(context.Background())
+	require.Equal(t, 1, calls)
+	require.Len(t, store.runs, 1)
+	assert.Equal(t, jobRunDomain.StatusSucceeded, store.runs[0].Status)
+	assert.Equal(t, now, store.runs[0].ScheduledAt)
+	assert.NotNil(t, store.runs[0].FinishedAt)
+	assert.False(t, store.locked)
+
+	// Not due again until 03:15
+	now = now.Add(5 * time.Minute)
+	r.attempt(context.Background())
+	assert.Equal(t, 1, calls)
+
+	now = time.Date(2024, 3, 1, 3, 15, 0, 0, time.UTC)
+	r.attempt(context.Background())
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, now, store.runs[1].ScheduledAt)
+
+	// Another instance firing for the same time finds it already ran
+	now = now.Add(50 * time.Millisecond)
+	r.attempt(context.Background())
+	assert.Equal(t, 2, calls)
+
+	// Missed runs are caught up once
+	now = time.Date(2024, 3, 1, 5, 2, 0, 0, time.UTC)
+	r.attempt(context.Background())
+	r.attempt(context.Background())
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, time.Date(2024, 3, 1, 3, 30, 0, 0, time.UTC), store.runs[2].ScheduledAt)
+}
+
+// TestAttempt_LockedElsewhere tests that a job running on another instance is skipped
+func TestAttempt_LockedElsewhere(t *testing.T) {
+	now := time.Date(2024, 3, 1, 3, 7, 0, 0, time.UTC)
+	store := &fakeStore{lockedElsewhere: true}
+	r := newTestRunner(store, &now, func(time.Time) error {
+		t.Fatal("job ran while locked by another instance")
+		return nil
+	})
+
+	r.attempt(context.Background())
+	assert.Empty(t, store.runs)
+}
+
+// TestAttempt_Failure tests that errors and panics are recorded as failed runs
+func TestAttempt_Failure(t *testing.T) {
+	now := time.Date(2024, 3, 1, 3, 7, 0, 0, time.UTC)
+	store := &fakeStore{}
+	r := newTestRunner(store, &now, func(time.Time) error {
+		return errors.New("database is unavailable")
+	})
+
+	r.attempt(context.Background())
+	require.Len(t, store.runs, 1)
+	assert.Equal(t, jobRunDomain.StatusFailed, store.runs[0].Status)
+	require.NotNil(t, store.runs[0].Error)
+	assert.Equal(t, "database is unavailable", *store.runs[0].Error)
+
+	now = time.Date(2024, 3, 1, 3, 15, 0, 0, time.UTC)
+	r.job.Run = func(time.Time) error {
+		panic("nil map")
+	}
+	r.attempt(context.Background())
+	require.Len(t, store.runs, 2)
+	assert.Equal(t, jobRunDomain.StatusFailed, store.runs[1].Status)
+	assert.Equal(t, "panic: nil map", *store.runs[1].Error)
+	assert.False(t, store.locked)
+}
@@ -0,0 +1,60 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileName is the provider key for the file mailer
+const FileName = "file"
+
+// FileMailer writes each mail as an .eml file to a directory instead of
+// sending it. It is the default for local development.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer creates a file mailer writing to dir
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{dir: dir, from: from}
+}
+
+// Name returns the provider key
+func (m *FileMailer) Name() string {
+	return FileName
+}
+
+// Send writes the mail to a new file named after the time it was sent
+func (m *FileMailer) Send(mail *Mail) error {
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	now := time.Now()
+	name := fmt.Sprintf("%s_%s.eml", now.UTC().Format("20060102T150405"), uuid.New().String()[:8])
+	if err := os.WriteFile(filepath.Join(m.dir, name), message(m.from, mail, now), 0600); err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+	return nil
+}
+
+// message renders mail as an RFC 5322 message with CRLF line endings
+func message(from string, mail *Mail, date time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mail.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(mail.Body, "\r\n", "\n"), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,54 @@
+package mailer
+
+import (
+	"sync"
+)
+
+// Mail is a plain text email to one recipient
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer defines the interface every email transport must implement
+type Mailer interface {
+	// Name returns the provider key used in configuration
+	Name() string
+	// Send delivers the mail or returns an error
+	Send(mail *Mail) error
+}
+
+// MemoryName is the provider key for the in-memory mailer
+const MemoryName = "memory"
+
+// MemoryMailer keeps sent mail in memory. It is meant for tests.
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []Mail
+}
+
+// NewMemoryMailer creates an in-memory mailer
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Name returns the provider key
+func (m *MemoryMailer) Name() string {
+	return MemoryName
+}
+
+// Send records the mail
+func (m *MemoryMailer) Send(mail *Mail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, *mail)
+	return nil
+}
+
+// Sent returns a copy of every mail sent so far
+func (m *MemoryMailer) Sent() []Mail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Mail(nil), m.sent...)
+}
@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFileMailer tests that each mail is written as an .eml file
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m := NewFileMailer(dir, "MADR <no-reply@madr.local>")
+
+	require.NoError(t, m.Send(&Mail{To: "jamaah@example.com", Subject: "Verifikasi email", Body: "Baris satu\nBaris dua"}))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.True(t, strings.HasSuffix(files[0].Name(), ".eml"))
+
+	content, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "From: MADR <no-reply@madr.local>\r\n")
+	assert.Contains(t, string(content), "To: jamaah@example.com\r\n")
+	assert.Contains(t, string(content), "Subject: Verifikasi email\r\n")
+	assert.Contains(t, string(content), "\r\n\r\nBaris satu\r\nBaris dua\r\n")
+}
+
+// TestSMTPMailer tests the envelope passed to the SMTP client
+func TestSMTPMailer(t *testing.T) {
+	m := NewSMTPMailer("smtp.example.com", "587", "user", "secret", "no-reply@madr.local")
+	var addr string
+	var to []string
+	var auth smtp.Auth
+	m.send = func(a string, sa smtp.Auth, from string, rcpt []string, msg []byte) error {
+		addr, auth, to = a, sa, rcpt
+		return nil
+	}
+
+	require.NoError(t, m.Send(&Mail{To: "jamaah@example.com", Subject: "Atur ulang kata sandi", Body: "..."}))
+	assert.Equal(t, "smtp.example.com:587", addr)
+	assert.Equal(t, []string{"jamaah@example.com"}, to)
+	assert.NotNil(t, auth)
+
+	err := m.Send(&Mail{To: "jamaah@example.com\r\nBcc: eve@example.com", Subject: "x"})
+	assert.EqualError(t, err, "invalid mail header")
+}
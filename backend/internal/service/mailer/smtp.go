@@ -0,0 +1,61 @@
+package mailer
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPName is the provider key for the SMTP mailer
+const SMTPName = "smtp"
+
+// SMTPMailer sends mail through an SMTP server, authenticating with PLAIN
+// auth when a username is set
+type SMTPMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+	send     func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPMailer creates an SMTP mailer
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	return &SMTPMailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+		send:     smtp.SendMail,
+	}
+}
+
+// Name returns the provider key
+func (m *SMTPMailer) Name() string {
+	return SMTPName
+}
+
+// Send delivers the mail
+func (m *SMTPMailer) Send(mail *Mail) error {
+	if m.host == "" {
+		return fmt.Errorf("smtp host is not configured")
+	}
+	if strings.ContainsAny(mail.To, "\r\n") || strings.ContainsAny(mail.Subject, "\r\n") {
+		return fmt.Errorf("invalid mail header")
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	addr := net.JoinHostPort(m.host, m.port)
+	if err := m.send(addr, auth, m.from, []string{mail.To}, message(m.from, mail, time.Now())); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return nil
+}
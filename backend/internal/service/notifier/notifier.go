@@ -0,0 +1,52 @@
+package notifier
+
+import (
+	"github.com/madr/backend/pkg/logger"
+)
+
+// Message is a notification addressed to one person. Providers pick the
+// contact they can deliver to.
+type Message struct {
+	Name    string `json:"name"`
+	Phone   string `json:"phone,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Notifier defines the interface every notification channel must implement
+type Notifier interface {
+	// Name returns the provider key used in configuration
+	Name() string
+	// Send delivers the message or returns an error so it can be retried
+	Send(msg *Message) error
+}
+
+// LogName is the provider key for the log notifier
+const LogName = "log"
+
+// LogNotifier writes messages to the application log instead of sending
+// them. It is the default until a real channel is configured.
+type LogNotifier struct{}
+
+// NewLogNotifier creates a log notifier
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Name returns the provider key
+func (n *LogNotifier) Name() string {
+	return LogName
+}
+
+// Send logs the message
+func (n *LogNotifier) Send(msg *Message) error {
+	logger.Info().
+		Str("name", msg.Name).
+		Str("phone", msg.Phone).
+		Str("email", msg.Email).
+		Str("subject", msg.Subject).
+		Str("body", msg.Body).
+		Msg("Notification")
+	return nil
+}
@@ -0,0 +1,76 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WebhookName is the provider key for the webhook notifier
+const WebhookName = "webhook"
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body
+const SignatureHeader = "X-Signature"
+
+// WebhookNotifier posts messages as JSON to a URL, for example a WhatsApp or
+// email gateway. When a secret is set the body is signed in SignatureHeader.
+type WebhookNotifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a webhook notifier
+func NewWebhookNotifier(url, secret string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the provider key
+func (n *WebhookNotifier) Name() string {
+	return WebhookName
+}
+
+// Send posts the message and treats any non-2xx response as a failure
+func (n *WebhookNotifier) Send(msg *Message) error {
+	if n.url == "" {
+		return fmt.Errorf("notifier webhook url is not configured")
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notification webhook returned %d: %s", resp.StatusCode, respBody)
+	}
+	return nil
+}
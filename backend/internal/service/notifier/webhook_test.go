@@ -0,0 +1,55 @@
+package notifier
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWebhookNotifier_SignsAndPostsMessage tests the JSON body and signature
+func TestWebhookNotifier_SignsAndPostsMessage(t *testing.T) {
+	var received Message
+	var signature string
+	var raw []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		_ = json.Unmarshal(raw, &received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL, "secret")
+	err := n.Send(&Message{Name: "Ahmad", Phone: "0812", Subject: "Pengingat", Body: "Infaq bulanan"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Ahmad", received.Name)
+	assert.Equal(t, "0812", received.Phone)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(raw)
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), signature)
+}
+
+// TestWebhookNotifier_FailsOnErrorStatus tests that a rejected message is
+// reported so it can be retried
+func TestWebhookNotifier_FailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gateway down", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(server.URL, "").Send(&Message{Name: "Ahmad"})
+	assert.ErrorContains(t, err, "502")
+
+	err = NewWebhookNotifier("", "").Send(&Message{Name: "Ahmad"})
+	assert.EqualError(t, err, "notifier webhook url is not configured")
+}
@@ -0,0 +1,111 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/madr/backend/pkg/money"
+)
+
+// FakeName is the provider key for the local fake provider
+const FakeName = "fake"
+
+// FakeSignatureHeader carries the hex HMAC-SHA256 of the request body
+const FakeSignatureHeader = "X-Signature"
+
+// FakeNotification is the webhook body accepted by the fake provider
+type FakeNotification struct {
+	OrderID       string      `json:"order_id"`
+	TransactionID string      `json:"transaction_id"`
+	Status        Status      `json:"status"`
+	Amount        money.Money `json:"amount"`
+}
+
+// FakeProvider is an in-memory provider for local development and tests
+type FakeProvider struct {
+	secret   string
+	mu       sync.Mutex
+	statuses map[string]Status
+}
+
+// NewFakeProvider creates a fake provider that signs webhooks with secret
+func NewFakeProvider(secret string) *FakeProvider {
+	return &FakeProvider{
+		secret:   secret,
+		statuses: make(map[string]Status),
+	}
+}
+
+// Name returns the provider key
+func (p *FakeProvider) Name() string {
+	return FakeName
+}
+
+// CreateCharge records the order as pending and returns a fake transaction
+func (p *FakeProvider) CreateCharge(req *ChargeRequest) (*ChargeResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.statuses[req.OrderID] = StatusPending
+	return &ChargeResponse{
+		TransactionID: "fake-" + req.OrderID,
+		Token:         "fake-token-" + req.OrderID,
+		RedirectURL:   fmt.Sprintf("http://localhost/fake-pay/%s", req.OrderID),
+	}, nil
+}
+
+// VerifyWebhook checks the HMAC signature header and parses the body
+func (p *FakeProvider) VerifyWebhook(header http.Header, body []byte) (*WebhookEvent, error) {
+	signature, err := hex.DecodeString(header.Get(FakeSignatureHeader))
+	if err != nil || !hmac.Equal(signature, p.mac(body)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var n FakeNotification
+	if err := json.Unmarshal(body, &n); err != nil || n.OrderID == "" {
+		return nil, ErrInvalidPayload
+	}
+
+	return &WebhookEvent{
+		OrderID:       n.OrderID,
+		TransactionID: n.TransactionID,
+		Status:        n.Status,
+		Amount:        n.Amount,
+		RawPayload:    body,
+	}, nil
+}
+
+// QueryStatus returns the last status set for the order
+func (p *FakeProvider) QueryStatus(orderID string) (Status, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status, ok := p.statuses[orderID]
+	if !ok {
+		return "", fmt.Errorf("order %s not found", orderID)
+	}
+	return status, nil
+}
+
+// SetStatus changes the status returned by QueryStatus
+func (p *FakeProvider) SetStatus(orderID string, status Status) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.statuses[orderID] = status
+}
+
+// Sign returns the signature header value for body
+func (p *FakeProvider) Sign(body []byte) string {
+	return hex.EncodeToString(p.mac(body))
+}
+
+func (p *FakeProvider) mac(body []byte) []byte {
+	m := hmac.New(sha256.New, []byte(p.secret))
+	m.Write(body)
+	return m.Sum(nil)
+}
@@ -0,0 +1,211 @@
+package payment
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/madr/backend/internal/config"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/money"
+)
+
+// MidtransName is the provider key for Midtrans
+const MidtransName = "midtrans"
+
+type midtransProvider struct {
+	serverKey string
+	snapURL   string
+	apiURL    string
+	client    *http.Client
+}
+
+// NewMidtransProvider creates a Midtrans provider from configuration
+func NewMidtransProvider() Provider {
+	cfg := config.AppConfig.Payment
+	return &midtransProvider{
+		serverKey: cfg.MidtransServerKey,
+		snapURL:   strings.TrimSuffix(cfg.MidtransSnapURL, "/"),
+		apiURL:    strings.TrimSuffix(cfg.MidtransAPIURL, "/"),
+		client:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// midtransNotification represents the HTTP notification body sent by Midtrans
+type midtransNotification struct {
+	OrderID           string `json:"order_id"`
+	TransactionID     string `json:"transaction_id"`
+	TransactionStatus string `json:"transaction_status"`
+	FraudStatus       string `json:"fraud_status"`
+	StatusCode        string `json:"status_code"`
+	GrossAmount       string `json:"gross_amount"`
+	SignatureKey      string `json:"signature_key"`
+}
+
+// Name returns the provider key
+func (p *midtransProvider) Name() string {
+	return MidtransName
+}
+
+// CreateCharge creates a Snap transaction
+func (p *midtransProvider) CreateCharge(req *ChargeRequest) (*ChargeResponse, error) {
+	if p.serverKey == "" {
+		return nil, fmt.Errorf("midtrans server key is not configured")
+	}
+	// IDR transactions on Midtrans must be whole rupiah
+	if !req.Amount.IsWhole() {
+		return nil, fmt.Errorf("midtrans requires a whole rupiah amount, got %s", req.Amount)
+	}
+
+	payload := map[string]interface{}{
+		"transaction_details": map[string]interface{}{
+			"order_id":     req.OrderID,
+			"gross_amount": req.Amount.Rupiah(),
+		},
+		"customer_details": map[string]interface{}{
+			"first_name": req.DonorName,
+		},
+		"item_details": []map[string]interface{}{
+			{
+				"id":       req.OrderID,
+				"price":    req.Amount.Rupiah(),
+				"quantity": 1,
+				"name":     req.Description,
+			},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode charge request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, p.snapURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build charge request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.SetBasicAuth(p.serverKey, "")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		logger.Error().Err(err).Str("order_id", req.OrderID).Msg("Failed to call Midtrans Snap API")
+		return nil, fmt.Errorf("failed to create charge: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		logger.Error().
+			Int("status", resp.StatusCode).
+			Str("body", string(respBody)).
+			Str("order_id", req.OrderID).
+			Msg("Midtrans Snap API returned error")
+		return nil, fmt.Errorf("midtrans error: status %d", resp.StatusCode)
+	}
+
+	var snapResp struct {
+		Token       string `json:"token"`
+		RedirectURL string `json:"redirect_url"`
+	}
+	if err := json.Unmarshal(respBody, &snapResp); err != nil {
+		return nil, fmt.Errorf("failed to parse charge response: %w", err)
+	}
+
+	return &ChargeResponse{
+		Token:       snapResp.Token,
+		RedirectURL: snapResp.RedirectURL,
+	}, nil
+}
+
+// VerifyWebhook validates signature_key = SHA512(order_id + status_code + gross_amount + server_key)
+func (p *midtransProvider) VerifyWebhook(header http.Header, body []byte) (*WebhookEvent, error) {
+	var n midtransNotification
+	if err := json.Unmarshal(body, &n); err != nil {
+		return nil, ErrInvalidPayload
+	}
+	if n.OrderID == "" || n.SignatureKey == "" {
+		return nil, ErrInvalidPayload
+	}
+
+	expected := midtransSignature(n.OrderID, n.StatusCode, n.GrossAmount, p.serverKey)
+	if p.serverKey == "" || !hmac.Equal([]byte(expected), []byte(strings.ToLower(n.SignatureKey))) {
+		return nil, ErrInvalidSignature
+	}
+
+	amount, err := money.Parse(n.GrossAmount)
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+
+	return &WebhookEvent{
+		OrderID:       n.OrderID,
+		TransactionID: n.TransactionID,
+		Status:        mapMidtransStatus(n.TransactionStatus, n.FraudStatus),
+		Amount:        amount,
+		RawPayload:    body,
+	}, nil
+}
+
+// QueryStatus calls the Midtrans status API
+func (p *midtransProvider) QueryStatus(orderID string) (Status, error) {
+	if p.serverKey == "" {
+		return "", fmt.Errorf("midtrans server key is not configured")
+	}
+
+	httpReq, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s/status", p.apiURL, url.PathEscape(orderID)), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build status request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.SetBasicAuth(p.serverKey, "")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to query status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("midtrans error: status %d", resp.StatusCode)
+	}
+
+	var n midtransNotification
+	if err := json.NewDecoder(resp.Body).Decode(&n); err != nil {
+		return "", fmt.Errorf("failed to parse status response: %w", err)
+	}
+
+	return mapMidtransStatus(n.TransactionStatus, n.FraudStatus), nil
+}
+
+// midtransSignature computes the notification signature key
+func midtransSignature(orderID, statusCode, grossAmount, serverKey string) string {
+	sum := sha512.Sum512([]byte(orderID + statusCode + grossAmount + serverKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// mapMidtransStatus converts Midtrans transaction_status to a normalized status
+func mapMidtransStatus(transactionStatus, fraudStatus string) Status {
+	switch transactionStatus {
+	case "capture":
+		if fraudStatus == "challenge" {
+			return StatusPending
+		}
+		return StatusSuccess
+	case "settlement":
+		return StatusSuccess
+	case "deny", "cancel", "expire", "failure":
+		return StatusFailed
+	default:
+		return StatusPending
+	}
+}
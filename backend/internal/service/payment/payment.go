@@ -0,0 +1,112 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/madr/backend/pkg/money"
+)
+
+// Status represents a normalized payment status reported by a gateway
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusSuccess Status = "success"
+	StatusFailed  Status = "failed"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrUnknownProvider  = errors.New("unknown payment provider")
+	ErrInvalidPayload   = errors.New("invalid webhook payload")
+)
+
+// ChargeRequest represents a request to create a charge at the gateway
+type ChargeRequest struct {
+	OrderID     string
+	Amount      money.Money
+	DonorName   string
+	Description string
+}
+
+// ChargeResponse represents the gateway response after creating a charge
+type ChargeResponse struct {
+	TransactionID string `json:"transaction_id,omitempty"`
+	Token         string `json:"token,omitempty"`
+	RedirectURL   string `json:"redirect_url,omitempty"`
+}
+
+// WebhookEvent represents a verified payment notification
+type WebhookEvent struct {
+	OrderID       string
+	TransactionID string
+	Status        Status
+	Amount        money.Money
+	RawPayload    []byte
+}
+
+// Provider defines the interface every payment gateway must implement
+type Provider interface {
+	// Name returns the provider key used in webhook URLs
+	Name() string
+	// CreateCharge registers a new transaction at the gateway
+	CreateCharge(req *ChargeRequest) (*ChargeResponse, error)
+	// VerifyWebhook checks the callback signature and parses the notification
+	VerifyWebhook(header http.Header, body []byte) (*WebhookEvent, error)
+	// QueryStatus asks the gateway for the current status of an order
+	QueryStatus(orderID string) (Status, error)
+}
+
+// Registry holds the configured payment providers
+type Registry struct {
+	providers   map[string]Provider
+	defaultName string
+}
+
+// NewRegistry creates a registry with the given default provider name
+func NewRegistry(defaultName string, providers ...Provider) *Registry {
+	r := &Registry{
+		providers:   make(map[string]Provider, len(providers)),
+		defaultName: defaultName,
+	}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+	return r
+}
+
+// Get returns the provider registered under name
+func (r *Registry) Get(name string) (Provider, error) {
+	if r == nil {
+		return nil, ErrUnknownProvider
+	}
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+	}
+	return p, nil
+}
+
+// Default returns the provider used for new charges
+func (r *Registry) Default() (Provider, error) {
+	if r == nil {
+		return nil, ErrUnknownProvider
+	}
+	return r.Get(r.defaultName)
+}
+
+// Names returns the registered provider names in sorted order
+func (r *Registry) Names() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,90 @@
+package payment
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/madr/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMidtransVerifyWebhook tests signature verification and status mapping
+func TestMidtransVerifyWebhook(t *testing.T) {
+	p := &midtransProvider{serverKey: "server-key"}
+
+	signature := midtransSignature("DON-1", "200", "50000.00", "server-key")
+	body := []byte(fmt.Sprintf(`{
+		"order_id": "DON-1",
+		"transaction_id": "trx-1",
+		"transaction_status": "settlement",
+		"status_code": "200",
+		"gross_amount": "50000.00",
+		"signature_key": "%s"
+	}`, signature))
+
+	event, err := p.VerifyWebhook(http.Header{}, body)
+
+	require.NoError(t, err)
+	assert.Equal(t, "DON-1", event.OrderID)
+	assert.Equal(t, "trx-1", event.TransactionID)
+	assert.Equal(t, StatusSuccess, event.Status)
+	assert.Equal(t, money.FromRupiah(50000), event.Amount)
+}
+
+// TestMidtransVerifyWebhook_InvalidSignature tests that a tampered amount is rejected
+func TestMidtransVerifyWebhook_InvalidSignature(t *testing.T) {
+	p := &midtransProvider{serverKey: "server-key"}
+
+	signature := midtransSignature("DON-1", "200", "50000.00", "server-key")
+	body := []byte(fmt.Sprintf(`{
+		"order_id": "DON-1",
+		"transaction_status": "settlement",
+		"status_code": "200",
+		"gross_amount": "1.00",
+		"signature_key": "%s"
+	}`, signature))
+
+	_, err := p.VerifyWebhook(http.Header{}, body)
+
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+// TestMapMidtransStatus tests transaction status normalization
+func TestMapMidtransStatus(t *testing.T) {
+	assert.Equal(t, StatusSuccess, mapMidtransStatus("capture", "accept"))
+	assert.Equal(t, StatusPending, mapMidtransStatus("capture", "challenge"))
+	assert.Equal(t, StatusSuccess, mapMidtransStatus("settlement", ""))
+	assert.Equal(t, StatusPending, mapMidtransStatus("pending", ""))
+	assert.Equal(t, StatusFailed, mapMidtransStatus("expire", ""))
+	assert.Equal(t, StatusFailed, mapMidtransStatus("deny", ""))
+}
+
+// TestFakeProvider_SignAndVerify tests the HMAC round trip of the fake provider
+func TestFakeProvider_SignAndVerify(t *testing.T) {
+	p := NewFakeProvider("secret")
+	body := []byte(`{"order_id":"DON-1","status":"success","amount":1000}`)
+
+	header := http.Header{}
+	header.Set(FakeSignatureHeader, p.Sign(body))
+	event, err := p.VerifyWebhook(header, body)
+	require.NoError(t, err)
+	assert.Equal(t, StatusSuccess, event.Status)
+
+	header.Set(FakeSignatureHeader, NewFakeProvider("other").Sign(body))
+	_, err = p.VerifyWebhook(header, body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+// TestRegistry tests provider lookup
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(FakeName, NewFakeProvider("secret"))
+
+	p, err := r.Default()
+	require.NoError(t, err)
+	assert.Equal(t, FakeName, p.Name())
+
+	_, err = r.Get("xendit")
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+}
@@ -0,0 +1,57 @@
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/madr/backend/internal/config"
+	"github.com/madr/backend/pkg/database"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/migrate"
+	"github.com/madr/backend/pkg/seed"
+	"github.com/stretchr/testify/require"
+)
+
+// SetupDatabase connects to the database named by TEST_DB_NAME, runs all
+// migrations and seeds default data. The test is skipped when no test
+// database is configured. Set extra environment variables before calling.
+func SetupDatabase(t *testing.T) {
+	t.Helper()
+
+	dbName := os.Getenv("TEST_DB_NAME")
+	if dbName == "" {
+		t.Skip("TEST_DB_NAME not set, skipping database test")
+	}
+	t.Setenv("DB_NAME", dbName)
+
+	require.NoError(t, config.Load())
+	logger.Init("error", "json")
+
+	require.NoError(t, database.Connect())
+	t.Cleanup(func() { database.Close() })
+
+	// Migrations are resolved relative to the module root
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(moduleRoot(t, wd)))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	sqlDB, err := database.GetDB().DB()
+	require.NoError(t, err)
+	require.NoError(t, migrate.RunMigrations(sqlDB))
+	require.NoError(t, seed.SeedAll())
+}
+
+// moduleRoot walks up from dir to the directory containing go.mod
+func moduleRoot(t *testing.T, dir string) string {
+	t.Helper()
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		require.NotEqual(t, dir, parent, "go.mod not found")
+		dir = parent
+	}
+}
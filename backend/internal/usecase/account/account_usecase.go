@@ -0,0 +1,329 @@
+package account
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	userDomain "github.com/madr/backend/internal/domain/user"
+	userTokenDomain "github.com/madr/backend/internal/domain/usertoken"
+	refreshTokenRepo "github.com/madr/backend/internal/repository/refreshtoken"
+	userRepo "github.com/madr/backend/internal/repository/user"
+	userTokenRepo "github.com/madr/backend/internal/repository/usertoken"
+	"github.com/madr/backend/internal/service/mailer"
+	"github.com/madr/backend/pkg/bcrypt"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/password"
+)
+
+// tokenBytes is the amount of randomness in each emailed token
+const tokenBytes = 32
+
+// UseCase defines the interface for password reset and email verification
+type UseCase interface {
+	ForgotPassword(req *ForgotPasswordRequest) error
+	ResetPassword(req *ResetPasswordRequest) error
+	SendVerification(userID uint) error
+	VerifyEmail(req *VerifyEmailRequest) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// ForgotPasswordRequest represents the request to email a reset link
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest represents the request to set a new password with a
+// reset token
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// VerifyEmailRequest represents the request to confirm an email address
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// Options configures the links sent by email
+type Options struct {
+	AppURL       string        // Frontend base URL, e.g. https://madr.example.com
+	ResetExpiry  time.Duration // Lifetime of password reset tokens
+	VerifyExpiry time.Duration // Lifetime of email verification tokens
+}
+
+type useCase struct {
+	users         userRepo.Repository
+	tokens        userTokenRepo.Repository
+	refreshTokens refreshTokenRepo.Repository
+	mailer        mailer.Mailer
+	options       Options
+	now           func() time.Time
+}
+
+// NewUseCase creates a new account use case
+func NewUseCase(users userRepo.Repository, tokens userTokenRepo.Repository, refreshTokens refreshTokenRepo.Repository, m mailer.Mailer, options Options) UseCase {
+	return &useCase{
+		users:         users,
+		tokens:        tokens,
+		refreshTokens: refreshTokens,
+		mailer:        m,
+		options:       options,
+		now:           time.Now,
+	}
+}
+
+// ForgotPassword emails a reset link to an active account with the given
+// email. It reports success for unknown emails so that callers cannot probe
+// which addresses are registered.
+func (uc *useCase) ForgotPassword(req *ForgotPasswordRequest) error {
+	usr, err := uc.users.GetByEmail(strings.TrimSpace(req.Email))
+	if err != nil {
+		if err.Error() != "user not found" {
+			logger.Error().Err(err).Msg("Failed to get user for password reset")
+			return errors.New("failed to request password reset")
+		}
+		logger.Info().Msg("Password reset requested for unknown email")
+		return nil
+	}
+	if !usr.IsActive {
+		logger.Warn().Uint("user_id", usr.ID).Msg("Password reset requested for inactive user")
+		return nil
+	}
+
+	token, err := uc.issue(usr.ID, userTokenDomain.PurposePasswordReset, uc.options.ResetExpiry)
+	if err != nil {
+		return errors.New("failed to request password reset")
+	}
+
+	mail := &mailer.Mail{
+		To:      usr.Email,
+		Subject: "Atur ulang kata sandi",
+		Body: fmt.Sprintf(
+			"Assalamu'alaikum %s,\n\nKami menerima permintaan untuk mengatur ulang kata sandi akun Anda. Buka tautan berikut dalam %s:\n\n%s\n\nAbaikan email ini jika Anda tidak memintanya.",
+			displayName(usr), formatExpiry(uc.options.ResetExpiry), uc.link("/reset-password", token),
+		),
+	}
+	// A delivery failure is only logged; failing the request would tell the
+	// caller that the email is registered
+	if err := uc.mailer.Send(mail); err != nil {
+		logger.Error().Err(err).Uint("user_id", usr.ID).Str("mailer", uc.mailer.Name()).Msg("Failed to send password reset email")
+		return nil
+	}
+
+	logger.Info().Uint("user_id", usr.ID).Msg("Password reset email sent")
+	return nil
+}
+
+// ResetPassword sets a new password with a reset token. The token is used
+// up, other outstanding reset tokens are invalidated and every session of
+// the user is signed out.
+func (uc *useCase) ResetPassword(req *ResetPasswordRequest) error {
+	token, err := uc.lookup(userTokenDomain.PurposePasswordReset, req.Token)
+	if err != nil {
+		return err
+	}
+
+	usr, err := uc.users.GetByID(token.UserID)
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", token.UserID).Msg("Failed to get user for password reset")
+		return errors.New("invalid or expired token")
+	}
+	if !usr.IsActive {
+		return errors.New("account is inactive")
+	}
+	// Checked before the token is used up so that the user can try again
+	if err := password.Validate(req.Password, usr.Username, usr.Email); err != nil {
+		return err
+	}
+	if err := uc.consume(token); err != nil {
+		return err
+	}
+
+	if usr.Password, err = bcrypt.HashPassword(req.Password); err != nil {
+		logger.Error().Err(err).Msg("Failed to hash password")
+		return errors.New("failed to process password")
+	}
+	// Following the emailed link proves the address as well
+	if usr.EmailVerifiedAt == nil {
+		now := uc.now()
+		usr.EmailVerifiedAt = &now
+	}
+	if err := uc.users.Update(usr); err != nil {
+		logger.Error().Err(err).Uint("user_id", usr.ID).Msg("Failed to update password")
+		return errors.New("failed to reset password")
+	}
+
+	if err := uc.tokens.InvalidateForUser(usr.ID, userTokenDomain.PurposePasswordReset, uc.now()); err != nil {
+		logger.Warn().Err(err).Uint("user_id", usr.ID).Msg("Failed to invalidate other reset tokens")
+	}
+	if err := uc.refreshTokens.RevokeAllByUserID(usr.ID); err != nil {
+		logger.Error().Err(err).Uint("user_id", usr.ID).Msg("Failed to revoke refresh tokens after password reset")
+		return errors.New("failed to reset password")
+	}
+
+	logger.Info().Uint("user_id", usr.ID).Msg("Password reset")
+	return nil
+}
+
+// SendVerification emails a verification link to a user who has not
+// verified their email yet
+func (uc *useCase) SendVerification(userID uint) error {
+	usr, err := uc.users.GetByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if usr.EmailVerifiedAt != nil {
+		return errors.New("email already verified")
+	}
+
+	if err := uc.tokens.InvalidateForUser(usr.ID, userTokenDomain.PurposeEmailVerification, uc.now()); err != nil {
+		logger.Warn().Err(err).Uint("user_id", usr.ID).Msg("Failed to invalidate previous verification tokens")
+	}
+	token, err := uc.issue(usr.ID, userTokenDomain.PurposeEmailVerification, uc.options.VerifyExpiry)
+	if err != nil {
+		return errors.New("failed to send verification email")
+	}
+
+	mail := &mailer.Mail{
+		To:      usr.Email,
+		Subject: "Verifikasi email",
+		Body: fmt.Sprintf(
+			"Assalamu'alaikum %s,\n\nSilakan verifikasi alamat email Anda melalui tautan berikut dalam %s:\n\n%s",
+			displayName(usr), formatExpiry(uc.options.VerifyExpiry), uc.link("/verify-email", token),
+		),
+	}
+	if err := uc.mailer.Send(mail); err != nil {
+		logger.Error().Err(err).Uint("user_id", usr.ID).Str("mailer", uc.mailer.Name()).Msg("Failed to send verification email")
+		return errors.New("failed to send email")
+	}
+
+	logger.Info().Uint("user_id", usr.ID).Msg("Verification email sent")
+	return nil
+}
+
+// VerifyEmail marks the email of the token's user as verified
+func (uc *useCase) VerifyEmail(req *VerifyEmailRequest) error {
+	token, err := uc.lookup(userTokenDomain.PurposeEmailVerification, req.Token)
+	if err != nil {
+		return err
+	}
+	if err := uc.consume(token); err != nil {
+		return err
+	}
+
+	usr, err := uc.users.GetByID(token.UserID)
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", token.UserID).Msg("Failed to get user for email verification")
+		return errors.New("invalid or expired token")
+	}
+	if usr.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	now := uc.now()
+	usr.EmailVerifiedAt = &now
+	if err := uc.users.Update(usr); err != nil {
+		logger.Error().Err(err).Uint("user_id", usr.ID).Msg("Failed to verify email")
+		return errors.New("failed to verify email")
+	}
+
+	logger.Info().Uint("user_id", usr.ID).Msg("Email verified")
+	return nil
+}
+
+// issue stores the hash of a new random token and returns the token
+// DeleteExpired removes reset and verification tokens that can no longer be
+// used. Used tokens are kept until they expire.
+func (uc *useCase) DeleteExpired(now time.Time) (int64, error) {
+	deleted, err := uc.tokens.DeleteExpired(now)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to delete expired user tokens")
+		return 0, errors.New("failed to delete expired tokens")
+	}
+	if deleted > 0 {
+		logger.Info().Int64("deleted", deleted).Msg("Expired user tokens deleted")
+	}
+	return deleted, nil
+}
+
+func (uc *useCase) issue(userID uint, purpose userTokenDomain.Purpose, expiry time.Duration) (string, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		logger.Error().Err(err).Msg("Failed to generate user token")
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	record := &userTokenDomain.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: uc.now().Add(expiry),
+	}
+	if err := uc.tokens.Create(record); err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Str("purpose", string(purpose)).Msg("Failed to store user token")
+		return "", err
+	}
+	return token, nil
+}
+
+// lookup finds a token that can still be used
+func (uc *useCase) lookup(purpose userTokenDomain.Purpose, token string) (*userTokenDomain.UserToken, error) {
+	record, err := uc.tokens.GetByHash(purpose, hashToken(token))
+	if err != nil {
+		if err.Error() != "token not found" {
+			logger.Error().Err(err).Msg("Failed to get user token")
+		}
+		return nil, errors.New("invalid or expired token")
+	}
+	if !record.IsValidAt(uc.now()) {
+		return nil, errors.New("invalid or expired token")
+	}
+	return record, nil
+}
+
+// consume uses a token up. Of concurrent attempts only one succeeds.
+func (uc *useCase) consume(record *userTokenDomain.UserToken) error {
+	if err := uc.tokens.Consume(record.ID, uc.now()); err != nil {
+		if !errors.Is(err, userTokenRepo.ErrUnavailable) {
+			logger.Error().Err(err).Uint("token_id", record.ID).Msg("Failed to consume user token")
+		}
+		return errors.New("invalid or expired token")
+	}
+	return nil
+}
+
+// link builds a frontend URL carrying the token
+func (uc *useCase) link(path, token string) string {
+	return strings.TrimRight(uc.options.AppURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+// hashToken returns the hex SHA-256 digest stored instead of the token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func displayName(usr *userDomain.User) string {
+	if usr.Name != "" {
+		return usr.Name
+	}
+	return usr.Username
+}
+
+// formatExpiry renders a token lifetime in Indonesian, e.g. "1 jam"
+func formatExpiry(d time.Duration) string {
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%d hari", int(d/(24*time.Hour)))
+	}
+	if d >= time.Hour && d%time.Hour == 0 {
+		return fmt.Sprintf("%d jam", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%d menit", int(d/time.Minute))
+}
@@ -0,0 +1,266 @@
+package account
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	userDomain "github.com/madr/backend/internal/domain/user"
+	userTokenDomain "github.com/madr/backend/internal/domain/usertoken"
+	refreshTokenRepo "github.com/madr/backend/internal/repository/refreshtoken"
+	userRepo "github.com/madr/backend/internal/repository/user"
+	userTokenRepo "github.com/madr/backend/internal/repository/usertoken"
+	"github.com/madr/backend/internal/service/mailer"
+	"github.com/madr/backend/pkg/bcrypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUserRepository keeps users in memory
+type fakeUserRepository struct {
+	userRepo.Repository
+	users map[uint]*userDomain.User
+}
+
+func (r *fakeUserRepository) GetByID(id uint) (*userDomain.User, error) {
+	usr, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	clone := *usr
+	return &clone, nil
+}
+
+func (r *fakeUserRepository) GetByEmail(email string) (*userDomain.User, error) {
+	for _, usr := range r.users {
+		if usr.Email == email {
+			clone := *usr
+			return &clone, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (r *fakeUserRepository) Update(usr *userDomain.User) error {
+	clone := *usr
+	r.users[usr.ID] = &clone
+	return nil
+}
+
+// fakeTokenRepository keeps user tokens in memory
+type fakeTokenRepository struct {
+	userTokenRepo.Repository
+	tokens []*userTokenDomain.UserToken
+}
+
+func (r *fakeTokenRepository) Create(token *userTokenDomain.UserToken) error {
+	token.ID = uint(len(r.tokens) + 1)
+	clone := *token
+	r.tokens = append(r.tokens, &clone)
+	return nil
+}
+
+func (r *fakeTokenRepository) GetByHash(purpose userTokenDomain.Purpose, tokenHash string) (*userTokenDomain.UserToken, error) {
+	for _, token := range r.tokens {
+		if token.Purpose == purpose && token.TokenHash == tokenHash {
+			clone := *token
+			return &clone, nil
+		}
+	}
+	return nil, errors.New("token not found")
+}
+
+func (r *fakeTokenRepository) Consume(id uint, at time.Time) error {
+	token := r.tokens[id-1]
+	if !token.IsValidAt(at) {
+		return userTokenRepo.ErrUnavailable
+	}
+	token.UsedAt = &at
+	return nil
+}
+
+func (r *fakeTokenRepository) InvalidateForUser(userID uint, purpose userTokenDomain.Purpose, at time.Time) error {
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt == nil {
+			token.UsedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *fakeTokenRepository) DeleteExpired(before time.Time) (int64, error) {
+	var kept []*userTokenDomain.UserToken
+	for _, token := range r.tokens {
+		if !token.ExpiresAt.Before(before) {
+			kept = append(kept, token)
+		}
+	}
+	deleted := int64(len(r.tokens) - len(kept))
+	r.tokens = kept
+	return deleted, nil
+}
+
+// fakeRefreshTokenRepository records which users were signed out
+type fakeRefreshTokenRepository struct {
+	refreshTokenRepo.Repository
+	revoked []uint
+}
+
+func (r *fakeRefreshTokenRepository) RevokeAllByUserID(userID uint) error {
+	r.revoked = append(r.revoked, userID)
+	return nil
+}
+
+type testEnv struct {
+	uc       *useCase
+	users    *fakeUserRepository
+	tokens   *fakeTokenRepository
+	sessions *fakeRefreshTokenRepository
+	mail     *mailer.MemoryMailer
+	now      time.Time
+}
+
+func newTestEnv() *testEnv {
+	env := &testEnv{
+		users:    &fakeUserRepository{users: map[uint]*userDomain.User{}},
+		tokens:   &fakeTokenRepository{},
+		sessions: &fakeRefreshTokenRepository{},
+		mail:     mailer.NewMemoryMailer(),
+		now:      time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC),
+	}
+	jamaah := &userDomain.User{Username: "jamaah", Email: "jamaah@example.com", Name: "Ahmad", IsActive: true}
+	jamaah.ID = 1
+	env.users.users[1] = jamaah
+
+	env.uc = NewUseCase(env.users, env.tokens, env.sessions, env.mail, Options{
+		AppURL:       "https://madr.example.com/",
+		ResetExpiry:  time.Hour,
+		VerifyExpiry: 48 * time.Hour,
+	}).(*useCase)
+	env.uc.now = func() time.Time { return env.now }
+	return env
+}
+
+var tokenPattern = regexp.MustCompile(`\?token=([0-9a-f]+)`)
+
+// lastToken extracts the token from the link in the most recent mail
+func (env *testEnv) lastToken(t *testing.T) string {
+	sent := env.mail.Sent()
+	require.NotEmpty(t, sent)
+	match := tokenPattern.FindStringSubmatch(sent[len(sent)-1].Body)
+	require.Len(t, match, 2)
+	return match[1]
+}
+
+// TestResetPassword tests the full forgot-password flow
+func TestResetPassword(t *testing.T) {
+	env := newTestEnv()
+
+	require.NoError(t, env.uc.ForgotPassword(&ForgotPasswordRequest{Email: "jamaah@example.com"}))
+	sent := env.mail.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "jamaah@example.com", sent[0].To)
+	assert.Contains(t, sent[0].Body, "https://madr.example.com/reset-password?token=")
+	assert.Contains(t, sent[0].Body, "1 jam")
+
+	token := env.lastToken(t)
+	assert.Len(t, token, 2*tokenBytes)
+	assert.Equal(t, hashToken(token), env.tokens.tokens[0].TokenHash)
+	assert.NotEqual(t, token, env.tokens.tokens[0].TokenHash)
+
+	// A password rejected by the policy does not use the token up
+	err := env.uc.ResetPassword(&ResetPasswordRequest{Token: token, Password: "jamaah123"})
+	assert.EqualError(t, err, "password must not contain your username or email")
+	assert.Empty(t, env.sessions.revoked)
+
+	require.NoError(t, env.uc.ResetPassword(&ResetPasswordRequest{Token: token, Password: "kata-sandi-baru1"}))
+	usr := env.users.users[1]
+	assert.True(t, bcrypt.CheckPasswordHash("kata-sandi-baru1", usr.Password))
+	assert.NotNil(t, usr.EmailVerifiedAt)
+	assert.Equal(t, []uint{1}, env.sessions.revoked)
+
+	// Single use
+	err = env.uc.ResetPassword(&ResetPasswordRequest{Token: token, Password: "lagi-lagi-baru2"})
+	assert.EqualError(t, err, "invalid or expired token")
+	assert.True(t, bcrypt.CheckPasswordHash("kata-sandi-baru1", env.users.users[1].Password))
+}
+
+// TestDeleteExpired tests that the cleanup job removes only tokens that have expired
+func TestDeleteExpired(t *testing.T) {
+	env := newTestEnv()
+
+	// The reset token lives one hour and is used up, the verification token lives two days
+	require.NoError(t, env.uc.SendVerification(1))
+	require.NoError(t, env.uc.ForgotPassword(&ForgotPasswordRequest{Email: "jamaah@example.com"}))
+	require.NoError(t, env.uc.ResetPassword(&ResetPasswordRequest{Token: env.lastToken(t), Password: "kata-sandi-baru1"}))
+
+	deleted, err := env.uc.DeleteExpired(env.now)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	deleted, err = env.uc.DeleteExpired(env.now.Add(2 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	require.Len(t, env.tokens.tokens, 1)
+	assert.Equal(t, userTokenDomain.PurposeEmailVerification, env.tokens.tokens[0].Purpose)
+}
+
+// TestResetPassword_InvalidTokens tests expired, superseded and unknown tokens
+func TestResetPassword_InvalidTokens(t *testing.T) {
+	env := newTestEnv()
+
+	// A verification token cannot reset a password
+	require.NoError(t, env.uc.SendVerification(1))
+	assert.EqualError(t, env.uc.ResetPassword(&ResetPasswordRequest{Token: env.lastToken(t), Password: "kata-sandi-baru1"}), "invalid or expired token")
+
+	require.NoError(t, env.uc.ForgotPassword(&ForgotPasswordRequest{Email: "jamaah@example.com"}))
+	first := env.lastToken(t)
+	require.NoError(t, env.uc.ForgotPassword(&ForgotPasswordRequest{Email: "jamaah@example.com"}))
+	second := env.lastToken(t)
+
+	env.now = env.now.Add(2 * time.Hour)
+	assert.EqualError(t, env.uc.ResetPassword(&ResetPasswordRequest{Token: second, Password: "kata-sandi-baru1"}), "invalid or expired token")
+
+	env.now = env.now.Add(-2 * time.Hour)
+	require.NoError(t, env.uc.ResetPassword(&ResetPasswordRequest{Token: second, Password: "kata-sandi-baru1"}))
+	// A successful reset uses up the other outstanding links
+	assert.EqualError(t, env.uc.ResetPassword(&ResetPasswordRequest{Token: first, Password: "kata-sandi-lain3"}), "invalid or expired token")
+	assert.EqualError(t, env.uc.ResetPassword(&ResetPasswordRequest{Token: "tidak-ada", Password: "kata-sandi-baru1"}), "invalid or expired token")
+}
+
+// TestForgotPassword_DoesNotRevealAccounts tests unknown and inactive emails
+func TestForgotPassword_DoesNotRevealAccounts(t *testing.T) {
+	env := newTestEnv()
+	env.users.users[1].IsActive = false
+
+	assert.NoError(t, env.uc.ForgotPassword(&ForgotPasswordRequest{Email: "orang-lain@example.com"}))
+	assert.NoError(t, env.uc.ForgotPassword(&ForgotPasswordRequest{Email: "jamaah@example.com"}))
+	assert.Empty(t, env.mail.Sent())
+	assert.Empty(t, env.tokens.tokens)
+}
+
+// TestVerifyEmail tests the email verification flow
+func TestVerifyEmail(t *testing.T) {
+	env := newTestEnv()
+
+	require.NoError(t, env.uc.SendVerification(1))
+	sent := env.mail.Sent()
+	require.Len(t, sent, 1)
+	assert.Contains(t, sent[0].Body, "https://madr.example.com/verify-email?token=")
+	assert.Contains(t, sent[0].Body, "2 hari")
+	stale := env.lastToken(t)
+
+	// Resending replaces the previous link
+	require.NoError(t, env.uc.SendVerification(1))
+	token := env.lastToken(t)
+	assert.EqualError(t, env.uc.VerifyEmail(&VerifyEmailRequest{Token: stale}), "invalid or expired token")
+
+	require.NoError(t, env.uc.VerifyEmail(&VerifyEmailRequest{Token: token}))
+	require.NotNil(t, env.users.users[1].EmailVerifiedAt)
+	assert.Equal(t, env.now, *env.users.users[1].EmailVerifiedAt)
+	assert.Empty(t, env.sessions.revoked)
+
+	assert.EqualError(t, env.uc.SendVerification(1), "email already verified")
+	assert.EqualError(t, env.uc.SendVerification(9), "user not found")
+}
@@ -0,0 +1,160 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	auditDomain "github.com/madr/backend/internal/domain/audit"
+	"github.com/madr/backend/internal/domain/models"
+	auditRepo "github.com/madr/backend/internal/repository/audit"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// UseCase defines the interface for audit log use case
+type UseCase interface {
+	Snapshot(entityType string, id uint) models.JSON
+	Record(entry *auditDomain.Log)
+	GetAll(limit, offset int, filter *ListFilter) (*GetAllResponse, error)
+}
+
+// SnapshotFunc loads the current state of an entity. Singletons such as the
+// about page are loaded with ID 0.
+type SnapshotFunc func(id uint) (interface{}, error)
+
+// Snapshotter adapts a use case getter to a SnapshotFunc
+func Snapshotter[T any](get func(id uint) (T, error)) SnapshotFunc {
+	return func(id uint) (interface{}, error) {
+		return get(id)
+	}
+}
+
+// ListFilter narrows the audit log. From and To are inclusive Jakarta dates.
+type ListFilter struct {
+	ActorID    *uint
+	EntityType string
+	EntityID   *uint
+	Action     string
+	Outcome    *auditDomain.Outcome
+	From       *time.Time
+	To         *time.Time
+}
+
+// GetAllResponse represents the response for getting audit log entries
+type GetAllResponse struct {
+	Data       []auditDomain.Log `json:"data"`
+	Total      int64             `json:"total"`
+	Limit      int               `json:"limit"`
+	Offset     int               `json:"offset"`
+	TotalPages int               `json:"total_pages"`
+}
+
+type useCase struct {
+	repo  auditRepo.Repository
+	hooks map[string]SnapshotFunc
+}
+
+// NewUseCase creates a new audit log use case. Hooks are keyed by entity
+// type and provide the before and after snapshots of audited changes.
+func NewUseCase(repo auditRepo.Repository, hooks map[string]SnapshotFunc) UseCase {
+	return &useCase{
+		repo:  repo,
+		hooks: hooks,
+	}
+}
+
+// Snapshot returns the current state of an entity, or nil when the entity
+// type has no hook or the entity does not exist
+func (uc *useCase) Snapshot(entityType string, id uint) models.JSON {
+	hook, ok := uc.hooks[entityType]
+	if !ok {
+		return nil
+	}
+
+	entity, err := hook(id)
+	if err != nil {
+		return nil
+	}
+
+	doc, err := json.Marshal(entity)
+	if err != nil {
+		logger.Warn().Err(err).Str("entity_type", entityType).Uint("entity_id", id).Msg("Failed to encode audit snapshot")
+		return nil
+	}
+	return doc
+}
+
+// Record appends an audit log entry. Failures are logged only because the
+// audited request has already been answered.
+func (uc *useCase) Record(entry *auditDomain.Log) {
+	if err := uc.repo.Create(entry); err != nil {
+		logger.Error().Err(err).
+			Str("route", entry.Route).
+			Str("entity_type", entry.EntityType).
+			Msg("Failed to record audit log")
+	}
+}
+
+// GetAll retrieves audit log entries with pagination and optional filter
+func (uc *useCase) GetAll(limit, offset int, filter *ListFilter) (*GetAllResponse, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	repoFilter, err := filter.toRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	logs, total, err := uc.repo.GetAll(limit, offset, repoFilter)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get audit logs")
+		return nil, errors.New("failed to get audit logs")
+	}
+	if logs == nil {
+		logs = []auditDomain.Log{}
+	}
+
+	return &GetAllResponse{
+		Data:       logs,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, nil
+}
+
+// toRepository converts the inclusive Jakarta date range to the half-open
+// range used by the repository
+func (f *ListFilter) toRepository() (*auditRepo.Filter, error) {
+	if f == nil {
+		return nil, nil
+	}
+
+	filter := &auditRepo.Filter{
+		ActorID:    f.ActorID,
+		EntityType: f.EntityType,
+		EntityID:   f.EntityID,
+		Action:     f.Action,
+		Outcome:    f.Outcome,
+	}
+	if f.From != nil {
+		from := utils.StartOfDay(*f.From)
+		filter.From = &from
+	}
+	if f.To != nil {
+		to := utils.StartOfDay(*f.To).AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, errors.New("invalid date range")
+	}
+	return filter, nil
+}
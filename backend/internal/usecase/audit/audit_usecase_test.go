@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	auditDomain "github.com/madr/backend/internal/domain/audit"
+	auditRepo "github.com/madr/backend/internal/repository/audit"
+	"github.com/madr/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAuditRepository is a mock implementation of audit.Repository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(entry *auditDomain.Log) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) GetAll(limit, offset int, filter *auditRepo.Filter) ([]auditDomain.Log, int64, error) {
+	args := m.Called(limit, offset, filter)
+	return args.Get(0).([]auditDomain.Log), args.Get(1).(int64), args.Error(2)
+}
+
+type event struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+}
+
+// TestSnapshot tests that hooks are encoded and missing entities or entity
+// types without a hook give no snapshot
+func TestSnapshot(t *testing.T) {
+	useCase := NewUseCase(new(MockAuditRepository), map[string]SnapshotFunc{
+		"events": Snapshotter(func(id uint) (*event, error) {
+			if id != 9 {
+				return nil, errors.New("event not found")
+			}
+			return &event{ID: 9, Title: "Kajian"}, nil
+		}),
+	})
+
+	assert.JSONEq(t, `{"id":9,"title":"Kajian"}`, string(useCase.Snapshot("events", 9)))
+	assert.Nil(t, useCase.Snapshot("events", 10))
+	assert.Nil(t, useCase.Snapshot("zakat.settings", 0))
+}
+
+// TestGetAll_DateFilterIncludesWholeDays tests that the inclusive Jakarta date
+// range becomes a half-open range and that reversed ranges are rejected
+func TestGetAll_DateFilterIncludesWholeDays(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	useCase := NewUseCase(mockRepo, nil)
+
+	from, err := utils.ParseDate("2025-03-01")
+	require.NoError(t, err)
+	to, err := utils.ParseDate("2025-03-31")
+	require.NoError(t, err)
+
+	mockRepo.On("GetAll", 20, 0, mock.MatchedBy(func(f *auditRepo.Filter) bool {
+		return f.EntityType == "events" &&
+			f.From.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, utils.Jakarta)) &&
+			f.To.Equal(time.Date(2025, 4, 1, 0, 0, 0, 0, utils.Jakarta))
+	})).Return([]auditDomain.Log(nil), int64(0), nil)
+
+	response, err := useCase.GetAll(0, -5, &ListFilter{EntityType: "events", From: &from, To: &to})
+	require.NoError(t, err)
+	assert.NotNil(t, response.Data)
+	assert.Equal(t, 20, response.Limit)
+
+	_, err = useCase.GetAll(20, 0, &ListFilter{From: &to, To: &from})
+	assert.EqualError(t, err, "invalid date range")
+	mockRepo.AssertExpectations(t)
+}
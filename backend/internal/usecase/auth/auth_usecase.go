@@ -1,35 +1,58 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"sort"
+	"strings"
 	"time"
 
 	refreshTokenDomain "github.com/madr/backend/internal/domain/refreshtoken"
+	roleDomain "github.com/madr/backend/internal/domain/role"
 	userDomain "github.com/madr/backend/internal/domain/user"
 	refreshTokenRepo "github.com/madr/backend/internal/repository/refreshtoken"
 	userRepo "github.com/madr/backend/internal/repository/user"
+	mfaUsecase "github.com/madr/backend/internal/usecase/mfa"
 	"github.com/madr/backend/pkg/bcrypt"
 	"github.com/madr/backend/pkg/jwt"
 	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/password"
 )
 
 // UseCase defines the interface for auth use case
 type UseCase interface {
 	Register(req *RegisterRequest) (*RegisterResponse, error)
 	Login(req *LoginRequest, userAgent, ipAddress string) (*LoginResponse, error)
+	LoginMFA(req *MFALoginRequest, userAgent, ipAddress string) (*LoginResponse, error)
+	SetupMFA(req *MFASetupRequest) (*mfaUsecase.EnrollResponse, error)
+	ConfirmMFASetup(req *MFALoginRequest, userAgent, ipAddress string) (*MFASetupResponse, error)
 	RefreshToken(refreshToken string) (*RefreshTokenResponse, error)
 	GetMe(userID uint) (*MeResponse, error)
+	UpdateMe(userID uint, req *UpdateMeRequest, ipAddress string) (*UpdateMeResponse, error)
+	ChangePassword(userID uint, req *ChangePasswordRequest, userAgent, ipAddress string) (*ChangePasswordResponse, error)
 	Logout(refreshToken string) error
 	LogoutAll(userID uint) error
 }
 
-// RegisterRequest represents the request to register a new user
+// RegistrationMode controls who may register through the public endpoint
+type RegistrationMode string
+
+const (
+	RegistrationClosed RegistrationMode = "closed" // Accounts are created by admins only
+	RegistrationInvite RegistrationMode = "invite" // An invitation is required
+	RegistrationOpen   RegistrationMode = "open"   // Anyone may register as a regular user
+)
+
+// RegisterRequest represents the request to register a new user. Privileges
+// only ever come from the invitation; there is no way to ask for a role.
 type RegisterRequest struct {
-	Username string `json:"username" binding:"required,min=3,max=100"`
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
-	Name     string `json:"name" binding:"max=255"`
-	Role     string `json:"role,omitempty"` // Optional, defaults to "user"
+	Username    string `json:"username" binding:"required,min=3,max=100"`
+	Email       string `json:"email" binding:"required,email"`
+	Password    string `json:"password" binding:"required"`
+	Name        string `json:"name" binding:"max=255"`
+	InviteToken string `json:"invite_token"`
 }
 
 // RegisterResponse represents the response after registration
@@ -39,17 +62,40 @@ type RegisterResponse struct {
 
 // LoginRequest represents the request to login
 type LoginRequest struct {
-	Username string `json:"username" binding:"required"`
+	Username string `json:"username" binding:"required,max=100"`
 	Password string `json:"password" binding:"required"`
 }
 
-// LoginResponse represents the response after login
+// LoginResponse represents the response after login. When a second factor
+// is needed it carries only an MFA token for the next step.
 type LoginResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	TokenType    string `json:"token_type"`
-	ExpiresIn    int64  `json:"expires_in"`
-	User         *userDomain.User `json:"user"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenType    string `json:"token_type,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
+	User         *userDomain.User `json:"user,omitempty"`
+
+	MFARequired      bool   `json:"mfa_required,omitempty"`       // Send a code to /auth/login/2fa
+	MFASetupRequired bool   `json:"mfa_setup_required,omitempty"` // Enroll through /auth/2fa/setup first
+	MFAToken         string `json:"mfa_token,omitempty"`
+}
+
+// MFALoginRequest represents the second login step
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"` // Authenticator or recovery code
+}
+
+// MFASetupRequest represents the request to enroll during a login that
+// requires two-factor authentication
+type MFASetupRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+// MFASetupResponse completes a login that enrolled an authenticator
+type MFASetupResponse struct {
+	*LoginResponse
+	RecoveryCodes []string `json:"recovery_codes"`
 }
 
 // RefreshTokenRequest represents the request to refresh token
@@ -67,24 +113,125 @@ type RefreshTokenResponse struct {
 
 // MeResponse represents the current user information
 type MeResponse struct {
-	User *userDomain.User `json:"user"`
+	User        *userDomain.User `json:"user"`
+	Roles       []string         `json:"roles"`       // Names of the assigned roles
+	Permissions []string         `json:"permissions"` // Codes granted by those roles
+}
+
+// UpdateMeRequest represents the request to update the current user's
+// profile. Omitted fields are left unchanged.
+type UpdateMeRequest struct {
+	Name            *string `json:"name" binding:"omitempty,max=255"`
+	Email           *string `json:"email" binding:"omitempty,email"`
+	CurrentPassword string  `json:"current_password" binding:"required"`
+}
+
+// UpdateMeResponse represents the updated profile
+type UpdateMeResponse struct {
+	User         *userDomain.User `json:"user"`
+	EmailChanged bool             `json:"email_changed"` // The new email needs to be verified
+}
+
+// ChangePasswordRequest represents the request to change the current user's
+// password
+type ChangePasswordRequest struct {
+	CurrentPassword     string `json:"current_password" binding:"required"`
+	NewPassword         string `json:"new_password" binding:"required"`
+	LogoutOtherSessions bool   `json:"logout_other_sessions"`
+}
+
+// ChangePasswordResponse carries a fresh token pair when every other session
+// was logged out, since the caller's refresh token was revoked as well
+type ChangePasswordResponse struct {
+	*RefreshTokenResponse
+}
+
+// RoleLookup provides the roles assigned to a user
+type RoleLookup interface {
+	GetUserRoles(userID uint) ([]roleDomain.Role, error)
+}
+
+// InvitationAcceptor creates a user with the role granted by an invite token
+type InvitationAcceptor interface {
+	Accept(token string, usr *userDomain.User) error
+}
+
+// MFAService checks and enrolls the second factor of a login
+type MFAService interface {
+	IsEnabled(userID uint) (bool, error)
+	IsRequired(usr *userDomain.User) (bool, error)
+	Verify(userID uint, code string) error
+	Enroll(userID uint) (*mfaUsecase.EnrollResponse, error)
+	Confirm(userID uint, code string) (*mfaUsecase.RecoveryCodesResponse, error)
+}
+
+// LoginGuard throttles repeated failed logins per username and client IP
+type LoginGuard interface {
+	Check(username, ipAddress string) error
+	RecordFailure(username, ipAddress string) error
+	RecordSuccess(username string) error
 }
 
+const (
+	// mfaTokenExpiry is how long a user has to complete the second login step
+	mfaTokenExpiry = 5 * time.Minute
+	// refreshTokenBytes is the number of random bytes in a refresh token
+	refreshTokenBytes = 32
+	// familyIDBytes is the number of random bytes in a refresh token family ID
+	familyIDBytes = 16
+	// maxUserAgentLength is the size of the stored user agent column
+	maxUserAgentLength = 255
+)
+
 type useCase struct {
 	userRepo         userRepo.Repository
 	refreshTokenRepo refreshTokenRepo.Repository
+	roles            RoleLookup
+	invitations      InvitationAcceptor
+	mfa              MFAService
+	guard            LoginGuard
+	registration     RegistrationMode
 }
 
-// NewUseCase creates a new auth use case
-func NewUseCase(userRepoInstance userRepo.Repository, refreshTokenRepoInstance refreshTokenRepo.Repository) UseCase {
+// NewUseCase creates a new auth use case. Without a role lookup, tokens carry
+// no roles; without an MFA service, logins are password-only; without a login
+// guard, failed logins are not throttled; an unknown registration mode closes
+// registration.
+func NewUseCase(userRepoInstance userRepo.Repository, refreshTokenRepoInstance refreshTokenRepo.Repository, roles RoleLookup, invitations InvitationAcceptor, mfa MFAService, guard LoginGuard, registration RegistrationMode) UseCase {
+	switch registration {
+	case RegistrationClosed, RegistrationInvite, RegistrationOpen:
+	default:
+		logger.Warn().Str("mode", string(registration)).Msg("Unknown registration mode, registration is closed")
+		registration = RegistrationClosed
+	}
+
 	return &useCase{
 		userRepo:         userRepoInstance,
 		refreshTokenRepo: refreshTokenRepoInstance,
+		roles:            roles,
+		invitations:      invitations,
+		mfa:              mfa,
+		guard:            guard,
+		registration:     registration,
 	}
 }
 
-// Register registers a new user
+// Register registers a new user as allowed by the registration mode. Public
+// registrations are always regular users; roles come only from invitations.
 func (uc *useCase) Register(req *RegisterRequest) (*RegisterResponse, error) {
+	if uc.registration == RegistrationClosed {
+		return nil, errors.New("registration is closed")
+	}
+	if req.InviteToken == "" && uc.registration != RegistrationOpen {
+		return nil, errors.New("invitation required")
+	}
+	if req.InviteToken != "" && uc.invitations == nil {
+		return nil, errors.New("invalid invitation")
+	}
+	if err := password.Validate(req.Password, req.Username, req.Email); err != nil {
+		return nil, err
+	}
+
 	// Check if username already exists
 	exists, err := uc.userRepo.ExistsByUsername(req.Username)
 	if err != nil {
@@ -112,23 +259,21 @@ func (uc *useCase) Register(req *RegisterRequest) (*RegisterResponse, error) {
 		return nil, errors.New("failed to process password")
 	}
 
-	// Determine role
-	userRole := userDomain.RoleUser
-	if req.Role == "admin" {
-		userRole = userDomain.RoleAdmin
-	}
-
 	// Create user
 	newUser := &userDomain.User{
 		Username: req.Username,
 		Email:    req.Email,
 		Password: hashedPassword,
 		Name:     req.Name,
-		Role:     userRole,
+		Role:     userDomain.RoleUser,
 		IsActive: true,
 	}
 
-	if err := uc.userRepo.Create(newUser); err != nil {
+	if req.InviteToken != "" {
+		if err := uc.invitations.Accept(req.InviteToken, newUser); err != nil {
+			return nil, err
+		}
+	} else if err := uc.userRepo.Create(newUser); err != nil {
 		logger.Error().Err(err).Msg("Failed to create user")
 		return nil, errors.New("failed to create user")
 	}
@@ -145,10 +290,15 @@ func (uc *useCase) Register(req *RegisterRequest) (*RegisterResponse, error) {
 
 // Login authenticates a user and returns tokens
 func (uc *useCase) Login(req *LoginRequest, userAgent, ipAddress string) (*LoginResponse, error) {
+	if err := uc.checkGuard(req.Username, ipAddress); err != nil {
+		return nil, err
+	}
+
 	// Get user by username
 	usr, err := uc.userRepo.GetByUsername(req.Username)
 	if err != nil {
 		logger.Warn().Str("username", req.Username).Msg("Login attempt with invalid username")
+		uc.recordFailure(req.Username, ipAddress)
 		return nil, errors.New("invalid credentials")
 	}
 
@@ -161,36 +311,147 @@ func (uc *useCase) Login(req *LoginRequest, userAgent, ipAddress string) (*Login
 	// Verify password
 	if !bcrypt.CheckPasswordHash(req.Password, usr.Password) {
 		logger.Warn().Uint("user_id", usr.ID).Msg("Login attempt with invalid password")
+		uc.recordFailure(req.Username, ipAddress)
 		return nil, errors.New("invalid credentials")
 	}
 
-	// Generate access token
-	accessToken, err := jwt.GenerateAccessToken(usr.ID, usr.Username, string(usr.Role))
+	if uc.mfa != nil {
+		enabled, err := uc.mfa.IsEnabled(usr.ID)
+		if err != nil {
+			return nil, err
+		}
+		if enabled {
+			return uc.mfaChallenge(usr, false)
+		}
+		required, err := uc.mfa.IsRequired(usr)
+		if err != nil {
+			return nil, err
+		}
+		if required {
+			return uc.mfaChallenge(usr, true)
+		}
+	}
+
+	return uc.completeLogin(usr, userAgent, ipAddress)
+}
+
+// LoginMFA completes a login with an authenticator or recovery code
+func (uc *useCase) LoginMFA(req *MFALoginRequest, userAgent, ipAddress string) (*LoginResponse, error) {
+	usr, err := uc.challengeUser(req.MFAToken, false)
 	if err != nil {
-		logger.Error().Err(err).Uint("user_id", usr.ID).Msg("Failed to generate access token")
+		return nil, err
+	}
+	if err := uc.checkGuard(usr.Username, ipAddress); err != nil {
+		return nil, err
+	}
+	if err := uc.mfa.Verify(usr.ID, req.Code); err != nil {
+		logger.Warn().Uint("user_id", usr.ID).Msg("Login attempt with invalid two-factor code")
+		if err.Error() == "invalid code" {
+			uc.recordFailure(usr.Username, ipAddress)
+		}
+		return nil, err
+	}
+	return uc.completeLogin(usr, userAgent, ipAddress)
+}
+
+// SetupMFA starts enrolling an authenticator for a user whose role requires
+// two-factor authentication before they can log in
+func (uc *useCase) SetupMFA(req *MFASetupRequest) (*mfaUsecase.EnrollResponse, error) {
+	usr, err := uc.challengeUser(req.MFAToken, true)
+	if err != nil {
+		return nil, err
+	}
+	return uc.mfa.Enroll(usr.ID)
+}
+
+// ConfirmMFASetup enables the enrolled authenticator and completes the login
+func (uc *useCase) ConfirmMFASetup(req *MFALoginRequest, userAgent, ipAddress string) (*MFASetupResponse, error) {
+	usr, err := uc.challengeUser(req.MFAToken, true)
+	if err != nil {
+		return nil, err
+	}
+	codes, err := uc.mfa.Confirm(usr.ID, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := uc.completeLogin(usr, userAgent, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &MFASetupResponse{LoginResponse: response, RecoveryCodes: codes.RecoveryCodes}, nil
+}
+
+// mfaChallenge answers a correct password with a token for the second step
+func (uc *useCase) mfaChallenge(usr *userDomain.User, setup bool) (*LoginResponse, error) {
+	token, err := jwt.GenerateMFAToken(usr.ID, setup, time.Now().Add(mfaTokenExpiry))
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", usr.ID).Msg("Failed to generate MFA token")
 		return nil, errors.New("failed to generate token")
 	}
 
-	// Generate refresh token
-	refreshTokenString, err := jwt.GenerateRefreshToken()
+	logger.Info().Uint("user_id", usr.ID).Bool("setup", setup).Msg("Password accepted, second factor required")
+
+	return &LoginResponse{
+		MFARequired:      !setup,
+		MFASetupRequired: setup,
+		MFAToken:         token,
+	}, nil
+}
+
+// challengeUser returns the active user an MFA token was issued to
+func (uc *useCase) challengeUser(mfaToken string, setup bool) (*userDomain.User, error) {
+	if uc.mfa == nil {
+		return nil, errors.New("invalid or expired mfa token")
+	}
+	claims, err := jwt.ValidateMFAToken(mfaToken)
+	if err != nil || claims.Setup != setup {
+		return nil, errors.New("invalid or expired mfa token")
+	}
+
+	usr, err := uc.userRepo.GetByID(claims.UserID)
 	if err != nil {
-		logger.Error().Err(err).Uint("user_id", usr.ID).Msg("Failed to generate refresh token")
-		return nil, errors.New("failed to generate refresh token")
+		logger.Error().Err(err).Uint("user_id", claims.UserID).Msg("Failed to get user for MFA token")
+		return nil, errors.New("invalid or expired mfa token")
+	}
+	if !usr.IsActive {
+		return nil, errors.New("account is inactive")
 	}
+	return usr, nil
+}
 
-	// Store refresh token in database
-	refreshToken := &refreshTokenDomain.RefreshToken{
-		Token:     refreshTokenString,
-		UserID:    usr.ID,
-		ExpiresAt: time.Now().Add(jwt.GetRefreshTokenExpiry()),
-		IsRevoked: false,
-		UserAgent: userAgent,
-		IPAddress: ipAddress,
+// checkGuard returns an error while the username or client IP is throttled
+func (uc *useCase) checkGuard(username, ipAddress string) error {
+	if uc.guard == nil {
+		return nil
+	}
+	if err := uc.guard.Check(username, ipAddress); err != nil {
+		logger.Warn().Err(err).Str("username", username).Str("ip", ipAddress).Msg("Login attempt while throttled")
+		return err
 	}
+	return nil
+}
 
-	if err := uc.refreshTokenRepo.Create(refreshToken); err != nil {
-		logger.Error().Err(err).Uint("user_id", usr.ID).Msg("Failed to store refresh token")
-		return nil, errors.New("failed to store refresh token")
+// recordFailure counts a failed login. Errors are logged by the guard and do
+// not change the response.
+func (uc *useCase) recordFailure(username, ipAddress string) {
+	if uc.guard != nil {
+		_ = uc.guard.RecordFailure(username, ipAddress)
+	}
+}
+
+// completeLogin issues the tokens of a new session
+func (uc *useCase) completeLogin(usr *userDomain.User, userAgent, ipAddress string) (*LoginResponse, error) {
+	// Generate and store refresh token
+	refreshTokenString, sessionID, err := uc.issueRefreshToken(usr.ID, userAgent, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate access token
+	accessToken, err := uc.generateAccessToken(usr, sessionID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Update last login
@@ -198,6 +459,9 @@ func (uc *useCase) Login(req *LoginRequest, userAgent, ipAddress string) (*Login
 		logger.Warn().Err(err).Uint("user_id", usr.ID).Msg("Failed to update last login")
 		// Don't fail the login if this fails
 	}
+	if uc.guard != nil {
+		_ = uc.guard.RecordSuccess(usr.Username)
+	}
 
 	logger.Info().
 		Uint("user_id", usr.ID).
@@ -213,18 +477,24 @@ func (uc *useCase) Login(req *LoginRequest, userAgent, ipAddress string) (*Login
 	}, nil
 }
 
-// RefreshToken refreshes an access token using a refresh token
+// RefreshToken rotates a refresh token and issues a new access token.
+// Presenting a token that was already rotated or revoked means it may have
+// been stolen, so every token of its login is revoked.
 func (uc *useCase) RefreshToken(refreshTokenString string) (*RefreshTokenResponse, error) {
 	// Get refresh token from database
-	rt, err := uc.refreshTokenRepo.GetByToken(refreshTokenString)
+	rt, err := uc.refreshTokenRepo.GetByTokenHash(hashRefreshToken(refreshTokenString))
 	if err != nil {
 		logger.Warn().Msg("Invalid refresh token")
 		return nil, errors.New("invalid refresh token")
 	}
 
 	// Check if token is valid
-	if !rt.IsValid() {
-		logger.Warn().Uint("token_id", rt.ID).Msg("Refresh token is expired or revoked")
+	if rt.IsRevoked {
+		uc.revokeFamily(rt)
+		return nil, errors.New("refresh token is expired or revoked")
+	}
+	if rt.IsExpired() {
+		logger.Warn().Uint("token_id", rt.ID).Msg("Refresh token is expired")
 		return nil, errors.New("refresh token is expired or revoked")
 	}
 
@@ -242,37 +512,34 @@ func (uc *useCase) RefreshToken(refreshTokenString string) (*RefreshTokenRespons
 	}
 
 	// Generate new access token
-	accessToken, err := jwt.GenerateAccessToken(usr.ID, usr.Username, string(usr.Role))
+	accessToken, err := uc.generateAccessToken(usr, rt.FamilyID)
 	if err != nil {
-		logger.Error().Err(err).Uint("user_id", usr.ID).Msg("Failed to generate access token")
-		return nil, errors.New("failed to generate token")
+		return nil, err
 	}
 
-	// Optionally generate new refresh token (rotate refresh token)
-	newRefreshTokenString, err := jwt.GenerateRefreshToken()
+	// Rotate the refresh token within its family
+	newRefreshTokenString, err := randomHex(refreshTokenBytes)
 	if err != nil {
 		logger.Error().Err(err).Uint("user_id", usr.ID).Msg("Failed to generate new refresh token")
 		return nil, errors.New("failed to generate refresh token")
 	}
-
-	// Revoke old refresh token
-	if err := uc.refreshTokenRepo.Revoke(rt.Token); err != nil {
-		logger.Warn().Err(err).Uint("token_id", rt.ID).Msg("Failed to revoke old refresh token")
-		// Continue anyway
-	}
-
-	// Store new refresh token
 	newRefreshToken := &refreshTokenDomain.RefreshToken{
-		Token:     newRefreshTokenString,
-		UserID:    usr.ID,
-		ExpiresAt: time.Now().Add(jwt.GetRefreshTokenExpiry()),
-		IsRevoked: false,
-		UserAgent: rt.UserAgent,
-		IPAddress: rt.IPAddress,
+		TokenHash:  hashRefreshToken(newRefreshTokenString),
+		FamilyID:   rt.FamilyID,
+		UserID:     usr.ID,
+		ExpiresAt:  time.Now().Add(jwt.GetRefreshTokenExpiry()),
+		IsRevoked:  false,
+		UserAgent:  rt.UserAgent,
+		IPAddress:  rt.IPAddress,
+		SignedInAt: rt.SignedInAt,
 	}
-
-	if err := uc.refreshTokenRepo.Create(newRefreshToken); err != nil {
-		logger.Error().Err(err).Uint("user_id", usr.ID).Msg("Failed to store new refresh token")
+	if err := uc.refreshTokenRepo.Rotate(rt.ID, newRefreshToken); err != nil {
+		if errors.Is(err, refreshTokenRepo.ErrUnavailable) {
+			// Another request rotated the token first
+			uc.revokeFamily(rt)
+			return nil, errors.New("refresh token is expired or revoked")
+		}
+		logger.Error().Err(err).Uint("user_id", usr.ID).Msg("Failed to rotate refresh token")
 		return nil, errors.New("failed to store refresh token")
 	}
 
@@ -288,6 +555,24 @@ func (uc *useCase) RefreshToken(refreshTokenString string) (*RefreshTokenRespons
 	}, nil
 }
 
+// revokeFamily revokes every token rotated from the same login as rt
+func (uc *useCase) revokeFamily(rt *refreshTokenDomain.RefreshToken) {
+	revoked, err := uc.refreshTokenRepo.RevokeFamily(rt.FamilyID)
+	if err != nil {
+		logger.Error().Err(err).Uint("token_id", rt.ID).Msg("Failed to revoke refresh token family")
+		return
+	}
+	if revoked > 0 {
+		logger.Warn().
+			Uint("user_id", rt.UserID).
+			Uint("token_id", rt.ID).
+			Int64("revoked", revoked).
+			Msg("Revoked refresh token reused, session revoked")
+		return
+	}
+	logger.Warn().Uint("token_id", rt.ID).Msg("Refresh token is revoked")
+}
+
 // GetMe returns the current user information
 func (uc *useCase) GetMe(userID uint) (*MeResponse, error) {
 	usr, err := uc.userRepo.GetByID(userID)
@@ -296,14 +581,212 @@ func (uc *useCase) GetMe(userID uint) (*MeResponse, error) {
 		return nil, errors.New("user not found")
 	}
 
-	return &MeResponse{
-		User: usr,
-	}, nil
+	roles, err := uc.userRoles(usr.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &MeResponse{
+		User:        usr,
+		Roles:       []string{},
+		Permissions: []string{},
+	}
+	granted := make(map[string]bool)
+	for _, r := range roles {
+		response.Roles = append(response.Roles, r.Name)
+		for _, code := range r.PermissionCodes() {
+			if !granted[code] {
+				granted[code] = true
+				response.Permissions = append(response.Permissions, code)
+			}
+		}
+	}
+	sort.Strings(response.Permissions)
+
+	return response, nil
+}
+
+// UpdateMe updates the current user's name and email after checking their
+// password. A new email has to be verified again.
+func (uc *useCase) UpdateMe(userID uint, req *UpdateMeRequest, ipAddress string) (*UpdateMeResponse, error) {
+	usr, err := uc.currentUser(userID, req.CurrentPassword, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &UpdateMeResponse{}
+	if req.Email != nil {
+		email := strings.TrimSpace(*req.Email)
+		if !strings.EqualFold(email, usr.Email) {
+			exists, err := uc.userRepo.ExistsByEmail(email)
+			if err != nil {
+				logger.Error().Err(err).Msg("Failed to check email existence")
+				return nil, errors.New("failed to check email")
+			}
+			if exists {
+				return nil, errors.New("email already exists")
+			}
+			response.EmailChanged = true
+			usr.EmailVerifiedAt = nil
+		}
+		usr.Email = email
+	}
+	if req.Name != nil {
+		usr.Name = strings.TrimSpace(*req.Name)
+	}
+
+	if err := uc.userRepo.Update(usr); err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to update profile")
+		return nil, errors.New("failed to update profile")
+	}
+
+	logger.Info().Uint("user_id", userID).Bool("email_changed", response.EmailChanged).Msg("Profile updated")
+
+	response.User = usr
+	return response, nil
+}
+
+// ChangePassword sets a new password after checking the current one. With
+// LogoutOtherSessions, every refresh token is revoked and the caller gets a
+// new token pair.
+func (uc *useCase) ChangePassword(userID uint, req *ChangePasswordRequest, userAgent, ipAddress string) (*ChangePasswordResponse, error) {
+	usr, err := uc.currentUser(userID, req.CurrentPassword, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := password.Validate(req.NewPassword, usr.Username, usr.Email); err != nil {
+		return nil, err
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return nil, errors.New("new password must be different from the current password")
+	}
+
+	if usr.Password, err = bcrypt.HashPassword(req.NewPassword); err != nil {
+		logger.Error().Err(err).Msg("Failed to hash password")
+		return nil, errors.New("failed to process password")
+	}
+	if err := uc.userRepo.Update(usr); err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to change password")
+		return nil, errors.New("failed to change password")
+	}
+
+	logger.Info().Uint("user_id", userID).Bool("logout_other_sessions", req.LogoutOtherSessions).Msg("Password changed")
+
+	if !req.LogoutOtherSessions {
+		return &ChangePasswordResponse{}, nil
+	}
+	if err := uc.refreshTokenRepo.RevokeAllByUserID(userID); err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to revoke refresh tokens after password change")
+		return nil, errors.New("failed to logout other sessions")
+	}
+
+	refreshTokenString, sessionID, err := uc.issueRefreshToken(usr.ID, userAgent, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := uc.generateAccessToken(usr, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &ChangePasswordResponse{&RefreshTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshTokenString,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(jwt.GetAccessTokenExpiry().Seconds()),
+	}}, nil
+}
+
+// currentUser loads an active user and checks their password. Wrong
+// passwords count towards the same lockout as failed logins.
+func (uc *useCase) currentUser(userID uint, currentPassword, ipAddress string) (*userDomain.User, error) {
+	usr, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to get user")
+		return nil, errors.New("user not found")
+	}
+	if !usr.IsActive {
+		return nil, errors.New("account is inactive")
+	}
+	if err := uc.checkGuard(usr.Username, ipAddress); err != nil {
+		return nil, err
+	}
+	if !bcrypt.CheckPasswordHash(currentPassword, usr.Password) {
+		logger.Warn().Uint("user_id", userID).Msg("Invalid current password")
+		uc.recordFailure(usr.Username, ipAddress)
+		return nil, errors.New("invalid current password")
+	}
+	return usr, nil
+}
+
+// issueRefreshToken creates and stores the first refresh token of a new
+// session, starting a new token family. It returns the token and the
+// session ID.
+func (uc *useCase) issueRefreshToken(userID uint, userAgent, ipAddress string) (string, string, error) {
+	refreshTokenString, err := randomHex(refreshTokenBytes)
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to generate refresh token")
+		return "", "", errors.New("failed to generate refresh token")
+	}
+	familyID, err := randomHex(familyIDBytes)
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to generate refresh token family")
+		return "", "", errors.New("failed to generate refresh token")
+	}
+
+	now := time.Now()
+	refreshToken := &refreshTokenDomain.RefreshToken{
+		TokenHash:  hashRefreshToken(refreshTokenString),
+		FamilyID:   familyID,
+		UserID:     userID,
+		ExpiresAt:  now.Add(jwt.GetRefreshTokenExpiry()),
+		IsRevoked:  false,
+		UserAgent:  truncate(userAgent, maxUserAgentLength),
+		IPAddress:  ipAddress,
+		SignedInAt: now,
+	}
+	if err := uc.refreshTokenRepo.Create(refreshToken); err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to store refresh token")
+		return "", "", errors.New("failed to store refresh token")
+	}
+	return refreshTokenString, familyID, nil
+}
+
+// generateAccessToken issues an access token for a session carrying the
+// user's current roles
+func (uc *useCase) generateAccessToken(usr *userDomain.User, sessionID string) (string, error) {
+	roles, err := uc.userRoles(usr.ID)
+	if err != nil {
+		return "", err
+	}
+	roleIDs := make([]uint, len(roles))
+	for i, r := range roles {
+		roleIDs[i] = r.ID
+	}
+
+	accessToken, err := jwt.GenerateAccessToken(usr.ID, usr.Username, string(usr.Role), roleIDs, sessionID)
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", usr.ID).Msg("Failed to generate access token")
+		return "", errors.New("failed to generate token")
+	}
+	return accessToken, nil
+}
+
+func (uc *useCase) userRoles(userID uint) ([]roleDomain.Role, error) {
+	if uc.roles == nil {
+		return nil, nil
+	}
+	roles, err := uc.roles.GetUserRoles(userID)
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to get user roles")
+		return nil, errors.New("failed to get user roles")
+	}
+	return roles, nil
 }
 
 // Logout revokes a refresh token
 func (uc *useCase) Logout(refreshToken string) error {
-	if err := uc.refreshTokenRepo.Revoke(refreshToken); err != nil {
+	if err := uc.refreshTokenRepo.Revoke(hashRefreshToken(refreshToken)); err != nil {
 		logger.Error().Err(err).Msg("Failed to revoke refresh token")
 		return errors.New("failed to logout")
 	}
@@ -323,3 +806,25 @@ func (uc *useCase) LogoutAll(userID uint) error {
 	return nil
 }
 
+// randomHex returns n random bytes encoded as hex
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashRefreshToken returns the SHA-256 digest stored for a refresh token
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// truncate shortens s to at most max bytes without splitting a character
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return strings.ToValidUTF8(s[:max], "")
+}
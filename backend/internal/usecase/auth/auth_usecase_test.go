@@ -1,16 +1,44 @@
 package auth
 
 import (
+	"encoding/json"
+	"errors"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/madr/backend/internal/config"
 	"github.com/madr/backend/internal/domain/models"
 	refreshTokenDomain "github.com/madr/backend/internal/domain/refreshtoken"
 	userDomain "github.com/madr/backend/internal/domain/user"
+	refreshTokenRepo "github.com/madr/backend/internal/repository/refreshtoken"
+	userRepo "github.com/madr/backend/internal/repository/user"
+	"github.com/madr/backend/internal/usecase/lockout"
+	mfaUsecase "github.com/madr/backend/internal/usecase/mfa"
+	"github.com/madr/backend/pkg/bcrypt"
+	"github.com/madr/backend/pkg/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+func TestMain(m *testing.M) {
+	config.AppConfig = &config.Config{JWT: config.JWTConfig{
+		Secret:        "test-secret",
+		AccessExpiry:  15 * time.Minute,
+		RefreshExpiry: 24 * time.Hour,
+	}}
+	os.Exit(m.Run())
+}
+
+// passwordHash hashes password123, the password of the test users
+func passwordHash(t *testing.T) string {
+	t.Helper()
+	hash, err := bcrypt.HashPassword("password123")
+	require.NoError(t, err)
+	return hash
+}
+
 // MockUserRepository is a mock implementation of user.Repository
 type MockUserRepository struct {
 	mock.Mock
@@ -45,6 +73,11 @@ func (m *MockUserRepository) GetByEmail(email string) (*userDomain.User, error)
 	return args.Get(0).(*userDomain.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetAll(limit, offset int, filter *userRepo.Filter) ([]userDomain.User, int64, error) {
+	args := m.Called(limit, offset, filter)
+	return args.Get(0).([]userDomain.User), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockUserRepository) Update(usr *userDomain.User) error {
 	args := m.Called(usr)
 	return args.Error(0)
@@ -80,8 +113,8 @@ func (m *MockRefreshTokenRepository) Create(rt *refreshTokenDomain.RefreshToken)
 	return args.Error(0)
 }
 
-func (m *MockRefreshTokenRepository) GetByToken(token string) (*refreshTokenDomain.RefreshToken, error) {
-	args := m.Called(token)
+func (m *MockRefreshTokenRepository) GetByTokenHash(tokenHash string) (*refreshTokenDomain.RefreshToken, error) {
+	args := m.Called(tokenHash)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -93,19 +126,34 @@ func (m *MockRefreshTokenRepository) GetByUserID(userID uint) ([]refreshTokenDom
 	return args.Get(0).([]refreshTokenDomain.RefreshToken), args.Error(1)
 }
 
-func (m *MockRefreshTokenRepository) Revoke(token string) error {
-	args := m.Called(token)
+func (m *MockRefreshTokenRepository) GetActiveByUserID(userID uint, now time.Time) ([]refreshTokenDomain.RefreshToken, error) {
+	args := m.Called(userID, now)
+	return args.Get(0).([]refreshTokenDomain.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Rotate(id uint, next *refreshTokenDomain.RefreshToken) error {
+	args := m.Called(id, next)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) Revoke(tokenHash string) error {
+	args := m.Called(tokenHash)
 	return args.Error(0)
 }
 
+func (m *MockRefreshTokenRepository) RevokeFamily(familyID string) (int64, error) {
+	args := m.Called(familyID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockRefreshTokenRepository) RevokeAllByUserID(userID uint) error {
 	args := m.Called(userID)
 	return args.Error(0)
 }
 
-func (m *MockRefreshTokenRepository) DeleteExpired() error {
-	args := m.Called()
-	return args.Error(0)
+func (m *MockRefreshTokenRepository) DeleteInactive(now time.Time) (int64, error) {
+	args := m.Called(now)
+	return args.Get(0).(int64), args.Error(1)
 }
 
 func (m *MockRefreshTokenRepository) Delete(id uint) error {
@@ -124,7 +172,7 @@ func TestLogin_Success(t *testing.T) {
 		BaseModel: models.BaseModel{ID: 1},
 		Username:  "testuser",
 		Email:     "test@example.com",
-		Password:  "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy", // bcrypt hash for "password123"
+		Password:  passwordHash(t),
 		Name:      "Test User",
 		Role:      userDomain.RoleUser,
 		IsActive:  true,
@@ -136,7 +184,7 @@ func TestLogin_Success(t *testing.T) {
 	mockRefreshTokenRepo.On("Create", mock.Anything).Return(nil)
 
 	// Create use case
-	useCase := NewUseCase(mockUserRepo, mockRefreshTokenRepo)
+	useCase := NewUseCase(mockUserRepo, mockRefreshTokenRepo, nil, nil, nil, nil, RegistrationClosed)
 
 	// Test login
 	req := &LoginRequest{
@@ -179,7 +227,7 @@ func TestLogin_InvalidCredentials(t *testing.T) {
 	mockUserRepo.On("GetByUsername", "testuser").Return(testUser, nil)
 
 	// Create use case
-	useCase := NewUseCase(mockUserRepo, mockRefreshTokenRepo)
+	useCase := NewUseCase(mockUserRepo, mockRefreshTokenRepo, nil, nil, nil, nil, RegistrationClosed)
 
 	// Test login with wrong password
 	req := &LoginRequest{
@@ -218,7 +266,7 @@ func TestLogin_InactiveUser(t *testing.T) {
 	mockUserRepo.On("GetByUsername", "testuser").Return(testUser, nil)
 
 	// Create use case
-	useCase := NewUseCase(mockUserRepo, mockRefreshTokenRepo)
+	useCase := NewUseCase(mockUserRepo, mockRefreshTokenRepo, nil, nil, nil, nil, RegistrationClosed)
 
 	// Test login
 	req := &LoginRequest{
@@ -254,22 +302,27 @@ func TestRefreshToken_Success(t *testing.T) {
 	}
 
 	// Create valid refresh token
+	signedInAt := time.Now().Add(-72 * time.Hour)
 	validToken := &refreshTokenDomain.RefreshToken{
-		BaseModel: models.BaseModel{ID: 1},
-		Token:     "valid-refresh-token",
-		UserID:    1,
-		ExpiresAt: time.Now().Add(24 * time.Hour),
-		IsRevoked: false,
+		BaseModel:  models.BaseModel{ID: 1},
+		TokenHash:  hashRefreshToken("valid-refresh-token"),
+		FamilyID:   "family-1",
+		UserID:     1,
+		ExpiresAt:  time.Now().Add(24 * time.Hour),
+		IsRevoked:  false,
+		SignedInAt: signedInAt,
 	}
 
 	// Setup expectations
-	mockRefreshTokenRepo.On("GetByToken", "valid-refresh-token").Return(validToken, nil)
+	var rotated *refreshTokenDomain.RefreshToken
+	mockRefreshTokenRepo.On("GetByTokenHash", hashRefreshToken("valid-refresh-token")).Return(validToken, nil)
 	mockUserRepo.On("GetByID", uint(1)).Return(testUser, nil)
-	mockRefreshTokenRepo.On("Revoke", "valid-refresh-token").Return(nil)
-	mockRefreshTokenRepo.On("Create", mock.Anything).Return(nil)
+	mockRefreshTokenRepo.On("Rotate", uint(1), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		rotated = args.Get(1).(*refreshTokenDomain.RefreshToken)
+	})
 
 	// Create use case
-	useCase := NewUseCase(mockUserRepo, mockRefreshTokenRepo)
+	useCase := NewUseCase(mockUserRepo, mockRefreshTokenRepo, nil, nil, nil, nil, RegistrationClosed)
 
 	// Test refresh token
 	response, err := useCase.RefreshToken("valid-refresh-token")
@@ -281,8 +334,437 @@ func TestRefreshToken_Success(t *testing.T) {
 	assert.NotEmpty(t, response.RefreshToken)
 	assert.Equal(t, "Bearer", response.TokenType)
 
+	// The new token stays in the family and only its hash is stored
+	require.NotNil(t, rotated)
+	assert.Len(t, response.RefreshToken, 2*refreshTokenBytes)
+	assert.Equal(t, hashRefreshToken(response.RefreshToken), rotated.TokenHash)
+	assert.Equal(t, "family-1", rotated.FamilyID)
+	assert.Equal(t, signedInAt, rotated.SignedInAt)
+
+	// The access token belongs to the same session
+	claims, err := jwt.ValidateToken(response.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "family-1", claims.SessionID)
+
 	// Verify expectations
 	mockUserRepo.AssertExpectations(t)
 	mockRefreshTokenRepo.AssertExpectations(t)
 }
 
+// TestRefreshToken_Reuse tests that reusing a rotated token revokes its whole family
+func TestRefreshToken_Reuse(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+	rotatedAt := time.Now().Add(-time.Minute)
+	rotatedToken := &refreshTokenDomain.RefreshToken{
+		BaseModel: models.BaseModel{ID: 1},
+		TokenHash: hashRefreshToken("stolen-refresh-token"),
+		FamilyID:  "family-1",
+		UserID:    1,
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+		IsRevoked: true,
+		RevokedAt: &rotatedAt,
+	}
+	mockRefreshTokenRepo.On("GetByTokenHash", hashRefreshToken("stolen-refresh-token")).Return(rotatedToken, nil)
+	mockRefreshTokenRepo.On("RevokeFamily", "family-1").Return(int64(1), nil)
+
+	useCase := NewUseCase(mockUserRepo, mockRefreshTokenRepo, nil, nil, nil, nil, RegistrationClosed)
+
+	_, err := useCase.RefreshToken("stolen-refresh-token")
+	assert.EqualError(t, err, "refresh token is expired or revoked")
+	mockRefreshTokenRepo.AssertExpectations(t)
+	mockRefreshTokenRepo.AssertNotCalled(t, "Rotate", mock.Anything, mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+}
+
+// TestRefreshToken_ConcurrentRotation tests that losing a rotation race is treated as reuse
+func TestRefreshToken_ConcurrentRotation(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+	testUser := &userDomain.User{BaseModel: models.BaseModel{ID: 1}, Username: "testuser", Role: userDomain.RoleUser, IsActive: true}
+	token := &refreshTokenDomain.RefreshToken{
+		BaseModel: models.BaseModel{ID: 1},
+		TokenHash: hashRefreshToken("raced-refresh-token"),
+		FamilyID:  "family-1",
+		UserID:    1,
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+	}
+	mockRefreshTokenRepo.On("GetByTokenHash", hashRefreshToken("raced-refresh-token")).Return(token, nil)
+	mockUserRepo.On("GetByID", uint(1)).Return(testUser, nil)
+	mockRefreshTokenRepo.On("Rotate", uint(1), mock.Anything).Return(refreshTokenRepo.ErrUnavailable)
+	mockRefreshTokenRepo.On("RevokeFamily", "family-1").Return(int64(1), nil)
+
+	useCase := NewUseCase(mockUserRepo, mockRefreshTokenRepo, nil, nil, nil, nil, RegistrationClosed)
+
+	_, err := useCase.RefreshToken("raced-refresh-token")
+	assert.EqualError(t, err, "refresh token is expired or revoked")
+	mockRefreshTokenRepo.AssertExpectations(t)
+}
+
+// fakeInvitations accepts every token except "used-token"
+type fakeInvitations struct {
+	accepted []*userDomain.User
+}
+
+func (f *fakeInvitations) Accept(token string, usr *userDomain.User) error {
+	if token == "used-token" {
+		return errors.New("invitation has already been used")
+	}
+	usr.ID = 7
+	f.accepted = append(f.accepted, usr)
+	return nil
+}
+
+func newRegisterRequest(t *testing.T, body string) *RegisterRequest {
+	t.Helper()
+	var req RegisterRequest
+	require.NoError(t, json.Unmarshal([]byte(body), &req))
+	return &req
+}
+
+// TestRegister_CannotRequestAdminRole tests that a public registration asking
+// for the admin role still creates a regular user
+func TestRegister_CannotRequestAdminRole(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+	mockUserRepo.On("ExistsByUsername", "eve").Return(false, nil)
+	mockUserRepo.On("ExistsByEmail", "eve@example.com").Return(false, nil)
+	mockUserRepo.On("Create", mock.MatchedBy(func(usr *userDomain.User) bool {
+		return usr.Role == userDomain.RoleUser
+	})).Return(nil)
+
+	useCase := NewUseCase(mockUserRepo, mockRefreshTokenRepo, nil, nil, nil, nil, RegistrationOpen)
+	req := newRegisterRequest(t, `{"username":"eve","email":"eve@example.com","password":"kurma-ajwa7","role":"admin","roles":["admin"],"role_ids":[1]}`)
+
+	response, err := useCase.Register(req)
+	require.NoError(t, err)
+	assert.Equal(t, userDomain.RoleUser, response.User.Role)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// TestRegister_Modes tests that closed and invite-only modes reject public registration
+func TestRegister_Modes(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    RegistrationMode
+		token   string
+		wantErr string
+	}{
+		{name: "closed", mode: RegistrationClosed, wantErr: "registration is closed"},
+		{name: "closed with invitation", mode: RegistrationClosed, token: "invite-token", wantErr: "registration is closed"},
+		{name: "unknown mode is closed", mode: "everyone", wantErr: "registration is closed"},
+		{name: "invite without invitation", mode: RegistrationInvite, wantErr: "invitation required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := new(MockUserRepository)
+			invitations := &fakeInvitations{}
+			useCase := NewUseCase(mockUserRepo, new(MockRefreshTokenRepository), nil, invitations, nil, nil, tt.mode)
+
+			req := newRegisterRequest(t, `{"username":"eve","email":"eve@example.com","password":"kurma-ajwa7","role":"admin"}`)
+			req.InviteToken = tt.token
+
+			response, err := useCase.Register(req)
+			assert.Nil(t, response)
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Empty(t, invitations.accepted)
+			mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+// TestRegister_PasswordPolicy tests that new accounts follow the password policy
+func TestRegister_PasswordPolicy(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	useCase := NewUseCase(mockUserRepo, new(MockRefreshTokenRepository), nil, nil, nil, nil, RegistrationOpen)
+
+	for password, wantErr := range map[string]string{
+		"secret1":      "password must be at least 8 characters",
+		"secretsecret": "password must contain letters and numbers",
+		"eve-kurma-7":  "password must not contain your username or email",
+	} {
+		req := newRegisterRequest(t, `{"username":"eve","email":"eve@example.com"}`)
+		req.Password = password
+		_, err := useCase.Register(req)
+		assert.EqualError(t, err, wantErr, password)
+	}
+	mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestRegister_WithInvitation tests that invited users are created through the invitation
+func TestRegister_WithInvitation(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("ExistsByUsername", "bendahara").Return(false, nil)
+	mockUserRepo.On("ExistsByEmail", "bendahara@example.com").Return(false, nil)
+	invitations := &fakeInvitations{}
+	useCase := NewUseCase(mockUserRepo, new(MockRefreshTokenRepository), nil, invitations, nil, nil, RegistrationInvite)
+
+	req := newRegisterRequest(t, `{"username":"bendahara","email":"bendahara@example.com","password":"kurma-ajwa7","role":"admin","invite_token":"invite-token"}`)
+	response, err := useCase.Register(req)
+	require.NoError(t, err)
+	require.Len(t, invitations.accepted, 1)
+	assert.Equal(t, uint(7), response.User.ID)
+	assert.Equal(t, userDomain.RoleUser, response.User.Role)
+	mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+
+	req.InviteToken = "used-token"
+	_, err = useCase.Register(req)
+	assert.EqualError(t, err, "invitation has already been used")
+}
+
+// TestUpdateMe tests profile updates guarded by the current password
+func TestUpdateMe(t *testing.T) {
+	verifiedAt := time.Now()
+	newUser := func() *userDomain.User {
+		return &userDomain.User{
+			BaseModel:       models.BaseModel{ID: 1},
+			Username:        "testuser",
+			Email:           "test@example.com",
+			Password:        passwordHash(t),
+			IsActive:        true,
+			EmailVerifiedAt: &verifiedAt,
+		}
+	}
+	name, taken, fresh := "Hamba Allah", "taken@example.com", "baru@example.com"
+
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("GetByID", uint(1)).Return(newUser(), nil).Once()
+	useCase := NewUseCase(mockUserRepo, new(MockRefreshTokenRepository), nil, nil, nil, nil, RegistrationClosed)
+
+	_, err := useCase.UpdateMe(1, &UpdateMeRequest{Name: &name, CurrentPassword: "wrongpassword"}, "")
+	assert.EqualError(t, err, "invalid current password")
+
+	mockUserRepo.On("GetByID", uint(1)).Return(newUser(), nil).Once()
+	mockUserRepo.On("ExistsByEmail", taken).Return(true, nil)
+	_, err = useCase.UpdateMe(1, &UpdateMeRequest{Email: &taken, CurrentPassword: "password123"}, "")
+	assert.EqualError(t, err, "email already exists")
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+
+	mockUserRepo.On("GetByID", uint(1)).Return(newUser(), nil).Once()
+	mockUserRepo.On("ExistsByEmail", fresh).Return(false, nil)
+	mockUserRepo.On("Update", mock.MatchedBy(func(usr *userDomain.User) bool {
+		return usr.Email == fresh && usr.Name == name && usr.EmailVerifiedAt == nil
+	})).Return(nil).Once()
+	response, err := useCase.UpdateMe(1, &UpdateMeRequest{Name: &name, Email: &fresh, CurrentPassword: "password123"}, "")
+	require.NoError(t, err)
+	assert.True(t, response.EmailChanged)
+
+	// Keeping the same email does not need a uniqueness check or re-verification
+	same := "TEST@example.com"
+	mockUserRepo.On("GetByID", uint(1)).Return(newUser(), nil).Once()
+	mockUserRepo.On("Update", mock.MatchedBy(func(usr *userDomain.User) bool {
+		return usr.EmailVerifiedAt != nil
+	})).Return(nil).Once()
+	response, err = useCase.UpdateMe(1, &UpdateMeRequest{Email: &same, CurrentPassword: "password123"}, "")
+	require.NoError(t, err)
+	assert.False(t, response.EmailChanged)
+	mockUserRepo.AssertNotCalled(t, "ExistsByEmail", same)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// TestChangePassword tests the password policy and logging out other sessions
+func TestChangePassword(t *testing.T) {
+	newUser := func() *userDomain.User {
+		return &userDomain.User{
+			BaseModel: models.BaseModel{ID: 1},
+			Username:  "testuser",
+			Email:     "test@example.com",
+			Password:  passwordHash(t),
+			IsActive:  true,
+		}
+	}
+
+	mockUserRepo := new(MockUserRepository)
+	mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+	for i := 0; i < 5; i++ {
+		mockUserRepo.On("GetByID", uint(1)).Return(newUser(), nil).Once()
+	}
+	useCase := NewUseCase(mockUserRepo, mockRefreshTokenRepo, nil, nil, nil, nil, RegistrationClosed)
+
+	_, err := useCase.ChangePassword(1, &ChangePasswordRequest{CurrentPassword: "wrongpassword", NewPassword: "kurma-ajwa7"}, "", "")
+	assert.EqualError(t, err, "invalid current password")
+
+	_, err = useCase.ChangePassword(1, &ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "short1"}, "", "")
+	assert.EqualError(t, err, "password must be at least 8 characters")
+
+	_, err = useCase.ChangePassword(1, &ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "password123"}, "", "")
+	assert.EqualError(t, err, "new password must be different from the current password")
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+
+	isNewPassword := mock.MatchedBy(func(usr *userDomain.User) bool {
+		return bcrypt.CheckPasswordHash("kurma-ajwa7", usr.Password)
+	})
+	mockUserRepo.On("Update", isNewPassword).Return(nil)
+	response, err := useCase.ChangePassword(1, &ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "kurma-ajwa7"}, "", "")
+	require.NoError(t, err)
+	assert.Nil(t, response.RefreshTokenResponse)
+	mockRefreshTokenRepo.AssertNotCalled(t, "RevokeAllByUserID", mock.Anything)
+
+	mockRefreshTokenRepo.On("RevokeAllByUserID", uint(1)).Return(nil).Once()
+	mockRefreshTokenRepo.On("Create", mock.MatchedBy(func(rt *refreshTokenDomain.RefreshToken) bool {
+		return rt.UserID == 1 && rt.UserAgent == "test-agent"
+	})).Return(nil).Once()
+	response, err = useCase.ChangePassword(1, &ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "kurma-ajwa7", LogoutOtherSessions: true}, "test-agent", "127.0.0.1")
+	require.NoError(t, err)
+	require.NotNil(t, response.RefreshTokenResponse)
+	assert.NotEmpty(t, response.AccessToken)
+	assert.NotEmpty(t, response.RefreshToken)
+	mockRefreshTokenRepo.AssertExpectations(t)
+}
+
+// TestCurrentPassword_Throttled tests that wrong current passwords count as failed logins and throttled checks are refused
+func TestCurrentPassword_Throttled(t *testing.T) {
+	testUser := &userDomain.User{
+		BaseModel: models.BaseModel{ID: 1},
+		Username:  "testuser",
+		Email:     "test@example.com",
+		Password:  passwordHash(t),
+		IsActive:  true,
+	}
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("GetByID", uint(1)).Return(testUser, nil)
+
+	guard := &fakeGuard{}
+	useCase := NewUseCase(mockUserRepo, new(MockRefreshTokenRepository), nil, nil, nil, guard, RegistrationClosed)
+
+	name := "Hamba Allah"
+	_, err := useCase.UpdateMe(1, &UpdateMeRequest{Name: &name, CurrentPassword: "wrongpassword"}, "127.0.0.1")
+	assert.EqualError(t, err, "invalid current password")
+	_, err = useCase.ChangePassword(1, &ChangePasswordRequest{CurrentPassword: "wrongpassword", NewPassword: "kurma-ajwa7"}, "", "127.0.0.1")
+	assert.EqualError(t, err, "invalid current password")
+	assert.Equal(t, []string{"testuser@127.0.0.1", "testuser@127.0.0.1"}, guard.failures)
+
+	// A throttled check is refused even with the right password
+	guard.blocked = &lockout.ThrottledError{Locked: true, RetryAfter: time.Minute}
+	_, err = useCase.ChangePassword(1, &ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "kurma-ajwa7"}, "", "127.0.0.1")
+	assert.EqualError(t, err, "account is temporarily locked")
+	_, err = useCase.UpdateMe(1, &UpdateMeRequest{Name: &name, CurrentPassword: "password123"}, "127.0.0.1")
+	assert.EqualError(t, err, "account is temporarily locked")
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+// fakeMFA accepts the code 123456 for user 1, who has two-factor authentication enabled
+type fakeMFA struct {
+	verified []string
+}
+
+func (f *fakeMFA) IsEnabled(userID uint) (bool, error) { return userID == 1, nil }
+
+func (f *fakeMFA) IsRequired(usr *userDomain.User) (bool, error) {
+	return usr.Role == userDomain.RoleAdmin, nil
+}
+
+func (f *fakeMFA) Verify(userID uint, code string) error {
+	f.verified = append(f.verified, code)
+	if code != "123456" {
+		return errors.New("invalid code")
+	}
+	return nil
+}
+
+func (f *fakeMFA) Enroll(userID uint) (*mfaUsecase.EnrollResponse, error) {
+	return &mfaUsecase.EnrollResponse{Secret: "SECRET"}, nil
+}
+
+func (f *fakeMFA) Confirm(userID uint, code string) (*mfaUsecase.RecoveryCodesResponse, error) {
+	return &mfaUsecase.RecoveryCodesResponse{RecoveryCodes: []string{"abcde-fghjk"}}, nil
+}
+
+// TestLogin_TwoFactor tests that a password alone does not issue tokens when 2FA is on
+func TestLogin_TwoFactor(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+	testUser := &userDomain.User{
+		BaseModel: models.BaseModel{ID: 1},
+		Username:  "testuser",
+		Password:  passwordHash(t),
+		Role:      userDomain.RoleUser,
+		IsActive:  true,
+	}
+	mockUserRepo.On("GetByUsername", "testuser").Return(testUser, nil)
+	mockUserRepo.On("GetByID", uint(1)).Return(testUser, nil)
+	mockUserRepo.On("UpdateLastLogin", uint(1)).Return(nil)
+	mockRefreshTokenRepo.On("Create", mock.Anything).Return(nil)
+
+	mfa := &fakeMFA{}
+	useCase := NewUseCase(mockUserRepo, mockRefreshTokenRepo, nil, nil, mfa, nil, RegistrationClosed)
+
+	challenge, err := useCase.Login(&LoginRequest{Username: "testuser", Password: "password123"}, "test-agent", "127.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, challenge.MFARequired)
+	assert.False(t, challenge.MFASetupRequired)
+	assert.Empty(t, challenge.AccessToken)
+	require.NotEmpty(t, challenge.MFAToken)
+
+	// The challenge token is not an access token and cannot start setup
+	_, err = jwt.ValidateToken(challenge.MFAToken)
+	assert.Error(t, err)
+	_, err = useCase.SetupMFA(&MFASetupRequest{MFAToken: challenge.MFAToken})
+	assert.EqualError(t, err, "invalid or expired mfa token")
+
+	_, err = useCase.LoginMFA(&MFALoginRequest{MFAToken: challenge.MFAToken, Code: "000000"}, "test-agent", "127.0.0.1")
+	assert.EqualError(t, err, "invalid code")
+	mockRefreshTokenRepo.AssertNotCalled(t, "Create", mock.Anything)
+
+	response, err := useCase.LoginMFA(&MFALoginRequest{MFAToken: challenge.MFAToken, Code: "123456"}, "test-agent", "127.0.0.1")
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.AccessToken)
+	assert.NotEmpty(t, response.RefreshToken)
+	assert.Equal(t, []string{"000000", "123456"}, mfa.verified)
+}
+
+// fakeGuard throttles every login once blocked is set
+type fakeGuard struct {
+	blocked   error
+	failures  []string
+	successes []string
+}
+
+func (g *fakeGuard) Check(username, ipAddress string) error { return g.blocked }
+
+func (g *fakeGuard) RecordFailure(username, ipAddress string) error {
+	g.failures = append(g.failures, username+"@"+ipAddress)
+	return nil
+}
+
+func (g *fakeGuard) RecordSuccess(username string) error {
+	g.successes = append(g.successes, username)
+	return nil
+}
+
+// TestLogin_Throttled tests that failed logins are reported to the guard and throttled logins are refused
+func TestLogin_Throttled(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+	testUser := &userDomain.User{
+		BaseModel: models.BaseModel{ID: 1},
+		Username:  "testuser",
+		Password:  passwordHash(t),
+		Role:      userDomain.RoleUser,
+		IsActive:  true,
+	}
+	mockUserRepo.On("GetByUsername", "testuser").Return(testUser, nil)
+	mockUserRepo.On("GetByUsername", "unknown").Return(nil, errors.New("user not found"))
+	mockUserRepo.On("UpdateLastLogin", uint(1)).Return(nil)
+	mockRefreshTokenRepo.On("Create", mock.Anything).Return(nil)
+
+	guard := &fakeGuard{}
+	useCase := NewUseCase(mockUserRepo, mockRefreshTokenRepo, nil, nil, nil, guard, RegistrationClosed)
+
+	_, err := useCase.Login(&LoginRequest{Username: "unknown", Password: "password123"}, "test-agent", "127.0.0.1")
+	assert.EqualError(t, err, "invalid credentials")
+	_, err = useCase.Login(&LoginRequest{Username: "testuser", Password: "salah"}, "test-agent", "127.0.0.1")
+	assert.EqualError(t, err, "invalid credentials")
+	assert.Equal(t, []string{"unknown@127.0.0.1", "testuser@127.0.0.1"}, guard.failures)
+
+	_, err = useCase.Login(&LoginRequest{Username: "testuser", Password: "password123"}, "test-agent", "127.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"testuser"}, guard.successes)
+
+	// A throttled login is refused even with the right password
+	guard.blocked = &lockout.ThrottledError{Locked: true, RetryAfter: time.Minute}
+	_, err = useCase.Login(&LoginRequest{Username: "testuser", Password: "password123"}, "test-agent", "127.0.0.1")
+	assert.EqualError(t, err, "account is temporarily locked")
+	mockRefreshTokenRepo.AssertNumberOfCalls(t, "Create", 1)
+}
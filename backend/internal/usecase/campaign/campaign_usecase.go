@@ -0,0 +1,394 @@
+package campaign
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	campaignDomain "github.com/madr/backend/internal/domain/campaign"
+	campaignRepo "github.com/madr/backend/internal/repository/campaign"
+	donationCategoryRepo "github.com/madr/backend/internal/repository/donationcategory"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/money"
+)
+
+// UseCase defines the interface for campaign use case
+type UseCase interface {
+	Create(req *CreateRequest) (*Detail, error)
+	GetByID(id uint) (*Detail, error)
+	GetAll(limit, offset int, filter *campaignRepo.Filter) (*GetAllResponse, error)
+	Update(id uint, req *UpdateRequest) (*Detail, error)
+	Delete(id uint) error
+
+	CloseExpired(now time.Time) (int64, error)
+	EnsureOpen(categoryID uint) error
+}
+
+// CreateRequest represents the request to create a campaign
+type CreateRequest struct {
+	CategoryID   uint        `json:"category_id" binding:"required"`
+	Title        string      `json:"title" binding:"required,min=3,max=255"`
+	Description  string      `json:"description"`
+	TargetAmount money.Money `json:"target_amount" binding:"required,gt=0"`
+	StartDate    string      `json:"start_date"` // YYYY-MM-DD, defaults to today
+	EndDate      string      `json:"end_date" binding:"required"`
+	CoverImage   *string     `json:"cover_image"` // File name returned by POST /admin/upload
+}
+
+// UpdateRequest represents the request to update a campaign
+type UpdateRequest struct {
+	Title        *string                `json:"title" binding:"omitempty,min=3,max=255"`
+	Description  *string                `json:"description"`
+	TargetAmount *money.Money           `json:"target_amount" binding:"omitempty,gt=0"`
+	StartDate    *string                `json:"start_date"`
+	EndDate      *string                `json:"end_date"`
+	CoverImage   *string                `json:"cover_image"` // Empty string removes the cover
+	Status       *campaignDomain.Status `json:"status" binding:"omitempty,oneof=active closed"`
+}
+
+// Detail is a campaign with its fundraising progress
+type Detail struct {
+	campaignDomain.Campaign
+	CoverImageURL *string              `json:"cover_image_url"`
+	Phase         campaignDomain.Phase `json:"phase"`
+	Collected     money.Money          `json:"collected"`
+	Remaining     money.Money          `json:"remaining"`
+	Percentage    float64              `json:"percentage"`
+	DonorCount    int64                `json:"donor_count"`
+	DonationCount int64                `json:"donation_count"`
+	DaysLeft      int                  `json:"days_left"`
+}
+
+// GetAllResponse represents the response for listing campaigns
+type GetAllResponse struct {
+	Data       []Detail `json:"data"`
+	Total      int64    `json:"total"`
+	Limit      int      `json:"limit"`
+	Offset     int      `json:"offset"`
+	TotalPages int      `json:"total_pages"`
+}
+
+type useCase struct {
+	repo       campaignRepo.Repository
+	categories donationCategoryRepo.Repository
+	fileExists func(filename string) bool
+	now        func() time.Time
+}
+
+// NewUseCase creates a new campaign use case
+func NewUseCase(repo campaignRepo.Repository, categories donationCategoryRepo.Repository) UseCase {
+	return &useCase{
+		repo:       repo,
+		categories: categories,
+		fileExists: utils.UploadedFileExists,
+		now:        time.Now,
+	}
+}
+
+// Create creates a campaign collecting through a donation category
+func (uc *useCase) Create(req *CreateRequest) (*Detail, error) {
+	start := utils.StartOfDay(uc.now())
+	if req.StartDate != "" {
+		date, err := utils.ParseDate(req.StartDate)
+		if err != nil {
+			return nil, errors.New("invalid start date")
+		}
+		start = date
+	}
+	end, err := utils.ParseDate(req.EndDate)
+	if err != nil {
+		return nil, errors.New("invalid end date")
+	}
+	if end.Before(start) {
+		return nil, errors.New("end date is before start date")
+	}
+	if err := uc.checkCover(req.CoverImage); err != nil {
+		return nil, err
+	}
+	if _, err := uc.categories.GetByID(req.CategoryID); err != nil {
+		return nil, err
+	}
+	if err := uc.checkCategoryFree(req.CategoryID); err != nil {
+		return nil, err
+	}
+
+	c := &campaignDomain.Campaign{
+		CategoryID:   req.CategoryID,
+		Title:        req.Title,
+		Description:  req.Description,
+		TargetAmount: req.TargetAmount,
+		StartDate:    start,
+		EndDate:      end,
+		CoverImage:   emptyToNil(req.CoverImage),
+		Status:       campaignDomain.StatusActive,
+	}
+	if err := uc.repo.Create(c); err != nil {
+		logger.Error().Err(err).Msg("Failed to create campaign")
+		return nil, errors.New("failed to create campaign")
+	}
+
+	logger.Info().
+		Uint("id", c.ID).
+		Uint("category_id", c.CategoryID).
+		Str("target_amount", c.TargetAmount.String()).
+		Msg("Campaign created successfully")
+	return uc.GetByID(c.ID)
+}
+
+// GetByID retrieves a campaign with its progress
+func (uc *useCase) GetByID(id uint) (*Detail, error) {
+	c, err := uc.repo.GetByID(id)
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to get campaign")
+		return nil, err
+	}
+
+	details, err := uc.withProgress([]campaignDomain.Campaign{*c})
+	if err != nil {
+		return nil, err
+	}
+	return &details[0], nil
+}
+
+// GetAll retrieves campaigns with their progress
+func (uc *useCase) GetAll(limit, offset int, filter *campaignRepo.Filter) (*GetAllResponse, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	campaigns, total, err := uc.repo.GetAll(limit, offset, filter)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get campaigns")
+		return nil, errors.New("failed to get campaigns")
+	}
+
+	details, err := uc.withProgress(campaigns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetAllResponse{
+		Data:       details,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, nil
+}
+
+// Update updates a campaign. Moving the end date of a closed campaign does
+// not reopen it; set the status back to active for that.
+func (uc *useCase) Update(id uint, req *UpdateRequest) (*Detail, error) {
+	c, err := uc.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	normalizeDates(c)
+
+	if req.StartDate != nil {
+		start, err := utils.ParseDate(*req.StartDate)
+		if err != nil {
+			return nil, errors.New("invalid start date")
+		}
+		c.StartDate = start
+	}
+	if req.EndDate != nil {
+		end, err := utils.ParseDate(*req.EndDate)
+		if err != nil {
+			return nil, errors.New("invalid end date")
+		}
+		c.EndDate = end
+	}
+	if c.EndDate.Before(c.StartDate) {
+		return nil, errors.New("end date is before start date")
+	}
+	if req.CoverImage != nil {
+		if err := uc.checkCover(req.CoverImage); err != nil {
+			return nil, err
+		}
+		c.CoverImage = emptyToNil(req.CoverImage)
+	}
+	if req.Title != nil {
+		c.Title = *req.Title
+	}
+	if req.Description != nil {
+		c.Description = *req.Description
+	}
+	if req.TargetAmount != nil {
+		c.TargetAmount = *req.TargetAmount
+	}
+
+	if req.Status != nil && *req.Status != c.Status {
+		switch *req.Status {
+		case campaignDomain.StatusActive:
+			if utils.StartOfDay(uc.now()).After(c.EndDate) {
+				return nil, errors.New("campaign end date has passed")
+			}
+			c.ClosedAt = nil
+		case campaignDomain.StatusClosed:
+			closedAt := uc.now()
+			c.ClosedAt = &closedAt
+		}
+		c.Status = *req.Status
+	}
+
+	if err := uc.repo.Update(c); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to update campaign")
+		return nil, errors.New("failed to update campaign")
+	}
+
+	logger.Info().Uint("id", id).Str("status", string(c.Status)).Msg("Campaign updated successfully")
+	return uc.GetByID(id)
+}
+
+// Delete deletes a campaign. Its donations stay in the category.
+func (uc *useCase) Delete(id uint) error {
+	if _, err := uc.repo.GetByID(id); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(id); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to delete campaign")
+		return errors.New("failed to delete campaign")
+	}
+
+	logger.Info().Uint("id", id).Msg("Campaign deleted successfully")
+	return nil
+}
+
+// CloseExpired closes the campaigns whose end date has passed
+func (uc *useCase) CloseExpired(now time.Time) (int64, error) {
+	closed, err := uc.repo.CloseExpired(utils.StartOfDay(now), now)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to close expired campaigns")
+		return 0, errors.New("failed to close expired campaigns")
+	}
+	if closed > 0 {
+		logger.Info().Int64("closed", closed).Msg("Expired campaigns closed")
+	}
+	return closed, nil
+}
+
+// EnsureOpen rejects new donations to a category whose campaign is not
+// accepting donations. Categories without a campaign are always open.
+func (uc *useCase) EnsureOpen(categoryID uint) error {
+	c, err := uc.repo.GetByCategoryID(categoryID)
+	if err != nil {
+		if err.Error() == "campaign not found" {
+			return nil
+		}
+		logger.Error().Err(err).Uint("category_id", categoryID).Msg("Failed to get campaign of category")
+		return errors.New("failed to check campaign")
+	}
+	normalizeDates(c)
+
+	switch c.PhaseOn(utils.StartOfDay(uc.now())) {
+	case campaignDomain.PhaseClosed:
+		return errors.New("campaign is closed")
+	case campaignDomain.PhaseUpcoming:
+		return errors.New("campaign has not started")
+	}
+	return nil
+}
+
+// withProgress attaches the fundraising progress to campaigns
+func (uc *useCase) withProgress(campaigns []campaignDomain.Campaign) ([]Detail, error) {
+	ids := make([]uint, 0, len(campaigns))
+	for _, c := range campaigns {
+		ids = append(ids, c.ID)
+	}
+	progress, err := uc.repo.GetProgress(ids)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get campaign progress")
+		return nil, errors.New("failed to get campaign progress")
+	}
+
+	today := utils.StartOfDay(uc.now())
+	details := make([]Detail, 0, len(campaigns))
+	for _, c := range campaigns {
+		normalizeDates(&c)
+		details = append(details, buildDetail(c, progress[c.ID], today))
+	}
+	return details, nil
+}
+
+// buildDetail computes the progress figures of a campaign on the given day
+func buildDetail(c campaignDomain.Campaign, p campaignRepo.Progress, today time.Time) Detail {
+	d := Detail{
+		Campaign:      c,
+		Phase:         c.PhaseOn(today),
+		Collected:     p.Collected,
+		Remaining:     c.TargetAmount.Sub(p.Collected),
+		DonorCount:    p.Donors,
+		DonationCount: p.Donations,
+	}
+	if c.CoverImage != nil {
+		url := utils.GetPublicURL(*c.CoverImage)
+		d.CoverImageURL = &url
+	}
+	if !d.Remaining.IsPositive() {
+		d.Remaining = 0
+	}
+	if c.TargetAmount.IsPositive() {
+		d.Percentage = math.Round(float64(p.Collected)/float64(c.TargetAmount)*10000) / 100
+	}
+
+	// Days left counts the end date itself, so the last day shows 1
+	switch d.Phase {
+	case campaignDomain.PhaseOpen:
+		d.DaysLeft = daysBetween(today, c.EndDate) + 1
+	case campaignDomain.PhaseUpcoming:
+		d.DaysLeft = daysBetween(c.StartDate, c.EndDate) + 1
+	}
+	return d
+}
+
+// daysBetween counts the calendar days from a to b. Jakarta has no daylight
+// saving, so every day is 24 hours long.
+func daysBetween(a, b time.Time) int {
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// checkCover verifies a cover image was saved through the upload endpoint
+func (uc *useCase) checkCover(cover *string) error {
+	if cover == nil || *cover == "" {
+		return nil
+	}
+	if !uc.fileExists(*cover) {
+		return errors.New("cover image not found")
+	}
+	return nil
+}
+
+// checkCategoryFree rejects a category that already has a campaign
+func (uc *useCase) checkCategoryFree(categoryID uint) error {
+	exists, err := uc.repo.ExistsForCategory(categoryID, 0)
+	if err != nil {
+		logger.Error().Err(err).Uint("category_id", categoryID).Msg("Failed to check campaign category")
+		return errors.New("failed to check campaign category")
+	}
+	if exists {
+		return errors.New("category already has a campaign")
+	}
+	return nil
+}
+
+// normalizeDates reads the DATE columns of c as Jakarta dates
+func normalizeDates(c *campaignDomain.Campaign) {
+	c.StartDate = utils.AsDate(c.StartDate)
+	c.EndDate = utils.AsDate(c.EndDate)
+}
+
+func emptyToNil(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return s
+}
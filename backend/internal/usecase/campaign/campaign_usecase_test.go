@@ -0,0 +1,191 @@
+package campaign
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/madr/backend/internal/config"
+	campaignDomain "github.com/madr/backend/internal/domain/campaign"
+	donationCategoryDomain "github.com/madr/backend/internal/domain/donationcategory"
+	campaignRepo "github.com/madr/backend/internal/repository/campaign"
+	donationCategoryRepo "github.com/madr/backend/internal/repository/donationcategory"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCampaignRepository mocks the campaign repository methods used by the tests
+type MockCampaignRepository struct {
+	campaignRepo.Repository
+	mock.Mock
+}
+
+func (m *MockCampaignRepository) Create(c *campaignDomain.Campaign) error {
+	args := m.Called(c)
+	return args.Error(0)
+}
+
+func (m *MockCampaignRepository) GetByID(id uint) (*campaignDomain.Campaign, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*campaignDomain.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepository) GetByCategoryID(categoryID uint) (*campaignDomain.Campaign, error) {
+	args := m.Called(categoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*campaignDomain.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepository) ExistsForCategory(categoryID, excludeID uint) (bool, error) {
+	args := m.Called(categoryID, excludeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCampaignRepository) GetProgress(ids []uint) (map[uint]campaignRepo.Progress, error) {
+	args := m.Called(ids)
+	return args.Get(0).(map[uint]campaignRepo.Progress), args.Error(1)
+}
+
+// MockCategoryRepository mocks the donation category repository methods used by the tests
+type MockCategoryRepository struct {
+	donationCategoryRepo.Repository
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) GetByID(id uint) (*donationCategoryDomain.DonationCategory, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*donationCategoryDomain.DonationCategory), args.Error(1)
+}
+
+func newTestUseCase(repo campaignRepo.Repository, categories donationCategoryRepo.Repository, now time.Time) *useCase {
+	return &useCase{
+		repo:       repo,
+		categories: categories,
+		fileExists: func(filename string) bool { return filename == "wudhu.jpg" },
+		now:        func() time.Time { return now },
+	}
+}
+
+// wudhuCampaign runs from 1 to 30 June with DATE columns read back as UTC midnight
+func wudhuCampaign() *campaignDomain.Campaign {
+	c := &campaignDomain.Campaign{
+		CategoryID:   3,
+		Title:        "Tempat Wudhu Baru",
+		TargetAmount: money.FromRupiah(50000000),
+		StartDate:    time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:      time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		Status:       campaignDomain.StatusActive,
+	}
+	c.ID = 1
+	return c
+}
+
+// TestGetByID_Progress tests the percentage, remaining amount and days left
+func TestGetByID_Progress(t *testing.T) {
+	config.AppConfig = &config.Config{Upload: config.UploadConfig{PublicURL: "http://localhost:8080/uploads"}}
+	mockRepo := new(MockCampaignRepository)
+	now := time.Date(2025, 6, 21, 22, 0, 0, 0, utils.Jakarta)
+	useCase := newTestUseCase(mockRepo, nil, now)
+
+	cover := "wudhu.jpg"
+	c := wudhuCampaign()
+	c.CoverImage = &cover
+	mockRepo.On("GetByID", uint(1)).Return(c, nil)
+	mockRepo.On("GetProgress", []uint{1}).Return(map[uint]campaignRepo.Progress{
+		1: {CampaignID: 1, Collected: money.FromRupiah(12345000), Donations: 40, Donors: 31},
+	}, nil)
+
+	detail, err := useCase.GetByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, campaignDomain.PhaseOpen, detail.Phase)
+	assert.Equal(t, 24.69, detail.Percentage)
+	assert.Equal(t, money.FromRupiah(37655000), detail.Remaining)
+	assert.Equal(t, int64(31), detail.DonorCount)
+	assert.Equal(t, int64(40), detail.DonationCount)
+	assert.Equal(t, 10, detail.DaysLeft) // 21 to 30 June inclusive
+	require.NotNil(t, detail.CoverImageURL)
+	assert.Equal(t, "http://localhost:8080/uploads/wudhu.jpg", *detail.CoverImageURL)
+}
+
+// TestGetByID_OverTargetAndClosed tests a campaign past its deadline that
+// raised more than its target
+func TestGetByID_OverTargetAndClosed(t *testing.T) {
+	mockRepo := new(MockCampaignRepository)
+	useCase := newTestUseCase(mockRepo, nil, time.Date(2025, 7, 1, 0, 30, 0, 0, utils.Jakarta))
+
+	mockRepo.On("GetByID", uint(1)).Return(wudhuCampaign(), nil)
+	mockRepo.On("GetProgress", []uint{1}).Return(map[uint]campaignRepo.Progress{
+		1: {CampaignID: 1, Collected: money.FromRupiah(60000000)},
+	}, nil)
+
+	detail, err := useCase.GetByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, campaignDomain.PhaseClosed, detail.Phase)
+	assert.Equal(t, float64(120), detail.Percentage)
+	assert.Equal(t, money.Money(0), detail.Remaining)
+	assert.Equal(t, 0, detail.DaysLeft)
+}
+
+// TestEnsureOpen tests that only open campaigns accept donations
+func TestEnsureOpen(t *testing.T) {
+	mockRepo := new(MockCampaignRepository)
+	mockRepo.On("GetByCategoryID", uint(3)).Return(wudhuCampaign(), nil)
+	mockRepo.On("GetByCategoryID", uint(4)).Return(nil, errors.New("campaign not found"))
+
+	lastDay := newTestUseCase(mockRepo, nil, time.Date(2025, 6, 30, 23, 59, 0, 0, utils.Jakarta))
+	assert.NoError(t, lastDay.EnsureOpen(3))
+	assert.NoError(t, lastDay.EnsureOpen(4))
+
+	after := newTestUseCase(mockRepo, nil, time.Date(2025, 6, 30, 17, 0, 0, 0, time.UTC)) // 1 July 00:00 WIB
+	assert.EqualError(t, after.EnsureOpen(3), "campaign is closed")
+
+	before := newTestUseCase(mockRepo, nil, time.Date(2025, 5, 31, 12, 0, 0, 0, utils.Jakarta))
+	assert.EqualError(t, before.EnsureOpen(3), "campaign has not started")
+}
+
+// TestCreate_Validation tests the category and cover image checks
+func TestCreate_Validation(t *testing.T) {
+	mockRepo := new(MockCampaignRepository)
+	mockCategories := new(MockCategoryRepository)
+	useCase := newTestUseCase(mockRepo, mockCategories, time.Date(2025, 6, 1, 9, 0, 0, 0, utils.Jakarta))
+
+	cat := &donationCategoryDomain.DonationCategory{Name: "Pembangunan Wudhu"}
+	mockCategories.On("GetByID", uint(3)).Return(cat, nil)
+	mockRepo.On("ExistsForCategory", uint(3), uint(0)).Return(true, nil).Once()
+
+	missing := "missing.jpg"
+	_, err := useCase.Create(&CreateRequest{CategoryID: 3, Title: "Wudhu", TargetAmount: money.FromRupiah(1000), EndDate: "2025-06-30", CoverImage: &missing})
+	assert.EqualError(t, err, "cover image not found")
+
+	_, err = useCase.Create(&CreateRequest{CategoryID: 3, Title: "Wudhu", TargetAmount: money.FromRupiah(1000), EndDate: "2025-05-30"})
+	assert.EqualError(t, err, "end date is before start date")
+
+	_, err = useCase.Create(&CreateRequest{CategoryID: 3, Title: "Wudhu", TargetAmount: money.FromRupiah(1000), EndDate: "2025-06-30"})
+	assert.EqualError(t, err, "category already has a campaign")
+
+	cover := "wudhu.jpg"
+	mockRepo.On("ExistsForCategory", uint(3), uint(0)).Return(false, nil)
+	mockRepo.On("Create", mock.MatchedBy(func(c *campaignDomain.Campaign) bool {
+		return c.StartDate.Format(utils.DateLayout) == "2025-06-01" && *c.CoverImage == cover && c.Status == campaignDomain.StatusActive
+	})).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(0).(*campaignDomain.Campaign).ID = 1
+	})
+	mockRepo.On("GetByID", uint(1)).Return(wudhuCampaign(), nil)
+	mockRepo.On("GetProgress", []uint{1}).Return(map[uint]campaignRepo.Progress{}, nil)
+
+	detail, err := useCase.Create(&CreateRequest{CategoryID: 3, Title: "Wudhu", TargetAmount: money.FromRupiah(1000), EndDate: "2025-06-30", CoverImage: &cover})
+	require.NoError(t, err)
+	assert.Equal(t, 30, detail.DaysLeft)
+	assert.Equal(t, money.Money(0), detail.Collected)
+}
@@ -0,0 +1,125 @@
+package donation
+
+import (
+	"encoding/json"
+	"testing"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	paymentService "github.com/madr/backend/internal/service/payment"
+	"github.com/madr/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func auditChanges(t *testing.T, entry donationDomain.AuditLog) map[string]donationDomain.FieldChange {
+	t.Helper()
+	var changes map[string]donationDomain.FieldChange
+	require.NoError(t, json.Unmarshal(entry.Changes, &changes))
+	return changes
+}
+
+// TestUpdate_RecordsAuditEntry tests that an admin edit of a confirmed donation
+// is recorded with the actor, the address and only the fields that changed
+func TestUpdate_RecordsAuditEntry(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	useCase := NewUseCase(mockRepo, nil, nil, nil, nil)
+
+	don := newPendingDonation()
+	don.PaymentStatus = donationDomain.PaymentStatusFailed
+	mockRepo.On("GetByID", uint(7)).Return(don, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*donation.Donation")).Return(nil)
+
+	admin := uint(3)
+	amount := money.FromRupiah(75000)
+	status := string(donationDomain.PaymentStatusPending)
+	_, err := useCase.Update(7, &UpdateRequest{Amount: &amount, PaymentStatus: &status}, &donationDomain.Actor{UserID: &admin, IP: "10.0.0.5"})
+	require.NoError(t, err)
+
+	require.Len(t, mockRepo.audit, 1)
+	entry := mockRepo.audit[0]
+	assert.Equal(t, donationDomain.AuditActionUpdate, entry.Action)
+	assert.Equal(t, uint(7), entry.DonationID)
+	assert.Equal(t, &admin, entry.ActorID)
+	assert.Equal(t, "10.0.0.5", entry.IP)
+	assert.NotEmpty(t, entry.Before)
+	assert.NotEmpty(t, entry.After)
+
+	changes := auditChanges(t, entry)
+	assert.Len(t, changes, 2)
+	assert.JSONEq(t, "50000.00", string(changes["amount"].From))
+	assert.JSONEq(t, "75000.00", string(changes["amount"].To))
+	assert.JSONEq(t, `"failed"`, string(changes["payment_status"].From))
+	assert.JSONEq(t, `"pending"`, string(changes["payment_status"].To))
+}
+
+// TestDelete_RecordsAuditEntry tests that a delete keeps the last state of the
+// donation, and that deleting an unknown donation records nothing
+func TestDelete_RecordsAuditEntry(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	useCase := NewUseCase(mockRepo, nil, nil, nil, nil)
+
+	mockRepo.On("GetByID", uint(7)).Return(newPendingDonation(), nil)
+	mockRepo.On("Delete", uint(7)).Return(nil)
+	mockRepo.On("GetByID", uint(8)).Return(nil, assert.AnError)
+
+	admin := uint(3)
+	require.NoError(t, useCase.Delete(7, &donationDomain.Actor{UserID: &admin}))
+	assert.Error(t, useCase.Delete(8, &donationDomain.Actor{UserID: &admin}))
+
+	require.Len(t, mockRepo.audit, 1)
+	entry := mockRepo.audit[0]
+	assert.Equal(t, donationDomain.AuditActionDelete, entry.Action)
+	assert.NotEmpty(t, entry.Before)
+	assert.Empty(t, entry.After)
+	assert.JSONEq(t, "null", string(auditChanges(t, entry)["amount"].To))
+	mockRepo.AssertNotCalled(t, "Delete", uint(8))
+}
+
+// TestHandleWebhook_RecordsSystemAuditEntry tests that a gateway confirmation is
+// recorded without an actor
+func TestHandleWebhook_RecordsSystemAuditEntry(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	fake := paymentService.NewFakeProvider(testWebhookSecret)
+	useCase := NewUseCase(mockRepo, paymentService.NewRegistry(paymentService.FakeName, fake), nil, nil, nil)
+
+	pending := newPendingDonation()
+	confirmed := *pending
+	confirmed.PaymentStatus = donationDomain.PaymentStatusSuccess
+
+	mockRepo.On("GetByPaymentReference", "DON-20250101-ABC").Return(pending, nil)
+	mockRepo.On("ApplyPaymentResult", uint(7), mock.Anything).Return(true, nil)
+	mockRepo.On("GetByID", uint(7)).Return(&confirmed, nil)
+
+	header, body := signedWebhook(t, fake, paymentService.FakeNotification{
+		OrderID: "DON-20250101-ABC",
+		Status:  paymentService.StatusSuccess,
+		Amount:  money.FromRupiah(50000),
+	})
+	_, err := useCase.HandleWebhook(paymentService.FakeName, header, body)
+	require.NoError(t, err)
+
+	require.Len(t, mockRepo.audit, 1)
+	assert.Nil(t, mockRepo.audit[0].ActorID)
+	assert.Contains(t, auditChanges(t, mockRepo.audit[0]), "payment_status")
+}
+
+// TestGetHistory tests that a donation without entries returns an empty trail
+// while an unknown donation is not found
+func TestGetHistory(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	useCase := NewUseCase(mockRepo, nil, nil, nil, nil)
+
+	mockRepo.On("GetAuditLogs", uint(7)).Return([]donationDomain.AuditLog(nil), nil)
+	mockRepo.On("GetByID", uint(7)).Return(newPendingDonation(), nil)
+	mockRepo.On("GetAuditLogs", uint(9)).Return([]donationDomain.AuditLog(nil), nil)
+	mockRepo.On("GetByID", uint(9)).Return(nil, assert.AnError)
+
+	logs, err := useCase.GetHistory(7)
+	require.NoError(t, err)
+	assert.NotNil(t, logs)
+	assert.Empty(t, logs)
+
+	_, err = useCase.GetHistory(9)
+	assert.Error(t, err)
+}
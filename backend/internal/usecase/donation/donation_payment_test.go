@@ -0,0 +1,212 @@
+package donation
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	"github.com/madr/backend/internal/domain/models"
+	receiptDomain "github.com/madr/backend/internal/domain/receipt"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	paymentService "github.com/madr/backend/internal/service/payment"
+	"github.com/madr/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookSecret = "test-secret"
+
+// MockReceiptIssuer is a mock implementation of ReceiptIssuer
+type MockReceiptIssuer struct {
+	mock.Mock
+}
+
+func (m *MockReceiptIssuer) Issue(donationID uint) (*receiptDomain.Receipt, error) {
+	args := m.Called(donationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*receiptDomain.Receipt), args.Error(1)
+}
+
+func newPendingDonation() *donationDomain.Donation {
+	provider := paymentService.FakeName
+	reference := "DON-20250101-ABC"
+	return &donationDomain.Donation{
+		BaseModel:        models.BaseModel{ID: 7},
+		CategoryID:       1,
+		Amount:           money.FromRupiah(50000),
+		PaymentStatus:    donationDomain.PaymentStatusPending,
+		PaymentProvider:  &provider,
+		PaymentReference: &reference,
+	}
+}
+
+func signedWebhook(t *testing.T, fake *paymentService.FakeProvider, n paymentService.FakeNotification) (http.Header, []byte) {
+	t.Helper()
+	body, err := json.Marshal(n)
+	require.NoError(t, err)
+	header := http.Header{}
+	header.Set(paymentService.FakeSignatureHeader, fake.Sign(body))
+	return header, body
+}
+
+// TestHandleWebhook_Success tests that a signed success callback confirms the donation
+func TestHandleWebhook_Success(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	mockReceipts := new(MockReceiptIssuer)
+	fake := paymentService.NewFakeProvider(testWebhookSecret)
+	useCase := NewUseCase(mockRepo, paymentService.NewRegistry(paymentService.FakeName, fake), mockReceipts, nil, nil)
+
+	pending := newPendingDonation()
+	confirmed := *pending
+	confirmed.PaymentStatus = donationDomain.PaymentStatusSuccess
+
+	mockRepo.On("GetByPaymentReference", "DON-20250101-ABC").Return(pending, nil)
+	mockRepo.On("ApplyPaymentResult", uint(7), mock.MatchedBy(func(r *donationRepo.PaymentResult) bool {
+		return r.Status == donationDomain.PaymentStatusSuccess &&
+			r.TransactionID == "trx-1" &&
+			r.PaidAt != nil &&
+			r.Payload != ""
+	})).Return(true, nil)
+	mockRepo.On("GetByID", uint(7)).Return(&confirmed, nil)
+	mockReceipts.On("Issue", uint(7)).Return(&receiptDomain.Receipt{Number: "KW-2025-000001"}, nil)
+
+	header, body := signedWebhook(t, fake, paymentService.FakeNotification{
+		OrderID:       "DON-20250101-ABC",
+		TransactionID: "trx-1",
+		Status:        paymentService.StatusSuccess,
+		Amount:        money.FromRupiah(50000),
+	})
+
+	don, err := useCase.HandleWebhook(paymentService.FakeName, header, body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, donationDomain.PaymentStatusSuccess, don.PaymentStatus)
+	mockRepo.AssertExpectations(t)
+	mockReceipts.AssertExpectations(t)
+}
+
+// TestHandleWebhook_Idempotent tests that a repeated callback does not change a finalized donation
+func TestHandleWebhook_Idempotent(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	fake := paymentService.NewFakeProvider(testWebhookSecret)
+	useCase := NewUseCase(mockRepo, paymentService.NewRegistry(paymentService.FakeName, fake), nil, nil, nil)
+
+	confirmed := newPendingDonation()
+	confirmed.PaymentStatus = donationDomain.PaymentStatusSuccess
+
+	mockRepo.On("GetByPaymentReference", "DON-20250101-ABC").Return(confirmed, nil)
+	mockRepo.On("ApplyPaymentResult", uint(7), mock.Anything).Return(false, nil)
+
+	header, body := signedWebhook(t, fake, paymentService.FakeNotification{
+		OrderID: "DON-20250101-ABC",
+		Status:  paymentService.StatusFailed,
+	})
+
+	don, err := useCase.HandleWebhook(paymentService.FakeName, header, body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, donationDomain.PaymentStatusSuccess, don.PaymentStatus)
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestHandleWebhook_PendingRetry tests that a pending callback for a pending donation writes nothing
+func TestHandleWebhook_PendingRetry(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	fake := paymentService.NewFakeProvider(testWebhookSecret)
+	useCase := NewUseCase(mockRepo, paymentService.NewRegistry(paymentService.FakeName, fake), nil, nil, nil)
+
+	mockRepo.On("GetByPaymentReference", "DON-20250101-ABC").Return(newPendingDonation(), nil)
+
+	header, body := signedWebhook(t, fake, paymentService.FakeNotification{
+		OrderID: "DON-20250101-ABC",
+		Status:  paymentService.StatusPending,
+		Amount:  money.FromRupiah(50000),
+	})
+
+	don, err := useCase.HandleWebhook(paymentService.FakeName, header, body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, donationDomain.PaymentStatusPending, don.PaymentStatus)
+	assert.Empty(t, mockRepo.audit)
+	mockRepo.AssertNotCalled(t, "ApplyPaymentResult", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestHandleWebhook_InvalidSignature tests that unsigned callbacks are rejected
+func TestHandleWebhook_InvalidSignature(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	fake := paymentService.NewFakeProvider(testWebhookSecret)
+	useCase := NewUseCase(mockRepo, paymentService.NewRegistry(paymentService.FakeName, fake), nil, nil, nil)
+
+	body := []byte(`{"order_id":"DON-20250101-ABC","status":"success","amount":50000}`)
+	header := http.Header{}
+	header.Set(paymentService.FakeSignatureHeader, paymentService.NewFakeProvider("other-secret").Sign(body))
+
+	don, err := useCase.HandleWebhook(paymentService.FakeName, header, body)
+
+	assert.Nil(t, don)
+	assert.EqualError(t, err, "invalid webhook signature")
+	mockRepo.AssertNotCalled(t, "GetByPaymentReference", mock.Anything)
+}
+
+// TestHandleWebhook_AmountMismatch tests that a success callback for the wrong amount is rejected
+func TestHandleWebhook_AmountMismatch(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	fake := paymentService.NewFakeProvider(testWebhookSecret)
+	useCase := NewUseCase(mockRepo, paymentService.NewRegistry(paymentService.FakeName, fake), nil, nil, nil)
+
+	mockRepo.On("GetByPaymentReference", "DON-20250101-ABC").Return(newPendingDonation(), nil)
+
+	header, body := signedWebhook(t, fake, paymentService.FakeNotification{
+		OrderID: "DON-20250101-ABC",
+		Status:  paymentService.StatusSuccess,
+		Amount:  money.FromRupiah(1000),
+	})
+
+	don, err := useCase.HandleWebhook(paymentService.FakeName, header, body)
+
+	assert.Nil(t, don)
+	assert.EqualError(t, err, "payment amount mismatch")
+	mockRepo.AssertNotCalled(t, "ApplyPaymentResult", mock.Anything, mock.Anything)
+}
+
+// TestHandleWebhook_UnknownProvider tests that callbacks for unregistered providers are rejected
+func TestHandleWebhook_UnknownProvider(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	useCase := NewUseCase(mockRepo, paymentService.NewRegistry(paymentService.FakeName), nil, nil, nil)
+
+	don, err := useCase.HandleWebhook("xendit", http.Header{}, []byte(`{}`))
+
+	assert.Nil(t, don)
+	assert.EqualError(t, err, "unknown payment provider")
+}
+
+// TestCheckout_Success tests that checkout creates a pending donation with a gateway reference
+func TestCheckout_Success(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	fake := paymentService.NewFakeProvider(testWebhookSecret)
+	useCase := NewUseCase(mockRepo, paymentService.NewRegistry(paymentService.FakeName, fake), nil, nil, nil)
+
+	mockRepo.On("Create", mock.AnythingOfType("*donation.Donation")).Run(func(args mock.Arguments) {
+		args.Get(0).(*donationDomain.Donation).ID = 9
+	}).Return(nil)
+	mockRepo.On("Update", mock.AnythingOfType("*donation.Donation")).Return(nil)
+
+	resp, err := useCase.Checkout(&CheckoutRequest{CategoryID: 1, Amount: money.FromRupiah(25000)})
+
+	require.NoError(t, err)
+	assert.Equal(t, donationDomain.PaymentStatusPending, resp.Donation.PaymentStatus)
+	require.NotNil(t, resp.Donation.PaymentReference)
+	assert.Equal(t, paymentService.FakeName, *resp.Donation.PaymentProvider)
+	assert.NotEmpty(t, resp.Payment.RedirectURL)
+
+	status, err := fake.QueryStatus(*resp.Donation.PaymentReference)
+	assert.NoError(t, err)
+	assert.Equal(t, paymentService.StatusPending, status)
+	mockRepo.AssertExpectations(t)
+}
@@ -2,76 +2,150 @@ package donation
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	donationDomain "github.com/madr/backend/internal/domain/donation"
+	receiptDomain "github.com/madr/backend/internal/domain/receipt"
 	donationRepo "github.com/madr/backend/internal/repository/donation"
+	paymentService "github.com/madr/backend/internal/service/payment"
 	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/money"
 )
 
+// anonymousDonorName is shown for donations without a donor name
+const anonymousDonorName = "Hamba Allah"
+
 // UseCase defines the interface for donation use case
 type UseCase interface {
-	Create(req *CreateRequest) (*donationDomain.Donation, error)
+	Create(req *CreateRequest, actor *donationDomain.Actor) (*donationDomain.Donation, error)
 	GetByID(id uint) (*donationDomain.Donation, error)
-	GetAll(limit, offset int, status *donationDomain.PaymentStatus) (*GetAllResponse, error)
-	Update(id uint, req *UpdateRequest) (*donationDomain.Donation, error)
-	Delete(id uint) error
+	GetAll(limit, offset int, filter *ListFilter) (*GetAllResponse, error)
+	Update(id uint, req *UpdateRequest, actor *donationDomain.Actor) (*donationDomain.Donation, error)
+	Delete(id uint, actor *donationDomain.Actor) error
+	GetHistory(id uint) ([]donationDomain.AuditLog, error)
 	GetSummary() (*SummaryResponse, error)
+	GetReport(req *ReportRequest) (*ReportResponse, error)
+	ExportDonations(w io.Writer, format ExportFormat, filter *ListFilter) error
+	ExportSummary(w io.Writer, format ExportFormat, filter *ListFilter) error
+	Checkout(req *CheckoutRequest) (*CheckoutResponse, error)
+	CreatePending(req *CheckoutRequest, provider, reference string) (*donationDomain.Donation, error)
+	HandleWebhook(provider string, header http.Header, body []byte) (*donationDomain.Donation, error)
+	SyncPaymentStatus(id uint) (*donationDomain.Donation, error)
+
+	GetDonorHistory(userID uint, limit, offset int, filter *ListFilter) (*DonorHistoryResponse, error)
+	ClaimDonation(userID uint, req *ClaimRequest) (*donationDomain.Donation, error)
 }
 
 // CreateRequest represents the request to create a donation
 type CreateRequest struct {
-	CategoryID    uint    `json:"category_id" binding:"required"`
-	DonorName     *string `json:"donor_name"`
-	Amount        float64 `json:"amount" binding:"required,gt=0"`
-	Message       string  `json:"message"`
-	PaymentStatus string  `json:"payment_status" binding:"omitempty,oneof=pending success failed"`
+	CategoryID    uint        `json:"category_id" binding:"required"`
+	DonorName     *string     `json:"donor_name"`
+	Amount        money.Money `json:"amount" binding:"required,gt=0"`
+	Message       string      `json:"message"`
+	PaymentStatus string      `json:"payment_status" binding:"omitempty,oneof=pending success failed"`
 }
 
 // UpdateRequest represents the request to update a donation
 type UpdateRequest struct {
-	CategoryID    *uint   `json:"category_id"`
-	DonorName     *string `json:"donor_name"`
-	Amount        *float64 `json:"amount" binding:"omitempty,gt=0"`
-	Message       *string `json:"message"`
-	PaymentStatus *string `json:"payment_status" binding:"omitempty,oneof=pending success failed"`
+	CategoryID    *uint        `json:"category_id"`
+	DonorName     *string      `json:"donor_name"`
+	Amount        *money.Money `json:"amount" binding:"omitempty,gt=0"`
+	Message       *string      `json:"message"`
+	PaymentStatus *string      `json:"payment_status" binding:"omitempty,oneof=pending success failed"`
+}
+
+// CheckoutRequest represents a public request to donate through the payment gateway
+type CheckoutRequest struct {
+	CategoryID uint        `json:"category_id" binding:"required"`
+	DonorName  *string     `json:"donor_name"`
+	Amount     money.Money `json:"amount" binding:"required,gt=0"`
+	Message    string      `json:"message"`
+	Anonymous  bool        `json:"anonymous"` // Do not link the donation to the signed-in donor
+	UserID     *uint       `json:"-"`         // Signed-in donor, set by the handler
+}
+
+// CheckoutResponse represents the pending donation and gateway charge details
+type CheckoutResponse struct {
+	Donation *donationDomain.Donation       `json:"donation"`
+	Payment  *paymentService.ChargeResponse `json:"payment"`
+}
+
+// ListFilter narrows the donation list. From and To are inclusive Jakarta dates.
+type ListFilter struct {
+	Status     *donationDomain.PaymentStatus
+	CategoryID *uint
+	UserID     *uint
+	From       *time.Time
+	To         *time.Time
 }
 
 // GetAllResponse represents the response for getting all donations
 type GetAllResponse struct {
 	Data       []donationDomain.Donation `json:"data"`
-	Total      int64                    `json:"total"`
-	Limit      int                      `json:"limit"`
-	Offset     int                      `json:"offset"`
-	TotalPages int                      `json:"total_pages"`
+	Total      int64                     `json:"total"`
+	Limit      int                       `json:"limit"`
+	Offset     int                       `json:"offset"`
+	TotalPages int                       `json:"total_pages"`
 }
 
 // SummaryResponse represents the donation summary response
 type SummaryResponse struct {
-	TotalAmount      float64        `json:"total_amount"`
-	TotalTransactions int64         `json:"total_transactions"`
-	PerCategory      []CategorySummary `json:"per_category"`
+	TotalAmount       money.Money       `json:"total_amount"`
+	TotalTransactions int64             `json:"total_transactions"`
+	PerCategory       []CategorySummary `json:"per_category"`
 }
 
 // CategorySummary represents donation summary per category
 type CategorySummary struct {
-	CategoryID   uint    `json:"category_id"`
-	CategoryName string  `json:"category"`
-	Amount       float64 `json:"amount"`
+	CategoryID   uint        `json:"category_id"`
+	CategoryName string      `json:"category"`
+	FundType     string      `json:"fund_type,omitempty"`
+	Amount       money.Money `json:"amount"`
+	Transactions int64       `json:"transactions,omitempty"`
+}
+
+// ReceiptIssuer issues the official receipt of a successful donation
+type ReceiptIssuer interface {
+	Issue(donationID uint) (*receiptDomain.Receipt, error)
+}
+
+// LedgerPoster keeps the cash ledger in sync with successful donations
+type LedgerPoster interface {
+	PostDonation(don *donationDomain.Donation) error
+	RemoveDonation(donationID uint) error
+}
+
+// CampaignGuard rejects donations to categories whose campaign is not open
+type CampaignGuard interface {
+	EnsureOpen(categoryID uint) error
 }
 
 type useCase struct {
-	repo donationRepo.Repository
+	repo      donationRepo.Repository
+	payments  *paymentService.Registry
+	receipts  ReceiptIssuer
+	ledger    LedgerPoster
+	campaigns CampaignGuard
 }
 
 // NewUseCase creates a new donation use case
-func NewUseCase(repo donationRepo.Repository) UseCase {
+func NewUseCase(repo donationRepo.Repository, payments *paymentService.Registry, receipts ReceiptIssuer, ledger LedgerPoster, campaigns CampaignGuard) UseCase {
 	return &useCase{
-		repo: repo,
+		repo:      repo,
+		payments:  payments,
+		receipts:  receipts,
+		ledger:    ledger,
+		campaigns: campaigns,
 	}
 }
 
 // Create creates a new donation
-func (uc *useCase) Create(req *CreateRequest) (*donationDomain.Donation, error) {
+func (uc *useCase) Create(req *CreateRequest, actor *donationDomain.Actor) (*donationDomain.Donation, error) {
 	// Set default payment status
 	paymentStatus := donationDomain.PaymentStatusPending
 	if req.PaymentStatus != "" {
@@ -85,8 +159,12 @@ func (uc *useCase) Create(req *CreateRequest) (*donationDomain.Donation, error)
 		Message:       req.Message,
 		PaymentStatus: paymentStatus,
 	}
+	if paymentStatus == donationDomain.PaymentStatusSuccess {
+		now := time.Now()
+		don.PaidAt = &now
+	}
 
-	if err := uc.repo.Create(don); err != nil {
+	if err := uc.create(don, actor); err != nil {
 		logger.Error().Err(err).Msg("Failed to create donation")
 		return nil, errors.New("failed to create donation")
 	}
@@ -94,10 +172,13 @@ func (uc *useCase) Create(req *CreateRequest) (*donationDomain.Donation, error)
 	logger.Info().
 		Uint("id", don.ID).
 		Uint("category_id", don.CategoryID).
-		Float64("amount", don.Amount).
+		Str("amount", don.Amount.String()).
 		Str("payment_status", string(don.PaymentStatus)).
 		Msg("Donation created successfully")
 
+	uc.issueReceipt(don)
+	uc.postToLedger(don)
+
 	return don, nil
 }
 
@@ -111,8 +192,8 @@ func (uc *useCase) GetByID(id uint) (*donationDomain.Donation, error) {
 	return don, nil
 }
 
-// GetAll retrieves all donations with pagination
-func (uc *useCase) GetAll(limit, offset int, status *donationDomain.PaymentStatus) (*GetAllResponse, error) {
+// GetAll retrieves all donations with pagination and optional filter
+func (uc *useCase) GetAll(limit, offset int, filter *ListFilter) (*GetAllResponse, error) {
 	// Validate pagination parameters
 	if limit <= 0 {
 		limit = 10
@@ -124,7 +205,12 @@ func (uc *useCase) GetAll(limit, offset int, status *donationDomain.PaymentStatu
 		offset = 0
 	}
 
-	donations, total, err := uc.repo.GetAll(limit, offset, status)
+	repoFilter, err := filter.toRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	donations, total, err := uc.repo.GetAll(limit, offset, repoFilter)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to get donations")
 		return nil, errors.New("failed to get donations")
@@ -142,11 +228,12 @@ func (uc *useCase) GetAll(limit, offset int, status *donationDomain.PaymentStatu
 }
 
 // Update updates an existing donation
-func (uc *useCase) Update(id uint, req *UpdateRequest) (*donationDomain.Donation, error) {
+func (uc *useCase) Update(id uint, req *UpdateRequest, actor *donationDomain.Actor) (*donationDomain.Donation, error) {
 	don, err := uc.repo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
+	before := *don
 
 	// Update fields if provided
 	if req.CategoryID != nil {
@@ -161,11 +248,19 @@ func (uc *useCase) Update(id uint, req *UpdateRequest) (*donationDomain.Donation
 	if req.Message != nil {
 		don.Message = *req.Message
 	}
+	previousStatus := don.PaymentStatus
 	if req.PaymentStatus != nil {
 		don.PaymentStatus = donationDomain.PaymentStatus(*req.PaymentStatus)
 	}
+	// Only successful donations count as paid in reports and receipts
+	if don.PaymentStatus != donationDomain.PaymentStatusSuccess {
+		don.PaidAt = nil
+	} else if don.PaidAt == nil {
+		now := time.Now()
+		don.PaidAt = &now
+	}
 
-	if err := uc.repo.Update(don); err != nil {
+	if err := uc.update(&before, don, actor); err != nil {
 		logger.Error().Err(err).Uint("id", id).Msg("Failed to update donation")
 		return nil, errors.New("failed to update donation")
 	}
@@ -174,20 +269,61 @@ func (uc *useCase) Update(id uint, req *UpdateRequest) (*donationDomain.Donation
 		Uint("id", don.ID).
 		Msg("Donation updated successfully")
 
+	if previousStatus != donationDomain.PaymentStatusSuccess {
+		uc.issueReceipt(don)
+	}
+	if previousStatus == donationDomain.PaymentStatusSuccess || don.PaymentStatus == donationDomain.PaymentStatusSuccess {
+		uc.postToLedger(don)
+	}
+
 	return don, nil
 }
 
 // Delete deletes a donation
-func (uc *useCase) Delete(id uint) error {
-	if err := uc.repo.Delete(id); err != nil {
+func (uc *useCase) Delete(id uint, actor *donationDomain.Actor) error {
+	don, err := uc.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.Audited(func(tx donationRepo.Repository) (*donationDomain.AuditLog, error) {
+		if err := tx.Delete(id); err != nil {
+			return nil, err
+		}
+		return donationDomain.NewAuditLog(donationDomain.AuditActionDelete, don, nil, actor)
+	}); err != nil {
 		logger.Error().Err(err).Uint("id", id).Msg("Failed to delete donation")
 		return errors.New("failed to delete donation")
 	}
 
+	if uc.ledger != nil {
+		if err := uc.ledger.RemoveDonation(id); err != nil {
+			logger.Error().Err(err).Uint("id", id).Msg("Failed to remove donation from ledger")
+		}
+	}
+
 	logger.Info().Uint("id", id).Msg("Donation deleted successfully")
 	return nil
 }
 
+// GetHistory retrieves the audit trail of a donation, including deleted ones
+func (uc *useCase) GetHistory(id uint) ([]donationDomain.AuditLog, error) {
+	logs, err := uc.repo.GetAuditLogs(id)
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to get donation history")
+		return nil, errors.New("failed to get donation history")
+	}
+
+	if len(logs) == 0 {
+		// Donations made before the audit trail existed have no history yet
+		if _, err := uc.repo.GetByID(id); err != nil {
+			return nil, err
+		}
+		logs = []donationDomain.AuditLog{}
+	}
+	return logs, nil
+}
+
 // GetSummary calculates donation summary with optimized SQL
 func (uc *useCase) GetSummary() (*SummaryResponse, error) {
 	// Get total amount (only success payments)
@@ -206,7 +342,7 @@ func (uc *useCase) GetSummary() (*SummaryResponse, error) {
 	}
 
 	// Get amount per category (only success payments)
-	categoryAmounts, err := uc.repo.GetAmountPerCategory(&successStatus)
+	categoryAmounts, err := uc.repo.GetAmountPerCategory(&donationRepo.Filter{Status: &successStatus})
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to calculate amount per category")
 		return nil, errors.New("failed to calculate amount per category")
@@ -218,19 +354,287 @@ func (uc *useCase) GetSummary() (*SummaryResponse, error) {
 		perCategory[i] = CategorySummary{
 			CategoryID:   ca.CategoryID,
 			CategoryName: ca.CategoryName,
+			FundType:     ca.FundType,
 			Amount:       ca.Amount,
 		}
 	}
 
 	logger.Info().
-		Float64("total_amount", totalAmount).
+		Str("total_amount", totalAmount.String()).
 		Int64("total_transactions", totalTransactions).
 		Msg("Donation summary calculated successfully")
 
 	return &SummaryResponse{
-		TotalAmount:      totalAmount,
+		TotalAmount:       totalAmount,
 		TotalTransactions: totalTransactions,
-		PerCategory:      perCategory,
+		PerCategory:       perCategory,
 	}, nil
 }
 
+// Checkout creates a pending donation and a charge at the default payment provider
+func (uc *useCase) Checkout(req *CheckoutRequest) (*CheckoutResponse, error) {
+	provider, err := uc.payments.Default()
+	if err != nil {
+		logger.Error().Err(err).Msg("No default payment provider configured")
+		return nil, errors.New("payment provider is not available")
+	}
+
+	providerName := provider.Name()
+	reference := GeneratePaymentReference()
+
+	don, err := uc.CreatePending(req, providerName, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	donorName := anonymousDonorName
+	if req.DonorName != nil && *req.DonorName != "" {
+		donorName = *req.DonorName
+	}
+
+	charge, err := provider.CreateCharge(&paymentService.ChargeRequest{
+		OrderID:     reference,
+		Amount:      req.Amount,
+		DonorName:   donorName,
+		Description: fmt.Sprintf("Donasi #%d", don.ID),
+	})
+	if err != nil {
+		logger.Error().Err(err).Uint("id", don.ID).Str("provider", providerName).Msg("Failed to create payment charge")
+		if _, markErr := uc.savePaymentResult(don, &donationRepo.PaymentResult{
+			Status: donationDomain.PaymentStatusFailed,
+		}); markErr != nil {
+			logger.Warn().Err(markErr).Uint("id", don.ID).Msg("Failed to mark donation as failed")
+		}
+		return nil, errors.New("failed to create payment")
+	}
+
+	if charge.TransactionID != "" {
+		before := *don
+		don.TransactionID = &charge.TransactionID
+		if err := uc.update(&before, don, nil); err != nil {
+			logger.Warn().Err(err).Uint("id", don.ID).Msg("Failed to store transaction ID")
+		}
+	}
+
+	logger.Info().
+		Uint("id", don.ID).
+		Str("provider", providerName).
+		Str("payment_reference", reference).
+		Str("amount", don.Amount.String()).
+		Msg("Donation checkout created")
+
+	return &CheckoutResponse{
+		Donation: don,
+		Payment:  charge,
+	}, nil
+}
+
+// CreatePending creates a donation awaiting payment through the given payment
+// channel under the given payment reference
+func (uc *useCase) CreatePending(req *CheckoutRequest, provider, reference string) (*donationDomain.Donation, error) {
+	if uc.campaigns != nil {
+		if err := uc.campaigns.EnsureOpen(req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	don := &donationDomain.Donation{
+		CategoryID:       req.CategoryID,
+		DonorName:        req.DonorName,
+		Amount:           req.Amount,
+		Message:          req.Message,
+		UserID:           DonorAccount(req.UserID, req.Anonymous),
+		PaymentStatus:    donationDomain.PaymentStatusPending,
+		PaymentProvider:  &provider,
+		PaymentReference: &reference,
+	}
+
+	if err := uc.create(don, DonorActor(don)); err != nil {
+		logger.Error().Err(err).Str("provider", provider).Msg("Failed to create pending donation")
+		return nil, errors.New("failed to create donation")
+	}
+	return don, nil
+}
+
+// HandleWebhook verifies a gateway callback and moves the donation out of pending
+func (uc *useCase) HandleWebhook(providerName string, header http.Header, body []byte) (*donationDomain.Donation, error) {
+	provider, err := uc.payments.Get(providerName)
+	if err != nil {
+		return nil, errors.New("unknown payment provider")
+	}
+
+	event, err := provider.VerifyWebhook(header, body)
+	if err != nil {
+		logger.Warn().Err(err).Str("provider", providerName).Msg("Rejected payment webhook")
+		if errors.Is(err, paymentService.ErrInvalidSignature) {
+			return nil, errors.New("invalid webhook signature")
+		}
+		return nil, errors.New("invalid webhook payload")
+	}
+
+	don, err := uc.repo.GetByPaymentReference(event.OrderID)
+	if err != nil {
+		logger.Warn().Err(err).Str("order_id", event.OrderID).Msg("Webhook for unknown donation")
+		return nil, err
+	}
+
+	if don.PaymentProvider == nil || *don.PaymentProvider != providerName {
+		logger.Warn().
+			Uint("id", don.ID).
+			Str("provider", providerName).
+			Msg("Webhook provider does not match donation")
+		return nil, errors.New("invalid webhook payload")
+	}
+
+	if event.Status == paymentService.StatusSuccess && event.Amount != don.Amount {
+		logger.Warn().
+			Uint("id", don.ID).
+			Str("expected", don.Amount.String()).
+			Str("received", event.Amount.String()).
+			Msg("Webhook amount does not match donation")
+		return nil, errors.New("payment amount mismatch")
+	}
+
+	return uc.applyPaymentResult(don, event.Status, event.TransactionID, string(event.RawPayload))
+}
+
+// SyncPaymentStatus asks the gateway for the latest status of a pending donation
+func (uc *useCase) SyncPaymentStatus(id uint) (*donationDomain.Donation, error) {
+	don, err := uc.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if don.PaymentReference == nil || don.PaymentProvider == nil {
+		return nil, errors.New("donation has no payment reference")
+	}
+
+	provider, err := uc.payments.Get(*don.PaymentProvider)
+	if err != nil {
+		return nil, errors.New("unknown payment provider")
+	}
+
+	status, err := provider.QueryStatus(*don.PaymentReference)
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to query payment status")
+		return nil, errors.New("failed to query payment status")
+	}
+
+	return uc.applyPaymentResult(don, status, "", "")
+}
+
+// applyPaymentResult transitions a pending donation; repeated results are no-ops
+func (uc *useCase) applyPaymentResult(don *donationDomain.Donation, status paymentService.Status, transactionID, payload string) (*donationDomain.Donation, error) {
+	// Providers retry notifications, often still pending; nothing to record
+	if donationDomain.PaymentStatus(status) == don.PaymentStatus {
+		logger.Debug().
+			Uint("id", don.ID).
+			Str("payment_status", string(status)).
+			Msg("Payment result ignored, status unchanged")
+		return don, nil
+	}
+
+	result := &donationRepo.PaymentResult{
+		Status:        donationDomain.PaymentStatus(status),
+		TransactionID: transactionID,
+		Payload:       payload,
+	}
+	if status == paymentService.StatusSuccess {
+		now := time.Now()
+		result.PaidAt = &now
+	}
+
+	changed, err := uc.savePaymentResult(don, result)
+	if err != nil {
+		logger.Error().Err(err).Uint("id", don.ID).Msg("Failed to apply payment result")
+		return nil, errors.New("failed to update payment status")
+	}
+
+	if !changed {
+		logger.Info().
+			Uint("id", don.ID).
+			Str("payment_status", string(don.PaymentStatus)).
+			Msg("Payment result ignored, donation already finalized")
+		return don, nil
+	}
+
+	logger.Info().
+		Uint("id", don.ID).
+		Str("payment_status", string(status)).
+		Msg("Donation payment status updated")
+
+	updated, err := uc.repo.GetByID(don.ID)
+	if err != nil {
+		return nil, err
+	}
+	uc.issueReceipt(updated)
+	uc.postToLedger(updated)
+
+	return updated, nil
+}
+
+// create stores a new donation together with its audit entry
+func (uc *useCase) create(don *donationDomain.Donation, actor *donationDomain.Actor) error {
+	return uc.repo.Audited(func(tx donationRepo.Repository) (*donationDomain.AuditLog, error) {
+		if err := tx.Create(don); err != nil {
+			return nil, err
+		}
+		return donationDomain.NewAuditLog(donationDomain.AuditActionCreate, nil, don, actor)
+	})
+}
+
+// update saves a changed donation together with its audit entry
+func (uc *useCase) update(before, after *donationDomain.Donation, actor *donationDomain.Actor) error {
+	return uc.repo.Audited(func(tx donationRepo.Repository) (*donationDomain.AuditLog, error) {
+		if err := tx.Update(after); err != nil {
+			return nil, err
+		}
+		return donationDomain.NewAuditLog(donationDomain.AuditActionUpdate, before, after, actor)
+	})
+}
+
+// savePaymentResult applies a gateway result to a pending donation and records
+// the transition. Gateway results are made by the system, so there is no actor.
+func (uc *useCase) savePaymentResult(don *donationDomain.Donation, result *donationRepo.PaymentResult) (bool, error) {
+	var changed bool
+	err := uc.repo.Audited(func(tx donationRepo.Repository) (*donationDomain.AuditLog, error) {
+		var err error
+		if changed, err = tx.ApplyPaymentResult(don.ID, result); err != nil || !changed {
+			return nil, err
+		}
+		after, err := tx.GetByID(don.ID)
+		if err != nil {
+			return nil, err
+		}
+		return donationDomain.NewAuditLog(donationDomain.AuditActionUpdate, don, after, nil)
+	})
+	return changed, err
+}
+
+// issueReceipt issues the receipt of a successful donation. Failures are logged
+// only; the receipt is issued again on first download.
+func (uc *useCase) issueReceipt(don *donationDomain.Donation) {
+	if uc.receipts == nil || don.PaymentStatus != donationDomain.PaymentStatusSuccess {
+		return
+	}
+	if _, err := uc.receipts.Issue(don.ID); err != nil {
+		logger.Error().Err(err).Uint("id", don.ID).Msg("Failed to issue donation receipt")
+	}
+}
+
+// postToLedger records the donation in the cash ledger, or removes it when it is
+// no longer successful. Failures are logged only so payments are never blocked.
+func (uc *useCase) postToLedger(don *donationDomain.Donation) {
+	if uc.ledger == nil {
+		return
+	}
+	if err := uc.ledger.PostDonation(don); err != nil {
+		logger.Error().Err(err).Uint("id", don.ID).Msg("Failed to post donation to ledger")
+	}
+}
+
+// GeneratePaymentReference creates a unique 25-character order ID for payment channels
+func GeneratePaymentReference() string {
+	id := strings.ToUpper(strings.ReplaceAll(uuid.New().String(), "-", "")[:12])
+	return fmt.Sprintf("DON-%s-%s", time.Now().Format("20060102"), id)
+}
@@ -2,9 +2,11 @@ package donation
 
 import (
 	"testing"
+	"time"
 
 	donationDomain "github.com/madr/backend/internal/domain/donation"
 	donationRepo "github.com/madr/backend/internal/repository/donation"
+	"github.com/madr/backend/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -12,6 +14,7 @@ import (
 // MockDonationRepository is a mock implementation of donation.Repository
 type MockDonationRepository struct {
 	mock.Mock
+	audit []donationDomain.AuditLog // Entries appended through Audited
 }
 
 func (m *MockDonationRepository) Create(don *donationDomain.Donation) error {
@@ -27,8 +30,8 @@ func (m *MockDonationRepository) GetByID(id uint) (*donationDomain.Donation, err
 	return args.Get(0).(*donationDomain.Donation), args.Error(1)
 }
 
-func (m *MockDonationRepository) GetAll(limit, offset int, status *donationDomain.PaymentStatus) ([]donationDomain.Donation, int64, error) {
-	args := m.Called(limit, offset, status)
+func (m *MockDonationRepository) GetAll(limit, offset int, filter *donationRepo.Filter) ([]donationDomain.Donation, int64, error) {
+	args := m.Called(limit, offset, filter)
 	return args.Get(0).([]donationDomain.Donation), args.Get(1).(int64), args.Error(2)
 }
 
@@ -42,9 +45,9 @@ func (m *MockDonationRepository) Delete(id uint) error {
 	return args.Error(0)
 }
 
-func (m *MockDonationRepository) GetTotalAmount(status *donationDomain.PaymentStatus) (float64, error) {
+func (m *MockDonationRepository) GetTotalAmount(status *donationDomain.PaymentStatus) (money.Money, error) {
 	args := m.Called(status)
-	return args.Get(0).(float64), args.Error(1)
+	return args.Get(0).(money.Money), args.Error(1)
 }
 
 func (m *MockDonationRepository) GetTotalTransactions(status *donationDomain.PaymentStatus) (int64, error) {
@@ -52,11 +55,73 @@ func (m *MockDonationRepository) GetTotalTransactions(status *donationDomain.Pay
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockDonationRepository) GetAmountPerCategory(status *donationDomain.PaymentStatus) ([]donationRepo.CategoryAmount, error) {
-	args := m.Called(status)
+func (m *MockDonationRepository) GetAmountPerCategory(filter *donationRepo.Filter) ([]donationRepo.CategoryAmount, error) {
+	args := m.Called(filter)
 	return args.Get(0).([]donationRepo.CategoryAmount), args.Error(1)
 }
 
+func (m *MockDonationRepository) GetByPaymentReference(reference string) (*donationDomain.Donation, error) {
+	args := m.Called(reference)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*donationDomain.Donation), args.Error(1)
+}
+
+func (m *MockDonationRepository) ApplyPaymentResult(id uint, result *donationRepo.PaymentResult) (bool, error) {
+	args := m.Called(id, result)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDonationRepository) GetTotals(filter *donationRepo.Filter) (*donationRepo.Totals, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*donationRepo.Totals), args.Error(1)
+}
+
+func (m *MockDonationRepository) FindInBatches(filter *donationRepo.Filter, batchSize int, fn func(batch []donationDomain.Donation) error) error {
+	args := m.Called(filter, batchSize)
+	if batches, ok := args.Get(0).([][]donationDomain.Donation); ok {
+		for _, batch := range batches {
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockDonationRepository) GetPeriodAmounts(period donationDomain.ReportPeriod, filter *donationRepo.Filter) ([]donationRepo.PeriodAmount, error) {
+	args := m.Called(period, filter)
+	return args.Get(0).([]donationRepo.PeriodAmount), args.Error(1)
+}
+
+func (m *MockDonationRepository) GetYearlyTotals(filter *donationRepo.Filter) ([]donationRepo.YearTotal, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]donationRepo.YearTotal), args.Error(1)
+}
+
+func (m *MockDonationRepository) Claim(id, userID uint) (bool, error) {
+	args := m.Called(id, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDonationRepository) Audited(fn func(tx donationRepo.Repository) (*donationDomain.AuditLog, error)) error {
+	entry, err := fn(m)
+	if err != nil || entry == nil {
+		return err
+	}
+	m.audit = append(m.audit, *entry)
+	return nil
+}
+
+func (m *MockDonationRepository) GetAuditLogs(donationID uint) ([]donationDomain.AuditLog, error) {
+	args := m.Called(donationID)
+	return args.Get(0).([]donationDomain.AuditLog), args.Error(1)
+}
+
 // TestGetSummary_Success tests successful summary calculation
 func TestGetSummary_Success(t *testing.T) {
 	// Setup mock
@@ -65,28 +130,28 @@ func TestGetSummary_Success(t *testing.T) {
 	successStatus := donationDomain.PaymentStatusSuccess
 
 	// Setup expectations
-	mockRepo.On("GetTotalAmount", &successStatus).Return(20000000.0, nil)
+	mockRepo.On("GetTotalAmount", &successStatus).Return(money.FromRupiah(20000000), nil)
 	mockRepo.On("GetTotalTransactions", &successStatus).Return(int64(150), nil)
-	mockRepo.On("GetAmountPerCategory", &successStatus).Return([]donationRepo.CategoryAmount{
+	mockRepo.On("GetAmountPerCategory", &donationRepo.Filter{Status: &successStatus}).Return([]donationRepo.CategoryAmount{
 		{
 			CategoryID:   1,
 			CategoryName: "Pembangunan",
-			Amount:       12000000.0,
+			Amount:       money.FromRupiah(12000000),
 		},
 		{
 			CategoryID:   2,
 			CategoryName: "Operasional",
-			Amount:       6000000.0,
+			Amount:       money.FromRupiah(6000000),
 		},
 		{
 			CategoryID:   3,
 			CategoryName: "Sosial",
-			Amount:       2000000.0,
+			Amount:       money.FromRupiah(2000000),
 		},
 	}, nil)
 
 	// Create use case
-	useCase := NewUseCase(mockRepo)
+	useCase := NewUseCase(mockRepo, nil, nil, nil, nil)
 
 	// Test summary
 	summary, err := useCase.GetSummary()
@@ -94,13 +159,13 @@ func TestGetSummary_Success(t *testing.T) {
 	// Assertions
 	assert.NoError(t, err)
 	assert.NotNil(t, summary)
-	assert.Equal(t, 20000000.0, summary.TotalAmount)
+	assert.Equal(t, money.FromRupiah(20000000), summary.TotalAmount)
 	assert.Equal(t, int64(150), summary.TotalTransactions)
 	assert.Len(t, summary.PerCategory, 3)
 	assert.Equal(t, "Pembangunan", summary.PerCategory[0].CategoryName)
-	assert.Equal(t, 12000000.0, summary.PerCategory[0].Amount)
+	assert.Equal(t, money.FromRupiah(12000000), summary.PerCategory[0].Amount)
 	assert.Equal(t, "Operasional", summary.PerCategory[1].CategoryName)
-	assert.Equal(t, 6000000.0, summary.PerCategory[1].Amount)
+	assert.Equal(t, money.FromRupiah(6000000), summary.PerCategory[1].Amount)
 
 	// Verify all expectations were met
 	mockRepo.AssertExpectations(t)
@@ -114,12 +179,12 @@ func TestGetSummary_EmptyData(t *testing.T) {
 	successStatus := donationDomain.PaymentStatusSuccess
 
 	// Setup expectations
-	mockRepo.On("GetTotalAmount", &successStatus).Return(0.0, nil)
+	mockRepo.On("GetTotalAmount", &successStatus).Return(money.Money(0), nil)
 	mockRepo.On("GetTotalTransactions", &successStatus).Return(int64(0), nil)
-	mockRepo.On("GetAmountPerCategory", &successStatus).Return([]donationRepo.CategoryAmount{}, nil)
+	mockRepo.On("GetAmountPerCategory", &donationRepo.Filter{Status: &successStatus}).Return([]donationRepo.CategoryAmount{}, nil)
 
 	// Create use case
-	useCase := NewUseCase(mockRepo)
+	useCase := NewUseCase(mockRepo, nil, nil, nil, nil)
 
 	// Test summary
 	summary, err := useCase.GetSummary()
@@ -127,7 +192,7 @@ func TestGetSummary_EmptyData(t *testing.T) {
 	// Assertions
 	assert.NoError(t, err)
 	assert.NotNil(t, summary)
-	assert.Equal(t, 0.0, summary.TotalAmount)
+	assert.Equal(t, money.Money(0), summary.TotalAmount)
 	assert.Equal(t, int64(0), summary.TotalTransactions)
 	assert.Len(t, summary.PerCategory, 0)
 
@@ -143,10 +208,10 @@ func TestGetSummary_ErrorHandling(t *testing.T) {
 	successStatus := donationDomain.PaymentStatusSuccess
 
 	// Setup expectations - simulate error
-	mockRepo.On("GetTotalAmount", &successStatus).Return(0.0, assert.AnError)
+	mockRepo.On("GetTotalAmount", &successStatus).Return(money.Money(0), assert.AnError)
 
 	// Create use case
-	useCase := NewUseCase(mockRepo)
+	useCase := NewUseCase(mockRepo, nil, nil, nil, nil)
 
 	// Test summary
 	summary, err := useCase.GetSummary()
@@ -160,3 +225,32 @@ func TestGetSummary_ErrorHandling(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+// TestUpdate_PaidAt tests that only successful donations keep a payment time
+func TestUpdate_PaidAt(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	useCase := NewUseCase(mockRepo, nil, nil, nil, nil)
+
+	paidAt := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	don := newPendingDonation()
+	don.PaymentStatus = donationDomain.PaymentStatusSuccess
+	don.PaidAt = &paidAt
+	mockRepo.On("GetByID", uint(7)).Return(don, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*donation.Donation")).Return(nil)
+
+	failed := string(donationDomain.PaymentStatusFailed)
+	updated, err := useCase.Update(7, &UpdateRequest{PaymentStatus: &failed}, &donationDomain.Actor{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, donationDomain.PaymentStatusFailed, updated.PaymentStatus)
+	assert.Nil(t, updated.PaidAt)
+	mockRepo.AssertCalled(t, "Update", mock.MatchedBy(func(d *donationDomain.Donation) bool {
+		return d.PaidAt == nil
+	}))
+
+	// Marking it successful again sets a new payment time
+	success := string(donationDomain.PaymentStatusSuccess)
+	updated, err = useCase.Update(7, &UpdateRequest{PaymentStatus: &success}, &donationDomain.Actor{})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, updated.PaidAt)
+}
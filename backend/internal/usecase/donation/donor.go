@@ -0,0 +1,108 @@
+package donation
+
+import (
+	"errors"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// ClaimRequest represents a donor claiming a past donation
+type ClaimRequest struct {
+	PaymentReference string `json:"payment_reference" binding:"required,max=100"`
+}
+
+// DonorHistoryResponse lists a donor's donations with their successful
+// donations summed per year
+type DonorHistoryResponse struct {
+	Data         []donationDomain.Donation `json:"data"`
+	Total        int64                     `json:"total"`
+	Limit        int                       `json:"limit"`
+	Offset       int                       `json:"offset"`
+	TotalPages   int                       `json:"total_pages"`
+	YearlyTotals []donationRepo.YearTotal  `json:"yearly_totals"`
+}
+
+// DonorAccount returns the account a new donation is linked to, or nil when
+// the donor is not signed in or asked to give anonymously
+func DonorAccount(userID *uint, anonymous bool) *uint {
+	if anonymous || userID == nil {
+		return nil
+	}
+	id := *userID
+	return &id
+}
+
+// DonorActor is the audit actor of a public donation: the linked donor
+// account, or nil when the donation is not linked to one
+func DonorActor(don *donationDomain.Donation) *donationDomain.Actor {
+	if don.UserID == nil {
+		return nil
+	}
+	return &donationDomain.Actor{UserID: don.UserID}
+}
+
+// GetDonorHistory lists the donations linked to a donor account
+func (uc *useCase) GetDonorHistory(userID uint, limit, offset int, filter *ListFilter) (*DonorHistoryResponse, error) {
+	if filter == nil {
+		filter = &ListFilter{}
+	}
+	own := *filter
+	own.UserID = &userID
+
+	page, err := uc.GetAll(limit, offset, &own)
+	if err != nil {
+		return nil, err
+	}
+
+	// Yearly totals cover every successful donation of the donor, not only
+	// the filtered page
+	success := donationDomain.PaymentStatusSuccess
+	totals, err := uc.repo.GetYearlyTotals(&donationRepo.Filter{Status: &success, UserID: &userID})
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to get donor yearly totals")
+		return nil, errors.New("failed to get donations")
+	}
+	if totals == nil {
+		totals = []donationRepo.YearTotal{}
+	}
+
+	return &DonorHistoryResponse{
+		Data:         page.Data,
+		Total:        page.Total,
+		Limit:        page.Limit,
+		Offset:       page.Offset,
+		TotalPages:   page.TotalPages,
+		YearlyTotals: totals,
+	}, nil
+}
+
+// ClaimDonation links a past donation to the donor account. The payment
+// reference, only known to whoever made the payment, proves ownership.
+func (uc *useCase) ClaimDonation(userID uint, req *ClaimRequest) (*donationDomain.Donation, error) {
+	don, err := uc.repo.GetByPaymentReference(req.PaymentReference)
+	if err != nil {
+		return nil, err
+	}
+
+	if don.UserID != nil {
+		if *don.UserID == userID {
+			return uc.repo.GetByID(don.ID)
+		}
+		return nil, errors.New("donation already claimed")
+	}
+
+	claimed, err := uc.repo.Claim(don.ID, userID)
+	if err != nil {
+		logger.Error().Err(err).Uint("id", don.ID).Uint("user_id", userID).Msg("Failed to claim donation")
+		return nil, errors.New("failed to claim donation")
+	}
+	if !claimed {
+		// Claimed by someone else between the read and the update
+		return nil, errors.New("donation already claimed")
+	}
+
+	logger.Info().Uint("id", don.ID).Uint("user_id", userID).Msg("Donation claimed")
+	return uc.repo.GetByID(don.ID)
+}
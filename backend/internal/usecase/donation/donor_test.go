@@ -0,0 +1,83 @@
+package donation
+
+import (
+	"testing"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	"github.com/madr/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClaimDonation tests claiming an anonymous donation by its payment
+// reference, claiming it again, and claiming another donor's donation
+func TestClaimDonation(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	useCase := NewUseCase(mockRepo, nil, nil, nil, nil)
+
+	anonymous := &donationDomain.Donation{Amount: money.FromRupiah(50000)}
+	anonymous.ID = 5
+	mockRepo.On("GetByPaymentReference", "DON-A").Return(anonymous, nil)
+	mockRepo.On("Claim", uint(5), uint(7)).Return(true, nil)
+
+	owner := uint(7)
+	claimed := &donationDomain.Donation{Amount: money.FromRupiah(50000), UserID: &owner}
+	claimed.ID = 5
+	mockRepo.On("GetByID", uint(5)).Return(claimed, nil)
+
+	don, err := useCase.ClaimDonation(7, &ClaimRequest{PaymentReference: "DON-A"})
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), *don.UserID)
+
+	// Claiming your own donation again is a no-op
+	mockRepo.On("GetByPaymentReference", "DON-B").Return(claimed, nil)
+	_, err = useCase.ClaimDonation(7, &ClaimRequest{PaymentReference: "DON-B"})
+	require.NoError(t, err)
+	mockRepo.AssertNumberOfCalls(t, "Claim", 1)
+
+	_, err = useCase.ClaimDonation(8, &ClaimRequest{PaymentReference: "DON-B"})
+	assert.EqualError(t, err, "donation already claimed")
+
+	// Lost race against another donor
+	race := &donationDomain.Donation{}
+	race.ID = 6
+	mockRepo.On("GetByPaymentReference", "DON-C").Return(race, nil)
+	mockRepo.On("Claim", uint(6), uint(7)).Return(false, nil)
+	_, err = useCase.ClaimDonation(7, &ClaimRequest{PaymentReference: "DON-C"})
+	assert.EqualError(t, err, "donation already claimed")
+}
+
+// TestGetDonorHistory tests that the history is scoped to the donor and the
+// yearly totals only count successful donations
+func TestGetDonorHistory(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	useCase := NewUseCase(mockRepo, nil, nil, nil, nil)
+
+	pending := donationDomain.PaymentStatusPending
+	mockRepo.On("GetAll", 10, 0, mock.MatchedBy(func(f *donationRepo.Filter) bool {
+		return f.UserID != nil && *f.UserID == 7 && f.Status != nil && *f.Status == pending
+	})).Return([]donationDomain.Donation{{Amount: money.FromRupiah(25000)}}, int64(1), nil)
+	mockRepo.On("GetYearlyTotals", mock.MatchedBy(func(f *donationRepo.Filter) bool {
+		return *f.UserID == 7 && *f.Status == donationDomain.PaymentStatusSuccess && f.From == nil
+	})).Return([]donationRepo.YearTotal{
+		{Year: 2025, Amount: money.FromRupiah(300000), Transactions: 4},
+		{Year: 2024, Amount: money.FromRupiah(100000), Transactions: 1},
+	}, nil)
+
+	history, err := useCase.GetDonorHistory(7, 10, 0, &ListFilter{Status: &pending})
+	require.NoError(t, err)
+	assert.Len(t, history.Data, 1)
+	assert.Equal(t, int64(1), history.Total)
+	require.Len(t, history.YearlyTotals, 2)
+	assert.Equal(t, 2025, history.YearlyTotals[0].Year)
+}
+
+// TestDonorAccount tests when new donations are linked to the donor
+func TestDonorAccount(t *testing.T) {
+	id := uint(3)
+	assert.Equal(t, uint(3), *DonorAccount(&id, false))
+	assert.Nil(t, DonorAccount(&id, true))
+	assert.Nil(t, DonorAccount(nil, false))
+}
@@ -0,0 +1,328 @@
+package donation
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/money"
+	"github.com/madr/backend/pkg/xlsx"
+)
+
+// ExportFormat is the file format of a donation export
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportXLSX ExportFormat = "xlsx"
+)
+
+// exportBatchSize is the number of donations loaded per query while exporting
+const exportBatchSize = 500
+
+// exportTimeLayout formats donation times in Jakarta time
+const exportTimeLayout = "2006-01-02 15:04"
+
+var donationColumns = []string{"No", "Tanggal", "ID", "Referensi", "Donatur", "Kategori", "Nominal", "Status", "Pesan"}
+
+// IsValid reports whether f is a supported export format
+func (f ExportFormat) IsValid() bool {
+	return f == ExportCSV || f == ExportXLSX
+}
+
+// ContentType returns the MIME type of the export format
+func (f ExportFormat) ContentType() string {
+	if f == ExportXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ExportDonations streams the donations matching the filter to w. XLSX exports
+// also contain a summary sheet with category subtotals. Validation errors are
+// returned before anything is written.
+func (uc *useCase) ExportDonations(w io.Writer, format ExportFormat, filter *ListFilter) error {
+	repoFilter, err := prepareExport(format, filter)
+	if err != nil {
+		return err
+	}
+
+	out := newSheetWriter(w, format)
+	if err := out.newSheet("Donasi", 5, 17, 8, 28, 28, 20, 16, 10, 40); err != nil {
+		return exportFailed(err)
+	}
+	if err := out.writeRow(headerCells(donationColumns...)...); err != nil {
+		return exportFailed(err)
+	}
+
+	number := int64(0)
+	err = uc.repo.FindInBatches(repoFilter, exportBatchSize, func(batch []donationDomain.Donation) error {
+		for i := range batch {
+			number++
+			if err := out.writeRow(donationCells(number, &batch[i])...); err != nil {
+				return err
+			}
+		}
+		return out.flush()
+	})
+	if err != nil {
+		return exportFailed(err)
+	}
+
+	if format == ExportXLSX {
+		if err := uc.writeSummary(out, filter, repoFilter); err != nil {
+			return err
+		}
+	}
+
+	if err := out.close(); err != nil {
+		return exportFailed(err)
+	}
+
+	logger.Info().Str("format", string(format)).Int64("rows", number).Msg("Donations exported")
+	return nil
+}
+
+// ExportSummary writes the category subtotals of the donations matching the filter
+func (uc *useCase) ExportSummary(w io.Writer, format ExportFormat, filter *ListFilter) error {
+	repoFilter, err := prepareExport(format, filter)
+	if err != nil {
+		return err
+	}
+
+	out := newSheetWriter(w, format)
+	if err := uc.writeSummary(out, filter, repoFilter); err != nil {
+		return err
+	}
+	if err := out.close(); err != nil {
+		return exportFailed(err)
+	}
+	return nil
+}
+
+// writeSummary writes the report header and category subtotals as a new sheet
+func (uc *useCase) writeSummary(out sheetWriter, filter *ListFilter, repoFilter *donationRepo.Filter) error {
+	amounts, err := uc.repo.GetAmountPerCategory(repoFilter)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to calculate amount per category for export")
+		return errors.New("failed to export donations")
+	}
+
+	period := "Semua waktu"
+	if filter != nil && (filter.From != nil || filter.To != nil) {
+		from, to := "awal", "sekarang"
+		if filter.From != nil {
+			from = filter.From.Format(utils.DateLayout)
+		}
+		if filter.To != nil {
+			to = filter.To.Format(utils.DateLayout)
+		}
+		period = from + " s.d. " + to
+	}
+	status := string(donationDomain.PaymentStatusSuccess)
+	if repoFilter != nil && repoFilter.Status != nil {
+		status = string(*repoFilter.Status)
+	}
+
+	rows := [][]exportCell{
+		headerCells("Ringkasan Donasi"),
+		{textCell("Periode"), textCell(period)},
+		{textCell("Status"), textCell(status)},
+		{},
+		headerCells("Kategori", "Transaksi", "Nominal"),
+	}
+
+	var total money.Money
+	var transactions int64
+	for _, ca := range amounts {
+		rows = append(rows, []exportCell{textCell(ca.CategoryName), intCell(ca.Transactions), amountCell(ca.Amount)})
+		total = total.Add(ca.Amount)
+		transactions += ca.Transactions
+	}
+	rows = append(rows, []exportCell{
+		{value: "Total", kind: cellHeader},
+		intCell(transactions),
+		amountCell(total),
+	})
+
+	if err := out.newSheet("Ringkasan", 28, 12, 18); err != nil {
+		return exportFailed(err)
+	}
+	for _, row := range rows {
+		if err := out.writeRow(row...); err != nil {
+			return exportFailed(err)
+		}
+	}
+	return nil
+}
+
+// prepareExport validates the export request before any output is written
+func prepareExport(format ExportFormat, filter *ListFilter) (*donationRepo.Filter, error) {
+	if !format.IsValid() {
+		return nil, errors.New("invalid export format")
+	}
+	return filter.toRepository()
+}
+
+func exportFailed(err error) error {
+	logger.Error().Err(err).Msg("Failed to write donation export")
+	return errors.New("failed to export donations")
+}
+
+func donationCells(number int64, don *donationDomain.Donation) []exportCell {
+	at := don.CreatedAt
+	if don.PaidAt != nil {
+		at = *don.PaidAt
+	}
+
+	donor := anonymousDonorName
+	if don.DonorName != nil && *don.DonorName != "" {
+		donor = *don.DonorName
+	}
+	category := ""
+	if don.Category != nil {
+		category = don.Category.Name
+	}
+	reference := ""
+	if don.PaymentReference != nil {
+		reference = *don.PaymentReference
+	}
+
+	return []exportCell{
+		intCell(number),
+		textCell(at.In(utils.Jakarta).Format(exportTimeLayout)),
+		intCell(int64(don.ID)),
+		textCell(reference),
+		textCell(donor),
+		textCell(category),
+		amountCell(don.Amount),
+		textCell(string(don.PaymentStatus)),
+		textCell(don.Message),
+	}
+}
+
+type cellKind int
+
+const (
+	cellText cellKind = iota
+	cellHeader
+	cellInt
+	cellAmount
+)
+
+// exportCell is a value written to either a CSV row or an XLSX row
+type exportCell struct {
+	value string
+	kind  cellKind
+}
+
+func textCell(s string) exportCell        { return exportCell{value: s} }
+func intCell(n int64) exportCell          { return exportCell{value: strconv.FormatInt(n, 10), kind: cellInt} }
+func amountCell(m money.Money) exportCell { return exportCell{value: m.String(), kind: cellAmount} }
+
+func headerCells(values ...string) []exportCell {
+	cells := make([]exportCell, len(values))
+	for i, v := range values {
+		cells[i] = exportCell{value: v, kind: cellHeader}
+	}
+	return cells
+}
+
+// sheetWriter writes rows in the export format. CSV has a single table, so
+// starting a new sheet separates the tables with an empty line.
+type sheetWriter interface {
+	newSheet(name string, widths ...float64) error
+	writeRow(cells ...exportCell) error
+	flush() error
+	close() error
+}
+
+func newSheetWriter(w io.Writer, format ExportFormat) sheetWriter {
+	if format == ExportXLSX {
+		return &xlsxSheetWriter{w: xlsx.NewWriter(w)}
+	}
+	return &csvSheetWriter{w: csv.NewWriter(w)}
+}
+
+type csvSheetWriter struct {
+	w      *csv.Writer
+	sheets int
+}
+
+func (c *csvSheetWriter) newSheet(string, ...float64) error {
+	c.sheets++
+	if c.sheets > 1 {
+		return c.w.Write(nil)
+	}
+	return nil
+}
+
+func (c *csvSheetWriter) writeRow(cells ...exportCell) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		record[i] = cell.value
+		if cell.kind == cellText {
+			record[i] = escapeFormula(cell.value)
+		}
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvSheetWriter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvSheetWriter) close() error {
+	return c.flush()
+}
+
+// escapeFormula keeps spreadsheet applications from evaluating user input
+// such as donor names as formulas when the CSV is opened
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+type xlsxSheetWriter struct {
+	w *xlsx.Writer
+}
+
+func (x *xlsxSheetWriter) newSheet(name string, widths ...float64) error {
+	return x.w.NewSheet(name, widths...)
+}
+
+func (x *xlsxSheetWriter) writeRow(cells ...exportCell) error {
+	row := make([]xlsx.Cell, len(cells))
+	for i, cell := range cells {
+		switch cell.kind {
+		case cellHeader:
+			row[i] = xlsx.Header(cell.value)
+		case cellInt:
+			n, _ := strconv.ParseInt(cell.value, 10, 64)
+			row[i] = xlsx.Int(n)
+		case cellAmount:
+			row[i] = xlsx.Amount(cell.value)
+		default:
+			row[i] = xlsx.String(cell.value)
+		}
+	}
+	return x.w.WriteRow(row...)
+}
+
+// flush is a no-op; rows are already streamed into the archive
+func (x *xlsxSheetWriter) flush() error {
+	return nil
+}
+
+func (x *xlsxSheetWriter) close() error {
+	return x.w.Close()
+}
@@ -0,0 +1,106 @@
+package donation
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"testing"
+	"time"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	"github.com/madr/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func exportDonation(id uint, donor string, amount money.Money) donationDomain.Donation {
+	paidAt := time.Date(2025, 1, 31, 18, 30, 0, 0, time.UTC)
+	don := donationDomain.Donation{
+		CategoryID:    1,
+		Amount:        amount,
+		PaymentStatus: donationDomain.PaymentStatusSuccess,
+		PaidAt:        &paidAt,
+		Category:      &donationDomain.DonationCategoryInfo{ID: 1, Name: "Infaq"},
+	}
+	don.ID = id
+	if donor != "" {
+		don.DonorName = &donor
+	}
+	return don
+}
+
+// TestExportDonations_CSV tests that batches are streamed as CSV rows in Jakarta
+// time and that user input cannot become a spreadsheet formula
+func TestExportDonations_CSV(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	useCase := NewUseCase(mockRepo, nil, nil, nil, nil)
+
+	mockRepo.On("FindInBatches", mock.Anything, exportBatchSize).Return([][]donationDomain.Donation{
+		{exportDonation(1, "Ahmad", money.MustParse("12500.75"))},
+		{exportDonation(2, "=HYPERLINK(\"x\")", money.FromRupiah(50000)), exportDonation(3, "", money.FromRupiah(1000))},
+	}, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, useCase.ExportDonations(&buf, ExportCSV, &ListFilter{}))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, donationColumns, records[0])
+	assert.Equal(t, []string{"1", "2025-02-01 01:30", "1", "", "Ahmad", "Infaq", "12500.75", "success", ""}, records[1])
+	assert.Equal(t, "'=HYPERLINK(\"x\")", records[2][4])
+	assert.Equal(t, anonymousDonorName, records[3][4])
+	assert.Equal(t, "3", records[3][0])
+}
+
+// TestExportDonations_XLSXIncludesSummary tests that the workbook has a list
+// sheet and a summary sheet with category subtotals
+func TestExportDonations_XLSXIncludesSummary(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	useCase := NewUseCase(mockRepo, nil, nil, nil, nil)
+
+	mockRepo.On("FindInBatches", mock.Anything, exportBatchSize).Return([][]donationDomain.Donation{
+		{exportDonation(1, "Ahmad", money.MustParse("12500.75"))},
+	}, nil)
+	mockRepo.On("GetAmountPerCategory", mock.Anything).Return([]donationRepo.CategoryAmount{
+		{CategoryID: 1, CategoryName: "Infaq", Amount: money.MustParse("12500.75"), Transactions: 1},
+		{CategoryID: 2, CategoryName: "Zakat", Amount: money.FromRupiah(100000), Transactions: 2},
+	}, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, useCase.ExportDonations(&buf, ExportXLSX, &ListFilter{From: mustDate(t, "2025-01-01")}))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	read := func(name string) string {
+		f, err := zr.Open(name)
+		require.NoError(t, err)
+		defer f.Close()
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	assert.Contains(t, read("xl/workbook.xml"), `name="Ringkasan"`)
+	assert.Contains(t, read("xl/worksheets/sheet1.xml"), `<v>12500.75</v>`)
+	summary := read("xl/worksheets/sheet2.xml")
+	assert.Contains(t, summary, "2025-01-01 s.d. sekarang")
+	assert.Contains(t, summary, `<v>112500.75</v>`)
+	assert.Contains(t, summary, `<v>3</v>`)
+}
+
+// TestExportDonations_ValidatesBeforeWriting tests that invalid requests write nothing
+func TestExportDonations_ValidatesBeforeWriting(t *testing.T) {
+	useCase := NewUseCase(new(MockDonationRepository), nil, nil, nil, nil)
+
+	var buf bytes.Buffer
+	assert.EqualError(t, useCase.ExportDonations(&buf, "pdf", nil), "invalid export format")
+	assert.EqualError(t, useCase.ExportSummary(&buf, ExportCSV, &ListFilter{
+		From: mustDate(t, "2025-02-01"),
+		To:   mustDate(t, "2025-01-01"),
+	}), "invalid date range")
+	assert.Zero(t, buf.Len())
+}
@@ -0,0 +1,281 @@
+package donation
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/money"
+)
+
+const (
+	// defaultReportBuckets is the number of periods reported when no range is given
+	defaultReportBuckets = 12
+	// maxReportBuckets keeps a report within a reasonable response size
+	maxReportBuckets = 400
+)
+
+// ReportRequest represents the request for a time-bucketed donation report.
+// From and To are inclusive Jakarta dates; missing bounds default to the last
+// twelve periods up to today.
+type ReportRequest struct {
+	Period     donationDomain.ReportPeriod
+	From       *time.Time
+	To         *time.Time
+	CategoryID *uint
+}
+
+// ReportResponse represents successful donations grouped per period
+type ReportResponse struct {
+	Period            donationDomain.ReportPeriod `json:"period"`
+	From              string                      `json:"from"`
+	To                string                      `json:"to"`
+	TotalAmount       money.Money                 `json:"total_amount"`
+	TotalTransactions int64                       `json:"total_transactions"`
+	Buckets           []ReportBucket              `json:"buckets"`
+	Previous          PeriodComparison            `json:"previous"`
+}
+
+// ReportBucket represents the donations of one period. Start and End are
+// inclusive dates clipped to the report range.
+type ReportBucket struct {
+	Start             string            `json:"start"`
+	End               string            `json:"end"`
+	TotalAmount       money.Money       `json:"total_amount"`
+	TotalTransactions int64             `json:"total_transactions"`
+	PerCategory       []CategorySummary `json:"per_category"`
+}
+
+// PeriodComparison compares the report with the range of equal length before it
+type PeriodComparison struct {
+	From              string      `json:"from"`
+	To                string      `json:"to"`
+	TotalAmount       money.Money `json:"total_amount"`
+	TotalTransactions int64       `json:"total_transactions"`
+	AmountChange      money.Money `json:"amount_change"`
+	// AmountChangePercent is null when the previous period has no donations
+	AmountChangePercent *float64 `json:"amount_change_percent"`
+}
+
+// GetReport aggregates successful donations per period and category
+func (uc *useCase) GetReport(req *ReportRequest) (*ReportResponse, error) {
+	period := req.Period
+	if period == "" {
+		period = donationDomain.PeriodMonth
+	}
+	if !period.IsValid() {
+		return nil, errors.New("invalid report period")
+	}
+
+	from, to, err := reportRange(period, req.From, req.To, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Bucket boundaries are midnights in Jakarta; to is exclusive from here on
+	end := to.AddDate(0, 0, 1)
+	starts := bucketStarts(period, from, end)
+	if len(starts) > maxReportBuckets {
+		return nil, errors.New("date range too large")
+	}
+
+	status := donationDomain.PaymentStatusSuccess
+	filter := &donationRepo.Filter{Status: &status, CategoryID: req.CategoryID, From: &from, To: &end}
+
+	amounts, err := uc.repo.GetPeriodAmounts(period, filter)
+	if err != nil {
+		logger.Error().Err(err).Str("period", string(period)).Msg("Failed to calculate donation report")
+		return nil, errors.New("failed to calculate donation report")
+	}
+
+	previousFrom := previousRangeStart(period, from, end, len(starts))
+	previous, err := uc.repo.GetTotals(&donationRepo.Filter{
+		Status:     &status,
+		CategoryID: req.CategoryID,
+		From:       &previousFrom,
+		To:         &from,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to calculate previous period totals")
+		return nil, errors.New("failed to calculate donation report")
+	}
+
+	resp := &ReportResponse{
+		Period:  period,
+		From:    from.Format(utils.DateLayout),
+		To:      to.Format(utils.DateLayout),
+		Buckets: make([]ReportBucket, len(starts)),
+	}
+
+	index := make(map[string]int, len(starts))
+	for i, start := range starts {
+		bucketStart, bucketEnd := start, nextPeriod(period, start)
+		if bucketStart.Before(from) {
+			bucketStart = from
+		}
+		if bucketEnd.After(end) {
+			bucketEnd = end
+		}
+		resp.Buckets[i] = ReportBucket{
+			Start:       bucketStart.Format(utils.DateLayout),
+			End:         bucketEnd.AddDate(0, 0, -1).Format(utils.DateLayout),
+			PerCategory: []CategorySummary{},
+		}
+		index[start.Format(utils.DateLayout)] = i
+	}
+
+	for _, pa := range amounts {
+		i, ok := index[truncatePeriod(period, pa.PeriodStart).Format(utils.DateLayout)]
+		if !ok {
+			logger.Warn().Time("period_start", pa.PeriodStart).Msg("Donation report row outside requested range")
+			continue
+		}
+		bucket := &resp.Buckets[i]
+		bucket.TotalAmount = bucket.TotalAmount.Add(pa.Amount)
+		bucket.TotalTransactions += pa.Transactions
+		bucket.PerCategory = append(bucket.PerCategory, CategorySummary{
+			CategoryID:   pa.CategoryID,
+			CategoryName: pa.CategoryName,
+			Amount:       pa.Amount,
+			Transactions: pa.Transactions,
+		})
+		resp.TotalAmount = resp.TotalAmount.Add(pa.Amount)
+		resp.TotalTransactions += pa.Transactions
+	}
+
+	resp.Previous = PeriodComparison{
+		From:              previousFrom.Format(utils.DateLayout),
+		To:                from.AddDate(0, 0, -1).Format(utils.DateLayout),
+		TotalAmount:       previous.Amount,
+		TotalTransactions: previous.Transactions,
+		AmountChange:      resp.TotalAmount.Sub(previous.Amount),
+	}
+	if previous.Amount.IsPositive() {
+		percent := float64(resp.Previous.AmountChange.Sen()) / float64(previous.Amount.Sen()) * 100
+		percent = math.Round(percent*100) / 100
+		resp.Previous.AmountChangePercent = &percent
+	}
+
+	return resp, nil
+}
+
+// reportRange resolves the inclusive date range of a report
+func reportRange(period donationDomain.ReportPeriod, from, to *time.Time, now time.Time) (time.Time, time.Time, error) {
+	end := utils.StartOfDay(now)
+	if to != nil {
+		end = utils.StartOfDay(*to)
+	}
+
+	var start time.Time
+	if from != nil {
+		start = utils.StartOfDay(*from)
+	} else {
+		start = truncatePeriod(period, end)
+		for i := 1; i < defaultReportBuckets; i++ {
+			start = previousPeriod(period, start)
+		}
+	}
+
+	if start.After(end) {
+		return time.Time{}, time.Time{}, errors.New("invalid date range")
+	}
+	return start, end, nil
+}
+
+// previousRangeStart returns the start of the range of equal length before
+// [from, end). Ranges of whole periods step back whole periods, so a report for
+// March compares with February rather than with the last 31 days.
+func previousRangeStart(period donationDomain.ReportPeriod, from, end time.Time, buckets int) time.Time {
+	if truncatePeriod(period, from).Equal(from) && truncatePeriod(period, end).Equal(end) {
+		start := from
+		for i := 0; i < buckets; i++ {
+			start = previousPeriod(period, start)
+		}
+		return start
+	}
+
+	days := int(math.Round(end.Sub(from).Hours() / 24))
+	return from.AddDate(0, 0, -days)
+}
+
+// bucketStarts lists the period starts overlapping [from, end)
+func bucketStarts(period donationDomain.ReportPeriod, from, end time.Time) []time.Time {
+	var starts []time.Time
+	for start := truncatePeriod(period, from); start.Before(end); start = nextPeriod(period, start) {
+		starts = append(starts, start)
+		if len(starts) > maxReportBuckets {
+			break
+		}
+	}
+	return starts
+}
+
+// truncatePeriod returns the start of the period containing t, in Jakarta time.
+// Weeks start on Monday, matching PostgreSQL date_trunc.
+func truncatePeriod(period donationDomain.ReportPeriod, t time.Time) time.Time {
+	day := utils.StartOfDay(t)
+	switch period {
+	case donationDomain.PeriodWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case donationDomain.PeriodMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, utils.Jakarta)
+	case donationDomain.PeriodYear:
+		return time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, utils.Jakarta)
+	default:
+		return day
+	}
+}
+
+// nextPeriod returns the start of the period after the one starting at start
+func nextPeriod(period donationDomain.ReportPeriod, start time.Time) time.Time {
+	switch period {
+	case donationDomain.PeriodWeek:
+		return start.AddDate(0, 0, 7)
+	case donationDomain.PeriodMonth:
+		return start.AddDate(0, 1, 0)
+	case donationDomain.PeriodYear:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// previousPeriod returns the start of the period before the one starting at start
+func previousPeriod(period donationDomain.ReportPeriod, start time.Time) time.Time {
+	switch period {
+	case donationDomain.PeriodWeek:
+		return start.AddDate(0, 0, -7)
+	case donationDomain.PeriodMonth:
+		return start.AddDate(0, -1, 0)
+	case donationDomain.PeriodYear:
+		return start.AddDate(-1, 0, 0)
+	default:
+		return start.AddDate(0, 0, -1)
+	}
+}
+
+// toRepository converts inclusive list dates into a repository filter
+func (f *ListFilter) toRepository() (*donationRepo.Filter, error) {
+	if f == nil {
+		return nil, nil
+	}
+
+	filter := &donationRepo.Filter{Status: f.Status, CategoryID: f.CategoryID, UserID: f.UserID}
+	if f.From != nil {
+		from := utils.StartOfDay(*f.From)
+		filter.From = &from
+	}
+	if f.To != nil {
+		to := utils.StartOfDay(*f.To).AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, errors.New("invalid date range")
+	}
+	return filter, nil
+}
@@ -0,0 +1,160 @@
+package donation
+
+import (
+	"testing"
+	"time"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func mustDate(t *testing.T, s string) *time.Time {
+	t.Helper()
+	date, err := utils.ParseDate(s)
+	require.NoError(t, err)
+	return &date
+}
+
+// filterRange matches a repository filter on successful donations within [from, to)
+func filterRange(t *testing.T, from, to string) interface{} {
+	fromTime, toTime := *mustDate(t, from), *mustDate(t, to)
+	return mock.MatchedBy(func(f *donationRepo.Filter) bool {
+		return f.Status != nil && *f.Status == donationDomain.PaymentStatusSuccess &&
+			f.From != nil && f.From.Equal(fromTime) &&
+			f.To != nil && f.To.Equal(toTime)
+	})
+}
+
+// TestGetReport_MonthlyBucketsAndPreviousPeriod tests zero-filled monthly buckets
+// and comparison with the preceding months
+func TestGetReport_MonthlyBucketsAndPreviousPeriod(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	useCase := NewUseCase(mockRepo, nil, nil, nil, nil)
+
+	// The database returns period starts as instants of Jakarta midnight
+	january := *mustDate(t, "2025-01-01")
+	march := *mustDate(t, "2025-03-01")
+	mockRepo.On("GetPeriodAmounts", donationDomain.PeriodMonth, filterRange(t, "2025-01-01", "2025-04-01")).
+		Return([]donationRepo.PeriodAmount{
+			{PeriodStart: january.UTC(), CategoryID: 1, CategoryName: "Infaq", Amount: money.FromRupiah(300000), Transactions: 3},
+			{PeriodStart: january.UTC(), CategoryID: 2, CategoryName: "Zakat", Amount: money.MustParse("100000.50"), Transactions: 1},
+			{PeriodStart: march.UTC(), CategoryID: 1, CategoryName: "Infaq", Amount: money.FromRupiah(100000), Transactions: 1},
+		}, nil)
+	mockRepo.On("GetTotals", filterRange(t, "2024-10-01", "2025-01-01")).
+		Return(&donationRepo.Totals{Amount: money.FromRupiah(400000), Transactions: 4}, nil)
+
+	report, err := useCase.GetReport(&ReportRequest{
+		From: mustDate(t, "2025-01-01"),
+		To:   mustDate(t, "2025-03-31"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, donationDomain.PeriodMonth, report.Period)
+	require.Len(t, report.Buckets, 3)
+
+	assert.Equal(t, "2025-01-01", report.Buckets[0].Start)
+	assert.Equal(t, "2025-01-31", report.Buckets[0].End)
+	assert.Equal(t, money.MustParse("400000.50"), report.Buckets[0].TotalAmount)
+	assert.Equal(t, int64(4), report.Buckets[0].TotalTransactions)
+	assert.Len(t, report.Buckets[0].PerCategory, 2)
+
+	assert.Equal(t, "2025-02-01", report.Buckets[1].Start)
+	assert.Equal(t, "2025-02-28", report.Buckets[1].End)
+	assert.Equal(t, money.Money(0), report.Buckets[1].TotalAmount)
+	assert.Empty(t, report.Buckets[1].PerCategory)
+
+	assert.Equal(t, money.FromRupiah(100000), report.Buckets[2].TotalAmount)
+
+	assert.Equal(t, money.MustParse("500000.50"), report.TotalAmount)
+	assert.Equal(t, int64(5), report.TotalTransactions)
+
+	assert.Equal(t, "2024-10-01", report.Previous.From)
+	assert.Equal(t, "2024-12-31", report.Previous.To)
+	assert.Equal(t, money.MustParse("100000.50"), report.Previous.AmountChange)
+	require.NotNil(t, report.Previous.AmountChangePercent)
+	assert.Equal(t, 25.0, *report.Previous.AmountChangePercent)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestGetReport_WeeklyBucketsAreClipped tests that weeks start on Monday and
+// partial weeks are clipped to the requested range
+func TestGetReport_WeeklyBucketsAreClipped(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	useCase := NewUseCase(mockRepo, nil, nil, nil, nil)
+
+	// 2025-01-01 is a Wednesday, so the first week started on 2024-12-30
+	mockRepo.On("GetPeriodAmounts", donationDomain.PeriodWeek, filterRange(t, "2025-01-01", "2025-01-15")).
+		Return([]donationRepo.PeriodAmount{
+			{PeriodStart: mustDate(t, "2024-12-30").UTC(), CategoryID: 1, CategoryName: "Infaq", Amount: money.FromRupiah(50000), Transactions: 1},
+		}, nil)
+	mockRepo.On("GetTotals", filterRange(t, "2024-12-18", "2025-01-01")).
+		Return(&donationRepo.Totals{}, nil)
+
+	report, err := useCase.GetReport(&ReportRequest{
+		Period: donationDomain.PeriodWeek,
+		From:   mustDate(t, "2025-01-01"),
+		To:     mustDate(t, "2025-01-14"),
+	})
+
+	require.NoError(t, err)
+	require.Len(t, report.Buckets, 3)
+	assert.Equal(t, "2025-01-01", report.Buckets[0].Start)
+	assert.Equal(t, "2025-01-05", report.Buckets[0].End)
+	assert.Equal(t, money.FromRupiah(50000), report.Buckets[0].TotalAmount)
+	assert.Equal(t, "2025-01-06", report.Buckets[1].Start)
+	assert.Equal(t, "2025-01-13", report.Buckets[2].Start)
+	assert.Equal(t, "2025-01-14", report.Buckets[2].End)
+
+	// No previous donations means no meaningful percentage
+	assert.Equal(t, "2024-12-18", report.Previous.From)
+	assert.Equal(t, money.FromRupiah(50000), report.Previous.AmountChange)
+	assert.Nil(t, report.Previous.AmountChangePercent)
+}
+
+// TestGetReport_InvalidRequests tests validation of period and range
+func TestGetReport_InvalidRequests(t *testing.T) {
+	useCase := NewUseCase(new(MockDonationRepository), nil, nil, nil, nil)
+
+	_, err := useCase.GetReport(&ReportRequest{Period: "hour"})
+	assert.EqualError(t, err, "invalid report period")
+
+	_, err = useCase.GetReport(&ReportRequest{From: mustDate(t, "2025-02-01"), To: mustDate(t, "2025-01-01")})
+	assert.EqualError(t, err, "invalid date range")
+
+	_, err = useCase.GetReport(&ReportRequest{
+		Period: donationDomain.PeriodDay,
+		From:   mustDate(t, "2020-01-01"),
+		To:     mustDate(t, "2025-01-01"),
+	})
+	assert.EqualError(t, err, "date range too large")
+}
+
+// TestGetAll_DateFilterIncludesWholeDays tests that the inclusive to date
+// becomes an exclusive bound at the next Jakarta midnight
+func TestGetAll_DateFilterIncludesWholeDays(t *testing.T) {
+	mockRepo := new(MockDonationRepository)
+	useCase := NewUseCase(mockRepo, nil, nil, nil, nil)
+
+	categoryID := uint(2)
+	mockRepo.On("GetAll", 10, 0, mock.MatchedBy(func(f *donationRepo.Filter) bool {
+		return f.Status == nil && f.CategoryID != nil && *f.CategoryID == categoryID &&
+			f.From.Equal(*mustDate(t, "2025-01-01")) && f.To.Equal(*mustDate(t, "2025-02-01"))
+	})).Return([]donationDomain.Donation{}, int64(0), nil)
+
+	_, err := useCase.GetAll(10, 0, &ListFilter{
+		CategoryID: &categoryID,
+		From:       mustDate(t, "2025-01-01"),
+		To:         mustDate(t, "2025-01-31"),
+	})
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	_, err = useCase.GetAll(10, 0, &ListFilter{From: mustDate(t, "2025-02-01"), To: mustDate(t, "2025-01-31")})
+	assert.EqualError(t, err, "invalid date range")
+}
@@ -19,14 +19,16 @@ type UseCase interface {
 
 // CreateRequest represents the request to create a donation category
 type CreateRequest struct {
-	Name        string `json:"name" binding:"required,min=3,max=255"`
-	Description string `json:"description"`
+	Name        string                          `json:"name" binding:"required,min=3,max=255"`
+	Description string                          `json:"description"`
+	FundType    donationCategoryDomain.FundType `json:"fund_type" binding:"omitempty,oneof=infaq zakat_fitrah zakat_mal"` // Defaults to infaq
 }
 
 // UpdateRequest represents the request to update a donation category
 type UpdateRequest struct {
-	Name        string `json:"name" binding:"min=3,max=255"`
-	Description string `json:"description"`
+	Name        string                          `json:"name" binding:"min=3,max=255"`
+	Description string                          `json:"description"`
+	FundType    donationCategoryDomain.FundType `json:"fund_type" binding:"omitempty,oneof=infaq zakat_fitrah zakat_mal"`
 }
 
 type useCase struct {
@@ -52,9 +54,15 @@ func (uc *useCase) Create(req *CreateRequest) (*donationCategoryDomain.DonationC
 		return nil, errors.New("category name already exists")
 	}
 
+	fundType := donationCategoryDomain.FundInfaq
+	if req.FundType != "" {
+		fundType = req.FundType
+	}
+
 	cat := &donationCategoryDomain.DonationCategory{
 		Name:        req.Name,
 		Description: req.Description,
+		FundType:    fundType,
 	}
 
 	if err := uc.repo.Create(cat); err != nil {
@@ -114,6 +122,10 @@ func (uc *useCase) Update(id uint, req *UpdateRequest) (*donationCategoryDomain.
 		cat.Description = req.Description
 	}
 
+	if req.FundType != "" {
+		cat.FundType = req.FundType
+	}
+
 	if err := uc.repo.Update(cat); err != nil {
 		logger.Error().Err(err).Uint("id", id).Msg("Failed to update donation category")
 		return nil, errors.New("failed to update donation category")
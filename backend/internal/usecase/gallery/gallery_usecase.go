@@ -11,6 +11,7 @@ import (
 // UseCase defines the interface for gallery use case
 type UseCase interface {
 	Create(req *CreateRequest) (*galleryDomain.Gallery, error)
+	GetByID(id uint) (*galleryDomain.Gallery, error)
 	GetAll(limit, offset int) (*GetAllResponse, error)
 	Delete(id uint) error
 }
@@ -61,6 +62,16 @@ func (uc *useCase) Create(req *CreateRequest) (*galleryDomain.Gallery, error) {
 	return gal, nil
 }
 
+// GetByID retrieves a gallery item by ID
+func (uc *useCase) GetByID(id uint) (*galleryDomain.Gallery, error) {
+	gal, err := uc.repo.GetByID(id)
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to get gallery item")
+		return nil, err
+	}
+	return gal, nil
+}
+
 // GetAll retrieves all gallery items with pagination
 func (uc *useCase) GetAll(limit, offset int) (*GetAllResponse, error) {
 	// Validate pagination parameters
@@ -0,0 +1,239 @@
+package invitation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	invitationDomain "github.com/madr/backend/internal/domain/invitation"
+	roleDomain "github.com/madr/backend/internal/domain/role"
+	userDomain "github.com/madr/backend/internal/domain/user"
+	invitationRepo "github.com/madr/backend/internal/repository/invitation"
+	"github.com/madr/backend/pkg/jwt"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// maxExpiry bounds how long an invitation can stay open
+const maxExpiry = 30 * 24 * time.Hour
+
+// UseCase defines the interface for registration invitations
+type UseCase interface {
+	Create(req *CreateRequest, createdBy uint) (*CreateResponse, error)
+	GetByID(id uint) (*invitationDomain.Invitation, error)
+	GetAll(limit, offset int) (*GetAllResponse, error)
+	Revoke(id uint) error
+	Accept(token string, usr *userDomain.User) error
+}
+
+// RoleGetter retrieves the role an invitation grants and checks that the
+// inviter may grant it
+type RoleGetter interface {
+	GetByID(id uint) (*roleDomain.Role, error)
+	EnsureCanAssign(actorID uint, roles []roleDomain.Role) error
+}
+
+// CreateRequest represents the request to invite someone
+type CreateRequest struct {
+	Email          string `json:"email" binding:"omitempty,email"` // Restricts the invitation to this email
+	RoleID         uint   `json:"role_id" binding:"required"`
+	ExpiresInHours int    `json:"expires_in_hours" binding:"omitempty,min=1"`
+}
+
+// CreateResponse carries the signed invite token, shown only once
+type CreateResponse struct {
+	*invitationDomain.Invitation
+	Token string `json:"token"`
+}
+
+// GetAllResponse represents the response for listing invitations
+type GetAllResponse struct {
+	Data       []invitationDomain.Invitation `json:"data"`
+	Total      int64                         `json:"total"`
+	Limit      int                           `json:"limit"`
+	Offset     int                           `json:"offset"`
+	TotalPages int                           `json:"total_pages"`
+}
+
+type useCase struct {
+	repo          invitationRepo.Repository
+	roles         RoleGetter
+	defaultExpiry time.Duration
+	now           func() time.Time
+}
+
+// NewUseCase creates a new invitation use case
+func NewUseCase(repo invitationRepo.Repository, roles RoleGetter, defaultExpiry time.Duration) UseCase {
+	return &useCase{
+		repo:          repo,
+		roles:         roles,
+		defaultExpiry: defaultExpiry,
+		now:           time.Now,
+	}
+}
+
+// Create issues a signed invite token granting a role. The inviter has to
+// hold every permission of the role, and only administrators can invite
+// administrators.
+func (uc *useCase) Create(req *CreateRequest, createdBy uint) (*CreateResponse, error) {
+	role, err := uc.roles.GetByID(req.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.roles.EnsureCanAssign(createdBy, []roleDomain.Role{*role}); err != nil {
+		return nil, err
+	}
+
+	expiry := uc.defaultExpiry
+	if req.ExpiresInHours > 0 {
+		expiry = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	if expiry > maxExpiry {
+		return nil, errors.New("invitation expiry is too long")
+	}
+	expiresAt := uc.now().Add(expiry)
+
+	token, err := jwt.GenerateInviteToken(req.RoleID, expiresAt)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to sign invite token")
+		return nil, errors.New("failed to create invitation")
+	}
+
+	inv := &invitationDomain.Invitation{
+		TokenHash: hashToken(token),
+		Email:     strings.TrimSpace(req.Email),
+		RoleID:    req.RoleID,
+		ExpiresAt: expiresAt,
+	}
+	if createdBy != 0 {
+		inv.CreatedBy = &createdBy
+	}
+	if err := uc.repo.Create(inv); err != nil {
+		logger.Error().Err(err).Msg("Failed to create invitation")
+		return nil, errors.New("failed to create invitation")
+	}
+
+	logger.Info().Uint("id", inv.ID).Uint("role_id", inv.RoleID).Msg("Invitation created")
+
+	created, err := uc.GetByID(inv.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &CreateResponse{Invitation: created, Token: token}, nil
+}
+
+// GetByID retrieves an invitation with its current status
+func (uc *useCase) GetByID(id uint) (*invitationDomain.Invitation, error) {
+	inv, err := uc.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	inv.Status = inv.StatusAt(uc.now())
+	return inv, nil
+}
+
+// GetAll lists invitations, newest first
+func (uc *useCase) GetAll(limit, offset int) (*GetAllResponse, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	invitations, total, err := uc.repo.GetAll(limit, offset)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get invitations")
+		return nil, errors.New("failed to get invitations")
+	}
+
+	now := uc.now()
+	for i := range invitations {
+		invitations[i].Status = invitations[i].StatusAt(now)
+	}
+
+	return &GetAllResponse{
+		Data:       invitations,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, nil
+}
+
+// Revoke cancels a pending invitation
+func (uc *useCase) Revoke(id uint) error {
+	if _, err := uc.repo.GetByID(id); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Revoke(id, uc.now()); err != nil {
+		if errors.Is(err, invitationRepo.ErrUnavailable) {
+			return errors.New("invitation is no longer pending")
+		}
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to revoke invitation")
+		return errors.New("failed to revoke invitation")
+	}
+
+	logger.Info().Uint("id", id).Msg("Invitation revoked")
+	return nil
+}
+
+// Accept creates usr with the role granted by the invite token and uses the
+// invitation up. The role always comes from the stored invitation.
+func (uc *useCase) Accept(token string, usr *userDomain.User) error {
+	claims, err := jwt.ValidateInviteToken(token)
+	if err != nil {
+		if errors.Is(err, jwt.ErrExpiredToken) {
+			return errors.New("invitation has expired")
+		}
+		return errors.New("invalid invitation")
+	}
+
+	inv, err := uc.repo.GetByTokenHash(hashToken(token))
+	if err != nil {
+		if err.Error() == "invitation not found" {
+			return errors.New("invalid invitation")
+		}
+		logger.Error().Err(err).Msg("Failed to get invitation")
+		return errors.New("failed to accept invitation")
+	}
+	if inv.RoleID != claims.RoleID {
+		logger.Warn().Uint("id", inv.ID).Msg("Invite token role does not match the invitation")
+		return errors.New("invalid invitation")
+	}
+
+	now := uc.now()
+	switch inv.StatusAt(now) {
+	case invitationDomain.StatusUsed:
+		return errors.New("invitation has already been used")
+	case invitationDomain.StatusRevoked:
+		return errors.New("invitation has been revoked")
+	case invitationDomain.StatusExpired:
+		return errors.New("invitation has expired")
+	}
+	if inv.Email != "" && !strings.EqualFold(inv.Email, usr.Email) {
+		return errors.New("email does not match the invitation")
+	}
+
+	if err := uc.repo.Accept(inv.ID, usr, now); err != nil {
+		if errors.Is(err, invitationRepo.ErrUnavailable) {
+			return errors.New("invitation has already been used")
+		}
+		logger.Error().Err(err).Uint("id", inv.ID).Msg("Failed to accept invitation")
+		return errors.New("failed to create user")
+	}
+
+	logger.Info().Uint("id", inv.ID).Uint("user_id", usr.ID).Uint("role_id", inv.RoleID).Msg("Invitation accepted")
+	return nil
+}
+
+// hashToken returns the hex SHA-256 digest stored instead of the token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
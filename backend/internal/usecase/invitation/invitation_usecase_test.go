@@ -0,0 +1,216 @@
+package invitation
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/madr/backend/internal/config"
+	invitationDomain "github.com/madr/backend/internal/domain/invitation"
+	roleDomain "github.com/madr/backend/internal/domain/role"
+	userDomain "github.com/madr/backend/internal/domain/user"
+	invitationRepo "github.com/madr/backend/internal/repository/invitation"
+	"github.com/madr/backend/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	config.AppConfig = &config.Config{JWT: config.JWTConfig{Secret: "test-secret", AccessExpiry: time.Minute}}
+	os.Exit(m.Run())
+}
+
+// fakeRepository keeps invitations in memory and records accepted users
+type fakeRepository struct {
+	invitationRepo.Repository
+	invitations map[uint]*invitationDomain.Invitation
+	users       []*userDomain.User
+	userRoles   map[uint]uint
+}
+
+func (r *fakeRepository) Create(inv *invitationDomain.Invitation) error {
+	inv.ID = uint(len(r.invitations) + 1)
+	clone := *inv
+	r.invitations[inv.ID] = &clone
+	return nil
+}
+
+func (r *fakeRepository) GetByID(id uint) (*invitationDomain.Invitation, error) {
+	inv, ok := r.invitations[id]
+	if !ok {
+		return nil, errors.New("invitation not found")
+	}
+	clone := *inv
+	return &clone, nil
+}
+
+func (r *fakeRepository) GetByTokenHash(tokenHash string) (*invitationDomain.Invitation, error) {
+	for _, inv := range r.invitations {
+		if inv.TokenHash == tokenHash {
+			clone := *inv
+			return &clone, nil
+		}
+	}
+	return nil, errors.New("invitation not found")
+}
+
+func (r *fakeRepository) Revoke(id uint, at time.Time) error {
+	inv := r.invitations[id]
+	if inv.UsedAt != nil || inv.RevokedAt != nil {
+		return invitationRepo.ErrUnavailable
+	}
+	inv.RevokedAt = &at
+	return nil
+}
+
+func (r *fakeRepository) Accept(id uint, usr *userDomain.User, at time.Time) error {
+	inv := r.invitations[id]
+	if inv.UsedAt != nil {
+		return invitationRepo.ErrUnavailable
+	}
+	usr.ID = uint(len(r.users) + 10)
+	r.users = append(r.users, usr)
+	r.userRoles[usr.ID] = inv.RoleID
+	inv.UsedAt, inv.UsedBy = &at, &usr.ID
+	return nil
+}
+
+// fakeRoles knows roles 1 (admin) and 2 (treasurer)
+type fakeRoles struct{}
+
+func (fakeRoles) GetByID(id uint) (*roleDomain.Role, error) {
+	if id != 1 && id != 2 {
+		return nil, errors.New("role not found")
+	}
+	return &roleDomain.Role{ID: id}, nil
+}
+
+// EnsureCanAssign lets user 1 (an administrator) assign every role and user 2
+// (a treasurer) only role 2
+func (fakeRoles) EnsureCanAssign(actorID uint, roles []roleDomain.Role) error {
+	for _, role := range roles {
+		switch {
+		case actorID == 1:
+		case role.ID == 1:
+			return errors.New("only administrators can assign the admin role")
+		case actorID != 2:
+			return errors.New("cannot grant permissions you do not have")
+		}
+	}
+	return nil
+}
+
+func newTestUseCase(now *time.Time) (*useCase, *fakeRepository) {
+	repo := &fakeRepository{
+		invitations: map[uint]*invitationDomain.Invitation{},
+		userRoles:   map[uint]uint{},
+	}
+	uc := NewUseCase(repo, fakeRoles{}, 72*time.Hour).(*useCase)
+	uc.now = func() time.Time { return *now }
+	return uc, repo
+}
+
+// TestAccept tests that an invitation grants its role exactly once
+func TestAccept(t *testing.T) {
+	now := time.Now()
+	uc, repo := newTestUseCase(&now)
+
+	created, err := uc.Create(&CreateRequest{RoleID: 2}, 1)
+	require.NoError(t, err)
+	require.NotEmpty(t, created.Token)
+	assert.Equal(t, invitationDomain.StatusPending, created.Status)
+	assert.Equal(t, now.Add(72*time.Hour), created.ExpiresAt)
+	assert.NotContains(t, created.TokenHash, created.Token)
+
+	usr := &userDomain.User{Username: "bendahara", Email: "bendahara@example.com"}
+	require.NoError(t, uc.Accept(created.Token, usr))
+	assert.Equal(t, uint(2), repo.userRoles[usr.ID])
+
+	again := &userDomain.User{Username: "lain", Email: "lain@example.com"}
+	assert.EqualError(t, uc.Accept(created.Token, again), "invitation has already been used")
+	assert.Len(t, repo.users, 1)
+}
+
+// TestAccept_RejectsForgedTokens tests tokens that were not issued by an admin
+func TestAccept_RejectsForgedTokens(t *testing.T) {
+	now := time.Now()
+	uc, repo := newTestUseCase(&now)
+	usr := &userDomain.User{Email: "eve@example.com"}
+
+	// Validly signed but never stored, e.g. crafted to carry the admin role
+	forged, err := jwt.GenerateInviteToken(1, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.EqualError(t, uc.Accept(forged, usr), "invalid invitation")
+
+	// Signed with another secret
+	config.AppConfig.JWT.Secret = "attacker-secret"
+	foreign, err := jwt.GenerateInviteToken(1, now.Add(time.Hour))
+	config.AppConfig.JWT.Secret = "test-secret"
+	require.NoError(t, err)
+	assert.EqualError(t, uc.Accept(foreign, usr), "invalid invitation")
+
+	// An access token is not an invitation
+	access, err := jwt.GenerateAccessToken(1, "admin", "admin", []uint{1}, "")
+	require.NoError(t, err)
+	assert.EqualError(t, uc.Accept(access, usr), "invalid invitation")
+
+	assert.EqualError(t, uc.Accept("not-a-token", usr), "invalid invitation")
+	assert.Empty(t, repo.users)
+}
+
+// TestAccept_Unavailable tests expired, revoked and email-restricted invitations
+func TestAccept_Unavailable(t *testing.T) {
+	now := time.Now()
+	uc, repo := newTestUseCase(&now)
+
+	restricted, err := uc.Create(&CreateRequest{RoleID: 2, Email: "bendahara@example.com"}, 1)
+	require.NoError(t, err)
+	assert.EqualError(t, uc.Accept(restricted.Token, &userDomain.User{Email: "eve@example.com"}), "email does not match the invitation")
+	require.NoError(t, uc.Accept(restricted.Token, &userDomain.User{Email: "Bendahara@Example.com"}))
+
+	revoked, err := uc.Create(&CreateRequest{RoleID: 2}, 1)
+	require.NoError(t, err)
+	require.NoError(t, uc.Revoke(revoked.ID))
+	assert.EqualError(t, uc.Revoke(revoked.ID), "invitation is no longer pending")
+	assert.EqualError(t, uc.Accept(revoked.Token, &userDomain.User{}), "invitation has been revoked")
+
+	expiring, err := uc.Create(&CreateRequest{RoleID: 2, ExpiresInHours: 1}, 1)
+	require.NoError(t, err)
+	now = now.Add(2 * time.Hour)
+	inv, err := uc.GetByID(expiring.ID)
+	require.NoError(t, err)
+	assert.Equal(t, invitationDomain.StatusExpired, inv.Status)
+	assert.Error(t, uc.Accept(expiring.Token, &userDomain.User{}))
+	assert.Len(t, repo.users, 1)
+}
+
+// TestCreate_Validation tests unknown roles and overlong expiries
+func TestCreate_Validation(t *testing.T) {
+	now := time.Now()
+	uc, _ := newTestUseCase(&now)
+
+	_, err := uc.Create(&CreateRequest{RoleID: 9}, 1)
+	assert.EqualError(t, err, "role not found")
+
+	_, err = uc.Create(&CreateRequest{RoleID: 2, ExpiresInHours: 24 * 31}, 1)
+	assert.EqualError(t, err, "invitation expiry is too long")
+}
+
+// TestCreate_Escalation tests that inviters can only invite to roles they could grant themselves
+func TestCreate_Escalation(t *testing.T) {
+	now := time.Now()
+	uc, repo := newTestUseCase(&now)
+
+	_, err := uc.Create(&CreateRequest{RoleID: 1}, 2)
+	assert.EqualError(t, err, "only administrators can assign the admin role")
+
+	_, err = uc.Create(&CreateRequest{RoleID: 2}, 3)
+	assert.EqualError(t, err, "cannot grant permissions you do not have")
+	assert.Empty(t, repo.invitations)
+
+	_, err = uc.Create(&CreateRequest{RoleID: 2}, 2)
+	require.NoError(t, err)
+	_, err = uc.Create(&CreateRequest{RoleID: 1}, 1)
+	require.NoError(t, err)
+}
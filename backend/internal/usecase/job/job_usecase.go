@@ -0,0 +1,106 @@
+package job
+
+import (
+	"errors"
+	"time"
+
+	jobRunDomain "github.com/madr/backend/internal/domain/jobrun"
+	jobRunRepo "github.com/madr/backend/internal/repository/jobrun"
+	"github.com/madr/backend/internal/scheduler"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// UseCase defines the interface for viewing background jobs
+type UseCase interface {
+	GetAll() ([]Job, error)
+	GetRuns(limit, offset int, filter *jobRunRepo.Filter) (*GetRunsResponse, error)
+}
+
+// Job describes a registered background job
+type Job struct {
+	Name      string               `json:"name"`
+	Schedule  string               `json:"schedule"`
+	Enabled   bool                 `json:"enabled"` // False when the schedule is invalid
+	NextRunAt *time.Time           `json:"next_run_at"`
+	LastRun   *jobRunDomain.JobRun `json:"last_run"`
+}
+
+// GetRunsResponse represents the response for getting job runs
+type GetRunsResponse struct {
+	Data       []jobRunDomain.JobRun `json:"data"`
+	Total      int64                 `json:"total"`
+	Limit      int                   `json:"limit"`
+	Offset     int                   `json:"offset"`
+	TotalPages int                   `json:"total_pages"`
+}
+
+type useCase struct {
+	repo jobRunRepo.Repository
+	jobs []scheduler.Job
+	now  func() time.Time
+}
+
+// NewUseCase creates a new background job use case for the given jobs
+func NewUseCase(repo jobRunRepo.Repository, jobs []scheduler.Job) UseCase {
+	return &useCase{
+		repo: repo,
+		jobs: jobs,
+		now:  time.Now,
+	}
+}
+
+// GetAll lists the registered jobs with their next and latest run
+func (uc *useCase) GetAll() ([]Job, error) {
+	now := uc.now()
+	jobs := make([]Job, 0, len(uc.jobs))
+	for _, j := range uc.jobs {
+		job := Job{Name: j.Name, Schedule: j.Spec()}
+		if schedule, err := scheduler.ParseSchedule(job.Schedule); err == nil {
+			job.Enabled = true
+			if next := schedule.Next(now); !next.IsZero() {
+				job.NextRunAt = &next
+			}
+		}
+
+		runs, _, err := uc.repo.GetAll(1, 0, &jobRunRepo.Filter{JobName: j.Name})
+		if err != nil {
+			logger.Error().Err(err).Str("job", j.Name).Msg("Failed to get job runs")
+			return nil, errors.New("failed to get jobs")
+		}
+		if len(runs) > 0 {
+			job.LastRun = &runs[0]
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// GetRuns retrieves the job run history with pagination and optional filter
+func (uc *useCase) GetRuns(limit, offset int, filter *jobRunRepo.Filter) (*GetRunsResponse, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	runs, total, err := uc.repo.GetAll(limit, offset, filter)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get job runs")
+		return nil, errors.New("failed to get job runs")
+	}
+	if runs == nil {
+		runs = []jobRunDomain.JobRun{}
+	}
+
+	return &GetRunsResponse{
+		Data:       runs,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, nil
+}
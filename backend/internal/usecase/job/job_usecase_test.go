@@ -0,0 +1,94 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	jobRunDomain "github.com/madr/backend/internal/domain/jobrun"
+	jobRunRepo "github.com/madr/backend/internal/repository/jobrun"
+	"github.com/madr/backend/internal/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepository returns job runs, latest first
+type fakeRepository struct {
+	jobRunRepo.Repository
+	runs []jobRunDomain.JobRun
+}
+
+func (r *fakeRepository) GetAll(limit, offset int, filter *jobRunRepo.Filter) ([]jobRunDomain.JobRun, int64, error) {
+	var runs []jobRunDomain.JobRun
+	for _, run := range r.runs {
+		if filter != nil && filter.JobName != "" && run.JobName != filter.JobName {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	total := int64(len(runs))
+	if offset > len(runs) {
+		offset = len(runs)
+	}
+	runs = runs[offset:]
+	if limit < len(runs) {
+		runs = runs[:limit]
+	}
+	return runs, total, nil
+}
+
+// TestGetAll tests the registered jobs with their next and latest run
+func TestGetAll(t *testing.T) {
+	now := time.Date(2024, 3, 1, 3, 7, 0, 0, time.UTC)
+	repo := &fakeRepository{runs: []jobRunDomain.JobRun{
+		{ID: 3, JobName: "campaign-close", Status: jobRunDomain.StatusFailed, StartedAt: now.Add(-7 * time.Minute)},
+		{ID: 2, JobName: "refresh-token-cleanup", Status: jobRunDomain.StatusSucceeded, StartedAt: now.Add(-time.Hour)},
+		{ID: 1, JobName: "campaign-close", Status: jobRunDomain.StatusSucceeded, StartedAt: now.Add(-22 * time.Minute)},
+	}}
+	uc := NewUseCase(repo, []scheduler.Job{
+		{Name: "refresh-token-cleanup", Schedule: "0 3 * * *"},
+		{Name: "campaign-close", Interval: 15 * time.Minute},
+		{Name: "broken", Schedule: "every day"},
+	}).(*useCase)
+	uc.now = func() time.Time { return now }
+
+	jobs, err := uc.GetAll()
+	require.NoError(t, err)
+	require.Len(t, jobs, 3)
+
+	assert.Equal(t, "0 3 * * *", jobs[0].Schedule)
+	assert.True(t, jobs[0].Enabled)
+	require.NotNil(t, jobs[0].NextRunAt)
+	assert.True(t, time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC).Equal(*jobs[0].NextRunAt))
+	require.NotNil(t, jobs[0].LastRun)
+	assert.Equal(t, uint(2), jobs[0].LastRun.ID)
+
+	assert.Equal(t, "@every 15m0s", jobs[1].Schedule)
+	assert.True(t, time.Date(2024, 3, 1, 3, 15, 0, 0, time.UTC).Equal(*jobs[1].NextRunAt))
+	assert.Equal(t, jobRunDomain.StatusFailed, jobs[1].LastRun.Status)
+
+	assert.False(t, jobs[2].Enabled)
+	assert.Nil(t, jobs[2].NextRunAt)
+	assert.Nil(t, jobs[2].LastRun)
+}
+
+// TestGetRuns tests the pagination of the job run history
+func TestGetRuns(t *testing.T) {
+	repo := &fakeRepository{}
+	uc := NewUseCase(repo, nil)
+
+	response, err := uc.GetRuns(0, -1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 20, response.Limit)
+	assert.Equal(t, 0, response.Offset)
+	assert.NotNil(t, response.Data)
+	assert.Equal(t, 0, response.TotalPages)
+
+	for i := 0; i < 45; i++ {
+		repo.runs = append(repo.runs, jobRunDomain.JobRun{ID: uint(45 - i), JobName: "campaign-close"})
+	}
+	response, err = uc.GetRuns(500, 0, &jobRunRepo.Filter{JobName: "campaign-close"})
+	require.NoError(t, err)
+	assert.Equal(t, 100, response.Limit)
+	assert.Len(t, response.Data, 45)
+	assert.Equal(t, 1, response.TotalPages)
+}
@@ -0,0 +1,645 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	ledgerDomain "github.com/madr/backend/internal/domain/ledger"
+	ledgerRepo "github.com/madr/backend/internal/repository/ledger"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/money"
+)
+
+// UseCase defines the interface for cash ledger use case
+type UseCase interface {
+	CreateAccount(req *CreateAccountRequest) (*ledgerDomain.Account, error)
+	GetAccounts() ([]AccountBalance, error)
+	UpdateAccount(id uint, req *UpdateAccountRequest) (*ledgerDomain.Account, error)
+	DeleteAccount(id uint) error
+
+	CreateCategory(req *CreateCategoryRequest) (*ledgerDomain.Category, error)
+	GetCategories(entryType *ledgerDomain.EntryType) ([]ledgerDomain.Category, error)
+	UpdateCategory(id uint, req *UpdateCategoryRequest) (*ledgerDomain.Category, error)
+	DeleteCategory(id uint) error
+
+	CreateEntry(req *CreateEntryRequest) (*ledgerDomain.Entry, error)
+	GetEntry(id uint) (*ledgerDomain.Entry, error)
+	GetEntries(limit, offset int, filter *ledgerRepo.EntryFilter) (*GetEntriesResponse, error)
+	UpdateEntry(id uint, req *UpdateEntryRequest) (*ledgerDomain.Entry, error)
+	DeleteEntry(id uint) error
+
+	GetBalance(accountID *uint, date *time.Time) (*BalanceResponse, error)
+	GetWeeklyReport(date *time.Time, accountID *uint) (*WeeklyReport, error)
+
+	PostDonation(don *donationDomain.Donation) error
+	RemoveDonation(donationID uint) error
+}
+
+// CreateAccountRequest represents the request to create a ledger account
+type CreateAccountRequest struct {
+	Name        string `json:"name" binding:"required,min=2,max=100"`
+	Description string `json:"description"`
+	IsDefault   bool   `json:"is_default"`
+}
+
+// UpdateAccountRequest represents the request to update a ledger account
+type UpdateAccountRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=2,max=100"`
+	Description *string `json:"description"`
+	IsDefault   *bool   `json:"is_default"`
+}
+
+// AccountBalance represents a ledger account with its current balance
+type AccountBalance struct {
+	ledgerDomain.Account
+	Balance money.Money `json:"balance"`
+}
+
+// CreateCategoryRequest represents the request to create a ledger category
+type CreateCategoryRequest struct {
+	Name        string                 `json:"name" binding:"required,min=2,max=100"`
+	Type        ledgerDomain.EntryType `json:"type" binding:"required,oneof=income expense"`
+	Description string                 `json:"description"`
+}
+
+// UpdateCategoryRequest represents the request to update a ledger category.
+// The type of a category cannot change once entries may use it.
+type UpdateCategoryRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=2,max=100"`
+	Description *string `json:"description"`
+}
+
+// CreateEntryRequest represents the request to record income or expense.
+// The entry type follows the category; the default account is used when
+// no account is given.
+type CreateEntryRequest struct {
+	AccountID     *uint       `json:"account_id"`
+	CategoryID    uint        `json:"category_id" binding:"required"`
+	Amount        money.Money `json:"amount" binding:"required,gt=0"`
+	Description   string      `json:"description" binding:"max=1000"`
+	EntryDate     string      `json:"entry_date" binding:"required"`
+	AttachmentURL *string     `json:"attachment_url" binding:"omitempty,url,max=500"`
+}
+
+// UpdateEntryRequest represents the request to update a ledger entry
+type UpdateEntryRequest struct {
+	AccountID     *uint        `json:"account_id"`
+	CategoryID    *uint        `json:"category_id"`
+	Amount        *money.Money `json:"amount" binding:"omitempty,gt=0"`
+	Description   *string      `json:"description" binding:"omitempty,max=1000"`
+	EntryDate     *string      `json:"entry_date"`
+	AttachmentURL *string      `json:"attachment_url" binding:"omitempty,max=500"`
+}
+
+// GetEntriesResponse represents the response for getting ledger entries
+type GetEntriesResponse struct {
+	Data       []ledgerDomain.Entry `json:"data"`
+	Total      int64                `json:"total"`
+	Limit      int                  `json:"limit"`
+	Offset     int                  `json:"offset"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+// BalanceResponse represents the balance at the end of a day
+type BalanceResponse struct {
+	AccountID *uint       `json:"account_id,omitempty"`
+	Date      string      `json:"date"`
+	Balance   money.Money `json:"balance"`
+}
+
+// WeeklyReport is the cash report read out after Friday prayer. The week runs
+// from Saturday up to and including the report Friday.
+type WeeklyReport struct {
+	Friday         string                     `json:"friday"`
+	From           string                     `json:"from"`
+	To             string                     `json:"to"`
+	OpeningBalance money.Money                `json:"opening_balance"`
+	TotalIncome    money.Money                `json:"total_income"`
+	TotalExpense   money.Money                `json:"total_expense"`
+	ClosingBalance money.Money                `json:"closing_balance"`
+	Income         []ledgerRepo.CategoryTotal `json:"income"`
+	Expenses       []ledgerRepo.CategoryTotal `json:"expenses"`
+}
+
+type useCase struct {
+	repo ledgerRepo.Repository
+}
+
+// NewUseCase creates a new cash ledger use case
+func NewUseCase(repo ledgerRepo.Repository) UseCase {
+	return &useCase{
+		repo: repo,
+	}
+}
+
+// CreateAccount creates a new ledger account
+func (uc *useCase) CreateAccount(req *CreateAccountRequest) (*ledgerDomain.Account, error) {
+	exists, err := uc.repo.AccountNameExists(req.Name, 0)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to check ledger account name")
+		return nil, errors.New("failed to create ledger account")
+	}
+	if exists {
+		return nil, errors.New("account name already exists")
+	}
+
+	acc := &ledgerDomain.Account{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := uc.repo.CreateAccount(acc); err != nil {
+		logger.Error().Err(err).Msg("Failed to create ledger account")
+		return nil, errors.New("failed to create ledger account")
+	}
+
+	// Switching the default account happens atomically in UpdateAccount
+	if req.IsDefault {
+		acc.IsDefault = true
+		if err := uc.repo.UpdateAccount(acc); err != nil {
+			logger.Error().Err(err).Uint("id", acc.ID).Msg("Failed to make ledger account default")
+			return nil, errors.New("failed to create ledger account")
+		}
+	}
+
+	logger.Info().Uint("id", acc.ID).Str("name", acc.Name).Msg("Ledger account created successfully")
+	return acc, nil
+}
+
+// GetAccounts retrieves all ledger accounts with their current balance
+func (uc *useCase) GetAccounts() ([]AccountBalance, error) {
+	accounts, err := uc.repo.GetAccounts()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get ledger accounts")
+		return nil, errors.New("failed to get ledger accounts")
+	}
+
+	tomorrow := utils.StartOfDay(time.Now()).AddDate(0, 0, 1)
+	result := make([]AccountBalance, len(accounts))
+	for i := range accounts {
+		balance, err := uc.repo.GetBalance(&accounts[i].ID, tomorrow)
+		if err != nil {
+			logger.Error().Err(err).Uint("id", accounts[i].ID).Msg("Failed to get ledger account balance")
+			return nil, errors.New("failed to get ledger accounts")
+		}
+		result[i] = AccountBalance{Account: accounts[i], Balance: balance}
+	}
+	return result, nil
+}
+
+// UpdateAccount updates a ledger account
+func (uc *useCase) UpdateAccount(id uint, req *UpdateAccountRequest) (*ledgerDomain.Account, error) {
+	acc, err := uc.repo.GetAccountByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil && *req.Name != acc.Name {
+		exists, err := uc.repo.AccountNameExists(*req.Name, id)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to check ledger account name")
+			return nil, errors.New("failed to update ledger account")
+		}
+		if exists {
+			return nil, errors.New("account name already exists")
+		}
+		acc.Name = *req.Name
+	}
+	if req.Description != nil {
+		acc.Description = *req.Description
+	}
+	if req.IsDefault != nil {
+		// Another account must become the default instead
+		if acc.IsDefault && !*req.IsDefault {
+			return nil, errors.New("default account required")
+		}
+		acc.IsDefault = *req.IsDefault
+	}
+
+	if err := uc.repo.UpdateAccount(acc); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to update ledger account")
+		return nil, errors.New("failed to update ledger account")
+	}
+
+	logger.Info().Uint("id", acc.ID).Msg("Ledger account updated successfully")
+	return acc, nil
+}
+
+// DeleteAccount deletes an unused, non-default ledger account
+func (uc *useCase) DeleteAccount(id uint) error {
+	acc, err := uc.repo.GetAccountByID(id)
+	if err != nil {
+		return err
+	}
+	if acc.IsDefault {
+		return errors.New("default account required")
+	}
+
+	count, err := uc.repo.CountEntries(&ledgerRepo.EntryFilter{AccountID: &id})
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to count ledger account entries")
+		return errors.New("failed to delete ledger account")
+	}
+	if count > 0 {
+		return errors.New("account has entries")
+	}
+
+	if err := uc.repo.DeleteAccount(id); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to delete ledger account")
+		return errors.New("failed to delete ledger account")
+	}
+
+	logger.Info().Uint("id", id).Msg("Ledger account deleted successfully")
+	return nil
+}
+
+// CreateCategory creates a new ledger category
+func (uc *useCase) CreateCategory(req *CreateCategoryRequest) (*ledgerDomain.Category, error) {
+	exists, err := uc.repo.CategoryNameExists(req.Name, req.Type, 0)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to check ledger category name")
+		return nil, errors.New("failed to create ledger category")
+	}
+	if exists {
+		return nil, errors.New("category name already exists")
+	}
+
+	cat := &ledgerDomain.Category{
+		Name:        req.Name,
+		Type:        req.Type,
+		Description: req.Description,
+	}
+	if err := uc.repo.CreateCategory(cat); err != nil {
+		logger.Error().Err(err).Msg("Failed to create ledger category")
+		return nil, errors.New("failed to create ledger category")
+	}
+
+	logger.Info().Uint("id", cat.ID).Str("name", cat.Name).Msg("Ledger category created successfully")
+	return cat, nil
+}
+
+// GetCategories retrieves ledger categories, optionally of one type
+func (uc *useCase) GetCategories(entryType *ledgerDomain.EntryType) ([]ledgerDomain.Category, error) {
+	categories, err := uc.repo.GetCategories(entryType)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get ledger categories")
+		return nil, errors.New("failed to get ledger categories")
+	}
+	return categories, nil
+}
+
+// UpdateCategory updates a ledger category
+func (uc *useCase) UpdateCategory(id uint, req *UpdateCategoryRequest) (*ledgerDomain.Category, error) {
+	cat, err := uc.repo.GetCategoryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if cat.System {
+		return nil, errors.New("system category cannot be changed")
+	}
+
+	if req.Name != nil && *req.Name != cat.Name {
+		exists, err := uc.repo.CategoryNameExists(*req.Name, cat.Type, id)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to check ledger category name")
+			return nil, errors.New("failed to update ledger category")
+		}
+		if exists {
+			return nil, errors.New("category name already exists")
+		}
+		cat.Name = *req.Name
+	}
+	if req.Description != nil {
+		cat.Description = *req.Description
+	}
+
+	if err := uc.repo.UpdateCategory(cat); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to update ledger category")
+		return nil, errors.New("failed to update ledger category")
+	}
+	return cat, nil
+}
+
+// DeleteCategory deletes an unused ledger category
+func (uc *useCase) DeleteCategory(id uint) error {
+	cat, err := uc.repo.GetCategoryByID(id)
+	if err != nil {
+		return err
+	}
+	if cat.System {
+		return errors.New("system category cannot be changed")
+	}
+
+	count, err := uc.repo.CountEntries(&ledgerRepo.EntryFilter{CategoryID: &id})
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to count ledger category entries")
+		return errors.New("failed to delete ledger category")
+	}
+	if count > 0 {
+		return errors.New("category is in use")
+	}
+
+	if err := uc.repo.DeleteCategory(id); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to delete ledger category")
+		return errors.New("failed to delete ledger category")
+	}
+	return nil
+}
+
+// CreateEntry records income or expense
+func (uc *useCase) CreateEntry(req *CreateEntryRequest) (*ledgerDomain.Entry, error) {
+	entryDate, err := utils.ParseDate(req.EntryDate)
+	if err != nil {
+		return nil, errors.New("invalid entry date")
+	}
+
+	acc, err := uc.resolveAccount(req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	cat, err := uc.manualCategory(req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &ledgerDomain.Entry{
+		AccountID:     acc.ID,
+		CategoryID:    cat.ID,
+		Type:          cat.Type,
+		Amount:        req.Amount,
+		Description:   req.Description,
+		EntryDate:     entryDate,
+		AttachmentURL: req.AttachmentURL,
+	}
+	if err := uc.repo.CreateEntry(entry); err != nil {
+		logger.Error().Err(err).Msg("Failed to create ledger entry")
+		return nil, errors.New("failed to create ledger entry")
+	}
+
+	logger.Info().
+		Uint("id", entry.ID).
+		Str("type", string(entry.Type)).
+		Str("amount", entry.Amount.String()).
+		Msg("Ledger entry created successfully")
+
+	return uc.GetEntry(entry.ID)
+}
+
+// GetEntry retrieves a ledger entry by ID
+func (uc *useCase) GetEntry(id uint) (*ledgerDomain.Entry, error) {
+	entry, err := uc.repo.GetEntryByID(id)
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to get ledger entry")
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetEntries retrieves ledger entries with pagination and running balance
+func (uc *useCase) GetEntries(limit, offset int, filter *ledgerRepo.EntryFilter) (*GetEntriesResponse, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if filter != nil && filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, errors.New("invalid date range")
+	}
+
+	entries, total, err := uc.repo.GetEntries(limit, offset, filter)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get ledger entries")
+		return nil, errors.New("failed to get ledger entries")
+	}
+
+	return &GetEntriesResponse{
+		Data:       entries,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, nil
+}
+
+// UpdateEntry updates a manually recorded ledger entry
+func (uc *useCase) UpdateEntry(id uint, req *UpdateEntryRequest) (*ledgerDomain.Entry, error) {
+	entry, err := uc.repo.GetEntryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.DonationID != nil {
+		return nil, errors.New("entry is posted from a donation")
+	}
+
+	if req.AccountID != nil {
+		acc, err := uc.resolveAccount(req.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		entry.AccountID = acc.ID
+	}
+	if req.CategoryID != nil {
+		cat, err := uc.manualCategory(*req.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		entry.CategoryID = cat.ID
+		entry.Type = cat.Type
+	}
+	if req.Amount != nil {
+		entry.Amount = *req.Amount
+	}
+	if req.Description != nil {
+		entry.Description = *req.Description
+	}
+	if req.EntryDate != nil {
+		entryDate, err := utils.ParseDate(*req.EntryDate)
+		if err != nil {
+			return nil, errors.New("invalid entry date")
+		}
+		entry.EntryDate = entryDate
+	}
+	if req.AttachmentURL != nil {
+		if *req.AttachmentURL == "" {
+			entry.AttachmentURL = nil
+		} else {
+			entry.AttachmentURL = req.AttachmentURL
+		}
+	}
+
+	if err := uc.repo.UpdateEntry(entry); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to update ledger entry")
+		return nil, errors.New("failed to update ledger entry")
+	}
+
+	logger.Info().Uint("id", id).Msg("Ledger entry updated successfully")
+	return uc.GetEntry(id)
+}
+
+// DeleteEntry deletes a manually recorded ledger entry
+func (uc *useCase) DeleteEntry(id uint) error {
+	entry, err := uc.repo.GetEntryByID(id)
+	if err != nil {
+		return err
+	}
+	if entry.DonationID != nil {
+		return errors.New("entry is posted from a donation")
+	}
+
+	if err := uc.repo.DeleteEntry(id); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to delete ledger entry")
+		return errors.New("failed to delete ledger entry")
+	}
+
+	logger.Info().Uint("id", id).Msg("Ledger entry deleted successfully")
+	return nil
+}
+
+// GetBalance returns the balance at the end of the given day (default today)
+func (uc *useCase) GetBalance(accountID *uint, date *time.Time) (*BalanceResponse, error) {
+	day := utils.StartOfDay(time.Now())
+	if date != nil {
+		day = utils.StartOfDay(*date)
+	}
+
+	balance, err := uc.repo.GetBalance(accountID, day.AddDate(0, 0, 1))
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get ledger balance")
+		return nil, errors.New("failed to get ledger balance")
+	}
+
+	return &BalanceResponse{
+		AccountID: accountID,
+		Date:      day.Format(utils.DateLayout),
+		Balance:   balance,
+	}, nil
+}
+
+// GetWeeklyReport builds the Friday cash report for the week ending on the
+// Friday on or before the given date (default today)
+func (uc *useCase) GetWeeklyReport(date *time.Time, accountID *uint) (*WeeklyReport, error) {
+	day := utils.StartOfDay(time.Now())
+	if date != nil {
+		day = utils.StartOfDay(*date)
+	}
+	friday := LastFriday(day)
+	from := friday.AddDate(0, 0, -6)
+
+	opening, err := uc.repo.GetBalance(accountID, from)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get opening balance")
+		return nil, errors.New("failed to get weekly report")
+	}
+
+	totals, err := uc.repo.GetCategoryTotals(&ledgerRepo.EntryFilter{AccountID: accountID, From: &from, To: &friday})
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get weekly category totals")
+		return nil, errors.New("failed to get weekly report")
+	}
+
+	report := &WeeklyReport{
+		Friday:         friday.Format(utils.DateLayout),
+		From:           from.Format(utils.DateLayout),
+		To:             friday.Format(utils.DateLayout),
+		OpeningBalance: opening,
+		Income:         []ledgerRepo.CategoryTotal{},
+		Expenses:       []ledgerRepo.CategoryTotal{},
+	}
+	for _, total := range totals {
+		if total.Type == ledgerDomain.EntryTypeExpense {
+			report.Expenses = append(report.Expenses, total)
+			report.TotalExpense = report.TotalExpense.Add(total.Amount)
+		} else {
+			report.Income = append(report.Income, total)
+			report.TotalIncome = report.TotalIncome.Add(total.Amount)
+		}
+	}
+	report.ClosingBalance = opening.Add(report.TotalIncome).Sub(report.TotalExpense)
+
+	return report, nil
+}
+
+// PostDonation records a successful donation as income in the default account.
+// Posting is idempotent and follows later changes of the donation; a donation
+// that is no longer successful is removed from the ledger.
+func (uc *useCase) PostDonation(don *donationDomain.Donation) error {
+	if don.PaymentStatus != donationDomain.PaymentStatusSuccess {
+		return uc.RemoveDonation(don.ID)
+	}
+
+	acc, err := uc.repo.GetDefaultAccount()
+	if err != nil {
+		return err
+	}
+	cat, err := uc.repo.GetDonationCategory()
+	if err != nil {
+		return err
+	}
+
+	paidAt := don.CreatedAt
+	if don.PaidAt != nil {
+		paidAt = *don.PaidAt
+	}
+	description := fmt.Sprintf("Donasi #%d", don.ID)
+	if don.Category != nil && don.Category.Name != "" {
+		description += " - " + don.Category.Name
+	}
+
+	donationID := don.ID
+	entry := &ledgerDomain.Entry{
+		AccountID:   acc.ID,
+		CategoryID:  cat.ID,
+		Type:        ledgerDomain.EntryTypeIncome,
+		Amount:      don.Amount,
+		Description: description,
+		EntryDate:   utils.StartOfDay(paidAt),
+		DonationID:  &donationID,
+	}
+	if err := uc.repo.UpsertDonationEntry(entry); err != nil {
+		return err
+	}
+
+	logger.Info().
+		Uint("donation_id", don.ID).
+		Str("amount", don.Amount.String()).
+		Msg("Donation posted to ledger")
+	return nil
+}
+
+// RemoveDonation removes the ledger entry of a donation, if any
+func (uc *useCase) RemoveDonation(donationID uint) error {
+	return uc.repo.DeleteDonationEntry(donationID)
+}
+
+// resolveAccount returns the given account or the default account
+func (uc *useCase) resolveAccount(id *uint) (*ledgerDomain.Account, error) {
+	if id == nil {
+		return uc.repo.GetDefaultAccount()
+	}
+	return uc.repo.GetAccountByID(*id)
+}
+
+// manualCategory returns a category that may be used for manual entries
+func (uc *useCase) manualCategory(id uint) (*ledgerDomain.Category, error) {
+	cat, err := uc.repo.GetCategoryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if cat.System {
+		return nil, errors.New("system category cannot be changed")
+	}
+	return cat, nil
+}
+
+// LastFriday returns the Friday on or before the given day, in Jakarta time
+func LastFriday(day time.Time) time.Time {
+	day = utils.StartOfDay(day)
+	offset := (int(day.Weekday()) - int(time.Friday) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
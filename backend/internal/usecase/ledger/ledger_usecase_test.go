@@ -0,0 +1,201 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	ledgerDomain "github.com/madr/backend/internal/domain/ledger"
+	ledgerRepo "github.com/madr/backend/internal/repository/ledger"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockLedgerRepository mocks the ledger repository methods used by the tests
+type MockLedgerRepository struct {
+	ledgerRepo.Repository
+	mock.Mock
+}
+
+func (m *MockLedgerRepository) GetDefaultAccount() (*ledgerDomain.Account, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ledgerDomain.Account), args.Error(1)
+}
+
+func (m *MockLedgerRepository) GetCategoryByID(id uint) (*ledgerDomain.Category, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ledgerDomain.Category), args.Error(1)
+}
+
+func (m *MockLedgerRepository) GetDonationCategory() (*ledgerDomain.Category, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ledgerDomain.Category), args.Error(1)
+}
+
+func (m *MockLedgerRepository) GetEntryByID(id uint) (*ledgerDomain.Entry, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ledgerDomain.Entry), args.Error(1)
+}
+
+func (m *MockLedgerRepository) UpsertDonationEntry(entry *ledgerDomain.Entry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockLedgerRepository) DeleteDonationEntry(donationID uint) error {
+	args := m.Called(donationID)
+	return args.Error(0)
+}
+
+func (m *MockLedgerRepository) GetBalance(accountID *uint, before time.Time) (money.Money, error) {
+	args := m.Called(accountID, before)
+	return args.Get(0).(money.Money), args.Error(1)
+}
+
+func (m *MockLedgerRepository) GetCategoryTotals(filter *ledgerRepo.EntryFilter) ([]ledgerRepo.CategoryTotal, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]ledgerRepo.CategoryTotal), args.Error(1)
+}
+
+func mustDate(t *testing.T, value string) time.Time {
+	t.Helper()
+	date, err := utils.ParseDate(value)
+	require.NoError(t, err)
+	return date
+}
+
+// TestLastFriday tests that every day maps to the Friday on or before it
+func TestLastFriday(t *testing.T) {
+	tests := map[string]string{
+		"2025-01-10": "2025-01-10", // Friday
+		"2025-01-11": "2025-01-10", // Saturday
+		"2025-01-16": "2025-01-10", // Thursday
+	}
+	for day, friday := range tests {
+		assert.Equal(t, friday, LastFriday(mustDate(t, day)).Format(utils.DateLayout), day)
+	}
+
+	// 20:00 UTC on Thursday is already Friday in Jakarta
+	late := time.Date(2025, 1, 16, 20, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2025-01-17", LastFriday(late).Format(utils.DateLayout))
+}
+
+// TestGetWeeklyReport tests the Saturday-to-Friday window and the balance math
+func TestGetWeeklyReport(t *testing.T) {
+	mockRepo := new(MockLedgerRepository)
+	useCase := NewUseCase(mockRepo)
+
+	saturday := mustDate(t, "2025-01-04")
+	friday := mustDate(t, "2025-01-10")
+
+	mockRepo.On("GetBalance", (*uint)(nil), saturday).Return(money.FromRupiah(1000000), nil)
+	mockRepo.On("GetCategoryTotals", mock.MatchedBy(func(f *ledgerRepo.EntryFilter) bool {
+		return f.From.Equal(saturday) && f.To.Equal(friday)
+	})).Return([]ledgerRepo.CategoryTotal{
+		{CategoryName: "Donasi", Type: ledgerDomain.EntryTypeIncome, Amount: money.MustParse("250000.50"), Entries: 3},
+		{CategoryName: "Kotak Amal", Type: ledgerDomain.EntryTypeIncome, Amount: money.FromRupiah(400000), Entries: 1},
+		{CategoryName: "Listrik dan Air", Type: ledgerDomain.EntryTypeExpense, Amount: money.FromRupiah(150000), Entries: 1},
+	}, nil)
+
+	date := mustDate(t, "2025-01-14")
+	report, err := useCase.GetWeeklyReport(&date, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01-10", report.Friday)
+	assert.Equal(t, "2025-01-04", report.From)
+	assert.Len(t, report.Income, 2)
+	assert.Len(t, report.Expenses, 1)
+	assert.Equal(t, "650000.50", report.TotalIncome.String())
+	assert.Equal(t, "150000.00", report.TotalExpense.String())
+	assert.Equal(t, "1500000.50", report.ClosingBalance.String())
+}
+
+// TestPostDonation_UpsertsSuccessfulDonation tests that a successful donation
+// is posted as income dated by its Jakarta payment day
+func TestPostDonation_UpsertsSuccessfulDonation(t *testing.T) {
+	mockRepo := new(MockLedgerRepository)
+	useCase := NewUseCase(mockRepo)
+
+	acc := &ledgerDomain.Account{Name: "Kas Masjid", IsDefault: true}
+	acc.ID = 1
+	cat := &ledgerDomain.Category{Name: "Donasi", Type: ledgerDomain.EntryTypeIncome, System: true}
+	cat.ID = 2
+	paidAt := time.Date(2025, 1, 9, 18, 0, 0, 0, time.UTC)
+	don := &donationDomain.Donation{
+		Amount:        money.FromRupiah(50000),
+		PaymentStatus: donationDomain.PaymentStatusSuccess,
+		PaidAt:        &paidAt,
+		Category:      &donationDomain.DonationCategoryInfo{ID: 1, Name: "Infaq"},
+	}
+	don.ID = 7
+
+	mockRepo.On("GetDefaultAccount").Return(acc, nil)
+	mockRepo.On("GetDonationCategory").Return(cat, nil)
+	mockRepo.On("UpsertDonationEntry", mock.MatchedBy(func(e *ledgerDomain.Entry) bool {
+		return e.AccountID == 1 && e.CategoryID == 2 &&
+			e.Type == ledgerDomain.EntryTypeIncome &&
+			e.Amount == money.FromRupiah(50000) &&
+			e.Description == "Donasi #7 - Infaq" &&
+			e.EntryDate.Format(utils.DateLayout) == "2025-01-10" &&
+			e.DonationID != nil && *e.DonationID == 7
+	})).Return(nil)
+
+	require.NoError(t, useCase.PostDonation(don))
+	mockRepo.AssertExpectations(t)
+}
+
+// TestPostDonation_RemovesUnsuccessfulDonation tests that a refunded or
+// failed donation is taken out of the ledger
+func TestPostDonation_RemovesUnsuccessfulDonation(t *testing.T) {
+	mockRepo := new(MockLedgerRepository)
+	useCase := NewUseCase(mockRepo)
+
+	don := &donationDomain.Donation{PaymentStatus: donationDomain.PaymentStatusFailed}
+	don.ID = 7
+	mockRepo.On("DeleteDonationEntry", uint(7)).Return(nil)
+
+	require.NoError(t, useCase.PostDonation(don))
+	mockRepo.AssertNotCalled(t, "UpsertDonationEntry", mock.Anything)
+}
+
+// TestManualChangesProtectPostedEntries tests that donation postings and
+// system categories cannot be edited by hand
+func TestManualChangesProtectPostedEntries(t *testing.T) {
+	mockRepo := new(MockLedgerRepository)
+	useCase := NewUseCase(mockRepo)
+
+	donationID := uint(7)
+	posted := &ledgerDomain.Entry{DonationID: &donationID}
+	system := &ledgerDomain.Category{Name: "Donasi", Type: ledgerDomain.EntryTypeIncome, System: true}
+
+	mockRepo.On("GetDefaultAccount").Return(&ledgerDomain.Account{Name: "Kas Masjid", IsDefault: true}, nil)
+	mockRepo.On("GetEntryByID", uint(1)).Return(posted, nil)
+	mockRepo.On("GetCategoryByID", uint(2)).Return(system, nil)
+	mockRepo.On("GetEntryByID", uint(9)).Return(nil, errors.New("ledger entry not found"))
+
+	amount := money.FromRupiah(1)
+	_, err := useCase.UpdateEntry(1, &UpdateEntryRequest{Amount: &amount})
+	assert.EqualError(t, err, "entry is posted from a donation")
+	assert.EqualError(t, useCase.DeleteEntry(1), "entry is posted from a donation")
+	assert.EqualError(t, useCase.DeleteCategory(2), "system category cannot be changed")
+	assert.EqualError(t, useCase.DeleteEntry(9), "ledger entry not found")
+
+	_, err = useCase.CreateEntry(&CreateEntryRequest{CategoryID: 2, Amount: amount, EntryDate: "2025-01-10"})
+	assert.EqualError(t, err, "system category cannot be changed")
+}
@@ -0,0 +1,184 @@
+package lockout
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/madr/backend/internal/domain/loginattempt"
+	loginAttemptRepo "github.com/madr/backend/internal/repository/loginattempt"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// UseCase defines the interface for failed login tracking
+type UseCase interface {
+	Check(username, ipAddress string) error
+	RecordFailure(username, ipAddress string) error
+	RecordSuccess(username string) error
+	Unlock(username string) error
+	DeleteStale(now time.Time) (int64, error)
+}
+
+// ThrottledError is returned while a username or client IP has to wait
+// before it may try to log in again
+type ThrottledError struct {
+	Locked     bool          // The account is locked out, not just backing off
+	RetryAfter time.Duration // Time left until the next attempt is allowed
+}
+
+func (e *ThrottledError) Error() string {
+	if e.Locked {
+		return "account is temporarily locked"
+	}
+	return "too many login attempts"
+}
+
+// Options configures when failed logins are throttled
+type Options struct {
+	MaxAttempts      int           // Failures per username before it is locked
+	MaxAttemptsPerIP int           // Failures per client IP before it is locked
+	LockoutDuration  time.Duration // How long a lockout lasts; older failures are forgotten
+	BackoffBase      time.Duration // Wait after the first failure, doubled for each further one
+}
+
+type useCase struct {
+	repo    loginAttemptRepo.Repository
+	options Options
+	now     func() time.Time
+}
+
+// NewUseCase creates a new failed login tracking use case
+func NewUseCase(repo loginAttemptRepo.Repository, options Options) UseCase {
+	return &useCase{
+		repo:    repo,
+		options: options,
+		now:     time.Now,
+	}
+}
+
+// Check returns a ThrottledError if the username or client IP may not try
+// to log in yet
+func (uc *useCase) Check(username, ipAddress string) error {
+	now := uc.now()
+	for _, counter := range uc.counters(username, ipAddress) {
+		attempt, err := uc.repo.Get(counter.scope, counter.key)
+		if err != nil {
+			if err.Error() == "login attempt not found" {
+				continue
+			}
+			logger.Error().Err(err).Str("scope", string(counter.scope)).Msg("Failed to get login attempts")
+			return errors.New("failed to check login attempts")
+		}
+		if err := uc.throttle(attempt, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed login for the username and client IP and
+// locks out whichever reached its limit
+func (uc *useCase) RecordFailure(username, ipAddress string) error {
+	now := uc.now()
+	since := now.Add(-uc.options.LockoutDuration)
+	for _, counter := range uc.counters(username, ipAddress) {
+		attempt, err := uc.repo.RecordFailure(counter.scope, counter.key, now, since)
+		if err != nil {
+			logger.Error().Err(err).Str("scope", string(counter.scope)).Msg("Failed to record failed login")
+			return errors.New("failed to record failed login")
+		}
+		if counter.limit <= 0 || attempt.Failures < counter.limit {
+			continue
+		}
+
+		until := now.Add(uc.options.LockoutDuration)
+		if err := uc.repo.Lock(counter.scope, counter.key, until); err != nil {
+			logger.Error().Err(err).Str("scope", string(counter.scope)).Msg("Failed to lock out logins")
+			return errors.New("failed to record failed login")
+		}
+		logger.Warn().
+			Str("scope", string(counter.scope)).
+			Str("key", counter.key).
+			Int("failures", attempt.Failures).
+			Time("locked_until", until).
+			Msg("Logins locked out after repeated failures")
+	}
+	return nil
+}
+
+// RecordSuccess forgets the failed logins of a username. Failures of the
+// client IP are kept so that one valid account cannot reset them.
+func (uc *useCase) RecordSuccess(username string) error {
+	if err := uc.repo.Reset(loginattempt.ScopeUsername, normalize(username)); err != nil {
+		logger.Error().Err(err).Msg("Failed to reset failed logins")
+		return errors.New("failed to reset failed logins")
+	}
+	return nil
+}
+
+// Unlock lifts the lockout of a username. IP counters are not tied to a
+// username, so an IP lockout stays until it expires.
+func (uc *useCase) Unlock(username string) error {
+	if err := uc.RecordSuccess(username); err != nil {
+		return err
+	}
+	logger.Info().Str("username", username).Msg("Login lockout lifted")
+	return nil
+}
+
+// DeleteStale removes counters that no longer throttle anyone
+func (uc *useCase) DeleteStale(now time.Time) (int64, error) {
+	return uc.repo.DeleteStale(now.Add(-uc.options.LockoutDuration))
+}
+
+// throttle returns a ThrottledError if attempt is locked out or still
+// backing off at now
+func (uc *useCase) throttle(attempt *loginattempt.LoginAttempt, now time.Time) error {
+	if attempt.IsLockedAt(now) {
+		return &ThrottledError{
+			Locked:     attempt.Scope == loginattempt.ScopeUsername,
+			RetryAfter: attempt.LockedUntil.Sub(now),
+		}
+	}
+	if !attempt.LastFailedAt.After(now.Add(-uc.options.LockoutDuration)) {
+		return nil
+	}
+	if wait := attempt.LastFailedAt.Add(uc.backoff(attempt.Failures)).Sub(now); wait > 0 {
+		return &ThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// backoff is how long to wait after the given number of failures
+func (uc *useCase) backoff(failures int) time.Duration {
+	if failures <= 0 || uc.options.BackoffBase <= 0 {
+		return 0
+	}
+	wait := uc.options.BackoffBase
+	for i := 1; i < failures; i++ {
+		wait *= 2
+		if wait >= uc.options.LockoutDuration {
+			return uc.options.LockoutDuration
+		}
+	}
+	return wait
+}
+
+type counter struct {
+	scope loginattempt.Scope
+	key   string
+	limit int
+}
+
+// counters lists the counters a login attempt is tracked by
+func (uc *useCase) counters(username, ipAddress string) []counter {
+	return []counter{
+		{loginattempt.ScopeUsername, normalize(username), uc.options.MaxAttempts},
+		{loginattempt.ScopeIP, ipAddress, uc.options.MaxAttemptsPerIP},
+	}
+}
+
+// normalize makes differently cased usernames share a counter
+func normalize(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
@@ -0,0 +1,160 @@
+package lockout
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/madr/backend/internal/domain/loginattempt"
+	loginAttemptRepo "github.com/madr/backend/internal/repository/loginattempt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepository keeps login attempts in memory
+type fakeRepository struct {
+	loginAttemptRepo.Repository
+	attempts map[string]*loginattempt.LoginAttempt
+}
+
+func key(scope loginattempt.Scope, key string) string {
+	return string(scope) + ":" + key
+}
+
+func (r *fakeRepository) Get(scope loginattempt.Scope, k string) (*loginattempt.LoginAttempt, error) {
+	attempt, ok := r.attempts[key(scope, k)]
+	if !ok {
+		return nil, errors.New("login attempt not found")
+	}
+	clone := *attempt
+	return &clone, nil
+}
+
+func (r *fakeRepository) RecordFailure(scope loginattempt.Scope, k string, at, since time.Time) (*loginattempt.LoginAttempt, error) {
+	attempt, ok := r.attempts[key(scope, k)]
+	if !ok || !attempt.LastFailedAt.After(since) {
+		attempt = &loginattempt.LoginAttempt{Scope: scope, Key: k}
+		r.attempts[key(scope, k)] = attempt
+	}
+	attempt.Failures++
+	attempt.LastFailedAt = at
+	clone := *attempt
+	return &clone, nil
+}
+
+func (r *fakeRepository) Lock(scope loginattempt.Scope, k string, until time.Time) error {
+	r.attempts[key(scope, k)].LockedUntil = &until
+	return nil
+}
+
+func (r *fakeRepository) Reset(scope loginattempt.Scope, k string) error {
+	delete(r.attempts, key(scope, k))
+	return nil
+}
+
+func newTestUseCase(now *time.Time) (*useCase, *fakeRepository) {
+	repo := &fakeRepository{attempts: map[string]*loginattempt.LoginAttempt{}}
+	uc := NewUseCase(repo, Options{
+		MaxAttempts:      3,
+		MaxAttemptsPerIP: 5,
+		LockoutDuration:  15 * time.Minute,
+		BackoffBase:      time.Second,
+	}).(*useCase)
+	uc.now = func() time.Time { return *now }
+	return uc, repo
+}
+
+func throttled(t *testing.T, err error) *ThrottledError {
+	t.Helper()
+	var throttled *ThrottledError
+	require.ErrorAs(t, err, &throttled)
+	return throttled
+}
+
+// TestBackoffAndLockout tests the growing wait between failures and the lockout
+func TestBackoffAndLockout(t *testing.T) {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	uc, _ := newTestUseCase(&now)
+
+	require.NoError(t, uc.Check("jamaah", "10.0.0.1"))
+	require.NoError(t, uc.RecordFailure("jamaah", "10.0.0.1"))
+
+	err := throttled(t, uc.Check("jamaah", "10.0.0.1"))
+	assert.False(t, err.Locked)
+	assert.Equal(t, time.Second, err.RetryAfter)
+	assert.EqualError(t, err, "too many login attempts")
+
+	now = now.Add(time.Second)
+	require.NoError(t, uc.Check("Jamaah", "10.0.0.1"))
+	require.NoError(t, uc.RecordFailure("Jamaah", "10.0.0.1"))
+	assert.Equal(t, 2*time.Second, throttled(t, uc.Check("jamaah", "10.0.0.1")).RetryAfter)
+
+	// Another address is still held back by the username
+	assert.Equal(t, 2*time.Second, throttled(t, uc.Check("jamaah", "10.0.0.2")).RetryAfter)
+
+	now = now.Add(2 * time.Second)
+	require.NoError(t, uc.RecordFailure("jamaah", "10.0.0.1"))
+	err = throttled(t, uc.Check("jamaah", "10.0.0.2"))
+	assert.True(t, err.Locked)
+	assert.Equal(t, 15*time.Minute, err.RetryAfter)
+	assert.EqualError(t, err, "account is temporarily locked")
+
+	now = now.Add(10 * time.Minute)
+	assert.Equal(t, 5*time.Minute, throttled(t, uc.Check("jamaah", "10.0.0.2")).RetryAfter)
+
+	// Once the lockout is over the count starts again
+	now = now.Add(5 * time.Minute)
+	require.NoError(t, uc.Check("jamaah", "10.0.0.2"))
+	require.NoError(t, uc.RecordFailure("jamaah", "10.0.0.2"))
+	assert.Equal(t, time.Second, throttled(t, uc.Check("jamaah", "10.0.0.2")).RetryAfter)
+}
+
+// TestIPLockout tests that one address cannot spread guesses over many usernames
+func TestIPLockout(t *testing.T) {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	uc, _ := newTestUseCase(&now)
+
+	for _, username := range []string{"a", "b", "c", "d", "e"} {
+		now = now.Add(time.Minute)
+		require.NoError(t, uc.Check(username, "10.0.0.1"))
+		require.NoError(t, uc.RecordFailure(username, "10.0.0.1"))
+	}
+
+	err := throttled(t, uc.Check("f", "10.0.0.1"))
+	assert.False(t, err.Locked, "an address lockout is not an account lockout")
+	assert.Equal(t, 15*time.Minute, err.RetryAfter)
+	require.NoError(t, uc.Check("f", "10.0.0.2"))
+}
+
+// TestRecordSuccessAndUnlock tests that a login or an administrator lifts the username lockout
+func TestRecordSuccessAndUnlock(t *testing.T) {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	uc, repo := newTestUseCase(&now)
+
+	require.NoError(t, uc.RecordFailure("jamaah", "10.0.0.1"))
+	now = now.Add(time.Minute)
+	require.NoError(t, uc.RecordSuccess("jamaah"))
+	require.NoError(t, uc.Check("jamaah", "10.0.0.1"))
+	_, ok := repo.attempts[key(loginattempt.ScopeIP, "10.0.0.1")]
+	assert.True(t, ok, "failures of the address are kept")
+
+	for i := 0; i < 3; i++ {
+		now = now.Add(time.Minute)
+		require.NoError(t, uc.RecordFailure("jamaah", "10.0.0.3"))
+	}
+	assert.True(t, throttled(t, uc.Check("jamaah", "10.0.0.3")).Locked)
+
+	require.NoError(t, uc.Unlock("JAMAAH"))
+	require.NoError(t, uc.Check("jamaah", "10.0.0.4"))
+}
+
+// TestBackoffIsCapped tests that waiting never exceeds the lockout duration
+func TestBackoffIsCapped(t *testing.T) {
+	now := time.Now()
+	uc, _ := newTestUseCase(&now)
+
+	assert.Equal(t, time.Duration(0), uc.backoff(0))
+	assert.Equal(t, 8*time.Second, uc.backoff(4))
+	assert.Equal(t, 15*time.Minute, uc.backoff(20))
+	assert.Equal(t, 15*time.Minute, uc.backoff(1000))
+}
@@ -0,0 +1,395 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	mfaDomain "github.com/madr/backend/internal/domain/mfa"
+	roleDomain "github.com/madr/backend/internal/domain/role"
+	userDomain "github.com/madr/backend/internal/domain/user"
+	mfaRepo "github.com/madr/backend/internal/repository/mfa"
+	userRepo "github.com/madr/backend/internal/repository/user"
+	"github.com/madr/backend/pkg/bcrypt"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/totp"
+)
+
+const (
+	// recoveryCodeCount is how many recovery codes a user gets at a time
+	recoveryCodeCount = 10
+	// recoveryCodeLength is the number of characters in a recovery code
+	recoveryCodeLength = 10
+	// recoveryCodeAlphabet leaves out characters that are easy to misread
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	// qrCodeSize is the width and height of the enrollment QR code in pixels
+	qrCodeSize = 256
+	// allowedSkew is how many 30 second steps a code may be off by
+	allowedSkew = 1
+)
+
+// UseCase defines the interface for two-factor authentication
+type UseCase interface {
+	GetStatus(userID uint) (*StatusResponse, error)
+	Enroll(userID uint) (*EnrollResponse, error)
+	Confirm(userID uint, code string) (*RecoveryCodesResponse, error)
+	Disable(userID uint, req *DisableRequest, ipAddress string) error
+	RegenerateRecoveryCodes(userID uint, code string) (*RecoveryCodesResponse, error)
+	IsEnabled(userID uint) (bool, error)
+	IsRequired(usr *userDomain.User) (bool, error)
+	Verify(userID uint, code string) error
+}
+
+// RoleLookup provides the roles assigned to a user
+type RoleLookup interface {
+	GetUserRoles(userID uint) ([]roleDomain.Role, error)
+}
+
+// LoginGuard throttles repeated wrong passwords per username and client IP
+type LoginGuard interface {
+	Check(username, ipAddress string) error
+	RecordFailure(username, ipAddress string) error
+}
+
+// CodeRequest carries an authenticator or recovery code
+type CodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableRequest represents the request to turn two-factor authentication off
+type DisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"` // Authenticator or recovery code
+}
+
+// StatusResponse describes the two-factor authentication of a user
+type StatusResponse struct {
+	Enabled                bool  `json:"enabled"`
+	Required               bool  `json:"required"` // Enforced for the user's role
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+// EnrollResponse carries the new secret for the authenticator app
+type EnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	QRCode     string `json:"qr_code"` // PNG data URI of OTPAuthURI
+}
+
+// RecoveryCodesResponse carries recovery codes, shown only once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// Options configures two-factor authentication
+type Options struct {
+	Issuer           string // Shown as the account issuer in authenticator apps
+	RequiredForAdmin bool   // Administrators must enroll before they can log in
+}
+
+type useCase struct {
+	repo    mfaRepo.Repository
+	users   userRepo.Repository
+	roles   RoleLookup
+	guard   LoginGuard
+	options Options
+	now     func() time.Time
+}
+
+// NewUseCase creates a new two-factor authentication use case. The guard may
+// be nil to disable throttling.
+func NewUseCase(repo mfaRepo.Repository, users userRepo.Repository, roles RoleLookup, guard LoginGuard, options Options) UseCase {
+	return &useCase{
+		repo:    repo,
+		users:   users,
+		roles:   roles,
+		guard:   guard,
+		options: options,
+		now:     time.Now,
+	}
+}
+
+// GetStatus reports whether two-factor authentication is enabled and required
+func (uc *useCase) GetStatus(userID uint) (*StatusResponse, error) {
+	usr, err := uc.users.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	response := &StatusResponse{}
+	if response.Enabled, err = uc.IsEnabled(userID); err != nil {
+		return nil, err
+	}
+	if response.Required, err = uc.IsRequired(usr); err != nil {
+		return nil, err
+	}
+	if response.Enabled {
+		if response.RecoveryCodesRemaining, err = uc.repo.CountRecoveryCodes(userID); err != nil {
+			logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to count recovery codes")
+			return nil, errors.New("failed to get two-factor authentication status")
+		}
+	}
+	return response, nil
+}
+
+// Enroll generates a new secret for a user without two-factor authentication.
+// It has no effect on logins until confirmed with a code.
+func (uc *useCase) Enroll(userID uint) (*EnrollResponse, error) {
+	usr, err := uc.users.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	enabled, err := uc.IsEnabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to generate TOTP secret")
+		return nil, errors.New("failed to enroll two-factor authentication")
+	}
+	if err := uc.repo.SaveTOTP(&mfaDomain.TOTP{UserID: userID, Secret: secret}); err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to store TOTP secret")
+		return nil, errors.New("failed to enroll two-factor authentication")
+	}
+
+	uri := totp.URI(uc.options.Issuer, usr.Email, secret)
+	png, err := totp.QRCode(uri, qrCodeSize)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to render TOTP QR code")
+		return nil, errors.New("failed to enroll two-factor authentication")
+	}
+
+	logger.Info().Uint("user_id", userID).Msg("Two-factor authentication enrollment started")
+
+	return &EnrollResponse{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
+
+// Confirm enables two-factor authentication once the user shows a valid code
+// from the enrolled authenticator, and returns the first recovery codes
+func (uc *useCase) Confirm(userID uint, code string) (*RecoveryCodesResponse, error) {
+	current, err := uc.repo.GetTOTP(userID)
+	if err != nil {
+		if err.Error() == "totp not found" {
+			return nil, errors.New("two-factor authentication has not been set up")
+		}
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to get TOTP")
+		return nil, errors.New("failed to confirm two-factor authentication")
+	}
+	if current.IsEnabled() {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	now := uc.now()
+	step, ok := totp.Validate(current.Secret, code, now, allowedSkew)
+	if !ok {
+		return nil, errors.New("invalid code")
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to generate recovery codes")
+		return nil, errors.New("failed to confirm two-factor authentication")
+	}
+	if err := uc.repo.Enable(userID, step, now, hashes); err != nil {
+		if errors.Is(err, mfaRepo.ErrUnavailable) {
+			return nil, errors.New("two-factor authentication is already enabled")
+		}
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to enable two-factor authentication")
+		return nil, errors.New("failed to confirm two-factor authentication")
+	}
+
+	logger.Info().Uint("user_id", userID).Msg("Two-factor authentication enabled")
+	return &RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable turns two-factor authentication off after checking the password
+// and a code. Users whose role requires it cannot turn it off. Wrong
+// passwords count towards the same lockout as failed logins.
+func (uc *useCase) Disable(userID uint, req *DisableRequest, ipAddress string) error {
+	usr, err := uc.users.GetByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if uc.guard != nil {
+		if err := uc.guard.Check(usr.Username, ipAddress); err != nil {
+			logger.Warn().Err(err).Uint("user_id", userID).Str("ip", ipAddress).Msg("Disabling two-factor authentication while throttled")
+			return err
+		}
+	}
+	if !bcrypt.CheckPasswordHash(req.Password, usr.Password) {
+		logger.Warn().Uint("user_id", userID).Msg("Invalid password when disabling two-factor authentication")
+		if uc.guard != nil {
+			_ = uc.guard.RecordFailure(usr.Username, ipAddress)
+		}
+		return errors.New("invalid current password")
+	}
+	required, err := uc.IsRequired(usr)
+	if err != nil {
+		return err
+	}
+	if required {
+		return errors.New("two-factor authentication is required for your role")
+	}
+	if err := uc.Verify(userID, req.Code); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Disable(userID); err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to disable two-factor authentication")
+		return errors.New("failed to disable two-factor authentication")
+	}
+
+	logger.Info().Uint("user_id", userID).Msg("Two-factor authentication disabled")
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of a user after
+// checking a code
+func (uc *useCase) RegenerateRecoveryCodes(userID uint, code string) (*RecoveryCodesResponse, error) {
+	if err := uc.Verify(userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to generate recovery codes")
+		return nil, errors.New("failed to regenerate recovery codes")
+	}
+	if err := uc.repo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to store recovery codes")
+		return nil, errors.New("failed to regenerate recovery codes")
+	}
+
+	logger.Info().Uint("user_id", userID).Msg("Recovery codes regenerated")
+	return &RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// IsEnabled reports whether a user has a confirmed authenticator
+func (uc *useCase) IsEnabled(userID uint) (bool, error) {
+	current, err := uc.repo.GetTOTP(userID)
+	if err != nil {
+		if err.Error() == "totp not found" {
+			return false, nil
+		}
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to get TOTP")
+		return false, errors.New("failed to check two-factor authentication")
+	}
+	return current.IsEnabled(), nil
+}
+
+// IsRequired reports whether a user's role requires two-factor
+// authentication
+func (uc *useCase) IsRequired(usr *userDomain.User) (bool, error) {
+	if !uc.options.RequiredForAdmin {
+		return false, nil
+	}
+	if usr.Role == userDomain.RoleAdmin {
+		return true, nil
+	}
+	if uc.roles == nil {
+		return false, nil
+	}
+
+	roles, err := uc.roles.GetUserRoles(usr.ID)
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", usr.ID).Msg("Failed to get user roles")
+		return false, errors.New("failed to get user roles")
+	}
+	for _, r := range roles {
+		if r.IsAdmin() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Verify checks an authenticator code or uses up a recovery code. Each
+// authenticator code is accepted only once.
+func (uc *useCase) Verify(userID uint, code string) error {
+	current, err := uc.repo.GetTOTP(userID)
+	if err != nil {
+		if err.Error() == "totp not found" {
+			return errors.New("two-factor authentication is not enabled")
+		}
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to get TOTP")
+		return errors.New("failed to verify code")
+	}
+	if !current.IsEnabled() {
+		return errors.New("two-factor authentication is not enabled")
+	}
+
+	now := uc.now()
+	if step, ok := totp.Validate(current.Secret, code, now, allowedSkew); ok {
+		if err := uc.repo.UseStep(userID, step); err != nil {
+			if errors.Is(err, mfaRepo.ErrUnavailable) {
+				logger.Warn().Uint("user_id", userID).Msg("Replayed two-factor authentication code")
+				return errors.New("invalid code")
+			}
+			logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to record TOTP step")
+			return errors.New("failed to verify code")
+		}
+		return nil
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if len(normalized) != recoveryCodeLength {
+		return errors.New("invalid code")
+	}
+	if err := uc.repo.UseRecoveryCode(userID, hashCode(normalized), now); err != nil {
+		if errors.Is(err, mfaRepo.ErrUnavailable) {
+			return errors.New("invalid code")
+		}
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to use recovery code")
+		return errors.New("failed to verify code")
+	}
+
+	logger.Warn().Uint("user_id", userID).Msg("Recovery code used")
+	return nil
+}
+
+// generateRecoveryCodes returns new codes formatted as xxxxx-xxxxx and their
+// hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw := make([]byte, recoveryCodeLength)
+		for j := range raw {
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return nil, nil, err
+			}
+			raw[j] = recoveryCodeAlphabet[n.Int64()]
+		}
+		half := recoveryCodeLength / 2
+		codes[i] = string(raw[:half]) + "-" + string(raw[half:])
+		hashes[i] = hashCode(string(raw))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode accepts codes typed with any case, spaces or dashes
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+}
+
+// hashCode returns the hex SHA-256 digest stored instead of a recovery code
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
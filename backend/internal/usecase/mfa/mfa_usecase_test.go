@@ -0,0 +1,293 @@
+package mfa
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	mfaDomain "github.com/madr/backend/internal/domain/mfa"
+	roleDomain "github.com/madr/backend/internal/domain/role"
+	userDomain "github.com/madr/backend/internal/domain/user"
+	mfaRepo "github.com/madr/backend/internal/repository/mfa"
+	userRepo "github.com/madr/backend/internal/repository/user"
+	"github.com/madr/backend/internal/usecase/lockout"
+	"github.com/madr/backend/pkg/bcrypt"
+	"github.com/madr/backend/pkg/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepository keeps authenticators and recovery codes in memory
+type fakeRepository struct {
+	mfaRepo.Repository
+	totps map[uint]*mfaDomain.TOTP
+	codes map[uint][]*mfaDomain.RecoveryCode
+}
+
+func (r *fakeRepository) GetTOTP(userID uint) (*mfaDomain.TOTP, error) {
+	t, ok := r.totps[userID]
+	if !ok {
+		return nil, errors.New("totp not found")
+	}
+	clone := *t
+	return &clone, nil
+}
+
+func (r *fakeRepository) SaveTOTP(t *mfaDomain.TOTP) error {
+	clone := *t
+	r.totps[t.UserID] = &clone
+	return nil
+}
+
+func (r *fakeRepository) Enable(userID uint, step int64, at time.Time, codeHashes []string) error {
+	t := r.totps[userID]
+	if t == nil || t.ConfirmedAt != nil {
+		return mfaRepo.ErrUnavailable
+	}
+	t.ConfirmedAt, t.LastUsedStep = &at, step
+	return r.ReplaceRecoveryCodes(userID, codeHashes)
+}
+
+func (r *fakeRepository) Disable(userID uint) error {
+	delete(r.totps, userID)
+	delete(r.codes, userID)
+	return nil
+}
+
+func (r *fakeRepository) UseStep(userID uint, step int64) error {
+	t := r.totps[userID]
+	if t.LastUsedStep >= step {
+		return mfaRepo.ErrUnavailable
+	}
+	t.LastUsedStep = step
+	return nil
+}
+
+func (r *fakeRepository) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	r.codes[userID] = nil
+	for _, hash := range codeHashes {
+		r.codes[userID] = append(r.codes[userID], &mfaDomain.RecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	return nil
+}
+
+func (r *fakeRepository) UseRecoveryCode(userID uint, codeHash string, at time.Time) error {
+	for _, code := range r.codes[userID] {
+		if code.CodeHash == codeHash && code.UsedAt == nil {
+			code.UsedAt = &at
+			return nil
+		}
+	}
+	return mfaRepo.ErrUnavailable
+}
+
+func (r *fakeRepository) CountRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+	for _, code := range r.codes[userID] {
+		if code.UsedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// fakeUserRepository knows a treasurer (1) and an administrator (2)
+type fakeUserRepository struct {
+	userRepo.Repository
+	users map[uint]*userDomain.User
+}
+
+func (r *fakeUserRepository) GetByID(id uint) (*userDomain.User, error) {
+	usr, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	return usr, nil
+}
+
+// fakeRoles assigns the admin system role to user 2
+type fakeRoles struct{}
+
+func (fakeRoles) GetUserRoles(userID uint) ([]roleDomain.Role, error) {
+	if userID == 2 {
+		return []roleDomain.Role{{ID: 1, Name: roleDomain.AdminRoleName, IsSystem: true}}, nil
+	}
+	return []roleDomain.Role{{ID: 2, Name: "bendahara"}}, nil
+}
+
+func newTestUseCase(t *testing.T, now *time.Time, options Options) (*useCase, *fakeRepository) {
+	hash, err := bcrypt.HashPassword("password123")
+	require.NoError(t, err)
+	users := &fakeUserRepository{users: map[uint]*userDomain.User{}}
+	for id, name := range map[uint]string{1: "bendahara", 2: "takmir"} {
+		usr := &userDomain.User{Username: name, Email: name + "@madr.local", Password: hash, Role: userDomain.RoleUser, IsActive: true}
+		usr.ID = id
+		users.users[id] = usr
+	}
+
+	repo := &fakeRepository{totps: map[uint]*mfaDomain.TOTP{}, codes: map[uint][]*mfaDomain.RecoveryCode{}}
+	uc := NewUseCase(repo, users, fakeRoles{}, nil, options).(*useCase)
+	uc.now = func() time.Time { return *now }
+	return uc, repo
+}
+
+func codeAt(t *testing.T, secret string, at time.Time) string {
+	code, err := totp.Code(secret, totp.Step(at))
+	require.NoError(t, err)
+	return code
+}
+
+// TestEnrollAndVerify tests enrollment, confirmation and code replay
+func TestEnrollAndVerify(t *testing.T) {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	uc, _ := newTestUseCase(t, &now, Options{Issuer: "MADR"})
+
+	enroll, err := uc.Enroll(1)
+	require.NoError(t, err)
+	assert.Contains(t, enroll.OTPAuthURI, "otpauth://totp/MADR:bendahara@madr.local?")
+	assert.True(t, strings.HasPrefix(enroll.QRCode, "data:image/png;base64,"))
+
+	// Not enabled until confirmed
+	enabled, err := uc.IsEnabled(1)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+	assert.EqualError(t, uc.Verify(1, codeAt(t, enroll.Secret, now)), "two-factor authentication is not enabled")
+
+	_, err = uc.Confirm(1, "000000")
+	assert.EqualError(t, err, "invalid code")
+
+	codes, err := uc.Confirm(1, codeAt(t, enroll.Secret, now))
+	require.NoError(t, err)
+	assert.Len(t, codes.RecoveryCodes, recoveryCodeCount)
+	enabled, err = uc.IsEnabled(1)
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	_, err = uc.Enroll(1)
+	assert.EqualError(t, err, "two-factor authentication is already enabled")
+
+	// The code that confirmed enrollment cannot be replayed
+	assert.EqualError(t, uc.Verify(1, codeAt(t, enroll.Secret, now)), "invalid code")
+
+	now = now.Add(totp.Period)
+	next := codeAt(t, enroll.Secret, now)
+	require.NoError(t, uc.Verify(1, next))
+	assert.EqualError(t, uc.Verify(1, next), "invalid code")
+}
+
+// TestRecoveryCodes tests that recovery codes work once and can be regenerated
+func TestRecoveryCodes(t *testing.T) {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	uc, repo := newTestUseCase(t, &now, Options{Issuer: "MADR"})
+
+	enroll, err := uc.Enroll(1)
+	require.NoError(t, err)
+	codes, err := uc.Confirm(1, codeAt(t, enroll.Secret, now))
+	require.NoError(t, err)
+	for _, hash := range repo.codes[1] {
+		assert.NotContains(t, codes.RecoveryCodes, hash.CodeHash)
+	}
+
+	first := codes.RecoveryCodes[0]
+	require.NoError(t, uc.Verify(1, strings.ToUpper(strings.ReplaceAll(first, "-", " "))))
+	assert.EqualError(t, uc.Verify(1, first), "invalid code")
+
+	status, err := uc.GetStatus(1)
+	require.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.Equal(t, int64(recoveryCodeCount-1), status.RecoveryCodesRemaining)
+
+	regenerated, err := uc.RegenerateRecoveryCodes(1, codes.RecoveryCodes[1])
+	require.NoError(t, err)
+	assert.EqualError(t, uc.Verify(1, codes.RecoveryCodes[2]), "invalid code")
+	require.NoError(t, uc.Verify(1, regenerated.RecoveryCodes[0]))
+}
+
+// TestDisable tests the password check and roles that require two-factor authentication
+func TestDisable(t *testing.T) {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	uc, _ := newTestUseCase(t, &now, Options{Issuer: "MADR", RequiredForAdmin: true})
+
+	for _, id := range []uint{1, 2} {
+		enroll, err := uc.Enroll(id)
+		require.NoError(t, err)
+		_, err = uc.Confirm(id, codeAt(t, enroll.Secret, now))
+		require.NoError(t, err)
+	}
+	now = now.Add(totp.Period)
+	secret1 := uc.repo.(*fakeRepository).totps[1].Secret
+
+	assert.EqualError(t, uc.Disable(1, &DisableRequest{Password: "salah", Code: codeAt(t, secret1, now)}, ""), "invalid current password")
+	assert.EqualError(t, uc.Disable(2, &DisableRequest{Password: "password123", Code: "000000"}, ""), "two-factor authentication is required for your role")
+	assert.EqualError(t, uc.Disable(1, &DisableRequest{Password: "password123", Code: "000000"}, ""), "invalid code")
+
+	require.NoError(t, uc.Disable(1, &DisableRequest{Password: "password123", Code: codeAt(t, secret1, now)}, ""))
+	enabled, err := uc.IsEnabled(1)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+}
+
+// fakeGuard throttles every check once blocked is set
+type fakeGuard struct {
+	blocked  error
+	failures []string
+}
+
+func (g *fakeGuard) Check(username, ipAddress string) error { return g.blocked }
+
+func (g *fakeGuard) RecordFailure(username, ipAddress string) error {
+	g.failures = append(g.failures, username+"@"+ipAddress)
+	return nil
+}
+
+// TestDisable_Throttled tests that wrong passwords count as failed logins and throttled attempts are refused
+func TestDisable_Throttled(t *testing.T) {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	uc, _ := newTestUseCase(t, &now, Options{Issuer: "MADR"})
+	guard := &fakeGuard{}
+	uc.guard = guard
+
+	enroll, err := uc.Enroll(1)
+	require.NoError(t, err)
+	_, err = uc.Confirm(1, codeAt(t, enroll.Secret, now))
+	require.NoError(t, err)
+	now = now.Add(totp.Period)
+
+	err = uc.Disable(1, &DisableRequest{Password: "salah", Code: codeAt(t, enroll.Secret, now)}, "127.0.0.1")
+	assert.EqualError(t, err, "invalid current password")
+	assert.Equal(t, []string{"bendahara@127.0.0.1"}, guard.failures)
+
+	// A throttled attempt is refused even with the right password
+	guard.blocked = &lockout.ThrottledError{Locked: true, RetryAfter: time.Minute}
+	err = uc.Disable(1, &DisableRequest{Password: "password123", Code: codeAt(t, enroll.Secret, now)}, "127.0.0.1")
+	assert.EqualError(t, err, "account is temporarily locked")
+	enabled, err := uc.IsEnabled(1)
+	require.NoError(t, err)
+	assert.True(t, enabled)
+}
+
+// TestIsRequired tests the admin-only enforcement option
+func TestIsRequired(t *testing.T) {
+	now := time.Now()
+	optional, _ := newTestUseCase(t, &now, Options{})
+	required, _ := newTestUseCase(t, &now, Options{RequiredForAdmin: true})
+
+	treasurer, admin := required.users.(*fakeUserRepository).users[1], required.users.(*fakeUserRepository).users[2]
+	legacyAdmin := &userDomain.User{Role: userDomain.RoleAdmin}
+
+	for _, tt := range []struct {
+		uc   *useCase
+		usr  *userDomain.User
+		want bool
+	}{
+		{optional, admin, false},
+		{required, treasurer, false},
+		{required, admin, true},
+		{required, legacyAdmin, true},
+	} {
+		got, err := tt.uc.IsRequired(tt.usr)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, tt.usr.Username)
+	}
+}
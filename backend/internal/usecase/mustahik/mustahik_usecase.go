@@ -0,0 +1,510 @@
+package mustahik
+
+import (
+	"errors"
+	"time"
+
+	donationCategoryDomain "github.com/madr/backend/internal/domain/donationcategory"
+	mustahikDomain "github.com/madr/backend/internal/domain/mustahik"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	donationCategoryRepo "github.com/madr/backend/internal/repository/donationcategory"
+	mustahikRepo "github.com/madr/backend/internal/repository/mustahik"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/money"
+)
+
+// UseCase defines the interface for mustahik use case
+type UseCase interface {
+	CreateMustahik(req *MustahikRequest) (*mustahikDomain.Mustahik, error)
+	GetMustahik(id uint) (*mustahikDomain.Mustahik, error)
+	GetMustahikList(limit, offset int, filter *mustahikRepo.MustahikFilter) (*GetMustahikResponse, error)
+	UpdateMustahik(id uint, req *MustahikRequest) (*mustahikDomain.Mustahik, error)
+	VerifyMustahik(id uint, req *VerifyRequest, verifierID uint) (*mustahikDomain.Mustahik, error)
+	DeleteMustahik(id uint) error
+
+	CreateDistribution(req *CreateDistributionRequest) (*mustahikDomain.Distribution, error)
+	GetDistribution(id uint) (*mustahikDomain.Distribution, error)
+	GetDistributions(limit, offset int, filter *mustahikRepo.DistributionFilter) (*GetDistributionsResponse, error)
+	DeleteDistribution(id uint) error
+
+	GetReport(from, to *time.Time) (*Report, error)
+}
+
+// MustahikRequest represents the request to create or update a mustahik household
+type MustahikRequest struct {
+	HeadName      string               `json:"head_name" binding:"required,min=2,max=255"`
+	Asnaf         mustahikDomain.Asnaf `json:"asnaf" binding:"required,oneof=fakir miskin amil muallaf riqab gharimin fisabilillah ibnu_sabil"`
+	Phone         *string              `json:"phone" binding:"omitempty,max=30"`
+	Address       string               `json:"address"`
+	HouseholdSize int                  `json:"household_size" binding:"omitempty,min=1,max=100"` // Defaults to 1
+	Notes         string               `json:"notes"`
+}
+
+// VerifyRequest represents the request to change the verification status of a mustahik
+type VerifyRequest struct {
+	Status mustahikDomain.VerificationStatus `json:"status" binding:"required,oneof=pending verified rejected"`
+	Notes  *string                           `json:"notes"`
+}
+
+// GetMustahikResponse represents the response for listing mustahik
+type GetMustahikResponse struct {
+	Data       []mustahikDomain.Mustahik `json:"data"`
+	Total      int64                     `json:"total"`
+	Limit      int                       `json:"limit"`
+	Offset     int                       `json:"offset"`
+	TotalPages int                       `json:"total_pages"`
+}
+
+// CreateDistributionRequest represents the request to record a distribution
+type CreateDistributionRequest struct {
+	MustahikID    uint        `json:"mustahik_id" binding:"required"`
+	CategoryID    uint        `json:"category_id" binding:"required"` // Donation category the funds come from
+	Amount        money.Money `json:"amount" binding:"required,gt=0"`
+	DistributedOn string      `json:"distributed_on"` // YYYY-MM-DD, defaults to today
+	Description   string      `json:"description"`
+}
+
+// GetDistributionsResponse represents the response for listing distributions
+type GetDistributionsResponse struct {
+	Data       []mustahikDomain.Distribution `json:"data"`
+	Total      int64                         `json:"total"`
+	Limit      int                           `json:"limit"`
+	Offset     int                           `json:"offset"`
+	TotalPages int                           `json:"total_pages"`
+}
+
+// Reconciliation reconciles the funds of a category or fund over a period:
+// opening balance + collected - distributed = closing balance
+type Reconciliation struct {
+	CategoryID     uint                            `json:"category_id,omitempty"`
+	CategoryName   string                          `json:"category_name,omitempty"`
+	FundType       donationCategoryDomain.FundType `json:"fund_type,omitempty"`
+	OpeningBalance money.Money                     `json:"opening_balance"`
+	Collected      money.Money                     `json:"collected"`
+	Distributed    money.Money                     `json:"distributed"`
+	ClosingBalance money.Money                     `json:"closing_balance"`
+}
+
+// Report reconciles collected and distributed funds in a date range
+type Report struct {
+	From       string                          `json:"from,omitempty"`
+	To         string                          `json:"to,omitempty"`
+	Categories []Reconciliation                `json:"categories"`
+	Funds      []Reconciliation                `json:"funds"`
+	Total      Reconciliation                  `json:"total"`
+	PerAsnaf   []mustahikRepo.AsnafDistributed `json:"per_asnaf"`
+	Overdrawn  []uint                          `json:"overdrawn_category_ids"` // Categories whose donations were refunded or deleted after distribution
+}
+
+type useCase struct {
+	repo       mustahikRepo.Repository
+	categories donationCategoryRepo.Repository
+	donations  donationRepo.Repository
+}
+
+// NewUseCase creates a new mustahik use case
+func NewUseCase(repo mustahikRepo.Repository, categories donationCategoryRepo.Repository, donations donationRepo.Repository) UseCase {
+	return &useCase{
+		repo:       repo,
+		categories: categories,
+		donations:  donations,
+	}
+}
+
+// CreateMustahik registers a new mustahik household, pending verification
+func (uc *useCase) CreateMustahik(req *MustahikRequest) (*mustahikDomain.Mustahik, error) {
+	m := &mustahikDomain.Mustahik{VerificationStatus: mustahikDomain.VerificationPending}
+	applyMustahik(m, req)
+
+	if err := uc.repo.CreateMustahik(m); err != nil {
+		logger.Error().Err(err).Msg("Failed to create mustahik")
+		return nil, errors.New("failed to create mustahik")
+	}
+
+	logger.Info().Uint("id", m.ID).Str("asnaf", string(m.Asnaf)).Msg("Mustahik created successfully")
+	return m, nil
+}
+
+// GetMustahik retrieves a mustahik household by ID
+func (uc *useCase) GetMustahik(id uint) (*mustahikDomain.Mustahik, error) {
+	m, err := uc.repo.GetMustahikByID(id)
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to get mustahik")
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetMustahikList retrieves mustahik households with pagination
+func (uc *useCase) GetMustahikList(limit, offset int, filter *mustahikRepo.MustahikFilter) (*GetMustahikResponse, error) {
+	limit, offset = paginate(limit, offset)
+
+	list, total, err := uc.repo.GetMustahik(limit, offset, filter)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get mustahik")
+		return nil, errors.New("failed to get mustahik")
+	}
+
+	return &GetMustahikResponse{
+		Data:       list,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		TotalPages: totalPages(total, limit),
+	}, nil
+}
+
+// UpdateMustahik updates a mustahik household. The verification status is
+// only changed through VerifyMustahik.
+func (uc *useCase) UpdateMustahik(id uint, req *MustahikRequest) (*mustahikDomain.Mustahik, error) {
+	m, err := uc.repo.GetMustahikByID(id)
+	if err != nil {
+		return nil, err
+	}
+	applyMustahik(m, req)
+
+	if err := uc.repo.UpdateMustahik(m); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to update mustahik")
+		return nil, errors.New("failed to update mustahik")
+	}
+
+	logger.Info().Uint("id", id).Msg("Mustahik updated successfully")
+	return m, nil
+}
+
+// VerifyMustahik sets the verification status of a mustahik household
+func (uc *useCase) VerifyMustahik(id uint, req *VerifyRequest, verifierID uint) (*mustahikDomain.Mustahik, error) {
+	m, err := uc.repo.GetMustahikByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	m.VerificationStatus = req.Status
+	if req.Status == mustahikDomain.VerificationPending {
+		m.VerifiedAt = nil
+		m.VerifiedBy = nil
+	} else {
+		now := time.Now()
+		m.VerifiedAt = &now
+		m.VerifiedBy = &verifierID
+	}
+	if req.Notes != nil {
+		m.Notes = *req.Notes
+	}
+
+	if err := uc.repo.UpdateMustahik(m); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to verify mustahik")
+		return nil, errors.New("failed to update mustahik")
+	}
+
+	logger.Info().
+		Uint("id", id).
+		Uint("verified_by", verifierID).
+		Str("status", string(m.VerificationStatus)).
+		Msg("Mustahik verification updated")
+	return m, nil
+}
+
+// DeleteMustahik deletes a mustahik household without distributions
+func (uc *useCase) DeleteMustahik(id uint) error {
+	if _, err := uc.repo.GetMustahikByID(id); err != nil {
+		return err
+	}
+
+	count, err := uc.repo.CountDistributions(&mustahikRepo.DistributionFilter{MustahikID: &id})
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to count mustahik distributions")
+		return errors.New("failed to delete mustahik")
+	}
+	if count > 0 {
+		return errors.New("mustahik has distributions")
+	}
+
+	if err := uc.repo.DeleteMustahik(id); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to delete mustahik")
+		return errors.New("failed to delete mustahik")
+	}
+
+	logger.Info().Uint("id", id).Msg("Mustahik deleted successfully")
+	return nil
+}
+
+// CreateDistribution records aid paid to a verified mustahik. The amount may
+// not exceed the balance of the source category.
+func (uc *useCase) CreateDistribution(req *CreateDistributionRequest) (*mustahikDomain.Distribution, error) {
+	distributedOn := utils.StartOfDay(time.Now())
+	if req.DistributedOn != "" {
+		date, err := utils.ParseDate(req.DistributedOn)
+		if err != nil {
+			return nil, errors.New("invalid distribution date")
+		}
+		distributedOn = date
+	}
+
+	m, err := uc.repo.GetMustahikByID(req.MustahikID)
+	if err != nil {
+		return nil, err
+	}
+	if m.VerificationStatus != mustahikDomain.VerificationVerified {
+		return nil, errors.New("mustahik is not verified")
+	}
+
+	cat, err := uc.categories.GetByID(req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &mustahikDomain.Distribution{
+		MustahikID:    m.ID,
+		CategoryID:    cat.ID,
+		Amount:        req.Amount,
+		DistributedOn: distributedOn,
+		Description:   req.Description,
+	}
+	if err := uc.repo.CreateDistribution(d); err != nil {
+		switch err.Error() {
+		case "insufficient category balance", "donation category not found":
+			return nil, err
+		}
+		logger.Error().Err(err).Uint("mustahik_id", m.ID).Msg("Failed to create distribution")
+		return nil, errors.New("failed to create distribution")
+	}
+
+	d.MustahikName = m.HeadName
+	d.Asnaf = m.Asnaf
+	d.CategoryName = cat.Name
+
+	logger.Info().
+		Uint("id", d.ID).
+		Uint("mustahik_id", m.ID).
+		Uint("category_id", cat.ID).
+		Str("amount", d.Amount.String()).
+		Msg("Distribution recorded successfully")
+	return d, nil
+}
+
+// GetDistribution retrieves a distribution by ID
+func (uc *useCase) GetDistribution(id uint) (*mustahikDomain.Distribution, error) {
+	d, err := uc.repo.GetDistributionByID(id)
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to get distribution")
+		return nil, err
+	}
+	return d, nil
+}
+
+// GetDistributions retrieves distributions with pagination
+func (uc *useCase) GetDistributions(limit, offset int, filter *mustahikRepo.DistributionFilter) (*GetDistributionsResponse, error) {
+	limit, offset = paginate(limit, offset)
+
+	list, total, err := uc.repo.GetDistributions(limit, offset, filter)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get distributions")
+		return nil, errors.New("failed to get distributions")
+	}
+
+	return &GetDistributionsResponse{
+		Data:       list,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		TotalPages: totalPages(total, limit),
+	}, nil
+}
+
+// DeleteDistribution deletes a distribution recorded by mistake
+func (uc *useCase) DeleteDistribution(id uint) error {
+	if _, err := uc.repo.GetDistributionByID(id); err != nil {
+		return err
+	}
+
+	if err := uc.repo.DeleteDistribution(id); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to delete distribution")
+		return errors.New("failed to delete distribution")
+	}
+
+	logger.Info().Uint("id", id).Msg("Distribution deleted successfully")
+	return nil
+}
+
+// GetReport reconciles the successful donations collected and the
+// distributions paid per category and fund in the given inclusive date range
+// (default all time)
+func (uc *useCase) GetReport(from, to *time.Time) (*Report, error) {
+	report := &Report{}
+	period := &donationRepo.Filter{}
+	distributed := &mustahikRepo.DistributionFilter{}
+	if from != nil {
+		start := utils.StartOfDay(*from)
+		period.From = &start
+		distributed.From = &start
+		report.From = start.Format(utils.DateLayout)
+	}
+	if to != nil {
+		last := utils.StartOfDay(*to)
+		end := last.AddDate(0, 0, 1)
+		period.To = &end
+		distributed.To = &last
+		report.To = last.Format(utils.DateLayout)
+	}
+	if period.From != nil && period.To != nil && !period.From.Before(*period.To) {
+		return nil, errors.New("invalid date range")
+	}
+
+	categories, err := uc.categories.GetAll()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get donation categories")
+		return nil, errors.New("failed to get distribution report")
+	}
+	collected, err := uc.donations.GetAmountPerCategory(period)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get donation amount per category")
+		return nil, errors.New("failed to get distribution report")
+	}
+	paid, err := uc.repo.GetDistributedPerCategory(distributed)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get distributed amount per category")
+		return nil, errors.New("failed to get distribution report")
+	}
+	perAsnaf, err := uc.repo.GetDistributedPerAsnaf(distributed)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get distributed amount per asnaf")
+		return nil, errors.New("failed to get distribution report")
+	}
+
+	var openingCollected []donationRepo.CategoryAmount
+	var openingPaid []mustahikRepo.CategoryDistributed
+	if period.From != nil {
+		openingCollected, err = uc.donations.GetAmountPerCategory(&donationRepo.Filter{To: period.From})
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to get opening donation amount per category")
+			return nil, errors.New("failed to get distribution report")
+		}
+		dayBefore := period.From.AddDate(0, 0, -1)
+		openingPaid, err = uc.repo.GetDistributedPerCategory(&mustahikRepo.DistributionFilter{To: &dayBefore})
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to get opening distributed amount per category")
+			return nil, errors.New("failed to get distribution report")
+		}
+	}
+
+	// One row per category, including deleted categories that still have funds
+	report.Categories = make([]Reconciliation, 0, len(categories))
+	for _, cat := range categories {
+		report.Categories = append(report.Categories, Reconciliation{
+			CategoryID:   cat.ID,
+			CategoryName: cat.Name,
+			FundType:     cat.FundType,
+		})
+	}
+	rows := map[uint]int{}
+	for i := range report.Categories {
+		rows[report.Categories[i].CategoryID] = i
+	}
+	row := func(ca donationRepo.CategoryAmount) *Reconciliation {
+		if i, ok := rows[ca.CategoryID]; ok {
+			return &report.Categories[i]
+		}
+		rows[ca.CategoryID] = len(report.Categories)
+		report.Categories = append(report.Categories, Reconciliation{
+			CategoryID:   ca.CategoryID,
+			CategoryName: ca.CategoryName,
+			FundType:     fundOf(ca.FundType),
+		})
+		return &report.Categories[len(report.Categories)-1]
+	}
+	for _, ca := range openingCollected {
+		r := row(ca)
+		r.OpeningBalance = r.OpeningBalance.Add(ca.Amount)
+	}
+	for _, ca := range collected {
+		r := row(ca)
+		r.Collected = r.Collected.Add(ca.Amount)
+	}
+	for _, cd := range openingPaid {
+		r := row(donationRepo.CategoryAmount{CategoryID: cd.CategoryID})
+		r.OpeningBalance = r.OpeningBalance.Sub(cd.Amount)
+	}
+	for _, cd := range paid {
+		r := row(donationRepo.CategoryAmount{CategoryID: cd.CategoryID})
+		r.Distributed = r.Distributed.Add(cd.Amount)
+	}
+
+	report.Funds = []Reconciliation{
+		{FundType: donationCategoryDomain.FundZakatFitrah},
+		{FundType: donationCategoryDomain.FundZakatMal},
+		{FundType: donationCategoryDomain.FundInfaq},
+	}
+	funds := map[donationCategoryDomain.FundType]*Reconciliation{}
+	for i := range report.Funds {
+		funds[report.Funds[i].FundType] = &report.Funds[i]
+	}
+	report.Overdrawn = []uint{}
+	for i := range report.Categories {
+		r := &report.Categories[i]
+		r.ClosingBalance = r.OpeningBalance.Add(r.Collected).Sub(r.Distributed)
+		if r.ClosingBalance < 0 {
+			report.Overdrawn = append(report.Overdrawn, r.CategoryID)
+		}
+
+		fund := funds[fundOf(string(r.FundType))]
+		for _, total := range []*Reconciliation{fund, &report.Total} {
+			total.OpeningBalance = total.OpeningBalance.Add(r.OpeningBalance)
+			total.Collected = total.Collected.Add(r.Collected)
+			total.Distributed = total.Distributed.Add(r.Distributed)
+			total.ClosingBalance = total.ClosingBalance.Add(r.ClosingBalance)
+		}
+	}
+
+	report.PerAsnaf = make([]mustahikRepo.AsnafDistributed, 0, len(mustahikDomain.AllAsnaf))
+	for _, asnaf := range mustahikDomain.AllAsnaf {
+		total := mustahikRepo.AsnafDistributed{Asnaf: asnaf}
+		for _, a := range perAsnaf {
+			if a.Asnaf == asnaf {
+				total = a
+			}
+		}
+		report.PerAsnaf = append(report.PerAsnaf, total)
+	}
+
+	return report, nil
+}
+
+func applyMustahik(m *mustahikDomain.Mustahik, req *MustahikRequest) {
+	m.HeadName = req.HeadName
+	m.Asnaf = req.Asnaf
+	m.Phone = req.Phone
+	m.Address = req.Address
+	m.Notes = req.Notes
+	m.HouseholdSize = req.HouseholdSize
+	if m.HouseholdSize == 0 {
+		m.HouseholdSize = 1
+	}
+}
+
+// fundOf maps a stored fund type to a known fund, treating unknown as infaq
+func fundOf(fundType string) donationCategoryDomain.FundType {
+	fund := donationCategoryDomain.FundType(fundType)
+	if !fund.IsValid() {
+		return donationCategoryDomain.FundInfaq
+	}
+	return fund
+}
+
+func paginate(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+func totalPages(total int64, limit int) int {
+	return int((total + int64(limit) - 1) / int64(limit))
+}
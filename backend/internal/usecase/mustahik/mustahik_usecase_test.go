@@ -0,0 +1,223 @@
+package mustahik
+
+import (
+	"errors"
+	"testing"
+
+	donationCategoryDomain "github.com/madr/backend/internal/domain/donationcategory"
+	mustahikDomain "github.com/madr/backend/internal/domain/mustahik"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	donationCategoryRepo "github.com/madr/backend/internal/repository/donationcategory"
+	mustahikRepo "github.com/madr/backend/internal/repository/mustahik"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMustahikRepository mocks the mustahik repository methods used by the tests
+type MockMustahikRepository struct {
+	mustahikRepo.Repository
+	mock.Mock
+}
+
+func (m *MockMustahikRepository) GetMustahikByID(id uint) (*mustahikDomain.Mustahik, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mustahikDomain.Mustahik), args.Error(1)
+}
+
+func (m *MockMustahikRepository) UpdateMustahik(mustahik *mustahikDomain.Mustahik) error {
+	args := m.Called(mustahik)
+	return args.Error(0)
+}
+
+func (m *MockMustahikRepository) CreateDistribution(d *mustahikDomain.Distribution) error {
+	args := m.Called(d)
+	return args.Error(0)
+}
+
+func (m *MockMustahikRepository) GetDistributedPerCategory(filter *mustahikRepo.DistributionFilter) ([]mustahikRepo.CategoryDistributed, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]mustahikRepo.CategoryDistributed), args.Error(1)
+}
+
+func (m *MockMustahikRepository) GetDistributedPerAsnaf(filter *mustahikRepo.DistributionFilter) ([]mustahikRepo.AsnafDistributed, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]mustahikRepo.AsnafDistributed), args.Error(1)
+}
+
+// MockCategoryRepository mocks the donation category repository methods used by the tests
+type MockCategoryRepository struct {
+	donationCategoryRepo.Repository
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) GetByID(id uint) (*donationCategoryDomain.DonationCategory, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*donationCategoryDomain.DonationCategory), args.Error(1)
+}
+
+func (m *MockCategoryRepository) GetAll() ([]donationCategoryDomain.DonationCategory, error) {
+	args := m.Called()
+	return args.Get(0).([]donationCategoryDomain.DonationCategory), args.Error(1)
+}
+
+// MockDonationRepository mocks the donation repository methods used by the tests
+type MockDonationRepository struct {
+	donationRepo.Repository
+	mock.Mock
+}
+
+func (m *MockDonationRepository) GetAmountPerCategory(filter *donationRepo.Filter) ([]donationRepo.CategoryAmount, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]donationRepo.CategoryAmount), args.Error(1)
+}
+
+func category(id uint, name string, fund donationCategoryDomain.FundType) donationCategoryDomain.DonationCategory {
+	cat := donationCategoryDomain.DonationCategory{Name: name, FundType: fund}
+	cat.ID = id
+	return cat
+}
+
+// TestVerifyMustahik tests that verification records who verified and when,
+// and that resetting to pending clears it
+func TestVerifyMustahik(t *testing.T) {
+	mockRepo := new(MockMustahikRepository)
+	useCase := NewUseCase(mockRepo, nil, nil)
+
+	m := &mustahikDomain.Mustahik{HeadName: "Siti", Asnaf: mustahikDomain.AsnafMiskin, VerificationStatus: mustahikDomain.VerificationPending}
+	mockRepo.On("GetMustahikByID", uint(1)).Return(m, nil)
+	mockRepo.On("UpdateMustahik", m).Return(nil)
+
+	verified, err := useCase.VerifyMustahik(1, &VerifyRequest{Status: mustahikDomain.VerificationVerified}, 9)
+	require.NoError(t, err)
+	assert.Equal(t, mustahikDomain.VerificationVerified, verified.VerificationStatus)
+	require.NotNil(t, verified.VerifiedBy)
+	assert.Equal(t, uint(9), *verified.VerifiedBy)
+	assert.NotNil(t, verified.VerifiedAt)
+
+	pending, err := useCase.VerifyMustahik(1, &VerifyRequest{Status: mustahikDomain.VerificationPending}, 9)
+	require.NoError(t, err)
+	assert.Nil(t, pending.VerifiedBy)
+	assert.Nil(t, pending.VerifiedAt)
+}
+
+// TestCreateDistribution tests that only verified mustahik receive aid and
+// that the balance check of the repository is surfaced
+func TestCreateDistribution(t *testing.T) {
+	mockRepo := new(MockMustahikRepository)
+	mockCategories := new(MockCategoryRepository)
+	useCase := NewUseCase(mockRepo, mockCategories, nil)
+
+	pending := &mustahikDomain.Mustahik{HeadName: "Budi", Asnaf: mustahikDomain.AsnafFakir, VerificationStatus: mustahikDomain.VerificationPending}
+	verified := &mustahikDomain.Mustahik{HeadName: "Siti", Asnaf: mustahikDomain.AsnafMiskin, VerificationStatus: mustahikDomain.VerificationVerified}
+	verified.ID = 2
+	cat := category(5, "Zakat Mal", donationCategoryDomain.FundZakatMal)
+
+	mockRepo.On("GetMustahikByID", uint(1)).Return(pending, nil)
+	mockRepo.On("GetMustahikByID", uint(2)).Return(verified, nil)
+	mockCategories.On("GetByID", uint(5)).Return(&cat, nil)
+
+	_, err := useCase.CreateDistribution(&CreateDistributionRequest{MustahikID: 1, CategoryID: 5, Amount: money.FromRupiah(100000)})
+	assert.EqualError(t, err, "mustahik is not verified")
+
+	_, err = useCase.CreateDistribution(&CreateDistributionRequest{MustahikID: 2, CategoryID: 5, Amount: money.FromRupiah(100000), DistributedOn: "10-04-2025"})
+	assert.EqualError(t, err, "invalid distribution date")
+
+	mockRepo.On("CreateDistribution", mock.MatchedBy(func(d *mustahikDomain.Distribution) bool {
+		return d.Amount == money.FromRupiah(900000)
+	})).Return(errors.New("insufficient category balance")).Once()
+	_, err = useCase.CreateDistribution(&CreateDistributionRequest{MustahikID: 2, CategoryID: 5, Amount: money.FromRupiah(900000)})
+	assert.EqualError(t, err, "insufficient category balance")
+
+	mockRepo.On("CreateDistribution", mock.AnythingOfType("*mustahik.Distribution")).Return(nil).Once()
+	d, err := useCase.CreateDistribution(&CreateDistributionRequest{
+		MustahikID: 2, CategoryID: 5, Amount: money.FromRupiah(100000), DistributedOn: "2025-04-10",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), d.MustahikID)
+	assert.Equal(t, uint(5), d.CategoryID)
+	assert.Equal(t, "2025-04-10", d.DistributedOn.Format(utils.DateLayout))
+	assert.Equal(t, mustahikDomain.AsnafMiskin, d.Asnaf)
+	assert.Equal(t, "Zakat Mal", d.CategoryName)
+}
+
+// TestGetReport_Reconciles tests that opening balance + collected -
+// distributed gives the closing balance per category and fund
+func TestGetReport_Reconciles(t *testing.T) {
+	mockRepo := new(MockMustahikRepository)
+	mockCategories := new(MockCategoryRepository)
+	mockDonations := new(MockDonationRepository)
+	useCase := NewUseCase(mockRepo, mockCategories, mockDonations)
+
+	from, err := utils.ParseDate("2025-04-01")
+	require.NoError(t, err)
+	to, err := utils.ParseDate("2025-04-30")
+	require.NoError(t, err)
+
+	mockCategories.On("GetAll").Return([]donationCategoryDomain.DonationCategory{
+		category(1, "Infaq Umum", donationCategoryDomain.FundInfaq),
+		category(2, "Zakat Fitrah", donationCategoryDomain.FundZakatFitrah),
+	}, nil)
+
+	// Before April
+	mockDonations.On("GetAmountPerCategory", mock.MatchedBy(func(f *donationRepo.Filter) bool {
+		return f.From == nil && f.To != nil && f.To.Equal(from)
+	})).Return([]donationRepo.CategoryAmount{
+		{CategoryID: 1, FundType: "infaq", Amount: money.FromRupiah(1000000)},
+		{CategoryID: 2, FundType: "zakat_fitrah", Amount: money.FromRupiah(500000)},
+	}, nil)
+	mockRepo.On("GetDistributedPerCategory", mock.MatchedBy(func(f *mustahikRepo.DistributionFilter) bool {
+		return f.From == nil && f.To != nil && f.To.Format(utils.DateLayout) == "2025-03-31"
+	})).Return([]mustahikRepo.CategoryDistributed{
+		{CategoryID: 2, Amount: money.FromRupiah(200000)},
+	}, nil)
+
+	// During April, including a deleted category that still has donations
+	mockDonations.On("GetAmountPerCategory", mock.MatchedBy(func(f *donationRepo.Filter) bool {
+		return f.From != nil && f.From.Equal(from) && f.To.Format(utils.DateLayout) == "2025-05-01"
+	})).Return([]donationRepo.CategoryAmount{
+		{CategoryID: 2, FundType: "zakat_fitrah", Amount: money.FromRupiah(300000)},
+		{CategoryID: 7, CategoryName: "Santunan Yatim", FundType: "infaq", Amount: money.FromRupiah(50000)},
+	}, nil)
+	periodFilter := mock.MatchedBy(func(f *mustahikRepo.DistributionFilter) bool {
+		return f.From != nil && f.From.Equal(from) && f.To.Equal(to)
+	})
+	mockRepo.On("GetDistributedPerCategory", periodFilter).Return([]mustahikRepo.CategoryDistributed{
+		{CategoryID: 2, Amount: money.FromRupiah(700000), Distributions: 7},
+	}, nil)
+	mockRepo.On("GetDistributedPerAsnaf", periodFilter).Return([]mustahikRepo.AsnafDistributed{
+		{Asnaf: mustahikDomain.AsnafMiskin, Amount: money.FromRupiah(700000), Distributions: 7, Households: 5},
+	}, nil)
+
+	report, err := useCase.GetReport(&from, &to)
+	require.NoError(t, err)
+
+	require.Len(t, report.Categories, 3)
+	infaq, fitrah, yatim := report.Categories[0], report.Categories[1], report.Categories[2]
+	assert.Equal(t, money.FromRupiah(1000000), infaq.ClosingBalance)
+	assert.Equal(t, money.FromRupiah(300000), fitrah.OpeningBalance)
+	assert.Equal(t, money.FromRupiah(300000), fitrah.Collected)
+	assert.Equal(t, money.FromRupiah(700000), fitrah.Distributed)
+	assert.Equal(t, money.FromRupiah(-100000), fitrah.ClosingBalance)
+	assert.Equal(t, "Santunan Yatim", yatim.CategoryName)
+	assert.Equal(t, money.FromRupiah(50000), yatim.ClosingBalance)
+	assert.Equal(t, []uint{2}, report.Overdrawn)
+
+	require.Len(t, report.Funds, 3)
+	assert.Equal(t, money.FromRupiah(-100000), report.Funds[0].ClosingBalance)
+	assert.Equal(t, money.FromRupiah(1050000), report.Funds[2].ClosingBalance)
+	assert.Equal(t, money.FromRupiah(1300000), report.Total.OpeningBalance)
+	assert.Equal(t, money.FromRupiah(950000), report.Total.ClosingBalance)
+
+	require.Len(t, report.PerAsnaf, 8)
+	assert.Equal(t, mustahikDomain.AsnafFakir, report.PerAsnaf[0].Asnaf)
+	assert.Equal(t, int64(5), report.PerAsnaf[1].Households)
+}
@@ -0,0 +1,550 @@
+package pledge
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	pledgeDomain "github.com/madr/backend/internal/domain/pledge"
+	donationCategoryRepo "github.com/madr/backend/internal/repository/donationcategory"
+	pledgeRepo "github.com/madr/backend/internal/repository/pledge"
+	notifierService "github.com/madr/backend/internal/service/notifier"
+	donationUsecase "github.com/madr/backend/internal/usecase/donation"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/money"
+)
+
+// maxCatchUp bounds the installments generated for one pledge in a run, so a
+// pledge with a start date far in the past cannot flood the donations table
+const maxCatchUp = 60
+
+// UseCase defines the interface for pledge use case
+type UseCase interface {
+	Create(req *CreateRequest) (*pledgeDomain.Pledge, error)
+	GetByID(id uint) (*Detail, error)
+	GetAll(limit, offset int, filter *pledgeRepo.Filter) (*GetAllResponse, error)
+	Update(id uint, req *UpdateRequest) (*pledgeDomain.Pledge, error)
+	Delete(id uint) error
+	GetInstallments(id uint) ([]pledgeDomain.Installment, error)
+
+	RunScheduler(now time.Time) (*RunResult, error)
+}
+
+// DonationRecorder creates the pending donation of each installment
+type DonationRecorder interface {
+	Create(req *donationUsecase.CreateRequest, actor *donationDomain.Actor) (*donationDomain.Donation, error)
+	Delete(id uint, actor *donationDomain.Actor) error
+}
+
+// CreateRequest represents the request to create a pledge
+type CreateRequest struct {
+	DonorName  string                 `json:"donor_name" binding:"required,min=2,max=255"`
+	Phone      *string                `json:"phone" binding:"omitempty,max=30"`
+	Email      *string                `json:"email" binding:"omitempty,email,max=255"`
+	CategoryID uint                   `json:"category_id" binding:"required"`
+	Amount     money.Money            `json:"amount" binding:"required,gt=0"`
+	Frequency  pledgeDomain.Frequency `json:"frequency" binding:"required,oneof=weekly monthly yearly"`
+	StartDate  string                 `json:"start_date"` // YYYY-MM-DD, defaults to today
+	EndDate    *string                `json:"end_date"`   // YYYY-MM-DD, open-ended when empty
+	Notes      string                 `json:"notes"`
+}
+
+// UpdateRequest represents the request to update a pledge. Frequency and
+// start date can only change before the first installment.
+type UpdateRequest struct {
+	DonorName  *string                 `json:"donor_name" binding:"omitempty,min=2,max=255"`
+	Phone      *string                 `json:"phone" binding:"omitempty,max=30"`
+	Email      *string                 `json:"email" binding:"omitempty,email,max=255"`
+	CategoryID *uint                   `json:"category_id"`
+	Amount     *money.Money            `json:"amount" binding:"omitempty,gt=0"`
+	Frequency  *pledgeDomain.Frequency `json:"frequency" binding:"omitempty,oneof=weekly monthly yearly"`
+	StartDate  *string                 `json:"start_date"`
+	EndDate    *string                 `json:"end_date"` // Empty string clears the end date
+	Status     *pledgeDomain.Status    `json:"status" binding:"omitempty,oneof=active paused cancelled"`
+	Notes      *string                 `json:"notes"`
+}
+
+// GetAllResponse represents the response for listing pledges
+type GetAllResponse struct {
+	Data       []pledgeDomain.Pledge `json:"data"`
+	Total      int64                 `json:"total"`
+	Limit      int                   `json:"limit"`
+	Offset     int                   `json:"offset"`
+	TotalPages int                   `json:"total_pages"`
+}
+
+// Detail is a pledge with the summary of its installments
+type Detail struct {
+	pledgeDomain.Pledge
+	Installments []pledgeRepo.InstallmentTotal `json:"installment_summary"`
+}
+
+// RunResult counts what one scheduler run did
+type RunResult struct {
+	Generated int   `json:"generated"` // Installments created
+	Donations int   `json:"donations"` // Pending donations created for installments
+	Completed int   `json:"completed"` // Pledges past their end date
+	Fulfilled int64 `json:"fulfilled"`
+	Missed    int64 `json:"missed"`
+	Reminded  int   `json:"reminded"`
+	Failed    int   `json:"failed"` // Steps that failed and will be retried
+}
+
+type useCase struct {
+	repo       pledgeRepo.Repository
+	categories donationCategoryRepo.Repository
+	recorder   DonationRecorder
+	notifier   notifierService.Notifier
+	graceDays  int
+}
+
+// NewUseCase creates a new pledge use case. An installment still unpaid
+// graceDays after its due date is counted as missed.
+func NewUseCase(repo pledgeRepo.Repository, categories donationCategoryRepo.Repository, recorder DonationRecorder, notifier notifierService.Notifier, graceDays int) UseCase {
+	return &useCase{
+		repo:       repo,
+		categories: categories,
+		recorder:   recorder,
+		notifier:   notifier,
+		graceDays:  graceDays,
+	}
+}
+
+// Create creates a new active pledge. The first installment falls on the start date.
+func (uc *useCase) Create(req *CreateRequest) (*pledgeDomain.Pledge, error) {
+	start := utils.StartOfDay(time.Now())
+	if req.StartDate != "" {
+		date, err := utils.ParseDate(req.StartDate)
+		if err != nil {
+			return nil, errors.New("invalid start date")
+		}
+		start = date
+	}
+	end, err := parseEndDate(req.EndDate, start)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.categories.GetByID(req.CategoryID); err != nil {
+		return nil, err
+	}
+
+	p := &pledgeDomain.Pledge{
+		DonorName:   req.DonorName,
+		Phone:       req.Phone,
+		Email:       req.Email,
+		CategoryID:  req.CategoryID,
+		Amount:      req.Amount,
+		Frequency:   req.Frequency,
+		StartDate:   start,
+		EndDate:     end,
+		Status:      pledgeDomain.StatusActive,
+		NextDueDate: start,
+		Notes:       req.Notes,
+	}
+	if err := uc.repo.Create(p); err != nil {
+		logger.Error().Err(err).Msg("Failed to create pledge")
+		return nil, errors.New("failed to create pledge")
+	}
+
+	logger.Info().
+		Uint("id", p.ID).
+		Str("frequency", string(p.Frequency)).
+		Str("amount", p.Amount.String()).
+		Msg("Pledge created successfully")
+	return p, nil
+}
+
+// GetByID retrieves a pledge with its installment summary
+func (uc *useCase) GetByID(id uint) (*Detail, error) {
+	p, err := uc.repo.GetByID(id)
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to get pledge")
+		return nil, err
+	}
+
+	totals, err := uc.repo.GetInstallmentTotals(id)
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to get pledge installment totals")
+		return nil, errors.New("failed to get pledge")
+	}
+
+	detail := &Detail{Pledge: *p, Installments: make([]pledgeRepo.InstallmentTotal, 0, 3)}
+	for _, status := range []pledgeDomain.InstallmentStatus{
+		pledgeDomain.InstallmentFulfilled, pledgeDomain.InstallmentPending, pledgeDomain.InstallmentMissed,
+	} {
+		total := pledgeRepo.InstallmentTotal{Status: status}
+		for _, t := range totals {
+			if t.Status == status {
+				total = t
+			}
+		}
+		detail.Installments = append(detail.Installments, total)
+	}
+	return detail, nil
+}
+
+// GetAll retrieves pledges with pagination
+func (uc *useCase) GetAll(limit, offset int, filter *pledgeRepo.Filter) (*GetAllResponse, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	pledges, total, err := uc.repo.GetAll(limit, offset, filter)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get pledges")
+		return nil, errors.New("failed to get pledges")
+	}
+
+	return &GetAllResponse{
+		Data:       pledges,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, nil
+}
+
+// Update updates a pledge. Changes to the amount apply to installments not
+// generated yet. Resuming a paused pledge skips the due dates that passed
+// while it was paused.
+func (uc *useCase) Update(id uint, req *UpdateRequest) (*pledgeDomain.Pledge, error) {
+	p, err := uc.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	normalizeDates(p)
+	if p.Status == pledgeDomain.StatusCancelled || p.Status == pledgeDomain.StatusCompleted {
+		return nil, errors.New("pledge is closed")
+	}
+
+	if req.Frequency != nil || req.StartDate != nil {
+		if p.InstallmentsGenerated > 0 {
+			return nil, errors.New("pledge schedule cannot change after installments")
+		}
+		if req.Frequency != nil {
+			p.Frequency = *req.Frequency
+		}
+		if req.StartDate != nil {
+			start, err := utils.ParseDate(*req.StartDate)
+			if err != nil {
+				return nil, errors.New("invalid start date")
+			}
+			p.StartDate = start
+		}
+		p.NextDueDate = p.StartDate
+	}
+	if req.EndDate != nil {
+		end, err := parseEndDate(req.EndDate, p.StartDate)
+		if err != nil {
+			return nil, err
+		}
+		p.EndDate = end
+	}
+	if req.CategoryID != nil {
+		if _, err := uc.categories.GetByID(*req.CategoryID); err != nil {
+			return nil, err
+		}
+		p.CategoryID = *req.CategoryID
+	}
+	if req.DonorName != nil {
+		p.DonorName = *req.DonorName
+	}
+	if req.Phone != nil {
+		p.Phone = emptyToNil(*req.Phone)
+	}
+	if req.Email != nil {
+		p.Email = emptyToNil(*req.Email)
+	}
+	if req.Amount != nil {
+		p.Amount = *req.Amount
+	}
+	if req.Notes != nil {
+		p.Notes = *req.Notes
+	}
+	if req.Status != nil {
+		if p.Status == pledgeDomain.StatusPaused && *req.Status == pledgeDomain.StatusActive {
+			skipPassedDueDates(p, utils.StartOfDay(time.Now()))
+		}
+		p.Status = *req.Status
+	}
+
+	if err := uc.repo.Update(p); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to update pledge")
+		return nil, errors.New("failed to update pledge")
+	}
+
+	logger.Info().Uint("id", id).Str("status", string(p.Status)).Msg("Pledge updated successfully")
+	return p, nil
+}
+
+// Delete deletes a pledge that has no installments. Pledges with history are
+// cancelled instead so the commitment history is kept.
+func (uc *useCase) Delete(id uint) error {
+	if _, err := uc.repo.GetByID(id); err != nil {
+		return err
+	}
+
+	count, err := uc.repo.CountInstallments(id)
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to count pledge installments")
+		return errors.New("failed to delete pledge")
+	}
+	if count > 0 {
+		return errors.New("pledge has installments")
+	}
+
+	if err := uc.repo.Delete(id); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to delete pledge")
+		return errors.New("failed to delete pledge")
+	}
+
+	logger.Info().Uint("id", id).Msg("Pledge deleted successfully")
+	return nil
+}
+
+// GetInstallments retrieves the commitment history of a pledge
+func (uc *useCase) GetInstallments(id uint) ([]pledgeDomain.Installment, error) {
+	if _, err := uc.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	installments, err := uc.repo.GetInstallments(id)
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to get pledge installments")
+		return nil, errors.New("failed to get pledge installments")
+	}
+	if installments == nil {
+		installments = []pledgeDomain.Installment{}
+	}
+	return installments, nil
+}
+
+// RunScheduler generates the installments due by now with a pending donation
+// each, refreshes fulfilled and missed installments, and reminds donors of
+// new installments. Each step is idempotent, so a failed step is retried by
+// the next run.
+func (uc *useCase) RunScheduler(now time.Time) (*RunResult, error) {
+	today := utils.StartOfDay(now)
+	result := &RunResult{}
+
+	due, err := uc.repo.GetDue(today)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get due pledges")
+		return nil, errors.New("failed to run pledge scheduler")
+	}
+	for i := range due {
+		uc.generate(&due[i], today, result)
+	}
+
+	uc.createDonations(result)
+
+	missedBefore := today.AddDate(0, 0, -uc.graceDays)
+	fulfilled, missed, err := uc.repo.RefreshInstallmentStatuses(missedBefore)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to refresh pledge installment statuses")
+		result.Failed++
+	}
+	result.Fulfilled, result.Missed = fulfilled, missed
+
+	uc.sendReminders(now, result)
+
+	logger.Info().
+		Int("generated", result.Generated).
+		Int("donations", result.Donations).
+		Int64("fulfilled", result.Fulfilled).
+		Int64("missed", result.Missed).
+		Int("reminded", result.Reminded).
+		Int("failed", result.Failed).
+		Msg("Pledge scheduler run finished")
+	return result, nil
+}
+
+// generate claims every installment of p due by today
+func (uc *useCase) generate(p *pledgeDomain.Pledge, today time.Time, result *RunResult) {
+	normalizeDates(p)
+	for n := 0; n < maxCatchUp && !p.NextDueDate.After(today); n++ {
+		if pastEnd(p) {
+			break
+		}
+		sequence := p.InstallmentsGenerated + 1
+		next := p.Frequency.DueDate(p.StartDate, sequence+1)
+		inst := &pledgeDomain.Installment{
+			PledgeID: p.ID,
+			Sequence: sequence,
+			DueDate:  p.NextDueDate,
+			Amount:   p.Amount,
+			Status:   pledgeDomain.InstallmentPending,
+		}
+
+		claimed, err := uc.repo.ClaimInstallment(p, inst, next)
+		if err != nil {
+			logger.Error().Err(err).Uint("pledge_id", p.ID).Int("sequence", sequence).Msg("Failed to claim pledge installment")
+			result.Failed++
+			return
+		}
+		if !claimed {
+			// Another run got there first or the pledge changed meanwhile
+			return
+		}
+		p.InstallmentsGenerated = sequence
+		p.NextDueDate = next
+		result.Generated++
+	}
+
+	if pastEnd(p) {
+		if err := uc.repo.Complete(p.ID); err != nil {
+			logger.Error().Err(err).Uint("pledge_id", p.ID).Msg("Failed to complete pledge")
+			result.Failed++
+			return
+		}
+		result.Completed++
+	}
+}
+
+// createDonations creates the pending donation of every installment without one
+func (uc *useCase) createDonations(result *RunResult) {
+	installments, err := uc.repo.GetInstallmentsWithoutDonation()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get pledge installments without donation")
+		result.Failed++
+		return
+	}
+
+	pledges := map[uint]*pledgeDomain.Pledge{}
+	for _, inst := range installments {
+		p, ok := pledges[inst.PledgeID]
+		if !ok {
+			p, err = uc.repo.GetByID(inst.PledgeID)
+			if err != nil {
+				logger.Error().Err(err).Uint("pledge_id", inst.PledgeID).Msg("Failed to get pledge")
+				result.Failed++
+				continue
+			}
+			pledges[inst.PledgeID] = p
+		}
+
+		donorName := p.DonorName
+		don, err := uc.recorder.Create(&donationUsecase.CreateRequest{
+			CategoryID:    p.CategoryID,
+			DonorName:     &donorName,
+			Amount:        inst.Amount,
+			Message:       fmt.Sprintf("Komitmen donasi #%d - angsuran ke-%d (%s)", p.ID, inst.Sequence, utils.AsDate(inst.DueDate).Format(utils.DateLayout)),
+			PaymentStatus: string(donationDomain.PaymentStatusPending),
+		}, nil)
+		if err != nil {
+			logger.Error().Err(err).Uint("installment_id", inst.ID).Msg("Failed to create pledge donation")
+			result.Failed++
+			continue
+		}
+
+		attached, err := uc.repo.AttachDonation(inst.ID, don.ID)
+		if err != nil || !attached {
+			// Keep a single donation per installment
+			if err != nil {
+				logger.Error().Err(err).Uint("installment_id", inst.ID).Msg("Failed to attach pledge donation")
+				result.Failed++
+			}
+			if delErr := uc.recorder.Delete(don.ID, nil); delErr != nil {
+				logger.Error().Err(delErr).Uint("donation_id", don.ID).Msg("Failed to remove unattached pledge donation")
+			}
+			continue
+		}
+		result.Donations++
+	}
+}
+
+// sendReminders notifies donors of pending installments once. A failed send
+// is retried on the next run.
+func (uc *useCase) sendReminders(now time.Time, result *RunResult) {
+	reminders, err := uc.repo.GetReminderDue()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get pledge reminders")
+		result.Failed++
+		return
+	}
+
+	for _, r := range reminders {
+		if err := uc.notifier.Send(reminderMessage(&r)); err != nil {
+			logger.Warn().Err(err).Uint("installment_id", r.InstallmentID).Str("notifier", uc.notifier.Name()).Msg("Failed to send pledge reminder")
+			result.Failed++
+			continue
+		}
+		if err := uc.repo.MarkReminded(r.InstallmentID, now); err != nil {
+			logger.Error().Err(err).Uint("installment_id", r.InstallmentID).Msg("Failed to mark pledge reminder as sent")
+			result.Failed++
+			continue
+		}
+		result.Reminded++
+	}
+}
+
+// reminderMessage builds the reminder of one installment
+func reminderMessage(r *pledgeRepo.ReminderDue) *notifierService.Message {
+	msg := &notifierService.Message{
+		Name:    r.DonorName,
+		Subject: "Pengingat komitmen donasi",
+		Body: fmt.Sprintf(
+			"Assalamu'alaikum %s, ini pengingat komitmen donasi %s sebesar Rp %s untuk angsuran ke-%d yang jatuh tempo %s (donasi #%d). Jazakumullahu khairan.",
+			r.DonorName, r.CategoryName, r.Amount.Compact(), r.Sequence,
+			utils.AsDate(r.DueDate).Format(utils.DateLayout), r.DonationID,
+		),
+	}
+	if r.Phone != nil {
+		msg.Phone = *r.Phone
+	}
+	if r.Email != nil {
+		msg.Email = *r.Email
+	}
+	return msg
+}
+
+// skipPassedDueDates moves the schedule to the first due date on or after today
+func skipPassedDueDates(p *pledgeDomain.Pledge, today time.Time) {
+	for p.NextDueDate.Before(today) {
+		p.InstallmentsGenerated++
+		p.NextDueDate = p.Frequency.DueDate(p.StartDate, p.InstallmentsGenerated+1)
+	}
+}
+
+// normalizeDates reads the DATE columns of p as Jakarta dates
+func normalizeDates(p *pledgeDomain.Pledge) {
+	p.StartDate = utils.AsDate(p.StartDate)
+	p.NextDueDate = utils.AsDate(p.NextDueDate)
+	if p.EndDate != nil {
+		end := utils.AsDate(*p.EndDate)
+		p.EndDate = &end
+	}
+}
+
+func pastEnd(p *pledgeDomain.Pledge) bool {
+	return p.EndDate != nil && p.NextDueDate.After(*p.EndDate)
+}
+
+func parseEndDate(raw *string, start time.Time) (*time.Time, error) {
+	if raw == nil || *raw == "" {
+		return nil, nil
+	}
+	end, err := utils.ParseDate(*raw)
+	if err != nil {
+		return nil, errors.New("invalid end date")
+	}
+	if end.Before(start) {
+		return nil, errors.New("end date is before start date")
+	}
+	return &end, nil
+}
+
+func emptyToNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,273 @@
+package pledge
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	pledgeDomain "github.com/madr/backend/internal/domain/pledge"
+	pledgeRepo "github.com/madr/backend/internal/repository/pledge"
+	notifierService "github.com/madr/backend/internal/service/notifier"
+	donationUsecase "github.com/madr/backend/internal/usecase/donation"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPledgeRepository mocks the pledge repository methods used by the tests
+type MockPledgeRepository struct {
+	pledgeRepo.Repository
+	mock.Mock
+}
+
+func (m *MockPledgeRepository) GetByID(id uint) (*pledgeDomain.Pledge, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pledgeDomain.Pledge), args.Error(1)
+}
+
+func (m *MockPledgeRepository) Update(p *pledgeDomain.Pledge) error {
+	args := m.Called(p)
+	return args.Error(0)
+}
+
+func (m *MockPledgeRepository) GetDue(today time.Time) ([]pledgeDomain.Pledge, error) {
+	args := m.Called(today)
+	return args.Get(0).([]pledgeDomain.Pledge), args.Error(1)
+}
+
+func (m *MockPledgeRepository) ClaimInstallment(p *pledgeDomain.Pledge, inst *pledgeDomain.Installment, nextDue time.Time) (bool, error) {
+	args := m.Called(p, inst, nextDue)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPledgeRepository) Complete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPledgeRepository) GetInstallmentsWithoutDonation() ([]pledgeDomain.Installment, error) {
+	args := m.Called()
+	return args.Get(0).([]pledgeDomain.Installment), args.Error(1)
+}
+
+func (m *MockPledgeRepository) AttachDonation(installmentID, donationID uint) (bool, error) {
+	args := m.Called(installmentID, donationID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPledgeRepository) RefreshInstallmentStatuses(missedBefore time.Time) (int64, int64, error) {
+	args := m.Called(missedBefore)
+	return args.Get(0).(int64), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPledgeRepository) GetReminderDue() ([]pledgeRepo.ReminderDue, error) {
+	args := m.Called()
+	return args.Get(0).([]pledgeRepo.ReminderDue), args.Error(1)
+}
+
+func (m *MockPledgeRepository) MarkReminded(installmentID uint, at time.Time) error {
+	args := m.Called(installmentID, at)
+	return args.Error(0)
+}
+
+// MockRecorder mocks the donation recorder
+type MockRecorder struct {
+	mock.Mock
+}
+
+func (m *MockRecorder) Create(req *donationUsecase.CreateRequest, _ *donationDomain.Actor) (*donationDomain.Donation, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*donationDomain.Donation), args.Error(1)
+}
+
+func (m *MockRecorder) Delete(id uint, _ *donationDomain.Actor) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// MockNotifier records the messages it is asked to send
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Name() string {
+	return "mock"
+}
+
+func (m *MockNotifier) Send(msg *notifierService.Message) error {
+	args := m.Called(msg)
+	return args.Error(0)
+}
+
+func mustDate(t *testing.T, s string) time.Time {
+	t.Helper()
+	date, err := utils.ParseDate(s)
+	require.NoError(t, err)
+	return date
+}
+
+// TestFrequencyDueDate tests that monthly dates clamp to short months
+// without drifting away from the start day
+func TestFrequencyDueDate(t *testing.T) {
+	start := mustDate(t, "2025-01-31")
+
+	assert.Equal(t, "2025-01-31", pledgeDomain.FrequencyMonthly.DueDate(start, 1).Format(utils.DateLayout))
+	assert.Equal(t, "2025-02-28", pledgeDomain.FrequencyMonthly.DueDate(start, 2).Format(utils.DateLayout))
+	assert.Equal(t, "2025-03-31", pledgeDomain.FrequencyMonthly.DueDate(start, 3).Format(utils.DateLayout))
+	assert.Equal(t, "2025-02-14", pledgeDomain.FrequencyWeekly.DueDate(start, 3).Format(utils.DateLayout))
+	assert.Equal(t, "2028-02-29", pledgeDomain.FrequencyYearly.DueDate(mustDate(t, "2024-02-29"), 5).Format(utils.DateLayout))
+}
+
+// TestRunScheduler tests that a run catches up on due installments, stops
+// at the end date, creates their pending donations and sends reminders
+func TestRunScheduler(t *testing.T) {
+	mockRepo := new(MockPledgeRepository)
+	mockRecorder := new(MockRecorder)
+	mockNotifier := new(MockNotifier)
+	useCase := NewUseCase(mockRepo, nil, mockRecorder, mockNotifier, 7)
+
+	now := time.Date(2025, 3, 20, 10, 0, 0, 0, utils.Jakarta)
+	today := mustDate(t, "2025-03-20")
+
+	// Weekly from 1 March until 15 March; DATE columns come back as UTC midnight
+	end := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	p := pledgeDomain.Pledge{
+		DonorName:   "Ahmad",
+		CategoryID:  3,
+		Amount:      money.FromRupiah(50000),
+		Frequency:   pledgeDomain.FrequencyWeekly,
+		StartDate:   time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     &end,
+		Status:      pledgeDomain.StatusActive,
+		NextDueDate: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	p.ID = 4
+	mockRepo.On("GetDue", today).Return([]pledgeDomain.Pledge{p}, nil)
+
+	var claimed []string
+	mockRepo.On("ClaimInstallment", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			inst := args.Get(1).(*pledgeDomain.Installment)
+			claimed = append(claimed, inst.DueDate.Format(utils.DateLayout))
+		}).
+		Return(true, nil)
+	mockRepo.On("Complete", uint(4)).Return(nil)
+
+	// Two installments still waiting for a donation; the second was
+	// attached by a concurrent run
+	stored := p
+	mockRepo.On("GetByID", uint(4)).Return(&stored, nil)
+	mockRepo.On("GetInstallmentsWithoutDonation").Return([]pledgeDomain.Installment{
+		{ID: 11, PledgeID: 4, Sequence: 1, DueDate: mustDate(t, "2025-03-01"), Amount: money.FromRupiah(50000)},
+		{ID: 12, PledgeID: 4, Sequence: 2, DueDate: mustDate(t, "2025-03-08"), Amount: money.FromRupiah(50000)},
+	}, nil)
+	first := &donationDomain.Donation{}
+	first.ID = 101
+	second := &donationDomain.Donation{}
+	second.ID = 102
+	mockRecorder.On("Create", mock.MatchedBy(func(req *donationUsecase.CreateRequest) bool {
+		return req.PaymentStatus == "pending" && req.CategoryID == 3 && *req.DonorName == "Ahmad"
+	})).Return(first, nil).Once()
+	mockRecorder.On("Create", mock.Anything).Return(second, nil).Once()
+	mockRepo.On("AttachDonation", uint(11), uint(101)).Return(true, nil)
+	mockRepo.On("AttachDonation", uint(12), uint(102)).Return(false, nil)
+	mockRecorder.On("Delete", uint(102)).Return(nil)
+
+	mockRepo.On("RefreshInstallmentStatuses", mustDate(t, "2025-03-13")).Return(int64(1), int64(2), nil)
+
+	phone := "08123456789"
+	mockRepo.On("GetReminderDue").Return([]pledgeRepo.ReminderDue{
+		{InstallmentID: 11, Sequence: 1, DonationID: 101, DonorName: "Ahmad", Phone: &phone, Amount: money.FromRupiah(50000), DueDate: mustDate(t, "2025-03-01")},
+		{InstallmentID: 13, Sequence: 3, DonationID: 103, DonorName: "Ahmad", Phone: &phone, Amount: money.FromRupiah(50000), DueDate: mustDate(t, "2025-03-15")},
+	}, nil)
+	mockNotifier.On("Send", mock.MatchedBy(func(msg *notifierService.Message) bool {
+		return msg.Phone == phone && msg.Body != "" && msg.Name == "Ahmad"
+	})).Return(nil).Once()
+	mockNotifier.On("Send", mock.Anything).Return(errors.New("gateway down")).Once()
+	mockRepo.On("MarkReminded", uint(11), now).Return(nil)
+
+	result, err := useCase.RunScheduler(now)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2025-03-01", "2025-03-08", "2025-03-15"}, claimed)
+	assert.Equal(t, 3, result.Generated)
+	assert.Equal(t, 1, result.Completed)
+	assert.Equal(t, 1, result.Donations)
+	assert.Equal(t, int64(1), result.Fulfilled)
+	assert.Equal(t, int64(2), result.Missed)
+	assert.Equal(t, 1, result.Reminded)
+	assert.Equal(t, 1, result.Failed)
+	mockRepo.AssertNotCalled(t, "MarkReminded", uint(13), now)
+	mockRecorder.AssertExpectations(t)
+}
+
+// TestRunScheduler_LostClaim tests that a run stops generating for a pledge
+// once another run claimed its next installment
+func TestRunScheduler_LostClaim(t *testing.T) {
+	mockRepo := new(MockPledgeRepository)
+	useCase := NewUseCase(mockRepo, nil, new(MockRecorder), new(MockNotifier), 7)
+
+	now := time.Date(2025, 3, 20, 10, 0, 0, 0, utils.Jakarta)
+	p := pledgeDomain.Pledge{
+		Frequency:   pledgeDomain.FrequencyMonthly,
+		StartDate:   mustDate(t, "2025-01-10"),
+		Status:      pledgeDomain.StatusActive,
+		NextDueDate: mustDate(t, "2025-01-10"),
+	}
+	p.ID = 1
+	mockRepo.On("GetDue", mustDate(t, "2025-03-20")).Return([]pledgeDomain.Pledge{p}, nil)
+	mockRepo.On("ClaimInstallment", mock.Anything, mock.Anything, mock.Anything).Return(false, nil).Once()
+	mockRepo.On("GetInstallmentsWithoutDonation").Return([]pledgeDomain.Installment{}, nil)
+	mockRepo.On("RefreshInstallmentStatuses", mock.Anything).Return(int64(0), int64(0), nil)
+	mockRepo.On("GetReminderDue").Return([]pledgeRepo.ReminderDue{}, nil)
+
+	result, err := useCase.RunScheduler(now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Generated)
+	mockRepo.AssertNumberOfCalls(t, "ClaimInstallment", 1)
+	mockRepo.AssertNotCalled(t, "Complete", uint(1))
+}
+
+// TestUpdate tests the schedule and status rules of pledge updates
+func TestUpdate(t *testing.T) {
+	mockRepo := new(MockPledgeRepository)
+	useCase := NewUseCase(mockRepo, nil, nil, nil, 7)
+
+	started := &pledgeDomain.Pledge{
+		Frequency:             pledgeDomain.FrequencyMonthly,
+		StartDate:             mustDate(t, "2024-01-15"),
+		Status:                pledgeDomain.StatusPaused,
+		InstallmentsGenerated: 2,
+		NextDueDate:           mustDate(t, "2024-03-15"),
+	}
+	mockRepo.On("GetByID", uint(1)).Return(started, nil)
+	mockRepo.On("Update", started).Return(nil)
+
+	weekly := pledgeDomain.FrequencyWeekly
+	_, err := useCase.Update(1, &UpdateRequest{Frequency: &weekly})
+	assert.EqualError(t, err, "pledge schedule cannot change after installments")
+
+	// Resuming skips the due dates missed while paused
+	active := pledgeDomain.StatusActive
+	p, err := useCase.Update(1, &UpdateRequest{Status: &active})
+	require.NoError(t, err)
+	today := utils.StartOfDay(time.Now())
+	assert.Equal(t, pledgeDomain.StatusActive, p.Status)
+	assert.False(t, p.NextDueDate.Before(today))
+	assert.True(t, p.NextDueDate.Before(today.AddDate(0, 1, 1)))
+	assert.Equal(t, p.Frequency.DueDate(p.StartDate, p.InstallmentsGenerated+1), p.NextDueDate)
+
+	closed := &pledgeDomain.Pledge{Status: pledgeDomain.StatusCancelled}
+	mockRepo.On("GetByID", uint(2)).Return(closed, nil)
+	_, err = useCase.Update(2, &UpdateRequest{Status: &active})
+	assert.EqualError(t, err, "pledge is closed")
+}
@@ -0,0 +1,133 @@
+package qris
+
+import (
+	"errors"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	donationCategoryRepo "github.com/madr/backend/internal/repository/donationcategory"
+	donationUsecase "github.com/madr/backend/internal/usecase/donation"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/money"
+	"github.com/madr/backend/pkg/qris"
+)
+
+// ProviderName is stored as the payment provider of QRIS donations
+const ProviderName = "qris"
+
+// UseCase defines the interface for QRIS donation use case
+type UseCase interface {
+	Generate(req *GenerateRequest) (*GenerateResponse, error)
+	GetPayload(reference string) (string, error)
+}
+
+// GenerateRequest represents the request to generate a dynamic QRIS
+type GenerateRequest struct {
+	CategoryID uint        `json:"category_id" binding:"required"`
+	DonorName  *string     `json:"donor_name"`
+	Amount     money.Money `json:"amount" binding:"required,gt=0"`
+	Message    string      `json:"message"`
+	Anonymous  bool        `json:"anonymous"` // Do not link the donation to the signed-in donor
+	UserID     *uint       `json:"-"`         // Signed-in donor, set by the handler
+}
+
+// GenerateResponse represents the generated QRIS linked to a pending donation
+type GenerateResponse struct {
+	Donation  *donationDomain.Donation `json:"donation"`
+	Reference string                   `json:"reference"`
+	Payload   string                   `json:"qris"`
+}
+
+// DonationCreator creates the pending donation a QRIS is paid to
+type DonationCreator interface {
+	CreatePending(req *donationUsecase.CheckoutRequest, provider, reference string) (*donationDomain.Donation, error)
+}
+
+type useCase struct {
+	donations       DonationCreator
+	donationRepo    donationRepo.Repository
+	categoryRepo    donationCategoryRepo.Repository
+	merchantPayload string
+}
+
+// NewUseCase creates a new QRIS use case from the static merchant QRIS
+func NewUseCase(donations DonationCreator, donationRepoInstance donationRepo.Repository, categoryRepoInstance donationCategoryRepo.Repository, merchantPayload string) UseCase {
+	return &useCase{
+		donations:       donations,
+		donationRepo:    donationRepoInstance,
+		categoryRepo:    categoryRepoInstance,
+		merchantPayload: merchantPayload,
+	}
+}
+
+// Generate creates a pending donation and its dynamic QRIS payload
+func (uc *useCase) Generate(req *GenerateRequest) (*GenerateResponse, error) {
+	if uc.merchantPayload == "" {
+		logger.Error().Msg("QRIS merchant payload is not configured")
+		return nil, errors.New("QRIS is not configured")
+	}
+
+	if _, err := uc.categoryRepo.GetByID(req.CategoryID); err != nil {
+		return nil, err
+	}
+
+	reference := donationUsecase.GeneratePaymentReference()
+	payload, err := qris.Dynamic(uc.merchantPayload, req.Amount, reference)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to generate dynamic QRIS")
+		return nil, errors.New("failed to generate QRIS")
+	}
+
+	don, err := uc.donations.CreatePending(&donationUsecase.CheckoutRequest{
+		CategoryID: req.CategoryID,
+		DonorName:  req.DonorName,
+		Amount:     req.Amount,
+		Message:    req.Message,
+		Anonymous:  req.Anonymous,
+		UserID:     req.UserID,
+	}, ProviderName, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info().
+		Uint("id", don.ID).
+		Uint("category_id", don.CategoryID).
+		Str("amount", don.Amount.String()).
+		Str("payment_reference", reference).
+		Msg("QRIS donation created")
+
+	return &GenerateResponse{
+		Donation:  don,
+		Reference: reference,
+		Payload:   payload,
+	}, nil
+}
+
+// GetPayload regenerates the QRIS payload of a pending QRIS donation
+func (uc *useCase) GetPayload(reference string) (string, error) {
+	if uc.merchantPayload == "" {
+		return "", errors.New("QRIS is not configured")
+	}
+
+	don, err := uc.donationRepo.GetByPaymentReference(reference)
+	if err != nil {
+		return "", err
+	}
+
+	if don.PaymentProvider == nil || *don.PaymentProvider != ProviderName {
+		return "", errors.New("donation not found")
+	}
+
+	if don.PaymentStatus != donationDomain.PaymentStatusPending {
+		return "", errors.New("donation is no longer pending")
+	}
+
+	payload, err := qris.Dynamic(uc.merchantPayload, don.Amount, reference)
+	if err != nil {
+		logger.Error().Err(err).Str("reference", reference).Msg("Failed to generate dynamic QRIS")
+		return "", errors.New("failed to generate QRIS")
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,143 @@
+package qris
+
+import (
+	"errors"
+	"testing"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	donationCategoryDomain "github.com/madr/backend/internal/domain/donationcategory"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	donationCategoryRepo "github.com/madr/backend/internal/repository/donationcategory"
+	donationUsecase "github.com/madr/backend/internal/usecase/donation"
+	"github.com/madr/backend/pkg/money"
+	"github.com/madr/backend/pkg/qris"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// staticQRIS is a static merchant QRIS used as the base payload
+const staticQRIS = "00020101021126660014ID.CO.QRIS.WWW01189360091100123456780215ID10200012345670303UMI51440014ID.CO.QRIS.WWW0215ID10200012345670303UMI5204866153033605802ID5916MASJID AL IKHLAS6015JAKARTA SELATAN61051234562070703A016304A1EC"
+
+// MockDonationRepository mocks the donation repository methods used by QRIS
+type MockDonationRepository struct {
+	donationRepo.Repository
+	mock.Mock
+}
+
+func (m *MockDonationRepository) Create(don *donationDomain.Donation) error {
+	args := m.Called(don)
+	return args.Error(0)
+}
+
+func (m *MockDonationRepository) Audited(fn func(tx donationRepo.Repository) (*donationDomain.AuditLog, error)) error {
+	_, err := fn(m)
+	return err
+}
+
+func (m *MockDonationRepository) GetByPaymentReference(reference string) (*donationDomain.Donation, error) {
+	args := m.Called(reference)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*donationDomain.Donation), args.Error(1)
+}
+
+// MockCategoryRepository mocks the donation category repository methods used by QRIS
+type MockCategoryRepository struct {
+	donationCategoryRepo.Repository
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) GetByID(id uint) (*donationCategoryDomain.DonationCategory, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*donationCategoryDomain.DonationCategory), args.Error(1)
+}
+
+// TestGenerate_Success tests that a pending donation is created with a matching dynamic QRIS
+func TestGenerate_Success(t *testing.T) {
+	mockDonations := new(MockDonationRepository)
+	mockCategories := new(MockCategoryRepository)
+
+	mockCategories.On("GetByID", uint(1)).Return(&donationCategoryDomain.DonationCategory{Name: "Infaq"}, nil)
+	mockDonations.On("Create", mock.AnythingOfType("*donation.Donation")).Return(nil)
+
+	uc := NewUseCase(donationUsecase.NewUseCase(mockDonations, nil, nil, nil, nil), mockDonations, mockCategories, staticQRIS)
+	resp, err := uc.Generate(&GenerateRequest{CategoryID: 1, Amount: money.FromRupiah(50000)})
+	require.NoError(t, err)
+
+	assert.Equal(t, donationDomain.PaymentStatusPending, resp.Donation.PaymentStatus)
+	require.NotNil(t, resp.Donation.PaymentProvider)
+	assert.Equal(t, ProviderName, *resp.Donation.PaymentProvider)
+	require.NotNil(t, resp.Donation.PaymentReference)
+	assert.Equal(t, resp.Reference, *resp.Donation.PaymentReference)
+
+	p, err := qris.Decode(resp.Payload)
+	require.NoError(t, err)
+	assert.True(t, p.IsDynamic())
+
+	amount, ok := p.Amount()
+	assert.True(t, ok)
+	assert.Equal(t, money.FromRupiah(50000), amount)
+
+	ref, ok := p.ReferenceLabel()
+	assert.True(t, ok)
+	assert.Equal(t, resp.Reference, ref)
+
+	mockDonations.AssertExpectations(t)
+	mockCategories.AssertExpectations(t)
+}
+
+// TestGenerate_CategoryNotFound tests that unknown categories are rejected
+func TestGenerate_CategoryNotFound(t *testing.T) {
+	mockDonations := new(MockDonationRepository)
+	mockCategories := new(MockCategoryRepository)
+
+	mockCategories.On("GetByID", uint(99)).Return(nil, errors.New("donation category not found"))
+
+	uc := NewUseCase(donationUsecase.NewUseCase(mockDonations, nil, nil, nil, nil), mockDonations, mockCategories, staticQRIS)
+	_, err := uc.Generate(&GenerateRequest{CategoryID: 99, Amount: money.FromRupiah(50000)})
+	assert.EqualError(t, err, "donation category not found")
+
+	mockDonations.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestGenerate_NotConfigured tests that QRIS is unavailable without a merchant payload
+func TestGenerate_NotConfigured(t *testing.T) {
+	uc := NewUseCase(nil, new(MockDonationRepository), new(MockCategoryRepository), "")
+	_, err := uc.Generate(&GenerateRequest{CategoryID: 1, Amount: money.FromRupiah(50000)})
+	assert.EqualError(t, err, "QRIS is not configured")
+}
+
+// TestGetPayload tests regenerating the payload of stored donations
+func TestGetPayload(t *testing.T) {
+	provider := ProviderName
+	other := "midtrans"
+	mockDonations := new(MockDonationRepository)
+	mockDonations.On("GetByPaymentReference", "DON-PENDING").Return(&donationDomain.Donation{
+		Amount: money.FromRupiah(25000), PaymentStatus: donationDomain.PaymentStatusPending, PaymentProvider: &provider,
+	}, nil)
+	mockDonations.On("GetByPaymentReference", "DON-PAID").Return(&donationDomain.Donation{
+		Amount: money.FromRupiah(25000), PaymentStatus: donationDomain.PaymentStatusSuccess, PaymentProvider: &provider,
+	}, nil)
+	mockDonations.On("GetByPaymentReference", "DON-MIDTRANS").Return(&donationDomain.Donation{
+		Amount: money.FromRupiah(25000), PaymentStatus: donationDomain.PaymentStatusPending, PaymentProvider: &other,
+	}, nil)
+
+	uc := NewUseCase(nil, mockDonations, new(MockCategoryRepository), staticQRIS)
+
+	payload, err := uc.GetPayload("DON-PENDING")
+	require.NoError(t, err)
+	expected, err := qris.Dynamic(staticQRIS, money.FromRupiah(25000), "DON-PENDING")
+	require.NoError(t, err)
+	assert.Equal(t, expected, payload)
+
+	_, err = uc.GetPayload("DON-PAID")
+	assert.EqualError(t, err, "donation is no longer pending")
+
+	_, err = uc.GetPayload("DON-MIDTRANS")
+	assert.EqualError(t, err, "donation not found")
+}
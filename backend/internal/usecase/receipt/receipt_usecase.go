@@ -0,0 +1,216 @@
+package receipt
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	receiptDomain "github.com/madr/backend/internal/domain/receipt"
+	aboutRepo "github.com/madr/backend/internal/repository/about"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	receiptRepo "github.com/madr/backend/internal/repository/receipt"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// defaultMosqueName is printed when no about record exists yet
+const defaultMosqueName = "Masjid"
+
+// UseCase defines the interface for donation receipt use case
+type UseCase interface {
+	Issue(donationID uint) (*receiptDomain.Receipt, error)
+	GetPDF(donationID uint) (*File, error)
+	GetPDFByReference(donationID uint, reference string) (*File, error)
+}
+
+// Storage persists generated receipt PDFs
+type Storage interface {
+	Save(fileName string, content []byte) error
+	Read(fileName string) ([]byte, error)
+}
+
+// File is a rendered receipt ready to be served
+type File struct {
+	Receipt *receiptDomain.Receipt
+	Content []byte
+}
+
+type useCase struct {
+	receiptRepo  receiptRepo.Repository
+	donationRepo donationRepo.Repository
+	aboutRepo    aboutRepo.Repository
+	storage      Storage
+}
+
+// NewUseCase creates a new donation receipt use case
+func NewUseCase(receiptRepoInstance receiptRepo.Repository, donationRepoInstance donationRepo.Repository, aboutRepoInstance aboutRepo.Repository, storage Storage) UseCase {
+	return &useCase{
+		receiptRepo:  receiptRepoInstance,
+		donationRepo: donationRepoInstance,
+		aboutRepo:    aboutRepoInstance,
+		storage:      storage,
+	}
+}
+
+// Issue assigns a receipt number to a successful donation and stores its PDF.
+// Issuing is idempotent: an existing receipt is returned unchanged.
+func (uc *useCase) Issue(donationID uint) (*receiptDomain.Receipt, error) {
+	don, err := uc.donationRepo.GetByID(donationID)
+	if err != nil {
+		return nil, err
+	}
+
+	rcpt, _, err := uc.issue(don)
+	return rcpt, err
+}
+
+// GetPDF returns the receipt PDF of a donation, issuing it on first access
+func (uc *useCase) GetPDF(donationID uint) (*File, error) {
+	don, err := uc.donationRepo.GetByID(donationID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.getPDF(don)
+}
+
+// GetPDFByReference returns the receipt PDF when the payment reference matches,
+// letting donors download their receipt without an account
+func (uc *useCase) GetPDFByReference(donationID uint, reference string) (*File, error) {
+	don, err := uc.donationRepo.GetByID(donationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if reference == "" || don.PaymentReference == nil ||
+		subtle.ConstantTimeCompare([]byte(*don.PaymentReference), []byte(reference)) != 1 {
+		return nil, errors.New("donation not found")
+	}
+
+	return uc.getPDF(don)
+}
+
+func (uc *useCase) getPDF(don *donationDomain.Donation) (*File, error) {
+	rcpt, content, err := uc.issue(don)
+	if err != nil {
+		return nil, err
+	}
+	if content != nil {
+		return &File{Receipt: rcpt, Content: content}, nil
+	}
+
+	if rcpt.FileName != nil {
+		content, err := uc.storage.Read(*rcpt.FileName)
+		if err == nil {
+			return &File{Receipt: rcpt, Content: content}, nil
+		}
+		logger.Warn().Err(err).Str("number", rcpt.Number).Msg("Stored receipt not readable, regenerating")
+	}
+
+	content, err = uc.store(rcpt, don)
+	if err != nil {
+		return nil, err
+	}
+	return &File{Receipt: rcpt, Content: content}, nil
+}
+
+// issue allocates the receipt and stores the PDF when the receipt is new.
+// The PDF content is returned only when it was generated by this call.
+func (uc *useCase) issue(don *donationDomain.Donation) (*receiptDomain.Receipt, []byte, error) {
+	if don.PaymentStatus != donationDomain.PaymentStatusSuccess {
+		return nil, nil, errors.New("donation is not paid")
+	}
+
+	rcpt, created, err := uc.receiptRepo.Issue(don.ID, time.Now().In(utils.Jakarta))
+	if err != nil {
+		logger.Error().Err(err).Uint("donation_id", don.ID).Msg("Failed to issue receipt")
+		return nil, nil, errors.New("failed to issue receipt")
+	}
+	if !created {
+		return rcpt, nil, nil
+	}
+
+	logger.Info().
+		Uint("donation_id", don.ID).
+		Str("number", rcpt.Number).
+		Msg("Receipt issued")
+
+	// The number is already allocated; a storage failure is recovered on next download
+	content, err := uc.store(rcpt, don)
+	if err != nil {
+		return rcpt, nil, nil
+	}
+	return rcpt, content, nil
+}
+
+// store renders the receipt PDF and saves it in the upload storage
+func (uc *useCase) store(rcpt *receiptDomain.Receipt, don *donationDomain.Donation) ([]byte, error) {
+	content, err := renderPDF(uc.buildDocument(rcpt, don))
+	if err != nil {
+		logger.Error().Err(err).Str("number", rcpt.Number).Msg("Failed to render receipt")
+		return nil, errors.New("failed to render receipt")
+	}
+
+	fileName := utils.GenerateUniqueFilename(rcpt.Number + ".pdf")
+	if err := uc.storage.Save(fileName, content); err != nil {
+		logger.Error().Err(err).Str("number", rcpt.Number).Msg("Failed to store receipt")
+		return nil, errors.New("failed to store receipt")
+	}
+
+	if err := uc.receiptRepo.UpdateFileName(rcpt.ID, fileName); err != nil {
+		logger.Error().Err(err).Str("number", rcpt.Number).Msg("Failed to record receipt file")
+		return nil, errors.New("failed to store receipt")
+	}
+	rcpt.FileName = &fileName
+
+	return content, nil
+}
+
+func (uc *useCase) buildDocument(rcpt *receiptDomain.Receipt, don *donationDomain.Donation) *document {
+	doc := &document{
+		Number:     rcpt.Number,
+		IssuedAt:   rcpt.IssuedAt,
+		MosqueName: defaultMosqueName,
+		DonorName:  anonymousDonor,
+		Amount:     don.Amount,
+		PaidAt:     don.PaidAt,
+	}
+
+	if abt, err := uc.aboutRepo.GetLatest(); err == nil {
+		if abt.Title != "" {
+			doc.MosqueName = abt.Title
+		}
+		doc.MosqueTagline = abt.Subtitle
+	} else {
+		logger.Warn().Err(err).Msg("About record not available for receipt")
+	}
+
+	if don.DonorName != nil && *don.DonorName != "" {
+		doc.DonorName = *don.DonorName
+	}
+	if don.Category != nil {
+		doc.CategoryName = don.Category.Name
+	}
+	if don.PaymentReference != nil {
+		doc.Reference = *don.PaymentReference
+	}
+
+	return doc
+}
+
+type uploadStorage struct{}
+
+// NewUploadStorage stores receipts in the upload directory
+func NewUploadStorage() Storage {
+	return uploadStorage{}
+}
+
+func (uploadStorage) Save(fileName string, content []byte) error {
+	_, err := utils.SaveFile(bytes.NewReader(content), fileName)
+	return err
+}
+
+func (uploadStorage) Read(fileName string) ([]byte, error) {
+	return utils.ReadFile(fileName)
+}
@@ -0,0 +1,228 @@
+package receipt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	aboutDomain "github.com/madr/backend/internal/domain/about"
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	"github.com/madr/backend/internal/domain/models"
+	receiptDomain "github.com/madr/backend/internal/domain/receipt"
+	aboutRepo "github.com/madr/backend/internal/repository/about"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// MockReceiptRepository is a mock implementation of receipt.Repository
+type MockReceiptRepository struct {
+	mock.Mock
+}
+
+func (m *MockReceiptRepository) Issue(donationID uint, issuedAt time.Time) (*receiptDomain.Receipt, bool, error) {
+	args := m.Called(donationID, issuedAt)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*receiptDomain.Receipt), args.Bool(1), args.Error(2)
+}
+
+func (m *MockReceiptRepository) UpdateFileName(id uint, fileName string) error {
+	args := m.Called(id, fileName)
+	return args.Error(0)
+}
+
+// MockDonationRepository mocks the donation repository methods used by receipts
+type MockDonationRepository struct {
+	donationRepo.Repository
+	mock.Mock
+}
+
+func (m *MockDonationRepository) GetByID(id uint) (*donationDomain.Donation, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*donationDomain.Donation), args.Error(1)
+}
+
+// MockAboutRepository mocks the about repository methods used by receipts
+type MockAboutRepository struct {
+	aboutRepo.Repository
+	mock.Mock
+}
+
+func (m *MockAboutRepository) GetLatest() (*aboutDomain.About, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*aboutDomain.About), args.Error(1)
+}
+
+// memoryStorage keeps receipt files in memory
+type memoryStorage struct {
+	files map[string][]byte
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{files: make(map[string][]byte)}
+}
+
+func (s *memoryStorage) Save(fileName string, content []byte) error {
+	s.files[fileName] = content
+	return nil
+}
+
+func (s *memoryStorage) Read(fileName string) ([]byte, error) {
+	content, ok := s.files[fileName]
+	if !ok {
+		return nil, errors.New("file not found")
+	}
+	return content, nil
+}
+
+func newPaidDonation(id uint) *donationDomain.Donation {
+	reference := "DON-20250115-3F2A9C1B7D4E"
+	paidAt := time.Date(2025, 1, 15, 9, 30, 0, 0, time.UTC)
+	return &donationDomain.Donation{
+		BaseModel:        models.BaseModel{ID: id},
+		CategoryID:       1,
+		Amount:           money.FromRupiah(1250000),
+		PaymentStatus:    donationDomain.PaymentStatusSuccess,
+		PaymentReference: &reference,
+		PaidAt:           &paidAt,
+		Category:         &donationDomain.DonationCategoryInfo{ID: 1, Name: "Infaq Pembangunan"},
+	}
+}
+
+func newReceipt(donationID uint) *receiptDomain.Receipt {
+	return &receiptDomain.Receipt{
+		ID:         3,
+		DonationID: donationID,
+		Year:       2025,
+		Sequence:   42,
+		Number:     receiptDomain.FormatNumber(2025, 42),
+		IssuedAt:   time.Date(2025, 1, 15, 10, 0, 0, 0, utils.Jakarta),
+	}
+}
+
+// TestIssue_NewReceipt tests that a new receipt is rendered and stored
+func TestIssue_NewReceipt(t *testing.T) {
+	mockReceipts := new(MockReceiptRepository)
+	mockDonations := new(MockDonationRepository)
+	mockAbout := new(MockAboutRepository)
+	storage := newMemoryStorage()
+
+	mockDonations.On("GetByID", uint(5)).Return(newPaidDonation(5), nil)
+	mockReceipts.On("Issue", uint(5), mock.AnythingOfType("time.Time")).Return(newReceipt(5), true, nil)
+	mockReceipts.On("UpdateFileName", uint(3), mock.AnythingOfType("string")).Return(nil)
+	mockAbout.On("GetLatest").Return(&aboutDomain.About{Title: "Masjid Al Ikhlas", Subtitle: "Jakarta Selatan"}, nil)
+
+	uc := NewUseCase(mockReceipts, mockDonations, mockAbout, storage)
+	rcpt, err := uc.Issue(5)
+	require.NoError(t, err)
+
+	assert.Equal(t, "KW-2025-000042", rcpt.Number)
+	require.NotNil(t, rcpt.FileName)
+	assert.Contains(t, *rcpt.FileName, "KW-2025-000042")
+	assert.True(t, bytes.HasPrefix(storage.files[*rcpt.FileName], []byte("%PDF")))
+
+	mockReceipts.AssertExpectations(t)
+}
+
+// TestIssue_NotPaid tests that pending donations do not get a receipt
+func TestIssue_NotPaid(t *testing.T) {
+	mockReceipts := new(MockReceiptRepository)
+	mockDonations := new(MockDonationRepository)
+
+	pending := newPaidDonation(5)
+	pending.PaymentStatus = donationDomain.PaymentStatusPending
+	mockDonations.On("GetByID", uint(5)).Return(pending, nil)
+
+	uc := NewUseCase(mockReceipts, mockDonations, new(MockAboutRepository), newMemoryStorage())
+	_, err := uc.Issue(5)
+	assert.EqualError(t, err, "donation is not paid")
+
+	mockReceipts.AssertNotCalled(t, "Issue", mock.Anything, mock.Anything)
+}
+
+// TestGetPDF_ExistingReceipt tests that an issued receipt is served from storage
+func TestGetPDF_ExistingReceipt(t *testing.T) {
+	mockReceipts := new(MockReceiptRepository)
+	mockDonations := new(MockDonationRepository)
+	storage := newMemoryStorage()
+
+	fileName := "stored.pdf"
+	storage.files[fileName] = []byte("%PDF-stored")
+	existing := newReceipt(5)
+	existing.FileName = &fileName
+
+	mockDonations.On("GetByID", uint(5)).Return(newPaidDonation(5), nil)
+	mockReceipts.On("Issue", uint(5), mock.AnythingOfType("time.Time")).Return(existing, false, nil)
+
+	uc := NewUseCase(mockReceipts, mockDonations, new(MockAboutRepository), storage)
+	file, err := uc.GetPDF(5)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("%PDF-stored"), file.Content)
+
+	mockReceipts.AssertNotCalled(t, "UpdateFileName", mock.Anything, mock.Anything)
+}
+
+// TestGetPDFByReference tests that the payment reference must match
+func TestGetPDFByReference(t *testing.T) {
+	mockReceipts := new(MockReceiptRepository)
+	mockDonations := new(MockDonationRepository)
+	mockAbout := new(MockAboutRepository)
+
+	mockDonations.On("GetByID", uint(5)).Return(newPaidDonation(5), nil)
+	mockReceipts.On("Issue", uint(5), mock.AnythingOfType("time.Time")).Return(newReceipt(5), true, nil)
+	mockReceipts.On("UpdateFileName", uint(3), mock.AnythingOfType("string")).Return(nil)
+	mockAbout.On("GetLatest").Return(nil, gorm.ErrRecordNotFound)
+
+	uc := NewUseCase(mockReceipts, mockDonations, mockAbout, newMemoryStorage())
+
+	_, err := uc.GetPDFByReference(5, "DON-WRONG")
+	assert.EqualError(t, err, "donation not found")
+	_, err = uc.GetPDFByReference(5, "")
+	assert.EqualError(t, err, "donation not found")
+
+	file, err := uc.GetPDFByReference(5, "DON-20250115-3F2A9C1B7D4E")
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(file.Content, []byte("%PDF")))
+}
+
+// TestBuildDocument tests donor, category and mosque identity on the receipt
+func TestBuildDocument(t *testing.T) {
+	mockAbout := new(MockAboutRepository)
+	mockAbout.On("GetLatest").Return(&aboutDomain.About{Title: "Masjid Al Ikhlas", Subtitle: "Jakarta Selatan"}, nil)
+	uc := &useCase{aboutRepo: mockAbout}
+
+	don := newPaidDonation(5)
+	doc := uc.buildDocument(newReceipt(5), don)
+	assert.Equal(t, anonymousDonor, doc.DonorName)
+	assert.Equal(t, "Infaq Pembangunan", doc.CategoryName)
+	assert.Equal(t, "Masjid Al Ikhlas", doc.MosqueName)
+	assert.Equal(t, "Jakarta Selatan", doc.MosqueTagline)
+
+	name := "Ahmad"
+	don.DonorName = &name
+	doc = uc.buildDocument(newReceipt(5), don)
+	assert.Equal(t, "Ahmad", doc.DonorName)
+}
+
+// TestFormatting tests rupiah and date formatting
+func TestFormatting(t *testing.T) {
+	assert.Equal(t, "Rp 1.250.000", formatRupiah(money.FromRupiah(1250000)))
+	assert.Equal(t, "Rp 500", formatRupiah(money.FromRupiah(500)))
+	assert.Equal(t, "Rp 10.000,50", formatRupiah(money.MustParse("10000.5")))
+
+	// 31 Dec 20:00 UTC is already 1 Jan in WIB
+	assert.Equal(t, "1 Januari 2026", formatDate(time.Date(2025, 12, 31, 20, 0, 0, 0, time.UTC)))
+}
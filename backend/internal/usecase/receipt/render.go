@@ -0,0 +1,137 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/money"
+	"github.com/madr/backend/pkg/terbilang"
+)
+
+// anonymousDonor is printed when a donation has no donor name
+const anonymousDonor = "Hamba Allah"
+
+var monthNames = []string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+// document holds everything printed on a receipt
+type document struct {
+	Number        string
+	IssuedAt      time.Time
+	MosqueName    string
+	MosqueTagline string
+	DonorName     string
+	CategoryName  string
+	Amount        money.Money
+	Reference     string
+	PaidAt        *time.Time
+}
+
+// renderPDF renders a receipt as a single A5 landscape page
+func renderPDF(doc *document) ([]byte, error) {
+	pdf := gofpdf.New("L", "mm", "A5", "")
+	pdf.SetTitle("Kwitansi "+doc.Number, true)
+	pdf.SetCreator(doc.MosqueName, true)
+	pdf.SetCreationDate(doc.IssuedAt)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(false, 15)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	width, _ := pdf.GetPageSize()
+	contentWidth := width - 30
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(contentWidth, 8, tr(doc.MosqueName), "", 1, "C", false, 0, "")
+	if doc.MosqueTagline != "" {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(contentWidth, 5, tr(doc.MosqueTagline), "", 1, "C", false, 0, "")
+	}
+	pdf.Ln(2)
+	pdf.Line(15, pdf.GetY(), width-15, pdf.GetY())
+	pdf.Ln(4)
+
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(contentWidth, 7, "KWITANSI DONASI", "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(contentWidth, 5, "No. "+doc.Number, "", 1, "C", false, 0, "")
+	pdf.Ln(6)
+
+	row := func(label, value string) {
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.CellFormat(45, 7, label, "", 0, "L", false, 0, "")
+		pdf.CellFormat(5, 7, ":", "", 0, "L", false, 0, "")
+		pdf.MultiCell(contentWidth-50, 7, tr(value), "", "L", false)
+	}
+
+	row("Telah diterima dari", doc.DonorName)
+	row("Untuk", doc.CategoryName)
+	row("Sejumlah", formatRupiah(doc.Amount))
+
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(45, 7, "Terbilang", "", 0, "L", false, 0, "")
+	pdf.CellFormat(5, 7, ":", "", 0, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "I", 11)
+	pdf.MultiCell(contentWidth-50, 7, capitalize(terbilang.Rupiah(doc.Amount)), "", "L", false)
+
+	if doc.Reference != "" {
+		row("Referensi", doc.Reference)
+	}
+	if doc.PaidAt != nil {
+		row("Tanggal pembayaran", formatDate(*doc.PaidAt))
+	}
+
+	pdf.Ln(6)
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(contentWidth, 6, "Diterbitkan "+formatDate(doc.IssuedAt), "", 1, "R", false, 0, "")
+	pdf.Ln(4)
+	pdf.SetFont("Helvetica", "I", 9)
+	pdf.MultiCell(contentWidth, 5,
+		"Jazakumullahu khairan. Semoga Allah menerima amal ibadah Anda dan memberikan keberkahan.\n"+
+			"Kwitansi ini diterbitkan secara elektronik dan sah tanpa tanda tangan.",
+		"", "C", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render receipt: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// formatRupiah formats an amount as Indonesian currency, e.g. Rp 1.250.000,50
+func formatRupiah(amount money.Money) string {
+	cents := amount.Sen()
+	whole := fmt.Sprintf("%d", cents/money.Scale)
+
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+
+	if sen := cents % money.Scale; sen != 0 {
+		return fmt.Sprintf("Rp %s,%02d", b.String(), sen)
+	}
+	return "Rp " + b.String()
+}
+
+// formatDate formats a date in Bahasa Indonesia, e.g. 15 Januari 2025
+func formatDate(t time.Time) string {
+	t = t.In(utils.Jakarta)
+	return fmt.Sprintf("%d %s %d", t.Day(), monthNames[t.Month()-1], t.Year())
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,473 @@
+package role
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	roleDomain "github.com/madr/backend/internal/domain/role"
+	roleRepo "github.com/madr/backend/internal/repository/role"
+	userRepo "github.com/madr/backend/internal/repository/user"
+	"github.com/madr/backend/pkg/logger"
+)
+
+// cacheTTL bounds how long other instances keep serving stale permissions
+// after a role changes
+const cacheTTL = time.Minute
+
+// UseCase defines the interface for role use case
+type UseCase interface {
+	GetPermissions() ([]roleDomain.Permission, error)
+	GetAll() ([]roleDomain.Role, error)
+	GetByID(id uint) (*roleDomain.Role, error)
+	Create(req *CreateRequest, actorID uint) (*roleDomain.Role, error)
+	Update(id uint, req *UpdateRequest, actorID uint) (*roleDomain.Role, error)
+	Delete(id uint) error
+
+	GetUserRoles(userID uint) ([]roleDomain.Role, error)
+	SetUserRoles(userID uint, req *AssignRequest, actorID uint) ([]roleDomain.Role, error)
+	EnsureCanAssign(actorID uint, roles []roleDomain.Role) error
+	IsLastAdministrator(userID uint) (bool, error)
+	HasPermission(roleIDs []uint, permission string) (bool, error)
+}
+
+// CreateRequest represents the request to create a role
+type CreateRequest struct {
+	Name        string   `json:"name" binding:"required,min=2,max=100"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"` // Permission codes
+}
+
+// UpdateRequest represents the request to update a role
+type UpdateRequest struct {
+	Name        *string   `json:"name" binding:"omitempty,min=2,max=100"`
+	Description *string   `json:"description"`
+	Permissions *[]string `json:"permissions"` // Replaces every permission when set
+}
+
+// AssignRequest represents the roles assigned to a user
+type AssignRequest struct {
+	RoleIDs []uint `json:"role_ids" binding:"required"`
+}
+
+type useCase struct {
+	repo  roleRepo.Repository
+	users userRepo.Repository
+	now   func() time.Time
+
+	mu       sync.Mutex
+	grants   map[uint]map[string]bool // Role ID to permission codes
+	loadedAt time.Time
+}
+
+// NewUseCase creates a new role use case
+func NewUseCase(repo roleRepo.Repository, users userRepo.Repository) UseCase {
+	return &useCase{
+		repo:  repo,
+		users: users,
+		now:   time.Now,
+	}
+}
+
+// GetPermissions lists every permission a role can grant
+func (uc *useCase) GetPermissions() ([]roleDomain.Permission, error) {
+	permissions, err := uc.repo.GetPermissions()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get permissions")
+		return nil, errors.New("failed to get permissions")
+	}
+	return permissions, nil
+}
+
+// GetAll lists every role with its permissions
+func (uc *useCase) GetAll() ([]roleDomain.Role, error) {
+	roles, err := uc.repo.GetAll()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get roles")
+		return nil, errors.New("failed to get roles")
+	}
+	return roles, nil
+}
+
+// GetByID retrieves a role with its permissions
+func (uc *useCase) GetByID(id uint) (*roleDomain.Role, error) {
+	return uc.repo.GetByID(id)
+}
+
+// Create creates a role granting the given permissions. The actor has to
+// hold every one of them.
+func (uc *useCase) Create(req *CreateRequest, actorID uint) (*roleDomain.Role, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := uc.ensureUniqueName(name, 0); err != nil {
+		return nil, err
+	}
+
+	permissions, err := uc.resolvePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.ensureCanGrant(actorID, permissions, nil); err != nil {
+		return nil, err
+	}
+
+	role := &roleDomain.Role{
+		Name:        name,
+		Description: req.Description,
+		Permissions: permissions,
+	}
+	if err := uc.repo.Create(role); err != nil {
+		logger.Error().Err(err).Str("name", name).Msg("Failed to create role")
+		return nil, errors.New("failed to create role")
+	}
+
+	logger.Info().Uint("id", role.ID).Str("name", role.Name).Msg("Role created successfully")
+	return role, nil
+}
+
+// Update changes a role. The admin system role always keeps every permission,
+// and system roles keep their name. The actor has to hold every permission
+// the role gains.
+func (uc *useCase) Update(id uint, req *UpdateRequest, actorID uint) (*roleDomain.Role, error) {
+	role, err := uc.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name != role.Name {
+			if role.IsSystem {
+				return nil, errors.New("system role cannot be renamed")
+			}
+			if err := uc.ensureUniqueName(name, id); err != nil {
+				return nil, err
+			}
+			role.Name = name
+		}
+	}
+	if req.Description != nil {
+		role.Description = *req.Description
+	}
+	if req.Permissions != nil {
+		if role.IsAdmin() {
+			return nil, errors.New("admin role permissions cannot be changed")
+		}
+		permissions, err := uc.resolvePermissions(*req.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		if err := uc.ensureCanGrant(actorID, permissions, role.Permissions); err != nil {
+			return nil, err
+		}
+		role.Permissions = permissions
+	}
+
+	if err := uc.repo.Update(role); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to update role")
+		return nil, errors.New("failed to update role")
+	}
+	uc.invalidate()
+
+	logger.Info().Uint("id", id).Msg("Role updated successfully")
+	return uc.repo.GetByID(id)
+}
+
+// Delete deletes a role and unassigns it from its users
+func (uc *useCase) Delete(id uint) error {
+	role, err := uc.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if role.IsSystem {
+		return errors.New("system role cannot be deleted")
+	}
+
+	if err := uc.repo.Delete(id); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to delete role")
+		return errors.New("failed to delete role")
+	}
+	uc.invalidate()
+
+	logger.Info().Uint("id", id).Msg("Role deleted successfully")
+	return nil
+}
+
+// GetUserRoles lists the roles assigned to a user
+func (uc *useCase) GetUserRoles(userID uint) ([]roleDomain.Role, error) {
+	if _, err := uc.users.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	roles, err := uc.repo.GetUserRoles(userID)
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to get user roles")
+		return nil, errors.New("failed to get user roles")
+	}
+	return roles, nil
+}
+
+// SetUserRoles replaces the roles of a user. The last active administrator
+// cannot lose the admin role, so the system always keeps someone able to
+// manage roles. The actor may only add or remove roles they could grant
+// themselves (see EnsureCanAssign). New roles apply from the user's next
+// token refresh.
+func (uc *useCase) SetUserRoles(userID uint, req *AssignRequest, actorID uint) ([]roleDomain.Role, error) {
+	if _, err := uc.users.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	roleIDs := uniqueIDs(req.RoleIDs)
+	roles, err := uc.repo.GetByIDs(roleIDs)
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to get roles")
+		return nil, errors.New("failed to assign roles")
+	}
+	if len(roles) != len(roleIDs) {
+		return nil, errors.New("role not found")
+	}
+
+	current, err := uc.repo.GetUserRoles(userID)
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to get user roles")
+		return nil, errors.New("failed to assign roles")
+	}
+	changed := append(rolesNotIn(roles, current), rolesNotIn(current, roles)...)
+	if err := uc.EnsureCanAssign(actorID, changed); err != nil {
+		return nil, err
+	}
+
+	if err := uc.ensureAdminRemains(userID, roles); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.SetUserRoles(userID, roleIDs); err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to assign roles")
+		return nil, errors.New("failed to assign roles")
+	}
+
+	logger.Info().Uint("user_id", userID).Interface("role_ids", roleIDs).Msg("User roles assigned")
+	return roles, nil
+}
+
+// EnsureCanAssign rejects assigning roles that grant a permission the actor
+// does not hold, so nobody can hand out more than they have. Only
+// administrators can assign the admin role.
+func (uc *useCase) EnsureCanAssign(actorID uint, roles []roleDomain.Role) error {
+	for _, role := range roles {
+		if role.IsAdmin() {
+			admin, err := uc.isAdministrator(actorID)
+			if err != nil {
+				return err
+			}
+			if !admin {
+				return errors.New("only administrators can assign the admin role")
+			}
+		}
+		if err := uc.ensureCanGrant(actorID, role.Permissions, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasPermission reports whether any of the roles grants the permission
+func (uc *useCase) HasPermission(roleIDs []uint, permission string) (bool, error) {
+	if len(roleIDs) == 0 {
+		return false, nil
+	}
+
+	grants, err := uc.loadGrants()
+	if err != nil {
+		return false, err
+	}
+	for _, id := range roleIDs {
+		if grants[id][permission] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// loadGrants returns the cached permissions of every role, reloading them
+// once the cache is older than cacheTTL
+func (uc *useCase) loadGrants() (map[uint]map[string]bool, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.grants != nil && uc.now().Sub(uc.loadedAt) < cacheTTL {
+		return uc.grants, nil
+	}
+
+	roles, err := uc.repo.GetAll()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to load role permissions")
+		return nil, errors.New("failed to load permissions")
+	}
+
+	grants := make(map[uint]map[string]bool, len(roles))
+	for _, role := range roles {
+		codes := make(map[string]bool, len(role.Permissions))
+		for _, p := range role.Permissions {
+			codes[p.Code] = true
+		}
+		grants[role.ID] = codes
+	}
+	uc.grants, uc.loadedAt = grants, uc.now()
+	return grants, nil
+}
+
+// invalidate drops the cached permissions after a role changed
+func (uc *useCase) invalidate() {
+	uc.mu.Lock()
+	uc.grants = nil
+	uc.mu.Unlock()
+}
+
+func (uc *useCase) ensureUniqueName(name string, excludeID uint) error {
+	exists, err := uc.repo.ExistsByName(name, excludeID)
+	if err != nil {
+		logger.Error().Err(err).Str("name", name).Msg("Failed to check role name")
+		return errors.New("failed to check role name")
+	}
+	if exists {
+		return errors.New("role name already exists")
+	}
+	return nil
+}
+
+// resolvePermissions maps permission codes to permissions, rejecting unknown codes
+func (uc *useCase) resolvePermissions(codes []string) ([]roleDomain.Permission, error) {
+	seen := make(map[string]bool, len(codes))
+	unique := make([]string, 0, len(codes))
+	for _, code := range codes {
+		if !seen[code] {
+			seen[code] = true
+			unique = append(unique, code)
+		}
+	}
+
+	permissions, err := uc.repo.GetPermissionsByCodes(unique)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get permissions")
+		return nil, errors.New("failed to get permissions")
+	}
+	if len(permissions) != len(unique) {
+		return nil, errors.New("unknown permission")
+	}
+	return permissions, nil
+}
+
+// ensureCanGrant rejects permissions the actor does not hold. Permissions in
+// kept were granted before and may stay.
+func (uc *useCase) ensureCanGrant(actorID uint, permissions, kept []roleDomain.Permission) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	roles, err := uc.repo.GetUserRoles(actorID)
+	if err != nil {
+		logger.Error().Err(err).Uint("actor_id", actorID).Msg("Failed to get actor roles")
+		return errors.New("failed to load permissions")
+	}
+	held := make(map[string]bool)
+	for _, p := range kept {
+		held[p.Code] = true
+	}
+	for _, role := range roles {
+		for _, p := range role.Permissions {
+			held[p.Code] = true
+		}
+	}
+
+	for _, p := range permissions {
+		if !held[p.Code] {
+			logger.Warn().Uint("actor_id", actorID).Str("permission", p.Code).Msg("Refused to grant a permission the actor does not hold")
+			return errors.New("cannot grant permissions you do not have")
+		}
+	}
+	return nil
+}
+
+// isAdministrator reports whether the user holds the admin role
+func (uc *useCase) isAdministrator(userID uint) (bool, error) {
+	roles, err := uc.repo.GetUserRoles(userID)
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to get user roles")
+		return false, errors.New("failed to load permissions")
+	}
+	for _, role := range roles {
+		if role.IsAdmin() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ensureAdminRemains rejects taking the admin role away from the last active administrator
+func (uc *useCase) ensureAdminRemains(userID uint, newRoles []roleDomain.Role) error {
+	for _, role := range newRoles {
+		if role.IsAdmin() {
+			return nil
+		}
+	}
+
+	last, err := uc.IsLastAdministrator(userID)
+	if err != nil {
+		return errors.New("failed to assign roles")
+	}
+	if last {
+		return errors.New("cannot remove the last administrator")
+	}
+	return nil
+}
+
+// IsLastAdministrator reports whether the user is the only active holder of the admin role
+func (uc *useCase) IsLastAdministrator(userID uint) (bool, error) {
+	current, err := uc.repo.GetUserRoles(userID)
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to get user roles")
+		return false, err
+	}
+	for _, role := range current {
+		if !role.IsAdmin() {
+			continue
+		}
+		admins, err := uc.repo.CountActiveUsers(role.ID)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to count administrators")
+			return false, err
+		}
+		return admins <= 1, nil
+	}
+	return false, nil
+}
+
+// rolesNotIn returns the roles that are not in others
+func rolesNotIn(roles, others []roleDomain.Role) []roleDomain.Role {
+	seen := make(map[uint]bool, len(others))
+	for _, role := range others {
+		seen[role.ID] = true
+	}
+	var missing []roleDomain.Role
+	for _, role := range roles {
+		if !seen[role.ID] {
+			missing = append(missing, role)
+		}
+	}
+	return missing
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i] < unique[j] })
+	return unique
+}
@@ -0,0 +1,331 @@
+package role
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	roleDomain "github.com/madr/backend/internal/domain/role"
+	userDomain "github.com/madr/backend/internal/domain/user"
+	roleRepo "github.com/madr/backend/internal/repository/role"
+	userRepo "github.com/madr/backend/internal/repository/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRoleRepository keeps roles and assignments in memory
+type fakeRoleRepository struct {
+	roleRepo.Repository
+	permissions []roleDomain.Permission
+	roles       map[uint]*roleDomain.Role
+	userRoles   map[uint][]uint
+	getAllCalls int
+}
+
+func newFakeRoleRepository() *fakeRoleRepository {
+	permissions := []roleDomain.Permission{
+		{ID: 1, Code: "donation:read"},
+		{ID: 2, Code: "donation:write"},
+		{ID: 3, Code: "event:write"},
+	}
+	return &fakeRoleRepository{
+		permissions: permissions,
+		roles: map[uint]*roleDomain.Role{
+			1: {ID: 1, Name: roleDomain.AdminRoleName, IsSystem: true, Permissions: permissions},
+			2: {ID: 2, Name: "treasurer", Permissions: permissions[:2]},
+		},
+		userRoles: map[uint][]uint{},
+	}
+}
+
+func (r *fakeRoleRepository) GetPermissionsByCodes(codes []string) ([]roleDomain.Permission, error) {
+	var found []roleDomain.Permission
+	for _, p := range r.permissions {
+		for _, code := range codes {
+			if p.Code == code {
+				found = append(found, p)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeRoleRepository) Create(role *roleDomain.Role) error {
+	role.ID = uint(len(r.roles) + 1)
+	r.roles[role.ID] = role
+	return nil
+}
+
+func (r *fakeRoleRepository) GetByID(id uint) (*roleDomain.Role, error) {
+	role, ok := r.roles[id]
+	if !ok {
+		return nil, errors.New("role not found")
+	}
+	clone := *role
+	return &clone, nil
+}
+
+func (r *fakeRoleRepository) GetByIDs(ids []uint) ([]roleDomain.Role, error) {
+	var roles []roleDomain.Role
+	for _, id := range ids {
+		if role, ok := r.roles[id]; ok {
+			roles = append(roles, *role)
+		}
+	}
+	return roles, nil
+}
+
+func (r *fakeRoleRepository) GetAll() ([]roleDomain.Role, error) {
+	r.getAllCalls++
+	var roles []roleDomain.Role
+	for _, role := range r.roles {
+		roles = append(roles, *role)
+	}
+	return roles, nil
+}
+
+func (r *fakeRoleRepository) Update(role *roleDomain.Role) error {
+	clone := *role
+	r.roles[role.ID] = &clone
+	return nil
+}
+
+func (r *fakeRoleRepository) Delete(id uint) error {
+	delete(r.roles, id)
+	return nil
+}
+
+func (r *fakeRoleRepository) ExistsByName(name string, excludeID uint) (bool, error) {
+	for _, role := range r.roles {
+		if role.Name == name && role.ID != excludeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeRoleRepository) GetUserRoles(userID uint) ([]roleDomain.Role, error) {
+	return r.GetByIDs(r.userRoles[userID])
+}
+
+func (r *fakeRoleRepository) SetUserRoles(userID uint, roleIDs []uint) error {
+	r.userRoles[userID] = roleIDs
+	return nil
+}
+
+func (r *fakeRoleRepository) CountActiveUsers(roleID uint) (int64, error) {
+	var count int64
+	for _, ids := range r.userRoles {
+		for _, id := range ids {
+			if id == roleID {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+// fakeUserRepository knows users by ID only
+type fakeUserRepository struct {
+	userRepo.Repository
+	ids map[uint]bool
+}
+
+func (r *fakeUserRepository) GetByID(id uint) (*userDomain.User, error) {
+	if !r.ids[id] {
+		return nil, errors.New("user not found")
+	}
+	usr := &userDomain.User{}
+	usr.ID = id
+	return usr, nil
+}
+
+func newTestUseCase() (*useCase, *fakeRoleRepository) {
+	repo := newFakeRoleRepository()
+	users := &fakeUserRepository{ids: map[uint]bool{10: true, 11: true}}
+	return NewUseCase(repo, users).(*useCase), repo
+}
+
+// TestSetUserRoles_LastAdministrator tests that the only admin keeps the admin role
+func TestSetUserRoles_LastAdministrator(t *testing.T) {
+	uc, repo := newTestUseCase()
+	repo.userRoles[10] = []uint{1}
+
+	_, err := uc.SetUserRoles(10, &AssignRequest{RoleIDs: []uint{2}}, 10)
+	assert.EqualError(t, err, "cannot remove the last administrator")
+	assert.Equal(t, []uint{1}, repo.userRoles[10])
+
+	// With a second admin the role can be moved
+	repo.userRoles[11] = []uint{1}
+	roles, err := uc.SetUserRoles(10, &AssignRequest{RoleIDs: []uint{2, 2}}, 10)
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+	assert.Equal(t, "treasurer", roles[0].Name)
+	assert.Equal(t, []uint{2}, repo.userRoles[10])
+}
+
+// TestSetUserRoles_Validation tests unknown users and roles
+func TestSetUserRoles_Validation(t *testing.T) {
+	uc, _ := newTestUseCase()
+
+	_, err := uc.SetUserRoles(99, &AssignRequest{RoleIDs: []uint{2}}, 10)
+	assert.EqualError(t, err, "user not found")
+
+	_, err = uc.SetUserRoles(10, &AssignRequest{RoleIDs: []uint{2, 42}}, 10)
+	assert.EqualError(t, err, "role not found")
+}
+
+// TestSystemRoleGuards tests that the admin role cannot be deleted, renamed or narrowed
+func TestSystemRoleGuards(t *testing.T) {
+	uc, repo := newTestUseCase()
+
+	assert.EqualError(t, uc.Delete(1), "system role cannot be deleted")
+
+	name := "superuser"
+	_, err := uc.Update(1, &UpdateRequest{Name: &name}, 10)
+	assert.EqualError(t, err, "system role cannot be renamed")
+
+	codes := []string{"event:write"}
+	_, err = uc.Update(1, &UpdateRequest{Permissions: &codes}, 10)
+	assert.EqualError(t, err, "admin role permissions cannot be changed")
+	assert.Len(t, repo.roles[1].Permissions, 3)
+
+	require.NoError(t, uc.Delete(2))
+	assert.NotContains(t, repo.roles, uint(2))
+}
+
+// TestCreate tests role creation with known and unknown permissions
+func TestCreate(t *testing.T) {
+	uc, repo := newTestUseCase()
+	repo.userRoles[10] = []uint{1}
+
+	_, err := uc.Create(&CreateRequest{Name: "media", Permissions: []string{"event:write", "gallery:fly"}}, 10)
+	assert.EqualError(t, err, "unknown permission")
+
+	_, err = uc.Create(&CreateRequest{Name: "treasurer"}, 10)
+	assert.EqualError(t, err, "role name already exists")
+
+	role, err := uc.Create(&CreateRequest{Name: " media ", Permissions: []string{"event:write", "event:write"}}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "media", role.Name)
+	assert.Equal(t, []string{"event:write"}, role.PermissionCodes())
+}
+
+// TestHasPermission tests permission checks and the cache
+func TestHasPermission(t *testing.T) {
+	uc, repo := newTestUseCase()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+
+	ok, err := uc.HasPermission([]uint{2}, "donation:write")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = uc.HasPermission([]uint{2}, "event:write")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = uc.HasPermission([]uint{2, 1}, "event:write")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = uc.HasPermission(nil, "donation:read")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 1, repo.getAllCalls)
+
+	// Changes made through the use case apply immediately
+	codes := []string{"donation:read"}
+	_, err = uc.Update(2, &UpdateRequest{Permissions: &codes}, 10)
+	require.NoError(t, err)
+	ok, err = uc.HasPermission([]uint{2}, "donation:write")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 2, repo.getAllCalls)
+
+	// Changes made elsewhere apply once the cache expires
+	repo.roles[2].Permissions = repo.permissions[:2]
+	ok, _ = uc.HasPermission([]uint{2}, "donation:write")
+	assert.False(t, ok)
+	now = now.Add(cacheTTL)
+	ok, _ = uc.HasPermission([]uint{2}, "donation:write")
+	assert.True(t, ok)
+}
+
+// TestSetUserRoles_Escalation tests that only administrators can assign the admin role
+func TestSetUserRoles_Escalation(t *testing.T) {
+	uc, repo := newTestUseCase()
+	repo.userRoles[11] = []uint{2}
+	repo.roles[3] = &roleDomain.Role{ID: 3, Name: "media", Permissions: repo.permissions[2:]}
+
+	// A treasurer with user:write cannot make anyone an administrator
+	_, err := uc.SetUserRoles(10, &AssignRequest{RoleIDs: []uint{1}}, 11)
+	assert.EqualError(t, err, "only administrators can assign the admin role")
+	assert.Empty(t, repo.userRoles[10])
+
+	// nor hand out a role with permissions they do not have
+	_, err = uc.SetUserRoles(10, &AssignRequest{RoleIDs: []uint{3}}, 11)
+	assert.EqualError(t, err, "cannot grant permissions you do not have")
+
+	_, err = uc.SetUserRoles(10, &AssignRequest{RoleIDs: []uint{2}}, 11)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{2}, repo.userRoles[10])
+
+	// Roles the user already holds may stay
+	repo.userRoles[10] = []uint{2, 3}
+	_, err = uc.SetUserRoles(10, &AssignRequest{RoleIDs: []uint{3}}, 11)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3}, repo.userRoles[10])
+
+	// An administrator can assign every role
+	repo.userRoles[11] = []uint{1}
+	_, err = uc.SetUserRoles(10, &AssignRequest{RoleIDs: []uint{1}}, 11)
+	require.NoError(t, err)
+}
+
+// TestSetUserRoles_RemovalEscalation tests that only administrators can take the admin role away
+func TestSetUserRoles_RemovalEscalation(t *testing.T) {
+	uc, repo := newTestUseCase()
+	repo.userRoles[10] = []uint{1, 2}
+	repo.userRoles[11] = []uint{2}
+	repo.userRoles[12] = []uint{1}
+
+	// A treasurer with user:write cannot demote an administrator, even with another admin left
+	_, err := uc.SetUserRoles(10, &AssignRequest{RoleIDs: []uint{2}}, 11)
+	assert.EqualError(t, err, "only administrators can assign the admin role")
+	assert.Equal(t, []uint{1, 2}, repo.userRoles[10])
+
+	// but may change the roles they can grant while the admin role stays
+	_, err = uc.SetUserRoles(10, &AssignRequest{RoleIDs: []uint{1}}, 11)
+	require.NoError(t, err)
+
+	_, err = uc.SetUserRoles(10, &AssignRequest{RoleIDs: []uint{2}}, 12)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{2}, repo.userRoles[10])
+}
+
+// TestCreate_Escalation tests that roles only grant permissions the actor holds
+func TestCreate_Escalation(t *testing.T) {
+	uc, repo := newTestUseCase()
+	repo.userRoles[11] = []uint{2}
+
+	// A treasurer with role:write cannot create a role with event:write
+	_, err := uc.Create(&CreateRequest{Name: "media", Permissions: []string{"donation:read", "event:write"}}, 11)
+	assert.EqualError(t, err, "cannot grant permissions you do not have")
+	assert.Len(t, repo.roles, 2)
+
+	role, err := uc.Create(&CreateRequest{Name: "kasir", Permissions: []string{"donation:read"}}, 11)
+	require.NoError(t, err)
+
+	// nor add it to an existing role
+	codes := []string{"donation:read", "event:write"}
+	_, err = uc.Update(role.ID, &UpdateRequest{Permissions: &codes}, 11)
+	assert.EqualError(t, err, "cannot grant permissions you do not have")
+	assert.Equal(t, []string{"donation:read"}, repo.roles[role.ID].PermissionCodes())
+
+	// Permissions the role already grants may stay
+	repo.roles[role.ID].Permissions = repo.permissions
+	codes = []string{"donation:write", "event:write"}
+	_, err = uc.Update(role.ID, &UpdateRequest{Permissions: &codes}, 11)
+	require.NoError(t, err)
+}
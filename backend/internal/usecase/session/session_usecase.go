@@ -0,0 +1,101 @@
+package session
+
+import (
+	"errors"
+	"time"
+
+	refreshTokenRepo "github.com/madr/backend/internal/repository/refreshtoken"
+	userRepo "github.com/madr/backend/internal/repository/user"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/useragent"
+)
+
+// UseCase defines the interface for login session management
+type UseCase interface {
+	List(userID uint, currentID string) ([]Session, error)
+	Revoke(userID uint, id string) error
+}
+
+// Session is a login on one device. It lives as long as its refresh token
+// family has an active token.
+type Session struct {
+	ID         string    `json:"id"`
+	Browser    string    `json:"browser"`
+	OS         string    `json:"os"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	SignedInAt time.Time `json:"signed_in_at"`
+	LastUsedAt time.Time `json:"last_used_at"` // When the refresh token was last rotated
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // The session of the access token making the request
+}
+
+type useCase struct {
+	refreshTokens refreshTokenRepo.Repository
+	users         userRepo.Repository
+	now           func() time.Time
+}
+
+// NewUseCase creates a new session management use case
+func NewUseCase(refreshTokens refreshTokenRepo.Repository, users userRepo.Repository) UseCase {
+	return &useCase{
+		refreshTokens: refreshTokens,
+		users:         users,
+		now:           time.Now,
+	}
+}
+
+// List returns the active sessions of a user, most recently used first.
+// currentID marks the session of the caller, if any.
+func (uc *useCase) List(userID uint, currentID string) ([]Session, error) {
+	if _, err := uc.users.GetByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	tokens, err := uc.refreshTokens.GetActiveByUserID(userID, uc.now())
+	if err != nil {
+		logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to get sessions")
+		return nil, errors.New("failed to get sessions")
+	}
+
+	sessions := make([]Session, 0, len(tokens))
+	for _, rt := range tokens {
+		info := useragent.Parse(rt.UserAgent)
+		sessions = append(sessions, Session{
+			ID:         rt.FamilyID,
+			Browser:    info.Browser,
+			OS:         info.OS,
+			Device:     info.Device,
+			UserAgent:  rt.UserAgent,
+			IPAddress:  rt.IPAddress,
+			SignedInAt: rt.SignedInAt,
+			LastUsedAt: rt.CreatedAt,
+			ExpiresAt:  rt.ExpiresAt,
+			Current:    currentID != "" && rt.FamilyID == currentID,
+		})
+	}
+	return sessions, nil
+}
+
+// Revoke logs a user out of one of their sessions. Access tokens already
+// issued for it stay valid until they expire.
+func (uc *useCase) Revoke(userID uint, id string) error {
+	sessions, err := uc.List(userID, "")
+	if err != nil {
+		return err
+	}
+
+	for _, s := range sessions {
+		if s.ID != id {
+			continue
+		}
+		if _, err := uc.refreshTokens.RevokeFamily(id); err != nil {
+			logger.Error().Err(err).Uint("user_id", userID).Msg("Failed to revoke session")
+			return errors.New("failed to revoke session")
+		}
+		logger.Info().Uint("user_id", userID).Str("session_id", id).Msg("Session revoked")
+		return nil
+	}
+	return errors.New("session not found")
+}
@@ -0,0 +1,139 @@
+package session
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/madr/backend/internal/domain/models"
+	refreshTokenDomain "github.com/madr/backend/internal/domain/refreshtoken"
+	userDomain "github.com/madr/backend/internal/domain/user"
+	refreshTokenRepo "github.com/madr/backend/internal/repository/refreshtoken"
+	userRepo "github.com/madr/backend/internal/repository/user"
+	"github.com/madr/backend/pkg/useragent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chromeWindows = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	safariIPhone  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1.2 Mobile/15E148 Safari/604.1"
+)
+
+// fakeRefreshTokenRepository keeps refresh tokens in memory
+type fakeRefreshTokenRepository struct {
+	refreshTokenRepo.Repository
+	tokens []*refreshTokenDomain.RefreshToken
+}
+
+func (r *fakeRefreshTokenRepository) GetActiveByUserID(userID uint, now time.Time) ([]refreshTokenDomain.RefreshToken, error) {
+	var active []refreshTokenDomain.RefreshToken
+	for _, rt := range r.tokens {
+		if rt.UserID == userID && !rt.IsRevoked && rt.ExpiresAt.After(now) {
+			active = append(active, *rt)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].CreatedAt.After(active[j].CreatedAt)
+	})
+	return active, nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeFamily(familyID string) (int64, error) {
+	var revoked int64
+	for _, rt := range r.tokens {
+		if rt.FamilyID == familyID && !rt.IsRevoked {
+			rt.IsRevoked = true
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+type fakeUserRepository struct {
+	userRepo.Repository
+}
+
+func (fakeUserRepository) GetByID(id uint) (*userDomain.User, error) {
+	if id != 1 && id != 2 {
+		return nil, errors.New("user not found")
+	}
+	return &userDomain.User{BaseModel: models.BaseModel{ID: id}}, nil
+}
+
+func newTestUseCase(now time.Time) (*useCase, *fakeRefreshTokenRepository) {
+	token := func(id, userID uint, family, ua string, age time.Duration) *refreshTokenDomain.RefreshToken {
+		return &refreshTokenDomain.RefreshToken{
+			BaseModel:  models.BaseModel{ID: id, CreatedAt: now.Add(-age)},
+			FamilyID:   family,
+			UserID:     userID,
+			UserAgent:  ua,
+			IPAddress:  "10.0.0.1",
+			ExpiresAt:  now.Add(7*24*time.Hour - age),
+			SignedInAt: now.Add(-48 * time.Hour),
+		}
+	}
+
+	expired := token(4, 1, "family-old", chromeWindows, 8*24*time.Hour)
+	rotated := token(5, 1, "family-laptop", chromeWindows, 2*time.Hour)
+	rotated.IsRevoked = true
+
+	repo := &fakeRefreshTokenRepository{tokens: []*refreshTokenDomain.RefreshToken{
+		token(1, 1, "family-laptop", chromeWindows, time.Hour),
+		token(2, 1, "family-phone", safariIPhone, 10*time.Minute),
+		token(3, 2, "family-other", chromeWindows, time.Minute),
+		expired,
+		rotated,
+	}}
+	uc := NewUseCase(repo, fakeUserRepository{}).(*useCase)
+	uc.now = func() time.Time { return now }
+	return uc, repo
+}
+
+// TestList tests the active sessions of a user
+func TestList(t *testing.T) {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	uc, _ := newTestUseCase(now)
+
+	sessions, err := uc.List(1, "family-laptop")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+
+	// Most recently used first
+	assert.Equal(t, "family-phone", sessions[0].ID)
+	assert.Equal(t, "Safari 17", sessions[0].Browser)
+	assert.Equal(t, "iOS 17.1", sessions[0].OS)
+	assert.Equal(t, useragent.DeviceMobile, sessions[0].Device)
+	assert.Equal(t, now.Add(-10*time.Minute), sessions[0].LastUsedAt)
+	assert.False(t, sessions[0].Current)
+
+	assert.Equal(t, "family-laptop", sessions[1].ID)
+	assert.Equal(t, "Chrome 120", sessions[1].Browser)
+	assert.Equal(t, "Windows 10", sessions[1].OS)
+	assert.Equal(t, now.Add(-48*time.Hour), sessions[1].SignedInAt)
+	assert.True(t, sessions[1].Current)
+
+	_, err = uc.List(99, "")
+	assert.EqualError(t, err, "user not found")
+}
+
+// TestRevoke tests logging out of a single session
+func TestRevoke(t *testing.T) {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	uc, repo := newTestUseCase(now)
+
+	require.NoError(t, uc.Revoke(1, "family-phone"))
+	assert.True(t, repo.tokens[1].IsRevoked)
+
+	sessions, err := uc.List(1, "")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "family-laptop", sessions[0].ID)
+
+	// Already revoked, expired, unknown and other users' sessions are not found
+	for _, id := range []string{"family-phone", "family-old", "missing", "family-other"} {
+		assert.EqualError(t, uc.Revoke(1, id), "session not found", id)
+	}
+	assert.False(t, repo.tokens[2].IsRevoked)
+}
@@ -0,0 +1,450 @@
+package user
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+
+	roleDomain "github.com/madr/backend/internal/domain/role"
+	userDomain "github.com/madr/backend/internal/domain/user"
+	refreshTokenRepo "github.com/madr/backend/internal/repository/refreshtoken"
+	userRepo "github.com/madr/backend/internal/repository/user"
+	roleUsecase "github.com/madr/backend/internal/usecase/role"
+	"github.com/madr/backend/pkg/bcrypt"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/password"
+)
+
+// temporaryPasswordLength is the length of generated reset passwords
+const temporaryPasswordLength = 12
+
+// temporaryPasswordAlphabet leaves out characters that are easy to misread
+const temporaryPasswordAlphabet = "abcdefghjkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// UseCase defines the interface for admin user management
+type UseCase interface {
+	GetAll(limit, offset int, filter *userRepo.Filter) (*GetAllResponse, error)
+	GetByID(id uint) (*Detail, error)
+	Create(req *CreateRequest, actorID uint) (*Detail, error)
+	Update(id uint, req *UpdateRequest, actorID uint) (*Detail, error)
+	SetActive(id, actorID uint, req *StatusRequest) (*Detail, error)
+	ResetPassword(id, actorID uint, req *ResetPasswordRequest) (*ResetPasswordResponse, error)
+	Unlock(id, actorID uint) error
+	Delete(id, actorID uint) error
+}
+
+// RoleService manages the RBAC roles of users
+type RoleService interface {
+	GetByID(id uint) (*roleDomain.Role, error)
+	GetUserRoles(userID uint) ([]roleDomain.Role, error)
+	SetUserRoles(userID uint, req *roleUsecase.AssignRequest, actorID uint) ([]roleDomain.Role, error)
+	EnsureCanAssign(actorID uint, roles []roleDomain.Role) error
+	IsLastAdministrator(userID uint) (bool, error)
+}
+
+// LoginUnlocker lifts the failed login lockout of a username
+type LoginUnlocker interface {
+	Unlock(username string) error
+}
+
+// CreateRequest represents the request to create a staff account
+type CreateRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=100"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+	Name     string `json:"name" binding:"max=255"`
+	RoleIDs  []uint `json:"role_ids"`
+}
+
+// UpdateRequest represents the request to update a user
+type UpdateRequest struct {
+	Email   *string `json:"email" binding:"omitempty,email"`
+	Name    *string `json:"name" binding:"omitempty,max=255"`
+	RoleIDs *[]uint `json:"role_ids"` // Replaces every role when set
+}
+
+// StatusRequest represents the request to activate or deactivate a user
+type StatusRequest struct {
+	IsActive *bool `json:"is_active" binding:"required"`
+}
+
+// ResetPasswordRequest represents an admin password reset. A temporary
+// password is generated when none is given.
+type ResetPasswordRequest struct {
+	Password string `json:"password"`
+}
+
+// ResetPasswordResponse carries the generated temporary password, shown once
+type ResetPasswordResponse struct {
+	TemporaryPassword string `json:"temporary_password,omitempty"`
+}
+
+// Detail is a user with its RBAC roles
+type Detail struct {
+	*userDomain.User
+	Roles []roleDomain.Role `json:"roles"`
+}
+
+// GetAllResponse represents the response for listing users
+type GetAllResponse struct {
+	Data       []userDomain.User `json:"data"`
+	Total      int64             `json:"total"`
+	Limit      int               `json:"limit"`
+	Offset     int               `json:"offset"`
+	TotalPages int               `json:"total_pages"`
+}
+
+type useCase struct {
+	repo          userRepo.Repository
+	refreshTokens refreshTokenRepo.Repository
+	roles         RoleService
+	unlocker      LoginUnlocker
+}
+
+// NewUseCase creates a new user management use case
+func NewUseCase(repo userRepo.Repository, refreshTokens refreshTokenRepo.Repository, roles RoleService, unlocker LoginUnlocker) UseCase {
+	return &useCase{
+		repo:          repo,
+		refreshTokens: refreshTokens,
+		roles:         roles,
+		unlocker:      unlocker,
+	}
+}
+
+// GetAll lists users ordered by username
+func (uc *useCase) GetAll(limit, offset int, filter *userRepo.Filter) (*GetAllResponse, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	users, total, err := uc.repo.GetAll(limit, offset, filter)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get users")
+		return nil, errors.New("failed to get users")
+	}
+
+	return &GetAllResponse{
+		Data:       users,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, nil
+}
+
+// GetByID retrieves a user with its roles
+func (uc *useCase) GetByID(id uint) (*Detail, error) {
+	usr, err := uc.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return uc.detail(usr)
+}
+
+// Create creates an active staff account with the given roles. The actor
+// may only assign roles they could grant themselves.
+func (uc *useCase) Create(req *CreateRequest, actorID uint) (*Detail, error) {
+	username := strings.TrimSpace(req.Username)
+	email := strings.TrimSpace(req.Email)
+	if err := password.Validate(req.Password, username, email); err != nil {
+		return nil, err
+	}
+
+	if exists, err := uc.repo.ExistsByUsername(username); err != nil {
+		logger.Error().Err(err).Msg("Failed to check username existence")
+		return nil, errors.New("failed to check username")
+	} else if exists {
+		return nil, errors.New("username already exists")
+	}
+	if err := uc.ensureEmailAvailable(email); err != nil {
+		return nil, err
+	}
+
+	// Unknown or forbidden roles must not leave a half-created account behind
+	roles := make([]roleDomain.Role, 0, len(req.RoleIDs))
+	for _, id := range req.RoleIDs {
+		role, err := uc.roles.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, *role)
+	}
+	if err := uc.roles.EnsureCanAssign(actorID, roles); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := bcrypt.HashPassword(req.Password)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to hash password")
+		return nil, errors.New("failed to process password")
+	}
+
+	usr := &userDomain.User{
+		Username: username,
+		Email:    email,
+		Password: hashedPassword,
+		Name:     req.Name,
+		Role:     userDomain.RoleUser,
+		IsActive: true,
+	}
+	if err := uc.repo.Create(usr); err != nil {
+		logger.Error().Err(err).Msg("Failed to create user")
+		return nil, errors.New("failed to create user")
+	}
+
+	if len(req.RoleIDs) > 0 {
+		if _, err := uc.roles.SetUserRoles(usr.ID, &roleUsecase.AssignRequest{RoleIDs: req.RoleIDs}, actorID); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info().Uint("user_id", usr.ID).Str("username", usr.Username).Msg("Staff account created")
+	return uc.detail(usr)
+}
+
+// Update changes a user's profile and, when given, replaces its roles. A new
+// email has to be verified again and signs the user out everywhere.
+func (uc *useCase) Update(id uint, req *UpdateRequest, actorID uint) (*Detail, error) {
+	usr, err := uc.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.ensureCanManage(id, actorID); err != nil {
+		return nil, err
+	}
+
+	emailChanged := false
+	if req.Email != nil {
+		email := strings.TrimSpace(*req.Email)
+		if !strings.EqualFold(email, usr.Email) {
+			if err := uc.ensureEmailAvailable(email); err != nil {
+				return nil, err
+			}
+			emailChanged = true
+			usr.EmailVerifiedAt = nil
+		}
+		usr.Email = email
+	}
+	if req.Name != nil {
+		usr.Name = *req.Name
+	}
+
+	// Roles go first so a rejected change (e.g. the last administrator) saves nothing
+	if req.RoleIDs != nil {
+		if _, err := uc.roles.SetUserRoles(id, &roleUsecase.AssignRequest{RoleIDs: *req.RoleIDs}, actorID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.repo.Update(usr); err != nil {
+		logger.Error().Err(err).Uint("user_id", id).Msg("Failed to update user")
+		return nil, errors.New("failed to update user")
+	}
+	if emailChanged {
+		if err := uc.revokeSessions(id); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info().Uint("user_id", id).Bool("email_changed", emailChanged).Msg("User updated successfully")
+	return uc.detail(usr)
+}
+
+// SetActive activates or deactivates a user. Deactivation revokes every
+// refresh token, so the user is signed out once the access token expires.
+func (uc *useCase) SetActive(id, actorID uint, req *StatusRequest) (*Detail, error) {
+	usr, err := uc.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.ensureCanManage(id, actorID); err != nil {
+		return nil, err
+	}
+
+	active := *req.IsActive
+	if !active {
+		if id == actorID {
+			return nil, errors.New("cannot deactivate your own account")
+		}
+		if err := uc.ensureNotLastAdministrator(id); err != nil {
+			return nil, err
+		}
+	}
+
+	if usr.IsActive != active {
+		usr.IsActive = active
+		if err := uc.repo.Update(usr); err != nil {
+			logger.Error().Err(err).Uint("user_id", id).Msg("Failed to update user status")
+			return nil, errors.New("failed to update user")
+		}
+	}
+	if !active {
+		if err := uc.revokeSessions(id); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info().Uint("user_id", id).Bool("is_active", active).Msg("User status updated")
+	return uc.detail(usr)
+}
+
+// ResetPassword replaces a user's password and signs it out everywhere. A
+// given password has to meet the password policy.
+func (uc *useCase) ResetPassword(id, actorID uint, req *ResetPasswordRequest) (*ResetPasswordResponse, error) {
+	usr, err := uc.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.ensureCanManage(id, actorID); err != nil {
+		return nil, err
+	}
+
+	response := &ResetPasswordResponse{}
+	newPassword := req.Password
+	if newPassword == "" {
+		if newPassword, err = generatePassword(usr); err != nil {
+			logger.Error().Err(err).Msg("Failed to generate temporary password")
+			return nil, errors.New("failed to reset password")
+		}
+		response.TemporaryPassword = newPassword
+	} else if err := password.Validate(newPassword, usr.Username, usr.Email); err != nil {
+		return nil, err
+	}
+
+	if usr.Password, err = bcrypt.HashPassword(newPassword); err != nil {
+		logger.Error().Err(err).Msg("Failed to hash password")
+		return nil, errors.New("failed to process password")
+	}
+	if err := uc.repo.Update(usr); err != nil {
+		logger.Error().Err(err).Uint("user_id", id).Msg("Failed to reset password")
+		return nil, errors.New("failed to reset password")
+	}
+	if err := uc.revokeSessions(id); err != nil {
+		return nil, err
+	}
+
+	logger.Info().Uint("user_id", id).Msg("User password reset by administrator")
+	return response, nil
+}
+
+// Unlock lifts the lockout caused by failed logins to a user's account
+func (uc *useCase) Unlock(id, actorID uint) error {
+	usr, err := uc.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := uc.ensureCanManage(id, actorID); err != nil {
+		return err
+	}
+	if err := uc.unlocker.Unlock(usr.Username); err != nil {
+		return errors.New("failed to unlock user")
+	}
+
+	logger.Info().Uint("user_id", id).Msg("User login lockout lifted by administrator")
+	return nil
+}
+
+// Delete soft deletes a user and revokes its refresh tokens
+func (uc *useCase) Delete(id, actorID uint) error {
+	if _, err := uc.repo.GetByID(id); err != nil {
+		return err
+	}
+	if id == actorID {
+		return errors.New("cannot delete your own account")
+	}
+	if err := uc.ensureCanManage(id, actorID); err != nil {
+		return err
+	}
+	if err := uc.ensureNotLastAdministrator(id); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(id); err != nil {
+		logger.Error().Err(err).Uint("user_id", id).Msg("Failed to delete user")
+		return errors.New("failed to delete user")
+	}
+	if err := uc.revokeSessions(id); err != nil {
+		return err
+	}
+
+	logger.Info().Uint("user_id", id).Msg("User deleted successfully")
+	return nil
+}
+
+func (uc *useCase) detail(usr *userDomain.User) (*Detail, error) {
+	roles, err := uc.roles.GetUserRoles(usr.ID)
+	if err != nil {
+		return nil, err
+	}
+	if roles == nil {
+		roles = []roleDomain.Role{}
+	}
+	return &Detail{User: usr, Roles: roles}, nil
+}
+
+func (uc *useCase) ensureEmailAvailable(email string) error {
+	exists, err := uc.repo.ExistsByEmail(email)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to check email existence")
+		return errors.New("failed to check email")
+	}
+	if exists {
+		return errors.New("email already exists")
+	}
+	return nil
+}
+
+// ensureCanManage rejects changes to a user whose roles the actor could not
+// assign, so nobody can take over or remove a user who outranks them
+func (uc *useCase) ensureCanManage(id, actorID uint) error {
+	roles, err := uc.roles.GetUserRoles(id)
+	if err != nil {
+		return err
+	}
+	return uc.roles.EnsureCanAssign(actorID, roles)
+}
+
+func (uc *useCase) ensureNotLastAdministrator(id uint) error {
+	last, err := uc.roles.IsLastAdministrator(id)
+	if err != nil {
+		return errors.New("failed to check administrators")
+	}
+	if last {
+		return errors.New("cannot remove the last administrator")
+	}
+	return nil
+}
+
+func (uc *useCase) revokeSessions(id uint) error {
+	if err := uc.refreshTokens.RevokeAllByUserID(id); err != nil {
+		logger.Error().Err(err).Uint("user_id", id).Msg("Failed to revoke refresh tokens")
+		return errors.New("failed to revoke sessions")
+	}
+	return nil
+}
+
+// generatePassword returns a random temporary password that meets the
+// password policy for usr
+func generatePassword(usr *userDomain.User) (string, error) {
+	max := big.NewInt(int64(len(temporaryPasswordAlphabet)))
+	for {
+		generated := make([]byte, temporaryPasswordLength)
+		for i := range generated {
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return "", err
+			}
+			generated[i] = temporaryPasswordAlphabet[n.Int64()]
+		}
+		if password.Validate(string(generated), usr.Username, usr.Email) == nil {
+			return string(generated), nil
+		}
+	}
+}
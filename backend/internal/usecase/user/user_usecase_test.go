@@ -0,0 +1,294 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	roleDomain "github.com/madr/backend/internal/domain/role"
+	userDomain "github.com/madr/backend/internal/domain/user"
+	refreshTokenRepo "github.com/madr/backend/internal/repository/refreshtoken"
+	userRepo "github.com/madr/backend/internal/repository/user"
+	roleUsecase "github.com/madr/backend/internal/usecase/role"
+	"github.com/madr/backend/pkg/bcrypt"
+	"github.com/madr/backend/pkg/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUserRepository keeps users in memory
+type fakeUserRepository struct {
+	userRepo.Repository
+	users map[uint]*userDomain.User
+}
+
+func (r *fakeUserRepository) Create(usr *userDomain.User) error {
+	usr.ID = uint(len(r.users) + 1)
+	r.users[usr.ID] = usr
+	return nil
+}
+
+func (r *fakeUserRepository) GetByID(id uint) (*userDomain.User, error) {
+	usr, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	clone := *usr
+	return &clone, nil
+}
+
+func (r *fakeUserRepository) Update(usr *userDomain.User) error {
+	clone := *usr
+	r.users[usr.ID] = &clone
+	return nil
+}
+
+func (r *fakeUserRepository) Delete(id uint) error {
+	delete(r.users, id)
+	return nil
+}
+
+func (r *fakeUserRepository) ExistsByUsername(username string) (bool, error) {
+	for _, usr := range r.users {
+		if usr.Username == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeUserRepository) ExistsByEmail(email string) (bool, error) {
+	for _, usr := range r.users {
+		if usr.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeRefreshTokenRepository records which users were signed out
+type fakeRefreshTokenRepository struct {
+	refreshTokenRepo.Repository
+	revoked []uint
+}
+
+func (r *fakeRefreshTokenRepository) RevokeAllByUserID(userID uint) error {
+	r.revoked = append(r.revoked, userID)
+	return nil
+}
+
+// fakeRoleService knows roles 1 (admin) and 2 (treasurer)
+type fakeRoleService struct {
+	userRoles map[uint][]uint
+	admins    int
+}
+
+func (s *fakeRoleService) GetByID(id uint) (*roleDomain.Role, error) {
+	if id != 1 && id != 2 {
+		return nil, errors.New("role not found")
+	}
+	role := &roleDomain.Role{ID: id}
+	return role, nil
+}
+
+func (s *fakeRoleService) GetUserRoles(userID uint) ([]roleDomain.Role, error) {
+	var roles []roleDomain.Role
+	for _, id := range s.userRoles[userID] {
+		roles = append(roles, roleDomain.Role{ID: id})
+	}
+	return roles, nil
+}
+
+func (s *fakeRoleService) SetUserRoles(userID uint, req *roleUsecase.AssignRequest, actorID uint) ([]roleDomain.Role, error) {
+	s.userRoles[userID] = req.RoleIDs
+	return s.GetUserRoles(userID)
+}
+
+// EnsureCanAssign lets only holders of role 1 assign role 1
+func (s *fakeRoleService) EnsureCanAssign(actorID uint, roles []roleDomain.Role) error {
+	for _, role := range roles {
+		if role.ID != 1 {
+			continue
+		}
+		for _, id := range s.userRoles[actorID] {
+			if id == 1 {
+				return nil
+			}
+		}
+		return errors.New("only administrators can assign the admin role")
+	}
+	return nil
+}
+
+func (s *fakeRoleService) IsLastAdministrator(userID uint) (bool, error) {
+	for _, id := range s.userRoles[userID] {
+		if id == 1 {
+			return s.admins <= 1, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeUnlocker records which usernames were unlocked
+type fakeUnlocker struct {
+	unlocked []string
+}
+
+func (u *fakeUnlocker) Unlock(username string) error {
+	u.unlocked = append(u.unlocked, username)
+	return nil
+}
+
+func newTestUseCase() (UseCase, *fakeUserRepository, *fakeRefreshTokenRepository, *fakeRoleService) {
+	users := &fakeUserRepository{users: map[uint]*userDomain.User{}}
+	admin := &userDomain.User{Username: "admin", Email: "admin@madr.local", IsActive: true}
+	staff := &userDomain.User{Username: "bendahara", Email: "bendahara@madr.local", IsActive: true}
+	users.Create(admin)
+	users.Create(staff)
+
+	tokens := &fakeRefreshTokenRepository{}
+	roles := &fakeRoleService{userRoles: map[uint][]uint{1: {1}, 2: {2}}, admins: 1}
+	return NewUseCase(users, tokens, roles, &fakeUnlocker{}), users, tokens, roles
+}
+
+// TestCreate tests creating staff accounts with roles
+func TestCreate(t *testing.T) {
+	uc, users, _, roles := newTestUseCase()
+
+	_, err := uc.Create(&CreateRequest{Username: "admin", Email: "new@madr.local", Password: "rahasia123"}, 1)
+	assert.EqualError(t, err, "username already exists")
+
+	_, err = uc.Create(&CreateRequest{Username: "sekretaris", Email: "admin@madr.local", Password: "rahasia123"}, 1)
+	assert.EqualError(t, err, "email already exists")
+
+	_, err = uc.Create(&CreateRequest{Username: "sekretaris", Email: "sekretaris@madr.local", Password: "rahasiaku"}, 1)
+	assert.EqualError(t, err, "password must contain letters and numbers")
+
+	_, err = uc.Create(&CreateRequest{Username: "sekretaris", Email: "sekretaris@madr.local", Password: "rahasia123", RoleIDs: []uint{7}}, 1)
+	assert.EqualError(t, err, "role not found")
+	assert.Len(t, users.users, 2)
+
+	// Only administrators can create another administrator
+	_, err = uc.Create(&CreateRequest{Username: "sekretaris", Email: "sekretaris@madr.local", Password: "rahasia123", RoleIDs: []uint{1}}, 2)
+	assert.EqualError(t, err, "only administrators can assign the admin role")
+	assert.Len(t, users.users, 2)
+
+	detail, err := uc.Create(&CreateRequest{Username: "sekretaris", Email: "sekretaris@madr.local", Password: "rahasia123", RoleIDs: []uint{2}}, 1)
+	require.NoError(t, err)
+	assert.True(t, detail.IsActive)
+	assert.Equal(t, userDomain.RoleUser, detail.Role)
+	assert.Equal(t, []uint{2}, roles.userRoles[detail.ID])
+	assert.True(t, bcrypt.CheckPasswordHash("rahasia123", users.users[detail.ID].Password))
+}
+
+// TestUpdate tests that a new email needs verification and ends every session
+func TestUpdate(t *testing.T) {
+	uc, users, tokens, _ := newTestUseCase()
+	verifiedAt := time.Now()
+	users.users[2].EmailVerifiedAt = &verifiedAt
+
+	name, same := "Bendahara Masjid", "BENDAHARA@madr.local"
+	detail, err := uc.Update(2, &UpdateRequest{Name: &name, Email: &same}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, name, detail.Name)
+	assert.NotNil(t, users.users[2].EmailVerifiedAt)
+	assert.Empty(t, tokens.revoked)
+
+	taken := "admin@madr.local"
+	_, err = uc.Update(2, &UpdateRequest{Email: &taken}, 1)
+	assert.EqualError(t, err, "email already exists")
+
+	fresh := "bendahara@masjid.id"
+	_, err = uc.Update(2, &UpdateRequest{Email: &fresh}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, fresh, users.users[2].Email)
+	assert.Nil(t, users.users[2].EmailVerifiedAt)
+	assert.Equal(t, []uint{2}, tokens.revoked)
+}
+
+// TestSetActive tests deactivation guards and session revocation
+func TestSetActive(t *testing.T) {
+	uc, users, tokens, roles := newTestUseCase()
+	inactive, active := false, true
+
+	_, err := uc.SetActive(1, 1, &StatusRequest{IsActive: &inactive})
+	assert.EqualError(t, err, "cannot deactivate your own account")
+
+	// A treasurer cannot deactivate an administrator
+	_, err = uc.SetActive(1, 2, &StatusRequest{IsActive: &inactive})
+	assert.EqualError(t, err, "only administrators can assign the admin role")
+
+	roles.userRoles[3] = []uint{1}
+	_, err = uc.SetActive(1, 3, &StatusRequest{IsActive: &inactive})
+	assert.EqualError(t, err, "cannot remove the last administrator")
+	assert.True(t, users.users[1].IsActive)
+
+	detail, err := uc.SetActive(2, 1, &StatusRequest{IsActive: &inactive})
+	require.NoError(t, err)
+	assert.False(t, detail.IsActive)
+	assert.False(t, users.users[2].IsActive)
+	assert.Equal(t, []uint{2}, tokens.revoked)
+
+	detail, err = uc.SetActive(2, 1, &StatusRequest{IsActive: &active})
+	require.NoError(t, err)
+	assert.True(t, detail.IsActive)
+	assert.Equal(t, []uint{2}, tokens.revoked)
+}
+
+// TestResetPassword tests given and generated passwords
+func TestResetPassword(t *testing.T) {
+	uc, users, tokens, _ := newTestUseCase()
+
+	response, err := uc.ResetPassword(2, 1, &ResetPasswordRequest{Password: "passwordbaru1"})
+	require.NoError(t, err)
+	assert.Empty(t, response.TemporaryPassword)
+	assert.True(t, bcrypt.CheckPasswordHash("passwordbaru1", users.users[2].Password))
+
+	response, err = uc.ResetPassword(2, 1, &ResetPasswordRequest{})
+	require.NoError(t, err)
+	assert.Len(t, response.TemporaryPassword, temporaryPasswordLength)
+	assert.NoError(t, password.Validate(response.TemporaryPassword, "bendahara", "bendahara@madr.local"))
+	assert.True(t, bcrypt.CheckPasswordHash(response.TemporaryPassword, users.users[2].Password))
+	assert.Equal(t, []uint{2, 2}, tokens.revoked)
+
+	_, err = uc.ResetPassword(9, 1, &ResetPasswordRequest{})
+	assert.EqualError(t, err, "user not found")
+
+	_, err = uc.ResetPassword(2, 1, &ResetPasswordRequest{Password: "bendahara99"})
+	assert.EqualError(t, err, "password must not contain your username or email")
+
+	// A treasurer cannot take over an administrator's account
+	adminPassword := users.users[1].Password
+	_, err = uc.ResetPassword(1, 2, &ResetPasswordRequest{})
+	assert.EqualError(t, err, "only administrators can assign the admin role")
+	assert.Equal(t, adminPassword, users.users[1].Password)
+	assert.Equal(t, []uint{2, 2}, tokens.revoked)
+}
+
+// TestUnlock tests lifting the login lockout of a user
+func TestUnlock(t *testing.T) {
+	uc, _, _, _ := newTestUseCase()
+	unlocker := uc.(*useCase).unlocker.(*fakeUnlocker)
+
+	require.NoError(t, uc.Unlock(2, 1))
+	assert.Equal(t, []string{"bendahara"}, unlocker.unlocked)
+	assert.EqualError(t, uc.Unlock(9, 1), "user not found")
+	assert.EqualError(t, uc.Unlock(1, 2), "only administrators can assign the admin role")
+	assert.Equal(t, []string{"bendahara"}, unlocker.unlocked)
+}
+
+// TestDelete tests that users cannot delete themselves, their superiors or the last administrator
+func TestDelete(t *testing.T) {
+	uc, users, tokens, roles := newTestUseCase()
+
+	assert.EqualError(t, uc.Delete(1, 1), "cannot delete your own account")
+	assert.EqualError(t, uc.Delete(1, 2), "only administrators can assign the admin role")
+
+	roles.userRoles[3] = []uint{1}
+	assert.EqualError(t, uc.Delete(1, 3), "cannot remove the last administrator")
+
+	roles.admins = 2
+	require.NoError(t, uc.Delete(1, 3))
+	assert.NotContains(t, users.users, uint(1))
+	assert.Equal(t, []uint{1}, tokens.revoked)
+}
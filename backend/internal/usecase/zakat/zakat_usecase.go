@@ -0,0 +1,674 @@
+package zakat
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	donationCategoryDomain "github.com/madr/backend/internal/domain/donationcategory"
+	zakatDomain "github.com/madr/backend/internal/domain/zakat"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	donationCategoryRepo "github.com/madr/backend/internal/repository/donationcategory"
+	zakatRepo "github.com/madr/backend/internal/repository/zakat"
+	donationUsecase "github.com/madr/backend/internal/usecase/donation"
+	"github.com/madr/backend/internal/utils"
+	"github.com/madr/backend/pkg/logger"
+	"github.com/madr/backend/pkg/money"
+)
+
+// Zakat mal is 2.5% (1/40) of wealth at or above nisab held for one haul
+const (
+	malRateNumerator   = 25
+	malRateDenominator = 1000
+	malRatePercent     = 2.5
+)
+
+// UseCase defines the interface for zakat use case
+type UseCase interface {
+	GetRates() (*RatesResponse, error)
+	UpdateSetting(req *UpdateSettingRequest) (*RatesResponse, error)
+	CalculateMal(req *MalRequest) (*MalResult, error)
+	CalculateFitrah(persons int) (*FitrahResult, error)
+
+	CreateMuzakki(req *MuzakkiRequest) (*zakatDomain.Muzakki, error)
+	GetMuzakki(id uint) (*zakatDomain.Muzakki, error)
+	GetMuzakkiList(limit, offset int, search string) (*GetMuzakkiResponse, error)
+	UpdateMuzakki(id uint, req *MuzakkiRequest) (*zakatDomain.Muzakki, error)
+	DeleteMuzakki(id uint) error
+
+	RecordPayment(req *RecordPaymentRequest) (*zakatDomain.Payment, error)
+	GetPayments(limit, offset int, filter *zakatRepo.PaymentFilter) (*GetPaymentsResponse, error)
+	DeletePayment(id uint) error
+
+	GetReport(from, to *time.Time) (*Report, error)
+}
+
+// DonationRecorder records cash zakat as a donation so it reaches the ledger,
+// receipts and donation reports
+type DonationRecorder interface {
+	Create(req *donationUsecase.CreateRequest, actor *donationDomain.Actor) (*donationDomain.Donation, error)
+	Delete(id uint, actor *donationDomain.Actor) error
+}
+
+// RatesResponse represents the current zakat rates
+type RatesResponse struct {
+	zakatDomain.Setting
+	Nisab          money.Money `json:"nisab"` // Nisab gold grams at the current gold price
+	MalRatePercent float64     `json:"mal_rate_percent"`
+}
+
+// UpdateSettingRequest represents the request to update zakat rates
+type UpdateSettingRequest struct {
+	GoldPricePerGram    *money.Money `json:"gold_price_per_gram" binding:"omitempty,gt=0"`
+	NisabGoldGrams      *float64     `json:"nisab_gold_grams" binding:"omitempty,gt=0"`
+	FitrahRiceKg        *float64     `json:"fitrah_rice_kg" binding:"omitempty,gt=0"`
+	FitrahCashPerPerson *money.Money `json:"fitrah_cash_per_person" binding:"omitempty,gt=0"`
+}
+
+// MalRequest represents the wealth to calculate zakat mal on. Each kind of
+// wealth is checked against nisab on its own and assumed held for one haul.
+type MalRequest struct {
+	Savings          money.Money `json:"savings" binding:"gte=0"`           // Savings, deposits and cash
+	Debts            money.Money `json:"debts" binding:"gte=0"`             // Debts due now, deducted from savings
+	GoldGrams        float64     `json:"gold_grams" binding:"gte=0"`        // Gold kept as savings
+	TradeInventory   money.Money `json:"trade_inventory" binding:"gte=0"`   // Stock valued at selling price
+	TradeReceivables money.Money `json:"trade_receivables" binding:"gte=0"` // Receivables expected to be paid
+	TradeCash        money.Money `json:"trade_cash" binding:"gte=0"`        // Business cash
+	TradeDebts       money.Money `json:"trade_debts" binding:"gte=0"`       // Business debts due now
+}
+
+// MalComponent is the zakat due on one kind of wealth
+type MalComponent struct {
+	Type         string      `json:"type"`
+	Wealth       money.Money `json:"wealth"`
+	ReachesNisab bool        `json:"reaches_nisab"`
+	Zakat        money.Money `json:"zakat"`
+}
+
+// MalResult represents the zakat mal calculation
+type MalResult struct {
+	GoldPricePerGram money.Money    `json:"gold_price_per_gram"`
+	NisabGoldGrams   float64        `json:"nisab_gold_grams"`
+	Nisab            money.Money    `json:"nisab"`
+	RatePercent      float64        `json:"rate_percent"`
+	Components       []MalComponent `json:"components"`
+	TotalZakat       money.Money    `json:"total_zakat"`
+}
+
+// FitrahResult represents the zakat fitrah due for a number of persons
+type FitrahResult struct {
+	Persons         int         `json:"persons"`
+	RiceKgPerPerson float64     `json:"rice_kg_per_person"`
+	CashPerPerson   money.Money `json:"cash_per_person"`
+	TotalRiceKg     float64     `json:"total_rice_kg"`
+	TotalCash       money.Money `json:"total_cash"`
+}
+
+// MuzakkiRequest represents the request to create or update a muzakki household
+type MuzakkiRequest struct {
+	HeadName      string  `json:"head_name" binding:"required,min=2,max=255"`
+	Phone         *string `json:"phone" binding:"omitempty,max=30"`
+	Email         *string `json:"email" binding:"omitempty,email,max=255"`
+	Address       string  `json:"address"`
+	HouseholdSize int     `json:"household_size" binding:"omitempty,min=1,max=100"` // Defaults to 1
+	Notes         string  `json:"notes"`
+}
+
+// GetMuzakkiResponse represents the response for listing muzakki
+type GetMuzakkiResponse struct {
+	Data       []zakatDomain.Muzakki `json:"data"`
+	Total      int64                 `json:"total"`
+	Limit      int                   `json:"limit"`
+	Offset     int                   `json:"offset"`
+	TotalPages int                   `json:"total_pages"`
+}
+
+// RecordPaymentRequest represents a zakat payment of a household.
+// For fitrah, persons defaults to the household size and the rice or cash
+// defaults to persons times the current rate. Zakat mal is paid in cash.
+type RecordPaymentRequest struct {
+	MuzakkiID  uint                    `json:"muzakki_id" binding:"required"`
+	ZakatType  zakatDomain.Type        `json:"zakat_type" binding:"required,oneof=fitrah mal"`
+	HijriYear  int                     `json:"hijri_year" binding:"required,min=1400,max=1700"`
+	Form       zakatDomain.PaymentForm `json:"form" binding:"required,oneof=rice cash"`
+	Persons    int                     `json:"persons" binding:"omitempty,min=1,max=100"`
+	RiceKg     float64                 `json:"rice_kg" binding:"omitempty,gt=0"`
+	Amount     *money.Money            `json:"amount" binding:"omitempty,gt=0"`
+	CategoryID *uint                   `json:"category_id"` // Donation category for cash, defaults to the first category of the fund
+	PaidOn     string                  `json:"paid_on"`     // YYYY-MM-DD, defaults to today
+	Notes      string                  `json:"notes"`
+}
+
+// GetPaymentsResponse represents the response for listing zakat payments
+type GetPaymentsResponse struct {
+	Data       []zakatDomain.Payment `json:"data"`
+	Total      int64                 `json:"total"`
+	Limit      int                   `json:"limit"`
+	Offset     int                   `json:"offset"`
+	TotalPages int                   `json:"total_pages"`
+}
+
+// FundTotal represents successful donations of one fund
+type FundTotal struct {
+	FundType     donationCategoryDomain.FundType `json:"fund_type"`
+	Amount       money.Money                     `json:"amount"`
+	Transactions int64                           `json:"transactions"`
+}
+
+// Report separates zakat from general infaq. Zakat funds may only be
+// distributed to the eight asnaf.
+type Report struct {
+	From        string                        `json:"from,omitempty"`
+	To          string                        `json:"to,omitempty"`
+	Funds       []FundTotal                   `json:"funds"`
+	ZakatTotal  money.Money                   `json:"zakat_total"`
+	InfaqTotal  money.Money                   `json:"infaq_total"`
+	PerCategory []donationRepo.CategoryAmount `json:"per_category"`
+	Payments    []zakatRepo.PaymentTotal      `json:"payments"`
+	FitrahRice  float64                       `json:"fitrah_rice_kg"` // Rice is not part of the cash funds
+}
+
+type useCase struct {
+	repo       zakatRepo.Repository
+	categories donationCategoryRepo.Repository
+	donations  donationRepo.Repository
+	recorder   DonationRecorder
+}
+
+// NewUseCase creates a new zakat use case
+func NewUseCase(repo zakatRepo.Repository, categories donationCategoryRepo.Repository, donations donationRepo.Repository, recorder DonationRecorder) UseCase {
+	return &useCase{
+		repo:       repo,
+		categories: categories,
+		donations:  donations,
+		recorder:   recorder,
+	}
+}
+
+// GetRates retrieves the current zakat rates and nisab
+func (uc *useCase) GetRates() (*RatesResponse, error) {
+	setting, err := uc.setting()
+	if err != nil {
+		return nil, err
+	}
+	return ratesOf(setting), nil
+}
+
+// UpdateSetting updates the zakat rates
+func (uc *useCase) UpdateSetting(req *UpdateSettingRequest) (*RatesResponse, error) {
+	setting, err := uc.setting()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GoldPricePerGram != nil {
+		setting.GoldPricePerGram = *req.GoldPricePerGram
+	}
+	if req.NisabGoldGrams != nil {
+		setting.NisabGoldGrams = *req.NisabGoldGrams
+	}
+	if req.FitrahRiceKg != nil {
+		setting.FitrahRiceKg = *req.FitrahRiceKg
+	}
+	if req.FitrahCashPerPerson != nil {
+		setting.FitrahCashPerPerson = *req.FitrahCashPerPerson
+	}
+
+	if err := uc.repo.UpdateSetting(setting); err != nil {
+		logger.Error().Err(err).Msg("Failed to update zakat settings")
+		return nil, errors.New("failed to update zakat settings")
+	}
+
+	logger.Info().
+		Str("gold_price_per_gram", setting.GoldPricePerGram.String()).
+		Str("fitrah_cash_per_person", setting.FitrahCashPerPerson.String()).
+		Msg("Zakat settings updated successfully")
+
+	return ratesOf(setting), nil
+}
+
+// CalculateMal calculates zakat mal on savings, gold and trade
+func (uc *useCase) CalculateMal(req *MalRequest) (*MalResult, error) {
+	setting, err := uc.setting()
+	if err != nil {
+		return nil, err
+	}
+
+	nisab := goldValue(setting.GoldPricePerGram, setting.NisabGoldGrams)
+	wealth := []struct {
+		kind   string
+		amount money.Money
+	}{
+		{"savings", req.Savings.Sub(req.Debts)},
+		{"gold", goldValue(setting.GoldPricePerGram, req.GoldGrams)},
+		{"trade", money.Sum(req.TradeInventory, req.TradeReceivables, req.TradeCash).Sub(req.TradeDebts)},
+	}
+
+	result := &MalResult{
+		GoldPricePerGram: setting.GoldPricePerGram,
+		NisabGoldGrams:   setting.NisabGoldGrams,
+		Nisab:            nisab,
+		RatePercent:      malRatePercent,
+		Components:       make([]MalComponent, 0, len(wealth)),
+	}
+	for _, w := range wealth {
+		component := MalComponent{Type: w.kind, Wealth: w.amount}
+		if w.amount.IsPositive() && w.amount >= nisab {
+			component.ReachesNisab = true
+			component.Zakat = w.amount.MulRatio(malRateNumerator, malRateDenominator)
+		}
+		result.Components = append(result.Components, component)
+		result.TotalZakat = result.TotalZakat.Add(component.Zakat)
+	}
+
+	return result, nil
+}
+
+// CalculateFitrah calculates the zakat fitrah due for a number of persons
+func (uc *useCase) CalculateFitrah(persons int) (*FitrahResult, error) {
+	if persons < 1 || persons > 100 {
+		return nil, errors.New("invalid number of persons")
+	}
+
+	setting, err := uc.setting()
+	if err != nil {
+		return nil, err
+	}
+
+	return &FitrahResult{
+		Persons:         persons,
+		RiceKgPerPerson: setting.FitrahRiceKg,
+		CashPerPerson:   setting.FitrahCashPerPerson,
+		TotalRiceKg:     riceFor(setting, persons),
+		TotalCash:       setting.FitrahCashPerPerson.Mul(int64(persons)),
+	}, nil
+}
+
+// CreateMuzakki creates a new muzakki household
+func (uc *useCase) CreateMuzakki(req *MuzakkiRequest) (*zakatDomain.Muzakki, error) {
+	m := &zakatDomain.Muzakki{}
+	applyMuzakki(m, req)
+
+	if err := uc.repo.CreateMuzakki(m); err != nil {
+		logger.Error().Err(err).Msg("Failed to create muzakki")
+		return nil, errors.New("failed to create muzakki")
+	}
+
+	logger.Info().Uint("id", m.ID).Str("head_name", m.HeadName).Msg("Muzakki created successfully")
+	return m, nil
+}
+
+// GetMuzakki retrieves a muzakki household by ID
+func (uc *useCase) GetMuzakki(id uint) (*zakatDomain.Muzakki, error) {
+	m, err := uc.repo.GetMuzakkiByID(id)
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to get muzakki")
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetMuzakkiList retrieves muzakki households with pagination
+func (uc *useCase) GetMuzakkiList(limit, offset int, search string) (*GetMuzakkiResponse, error) {
+	limit, offset = paginate(limit, offset)
+
+	list, total, err := uc.repo.GetMuzakki(limit, offset, search)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get muzakki")
+		return nil, errors.New("failed to get muzakki")
+	}
+
+	return &GetMuzakkiResponse{
+		Data:       list,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		TotalPages: totalPages(total, limit),
+	}, nil
+}
+
+// UpdateMuzakki updates a muzakki household
+func (uc *useCase) UpdateMuzakki(id uint, req *MuzakkiRequest) (*zakatDomain.Muzakki, error) {
+	m, err := uc.repo.GetMuzakkiByID(id)
+	if err != nil {
+		return nil, err
+	}
+	applyMuzakki(m, req)
+
+	if err := uc.repo.UpdateMuzakki(m); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to update muzakki")
+		return nil, errors.New("failed to update muzakki")
+	}
+
+	logger.Info().Uint("id", id).Msg("Muzakki updated successfully")
+	return m, nil
+}
+
+// DeleteMuzakki deletes a muzakki household without payments
+func (uc *useCase) DeleteMuzakki(id uint) error {
+	if _, err := uc.repo.GetMuzakkiByID(id); err != nil {
+		return err
+	}
+
+	count, err := uc.repo.CountPayments(&zakatRepo.PaymentFilter{MuzakkiID: &id})
+	if err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to count muzakki payments")
+		return errors.New("failed to delete muzakki")
+	}
+	if count > 0 {
+		return errors.New("muzakki has payments")
+	}
+
+	if err := uc.repo.DeleteMuzakki(id); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to delete muzakki")
+		return errors.New("failed to delete muzakki")
+	}
+
+	logger.Info().Uint("id", id).Msg("Muzakki deleted successfully")
+	return nil
+}
+
+// RecordPayment records a zakat payment of a household. Cash is also
+// recorded as a successful donation in a category of the matching fund.
+func (uc *useCase) RecordPayment(req *RecordPaymentRequest) (*zakatDomain.Payment, error) {
+	paidOn := utils.StartOfDay(time.Now())
+	if req.PaidOn != "" {
+		date, err := utils.ParseDate(req.PaidOn)
+		if err != nil {
+			return nil, errors.New("invalid payment date")
+		}
+		paidOn = date
+	}
+	if req.ZakatType == zakatDomain.TypeMal && req.Form != zakatDomain.FormCash {
+		return nil, errors.New("zakat mal must be paid in cash")
+	}
+	if req.ZakatType == zakatDomain.TypeMal && req.Amount == nil {
+		return nil, errors.New("amount is required for zakat mal")
+	}
+
+	m, err := uc.repo.GetMuzakkiByID(req.MuzakkiID)
+	if err != nil {
+		return nil, err
+	}
+	setting, err := uc.setting()
+	if err != nil {
+		return nil, err
+	}
+
+	payment := &zakatDomain.Payment{
+		MuzakkiID: m.ID,
+		ZakatType: req.ZakatType,
+		HijriYear: req.HijriYear,
+		Form:      req.Form,
+		PaidOn:    paidOn,
+		Notes:     req.Notes,
+	}
+
+	if req.ZakatType == zakatDomain.TypeFitrah {
+		payment.Persons = req.Persons
+		if payment.Persons == 0 {
+			payment.Persons = m.HouseholdSize
+		}
+		if req.Form == zakatDomain.FormRice {
+			payment.RiceKg = riceFor(setting, payment.Persons)
+			if req.RiceKg != 0 {
+				if req.RiceKg < payment.RiceKg {
+					return nil, errors.New("payment is below the fitrah rate")
+				}
+				payment.RiceKg = req.RiceKg
+			}
+		} else {
+			payment.Amount = setting.FitrahCashPerPerson.Mul(int64(payment.Persons))
+			if req.Amount != nil {
+				if *req.Amount < payment.Amount {
+					return nil, errors.New("payment is below the fitrah rate")
+				}
+				payment.Amount = *req.Amount
+			}
+		}
+	} else {
+		payment.Amount = *req.Amount
+	}
+
+	if payment.Form == zakatDomain.FormCash {
+		don, err := uc.recordDonation(req, m, payment)
+		if err != nil {
+			return nil, err
+		}
+		payment.DonationID = &don.ID
+	}
+
+	if err := uc.repo.CreatePayment(payment); err != nil {
+		logger.Error().Err(err).Msg("Failed to record zakat payment")
+		if payment.DonationID != nil {
+			if err := uc.recorder.Delete(*payment.DonationID, nil); err != nil {
+				logger.Error().Err(err).Uint("donation_id", *payment.DonationID).Msg("Failed to roll back zakat donation")
+			}
+		}
+		return nil, errors.New("failed to record zakat payment")
+	}
+
+	logger.Info().
+		Uint("id", payment.ID).
+		Uint("muzakki_id", payment.MuzakkiID).
+		Str("zakat_type", string(payment.ZakatType)).
+		Str("form", string(payment.Form)).
+		Msg("Zakat payment recorded successfully")
+
+	payment.MuzakkiName = m.HeadName
+	return payment, nil
+}
+
+// GetPayments retrieves zakat payments with pagination
+func (uc *useCase) GetPayments(limit, offset int, filter *zakatRepo.PaymentFilter) (*GetPaymentsResponse, error) {
+	limit, offset = paginate(limit, offset)
+	if filter != nil && filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, errors.New("invalid date range")
+	}
+
+	payments, total, err := uc.repo.GetPayments(limit, offset, filter)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get zakat payments")
+		return nil, errors.New("failed to get zakat payments")
+	}
+
+	return &GetPaymentsResponse{
+		Data:       payments,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		TotalPages: totalPages(total, limit),
+	}, nil
+}
+
+// DeletePayment deletes a zakat payment together with its donation
+func (uc *useCase) DeletePayment(id uint) error {
+	payment, err := uc.repo.GetPaymentByID(id)
+	if err != nil {
+		return err
+	}
+
+	if payment.DonationID != nil {
+		if err := uc.recorder.Delete(*payment.DonationID, nil); err != nil {
+			return err
+		}
+	}
+	if err := uc.repo.DeletePayment(id); err != nil {
+		logger.Error().Err(err).Uint("id", id).Msg("Failed to delete zakat payment")
+		return errors.New("failed to delete zakat payment")
+	}
+
+	logger.Info().Uint("id", id).Msg("Zakat payment deleted successfully")
+	return nil
+}
+
+// GetReport totals successful donations per fund and zakat payments in the
+// given inclusive date range (default all time)
+func (uc *useCase) GetReport(from, to *time.Time) (*Report, error) {
+	filter := &donationRepo.Filter{}
+	report := &Report{}
+	if from != nil {
+		start := utils.StartOfDay(*from)
+		filter.From = &start
+		report.From = start.Format(utils.DateLayout)
+	}
+	if to != nil {
+		end := utils.StartOfDay(*to).AddDate(0, 0, 1)
+		filter.To = &end
+		report.To = utils.StartOfDay(*to).Format(utils.DateLayout)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, errors.New("invalid date range")
+	}
+
+	categories, err := uc.donations.GetAmountPerCategory(filter)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get donation amount per category")
+		return nil, errors.New("failed to get zakat report")
+	}
+
+	payments, err := uc.repo.GetPaymentTotals(&zakatRepo.PaymentFilter{From: from, To: to})
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get zakat payment totals")
+		return nil, errors.New("failed to get zakat report")
+	}
+
+	report.Funds = []FundTotal{
+		{FundType: donationCategoryDomain.FundZakatFitrah},
+		{FundType: donationCategoryDomain.FundZakatMal},
+		{FundType: donationCategoryDomain.FundInfaq},
+	}
+	funds := map[donationCategoryDomain.FundType]*FundTotal{}
+	for i := range report.Funds {
+		funds[report.Funds[i].FundType] = &report.Funds[i]
+	}
+	for _, ca := range categories {
+		fund, ok := funds[donationCategoryDomain.FundType(ca.FundType)]
+		if !ok {
+			fund = funds[donationCategoryDomain.FundInfaq]
+		}
+		fund.Amount = fund.Amount.Add(ca.Amount)
+		fund.Transactions += ca.Transactions
+		if fund.FundType.IsZakat() {
+			report.ZakatTotal = report.ZakatTotal.Add(ca.Amount)
+		} else {
+			report.InfaqTotal = report.InfaqTotal.Add(ca.Amount)
+		}
+	}
+	for _, p := range payments {
+		report.FitrahRice += p.RiceKg
+	}
+
+	report.PerCategory = categories
+	report.Payments = payments
+	if report.PerCategory == nil {
+		report.PerCategory = []donationRepo.CategoryAmount{}
+	}
+	if report.Payments == nil {
+		report.Payments = []zakatRepo.PaymentTotal{}
+	}
+	return report, nil
+}
+
+// recordDonation records cash zakat as a successful donation
+func (uc *useCase) recordDonation(req *RecordPaymentRequest, m *zakatDomain.Muzakki, payment *zakatDomain.Payment) (*donationDomain.Donation, error) {
+	fundType := donationCategoryDomain.FundZakatFitrah
+	if payment.ZakatType == zakatDomain.TypeMal {
+		fundType = donationCategoryDomain.FundZakatMal
+	}
+
+	var cat *donationCategoryDomain.DonationCategory
+	var err error
+	if req.CategoryID != nil {
+		cat, err = uc.categories.GetByID(*req.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		if cat.FundType != fundType {
+			return nil, errors.New("donation category does not match zakat type")
+		}
+	} else {
+		cat, err = uc.categories.FindByFundType(fundType)
+		if err != nil {
+			logger.Warn().Err(err).Str("fund_type", string(fundType)).Msg("No donation category for zakat fund")
+			return nil, errors.New("zakat category not configured")
+		}
+	}
+
+	message := fmt.Sprintf("Zakat %s %d H", payment.ZakatType, payment.HijriYear)
+	if payment.Persons > 0 {
+		message += fmt.Sprintf(" - %d jiwa", payment.Persons)
+	}
+	donorName := m.HeadName
+
+	don, err := uc.recorder.Create(&donationUsecase.CreateRequest{
+		CategoryID:    cat.ID,
+		DonorName:     &donorName,
+		Amount:        payment.Amount,
+		Message:       message,
+		PaymentStatus: string(donationDomain.PaymentStatusSuccess),
+	}, nil)
+	if err != nil {
+		return nil, errors.New("failed to record zakat payment")
+	}
+	return don, nil
+}
+
+// setting loads the zakat settings
+func (uc *useCase) setting() (*zakatDomain.Setting, error) {
+	setting, err := uc.repo.GetSetting()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get zakat settings")
+		return nil, errors.New("failed to get zakat settings")
+	}
+	return setting, nil
+}
+
+func ratesOf(setting *zakatDomain.Setting) *RatesResponse {
+	return &RatesResponse{
+		Setting:        *setting,
+		Nisab:          goldValue(setting.GoldPricePerGram, setting.NisabGoldGrams),
+		MalRatePercent: malRatePercent,
+	}
+}
+
+func applyMuzakki(m *zakatDomain.Muzakki, req *MuzakkiRequest) {
+	m.HeadName = req.HeadName
+	m.Phone = req.Phone
+	m.Email = req.Email
+	m.Address = req.Address
+	m.Notes = req.Notes
+	m.HouseholdSize = req.HouseholdSize
+	if m.HouseholdSize == 0 {
+		m.HouseholdSize = 1
+	}
+}
+
+// goldValue prices gold to the milligram
+func goldValue(pricePerGram money.Money, grams float64) money.Money {
+	return pricePerGram.MulRatio(int64(math.Round(grams*1000)), 1000)
+}
+
+// riceFor returns the fitrah rice for a number of persons, in kg to two decimals
+func riceFor(setting *zakatDomain.Setting, persons int) float64 {
+	return math.Round(setting.FitrahRiceKg*float64(persons)*100) / 100
+}
+
+func paginate(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+func totalPages(total int64, limit int) int {
+	return int((total + int64(limit) - 1) / int64(limit))
+}
@@ -0,0 +1,254 @@
+package zakat
+
+import (
+	"testing"
+
+	donationDomain "github.com/madr/backend/internal/domain/donation"
+	donationCategoryDomain "github.com/madr/backend/internal/domain/donationcategory"
+	zakatDomain "github.com/madr/backend/internal/domain/zakat"
+	donationRepo "github.com/madr/backend/internal/repository/donation"
+	donationCategoryRepo "github.com/madr/backend/internal/repository/donationcategory"
+	zakatRepo "github.com/madr/backend/internal/repository/zakat"
+	donationUsecase "github.com/madr/backend/internal/usecase/donation"
+	"github.com/madr/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockZakatRepository mocks the zakat repository methods used by the tests
+type MockZakatRepository struct {
+	zakatRepo.Repository
+	mock.Mock
+}
+
+func (m *MockZakatRepository) GetSetting() (*zakatDomain.Setting, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*zakatDomain.Setting), args.Error(1)
+}
+
+func (m *MockZakatRepository) GetMuzakkiByID(id uint) (*zakatDomain.Muzakki, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*zakatDomain.Muzakki), args.Error(1)
+}
+
+func (m *MockZakatRepository) CreatePayment(payment *zakatDomain.Payment) error {
+	args := m.Called(payment)
+	return args.Error(0)
+}
+
+func (m *MockZakatRepository) GetPaymentTotals(filter *zakatRepo.PaymentFilter) ([]zakatRepo.PaymentTotal, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]zakatRepo.PaymentTotal), args.Error(1)
+}
+
+// MockCategoryRepository mocks the donation category repository methods used by zakat
+type MockCategoryRepository struct {
+	donationCategoryRepo.Repository
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) FindByFundType(fundType donationCategoryDomain.FundType) (*donationCategoryDomain.DonationCategory, error) {
+	args := m.Called(fundType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*donationCategoryDomain.DonationCategory), args.Error(1)
+}
+
+// MockDonationRepository mocks the donation repository methods used by zakat
+type MockDonationRepository struct {
+	donationRepo.Repository
+	mock.Mock
+}
+
+func (m *MockDonationRepository) GetAmountPerCategory(filter *donationRepo.Filter) ([]donationRepo.CategoryAmount, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]donationRepo.CategoryAmount), args.Error(1)
+}
+
+// MockDonationRecorder mocks the donation use case
+type MockDonationRecorder struct {
+	mock.Mock
+}
+
+func (m *MockDonationRecorder) Create(req *donationUsecase.CreateRequest, _ *donationDomain.Actor) (*donationDomain.Donation, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*donationDomain.Donation), args.Error(1)
+}
+
+func (m *MockDonationRecorder) Delete(id uint, _ *donationDomain.Actor) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func testSetting() *zakatDomain.Setting {
+	return &zakatDomain.Setting{
+		GoldPricePerGram:    money.FromRupiah(1500000),
+		NisabGoldGrams:      85,
+		FitrahRiceKg:        2.5,
+		FitrahCashPerPerson: money.FromRupiah(45000),
+	}
+}
+
+// TestCalculateMal tests that each kind of wealth is checked against nisab
+// on its own and charged 2.5%
+func TestCalculateMal(t *testing.T) {
+	mockRepo := new(MockZakatRepository)
+	useCase := NewUseCase(mockRepo, nil, nil, nil)
+	mockRepo.On("GetSetting").Return(testSetting(), nil)
+
+	result, err := useCase.CalculateMal(&MalRequest{
+		Savings:        money.FromRupiah(200000000),
+		Debts:          money.FromRupiah(20000000),
+		GoldGrams:      84.5,
+		TradeInventory: money.FromRupiah(100000000),
+		TradeCash:      money.FromRupiah(50000000),
+		TradeDebts:     money.FromRupiah(10000000),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, money.FromRupiah(127500000), result.Nisab)
+	require.Len(t, result.Components, 3)
+
+	savings, gold, trade := result.Components[0], result.Components[1], result.Components[2]
+	assert.Equal(t, money.FromRupiah(180000000), savings.Wealth)
+	assert.True(t, savings.ReachesNisab)
+	assert.Equal(t, money.FromRupiah(4500000), savings.Zakat)
+
+	assert.Equal(t, money.FromRupiah(126750000), gold.Wealth)
+	assert.False(t, gold.ReachesNisab)
+	assert.Equal(t, money.Money(0), gold.Zakat)
+
+	assert.Equal(t, money.FromRupiah(140000000), trade.Wealth)
+	assert.Equal(t, money.FromRupiah(3500000), trade.Zakat)
+
+	assert.Equal(t, money.FromRupiah(8000000), result.TotalZakat)
+}
+
+// TestCalculateFitrah tests the rice and cash due for a household
+func TestCalculateFitrah(t *testing.T) {
+	mockRepo := new(MockZakatRepository)
+	useCase := NewUseCase(mockRepo, nil, nil, nil)
+	mockRepo.On("GetSetting").Return(testSetting(), nil)
+
+	result, err := useCase.CalculateFitrah(3)
+	require.NoError(t, err)
+	assert.Equal(t, 7.5, result.TotalRiceKg)
+	assert.Equal(t, money.FromRupiah(135000), result.TotalCash)
+
+	_, err = useCase.CalculateFitrah(0)
+	assert.EqualError(t, err, "invalid number of persons")
+}
+
+// TestRecordPayment_FitrahCashCreatesDonation tests that cash fitrah defaults
+// to the household rate and is recorded as a successful zakat fitrah donation
+func TestRecordPayment_FitrahCashCreatesDonation(t *testing.T) {
+	mockRepo := new(MockZakatRepository)
+	mockCategories := new(MockCategoryRepository)
+	mockRecorder := new(MockDonationRecorder)
+	useCase := NewUseCase(mockRepo, mockCategories, nil, mockRecorder)
+
+	household := &zakatDomain.Muzakki{HeadName: "Ahmad", HouseholdSize: 4}
+	household.ID = 3
+	category := &donationCategoryDomain.DonationCategory{Name: "Zakat Fitrah", FundType: donationCategoryDomain.FundZakatFitrah}
+	category.ID = 5
+	don := &donationDomain.Donation{}
+	don.ID = 42
+
+	mockRepo.On("GetSetting").Return(testSetting(), nil)
+	mockRepo.On("GetMuzakkiByID", uint(3)).Return(household, nil)
+	mockCategories.On("FindByFundType", donationCategoryDomain.FundZakatFitrah).Return(category, nil)
+	mockRecorder.On("Create", mock.MatchedBy(func(req *donationUsecase.CreateRequest) bool {
+		return req.CategoryID == 5 &&
+			req.Amount == money.FromRupiah(180000) &&
+			*req.DonorName == "Ahmad" &&
+			req.Message == "Zakat fitrah 1446 H - 4 jiwa" &&
+			req.PaymentStatus == "success"
+	})).Return(don, nil)
+	mockRepo.On("CreatePayment", mock.AnythingOfType("*zakat.Payment")).Return(nil)
+
+	payment, err := useCase.RecordPayment(&RecordPaymentRequest{
+		MuzakkiID: 3,
+		ZakatType: zakatDomain.TypeFitrah,
+		HijriYear: 1446,
+		Form:      zakatDomain.FormCash,
+		PaidOn:    "2025-03-28",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, payment.Persons)
+	assert.Equal(t, money.FromRupiah(180000), payment.Amount)
+	require.NotNil(t, payment.DonationID)
+	assert.Equal(t, uint(42), *payment.DonationID)
+	assert.Equal(t, "2025-03-28", payment.PaidOn.Format("2006-01-02"))
+	mockRecorder.AssertExpectations(t)
+}
+
+// TestRecordPayment_Rejections tests invalid zakat payments
+func TestRecordPayment_Rejections(t *testing.T) {
+	mockRepo := new(MockZakatRepository)
+	mockRecorder := new(MockDonationRecorder)
+	useCase := NewUseCase(mockRepo, nil, nil, mockRecorder)
+
+	household := &zakatDomain.Muzakki{HeadName: "Ahmad", HouseholdSize: 2}
+	mockRepo.On("GetSetting").Return(testSetting(), nil)
+	mockRepo.On("GetMuzakkiByID", uint(3)).Return(household, nil)
+
+	_, err := useCase.RecordPayment(&RecordPaymentRequest{
+		MuzakkiID: 3, ZakatType: zakatDomain.TypeMal, HijriYear: 1446, Form: zakatDomain.FormRice,
+	})
+	assert.EqualError(t, err, "zakat mal must be paid in cash")
+
+	_, err = useCase.RecordPayment(&RecordPaymentRequest{
+		MuzakkiID: 3, ZakatType: zakatDomain.TypeMal, HijriYear: 1446, Form: zakatDomain.FormCash,
+	})
+	assert.EqualError(t, err, "amount is required for zakat mal")
+
+	_, err = useCase.RecordPayment(&RecordPaymentRequest{
+		MuzakkiID: 3, ZakatType: zakatDomain.TypeFitrah, HijriYear: 1446, Form: zakatDomain.FormRice, RiceKg: 4,
+	})
+	assert.EqualError(t, err, "payment is below the fitrah rate")
+
+	mockRecorder.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestGetReport_SeparatesZakatFromInfaq tests that donations are totalled per
+// fund and zakat is never mixed with infaq
+func TestGetReport_SeparatesZakatFromInfaq(t *testing.T) {
+	mockRepo := new(MockZakatRepository)
+	mockDonations := new(MockDonationRepository)
+	useCase := NewUseCase(mockRepo, nil, mockDonations, nil)
+
+	mockDonations.On("GetAmountPerCategory", mock.Anything).Return([]donationRepo.CategoryAmount{
+		{CategoryID: 1, CategoryName: "Infaq Pembangunan", FundType: "infaq", Amount: money.FromRupiah(500000), Transactions: 5},
+		{CategoryID: 2, CategoryName: "Zakat Fitrah", FundType: "zakat_fitrah", Amount: money.FromRupiah(180000), Transactions: 1},
+		{CategoryID: 3, CategoryName: "Zakat Mal", FundType: "zakat_mal", Amount: money.FromRupiah(2500000), Transactions: 2},
+		{CategoryID: 4, CategoryName: "Operasional", FundType: "infaq", Amount: money.FromRupiah(100000), Transactions: 1},
+	}, nil)
+	mockRepo.On("GetPaymentTotals", mock.Anything).Return([]zakatRepo.PaymentTotal{
+		{ZakatType: zakatDomain.TypeFitrah, Form: zakatDomain.FormRice, Payments: 3, Households: 3, Persons: 10, RiceKg: 25},
+	}, nil)
+
+	report, err := useCase.GetReport(nil, nil)
+
+	require.NoError(t, err)
+	require.Len(t, report.Funds, 3)
+	assert.Equal(t, donationCategoryDomain.FundZakatFitrah, report.Funds[0].FundType)
+	assert.Equal(t, money.FromRupiah(180000), report.Funds[0].Amount)
+	assert.Equal(t, money.FromRupiah(2500000), report.Funds[1].Amount)
+	assert.Equal(t, money.FromRupiah(600000), report.Funds[2].Amount)
+	assert.Equal(t, int64(6), report.Funds[2].Transactions)
+	assert.Equal(t, money.FromRupiah(2680000), report.ZakatTotal)
+	assert.Equal(t, money.FromRupiah(600000), report.InfaqTotal)
+	assert.Equal(t, 25.0, report.FitrahRice)
+}
@@ -171,3 +171,25 @@ func DeleteFile(filename string) error {
 	return nil
 }
 
+
+// ReadFile reads a file from the upload directory
+func ReadFile(filename string) ([]byte, error) {
+	uploadPath := config.AppConfig.Upload.UploadPath
+	fullPath := filepath.Join(uploadPath, filepath.Base(filename))
+
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return content, nil
+}
+
+// UploadedFileExists reports whether filename names a file saved in the
+// upload directory. Paths are rejected so callers cannot point outside it.
+func UploadedFileExists(filename string) bool {
+	if filename == "" || filename != filepath.Base(filename) || filename == "." || filename == ".." {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(config.AppConfig.Upload.UploadPath, filename))
+	return err == nil && info.Mode().IsRegular()
+}
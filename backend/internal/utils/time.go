@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateLayout is the layout of date-only query parameters
+const DateLayout = "2006-01-02"
+
+// Jakarta is Western Indonesia Time (WIB), used for reporting days and
+// receipt years. Indonesia has no daylight saving, so a fixed zone matches
+// the database's Asia/Jakarta zone without requiring tzdata.
+var Jakarta = time.FixedZone("WIB", 7*60*60)
+
+// ParseDate parses a YYYY-MM-DD date as midnight in Jakarta time
+func ParseDate(s string) (time.Time, error) {
+	t, err := time.ParseInLocation(DateLayout, s, Jakarta)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", s)
+	}
+	return t, nil
+}
+
+// StartOfDay returns midnight of t's day in Jakarta time
+func StartOfDay(t time.Time) time.Time {
+	t = t.In(Jakarta)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, Jakarta)
+}
+
+// AsDate returns the calendar date of t as midnight in Jakarta time, ignoring
+// t's zone. Use it on DATE columns, which the driver returns as UTC midnight.
+func AsDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, Jakarta)
+}
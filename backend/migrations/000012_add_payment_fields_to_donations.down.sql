@@ -0,0 +1,9 @@
+-- Remove payment gateway fields from donations table
+DROP INDEX IF EXISTS idx_donations_transaction_id;
+DROP INDEX IF EXISTS idx_donations_payment_reference;
+
+ALTER TABLE donations DROP COLUMN IF EXISTS paid_at;
+ALTER TABLE donations DROP COLUMN IF EXISTS payment_payload;
+ALTER TABLE donations DROP COLUMN IF EXISTS transaction_id;
+ALTER TABLE donations DROP COLUMN IF EXISTS payment_reference;
+ALTER TABLE donations DROP COLUMN IF EXISTS payment_provider;
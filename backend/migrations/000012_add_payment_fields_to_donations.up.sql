@@ -0,0 +1,10 @@
+-- Add payment gateway fields to donations table
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS payment_provider VARCHAR(50);
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS payment_reference VARCHAR(100);
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS transaction_id VARCHAR(255);
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS payment_payload TEXT;
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS paid_at TIMESTAMP;
+
+-- Payment reference is the order ID sent to the gateway and must be unique
+CREATE UNIQUE INDEX IF NOT EXISTS idx_donations_payment_reference ON donations(payment_reference);
+CREATE INDEX IF NOT EXISTS idx_donations_transaction_id ON donations(transaction_id);
@@ -0,0 +1,25 @@
+-- Per-year receipt counters. The row is locked while a number is allocated, so
+-- concurrent confirmations serialize and a rolled back allocation leaves no gap.
+CREATE TABLE IF NOT EXISTS receipt_sequences (
+    year INTEGER PRIMARY KEY,
+    last_number INTEGER NOT NULL DEFAULT 0
+);
+
+-- Create donation receipts table
+CREATE TABLE IF NOT EXISTS donation_receipts (
+    id SERIAL PRIMARY KEY,
+    donation_id INTEGER NOT NULL,
+    year INTEGER NOT NULL,
+    sequence INTEGER NOT NULL,
+    number VARCHAR(50) NOT NULL,
+    file_name VARCHAR(255),
+    issued_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_donation_receipts_donation FOREIGN KEY (donation_id) REFERENCES donations(id) ON DELETE RESTRICT,
+    CONSTRAINT uq_donation_receipts_year_sequence UNIQUE (year, sequence)
+);
+
+-- A donation has at most one receipt and numbers are never reused
+CREATE UNIQUE INDEX IF NOT EXISTS idx_donation_receipts_donation_id ON donation_receipts(donation_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_donation_receipts_number ON donation_receipts(number);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_donations_status_paid_at;
+
+ALTER TABLE donations
+    ALTER COLUMN paid_at TYPE TIMESTAMP USING paid_at AT TIME ZONE 'UTC',
+    ALTER COLUMN deleted_at TYPE TIMESTAMP USING deleted_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
@@ -0,0 +1,11 @@
+-- Store donation times as absolute instants so reports can bucket them in
+-- Asia/Jakarta time regardless of the server time zone. Existing values were
+-- written as UTC wall clock times.
+ALTER TABLE donations
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE 'UTC',
+    ALTER COLUMN paid_at TYPE TIMESTAMPTZ USING paid_at AT TIME ZONE 'UTC';
+
+-- Reports filter and group on the time a donation was paid
+CREATE INDEX IF NOT EXISTS idx_donations_status_paid_at ON donations(payment_status, (COALESCE(paid_at, created_at)));
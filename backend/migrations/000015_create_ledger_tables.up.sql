@@ -0,0 +1,82 @@
+-- Create ledger accounts table
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_accounts_name ON ledger_accounts(name) WHERE deleted_at IS NULL;
+-- Exactly one account receives donation income
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_accounts_default ON ledger_accounts(is_default) WHERE is_default AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_ledger_accounts_deleted_at ON ledger_accounts(deleted_at);
+
+-- Create ledger categories table
+CREATE TABLE IF NOT EXISTS ledger_categories (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('income', 'expense')),
+    description TEXT,
+    system BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_categories_name_type ON ledger_categories(name, type) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_ledger_categories_deleted_at ON ledger_categories(deleted_at);
+
+-- Create ledger entries table
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES ledger_accounts(id) ON DELETE RESTRICT,
+    category_id INTEGER NOT NULL REFERENCES ledger_categories(id) ON DELETE RESTRICT,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('income', 'expense')),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    description TEXT,
+    entry_date DATE NOT NULL,
+    attachment_url VARCHAR(500),
+    donation_id INTEGER REFERENCES donations(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+-- A donation is posted at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_entries_donation_id ON ledger_entries(donation_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account_date ON ledger_entries(account_id, entry_date, id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_category_id ON ledger_entries(category_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_deleted_at ON ledger_entries(deleted_at);
+
+-- Default account and categories
+INSERT INTO ledger_accounts (name, description, is_default)
+VALUES ('Kas Masjid', 'Kas utama masjid', TRUE)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO ledger_categories (name, type, description, system) VALUES
+    ('Donasi', 'income', 'Donasi yang tercatat otomatis dari pembayaran donasi', TRUE),
+    ('Kotak Amal', 'income', 'Penerimaan kotak amal dan infaq Jumat', FALSE),
+    ('Listrik dan Air', 'expense', 'Tagihan listrik, air, dan internet', FALSE),
+    ('Honor Imam dan Khatib', 'expense', 'Honorarium imam, khatib, dan muadzin', FALSE),
+    ('Kebersihan', 'expense', 'Kebersihan dan perlengkapan masjid', FALSE),
+    ('Renovasi', 'expense', 'Perbaikan dan renovasi bangunan', FALSE),
+    ('Kegiatan', 'expense', 'Kajian, peringatan hari besar, dan kegiatan sosial', FALSE)
+ON CONFLICT DO NOTHING;
+
+-- Post donations that were already paid before the ledger existed
+INSERT INTO ledger_entries (account_id, category_id, type, amount, description, entry_date, donation_id)
+SELECT
+    (SELECT id FROM ledger_accounts WHERE is_default AND deleted_at IS NULL),
+    (SELECT id FROM ledger_categories WHERE system AND type = 'income' AND deleted_at IS NULL),
+    'income',
+    d.amount,
+    'Donasi #' || d.id || COALESCE(' - ' || c.name, ''),
+    (COALESCE(d.paid_at, d.created_at) AT TIME ZONE 'Asia/Jakarta')::date,
+    d.id
+FROM donations d
+LEFT JOIN donation_categories c ON c.id = d.category_id
+WHERE d.payment_status = 'success' AND d.deleted_at IS NULL AND d.amount > 0
+ON CONFLICT DO NOTHING;
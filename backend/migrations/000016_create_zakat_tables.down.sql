@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS zakat_payments;
+DROP TABLE IF EXISTS muzakki;
+DROP TABLE IF EXISTS zakat_settings;
+ALTER TABLE donation_categories DROP COLUMN IF EXISTS fund_type;
@@ -0,0 +1,64 @@
+-- Classify donation categories by fund so zakat is reported apart from infaq
+ALTER TABLE donation_categories
+    ADD COLUMN IF NOT EXISTS fund_type VARCHAR(20) NOT NULL DEFAULT 'infaq'
+    CHECK (fund_type IN ('infaq', 'zakat_fitrah', 'zakat_mal'));
+
+-- Best-effort classification of existing categories by name
+UPDATE donation_categories SET fund_type = 'zakat_fitrah' WHERE name ILIKE '%fitrah%';
+UPDATE donation_categories SET fund_type = 'zakat_mal'
+WHERE name ILIKE '%zakat%' AND fund_type = 'infaq';
+
+-- Zakat settings, a single row
+CREATE TABLE IF NOT EXISTS zakat_settings (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    gold_price_per_gram DECIMAL(15,2) NOT NULL CHECK (gold_price_per_gram > 0),
+    nisab_gold_grams DECIMAL(8,3) NOT NULL DEFAULT 85 CHECK (nisab_gold_grams > 0),
+    fitrah_rice_kg DECIMAL(6,2) NOT NULL DEFAULT 2.5 CHECK (fitrah_rice_kg > 0),
+    fitrah_cash_per_person DECIMAL(15,2) NOT NULL CHECK (fitrah_cash_per_person > 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO zakat_settings (id, gold_price_per_gram, fitrah_cash_per_person)
+VALUES (1, 1500000, 45000)
+ON CONFLICT DO NOTHING;
+
+-- Create muzakki table, one row per household paying zakat
+CREATE TABLE IF NOT EXISTS muzakki (
+    id SERIAL PRIMARY KEY,
+    head_name VARCHAR(255) NOT NULL,
+    phone VARCHAR(30),
+    email VARCHAR(255),
+    address TEXT,
+    household_size INTEGER NOT NULL DEFAULT 1 CHECK (household_size > 0),
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_muzakki_head_name ON muzakki(head_name);
+CREATE INDEX IF NOT EXISTS idx_muzakki_deleted_at ON muzakki(deleted_at);
+
+-- Create zakat payments table. Cash payments are recorded as a successful
+-- donation as well; rice is only counted here.
+CREATE TABLE IF NOT EXISTS zakat_payments (
+    id SERIAL PRIMARY KEY,
+    muzakki_id INTEGER NOT NULL REFERENCES muzakki(id) ON DELETE RESTRICT,
+    zakat_type VARCHAR(10) NOT NULL CHECK (zakat_type IN ('fitrah', 'mal')),
+    hijri_year INTEGER NOT NULL,
+    persons INTEGER NOT NULL DEFAULT 0 CHECK (persons >= 0),
+    form VARCHAR(10) NOT NULL CHECK (form IN ('rice', 'cash')),
+    rice_kg DECIMAL(8,2) NOT NULL DEFAULT 0 CHECK (rice_kg >= 0),
+    amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    donation_id INTEGER REFERENCES donations(id) ON DELETE CASCADE,
+    paid_on DATE NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((form = 'rice' AND zakat_type = 'fitrah' AND rice_kg > 0 AND donation_id IS NULL)
+        OR (form = 'cash' AND amount > 0))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_zakat_payments_donation_id ON zakat_payments(donation_id);
+CREATE INDEX IF NOT EXISTS idx_zakat_payments_muzakki_id ON zakat_payments(muzakki_id);
+CREATE INDEX IF NOT EXISTS idx_zakat_payments_paid_on ON zakat_payments(paid_on);
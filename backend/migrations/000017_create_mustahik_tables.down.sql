@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS distributions;
+DROP TABLE IF EXISTS mustahik;
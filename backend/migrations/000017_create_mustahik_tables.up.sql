@@ -0,0 +1,40 @@
+-- Create mustahik table, one row per household eligible to receive zakat
+CREATE TABLE IF NOT EXISTS mustahik (
+    id SERIAL PRIMARY KEY,
+    head_name VARCHAR(255) NOT NULL,
+    asnaf VARCHAR(20) NOT NULL CHECK (asnaf IN (
+        'fakir', 'miskin', 'amil', 'muallaf', 'riqab', 'gharimin', 'fisabilillah', 'ibnu_sabil'
+    )),
+    phone VARCHAR(30),
+    address TEXT,
+    household_size INTEGER NOT NULL DEFAULT 1 CHECK (household_size > 0),
+    verification_status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (verification_status IN ('pending', 'verified', 'rejected')),
+    verified_at TIMESTAMPTZ,
+    verified_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_mustahik_head_name ON mustahik(head_name);
+CREATE INDEX IF NOT EXISTS idx_mustahik_asnaf ON mustahik(asnaf);
+CREATE INDEX IF NOT EXISTS idx_mustahik_deleted_at ON mustahik(deleted_at);
+
+-- Create distributions table. Each distribution is paid out of the funds
+-- collected in one donation category.
+CREATE TABLE IF NOT EXISTS distributions (
+    id SERIAL PRIMARY KEY,
+    mustahik_id INTEGER NOT NULL REFERENCES mustahik(id) ON DELETE RESTRICT,
+    category_id INTEGER NOT NULL REFERENCES donation_categories(id) ON DELETE RESTRICT,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    distributed_on DATE NOT NULL,
+    description TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_distributions_mustahik_id ON distributions(mustahik_id);
+CREATE INDEX IF NOT EXISTS idx_distributions_category_id ON distributions(category_id);
+CREATE INDEX IF NOT EXISTS idx_distributions_distributed_on ON distributions(distributed_on);
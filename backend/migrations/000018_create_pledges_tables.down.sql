@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS pledge_installments;
+DROP TABLE IF EXISTS pledges;
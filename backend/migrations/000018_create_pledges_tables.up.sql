@@ -0,0 +1,45 @@
+-- Create pledges table, a donor's commitment to give regularly
+CREATE TABLE IF NOT EXISTS pledges (
+    id SERIAL PRIMARY KEY,
+    donor_name VARCHAR(255) NOT NULL,
+    phone VARCHAR(30),
+    email VARCHAR(255),
+    category_id INTEGER NOT NULL REFERENCES donation_categories(id) ON DELETE RESTRICT,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    frequency VARCHAR(10) NOT NULL CHECK (frequency IN ('weekly', 'monthly', 'yearly')),
+    start_date DATE NOT NULL,
+    end_date DATE CHECK (end_date IS NULL OR end_date >= start_date),
+    status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'paused', 'cancelled', 'completed')),
+    installments_generated INTEGER NOT NULL DEFAULT 0 CHECK (installments_generated >= 0),
+    next_due_date DATE NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_pledges_status_next_due_date ON pledges(status, next_due_date);
+CREATE INDEX IF NOT EXISTS idx_pledges_category_id ON pledges(category_id);
+CREATE INDEX IF NOT EXISTS idx_pledges_deleted_at ON pledges(deleted_at);
+
+-- Create pledge installments table, one row per due date. The pending
+-- donation is attached after the row is claimed so a failed donation insert
+-- is retried on the next scheduler run.
+CREATE TABLE IF NOT EXISTS pledge_installments (
+    id SERIAL PRIMARY KEY,
+    pledge_id INTEGER NOT NULL REFERENCES pledges(id) ON DELETE CASCADE,
+    sequence INTEGER NOT NULL CHECK (sequence > 0),
+    due_date DATE NOT NULL,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    donation_id INTEGER REFERENCES donations(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'fulfilled', 'missed')),
+    reminder_sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (pledge_id, sequence)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pledge_installments_donation_id ON pledge_installments(donation_id);
+CREATE INDEX IF NOT EXISTS idx_pledge_installments_status ON pledge_installments(status);
@@ -0,0 +1,21 @@
+-- Create campaigns table, a fundraising target collected through one donation category
+CREATE TABLE IF NOT EXISTS campaigns (
+    id SERIAL PRIMARY KEY,
+    category_id INTEGER NOT NULL REFERENCES donation_categories(id) ON DELETE RESTRICT,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    target_amount DECIMAL(15,2) NOT NULL CHECK (target_amount > 0),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL CHECK (end_date >= start_date),
+    cover_image VARCHAR(500), -- File name in the upload directory
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'closed')),
+    closed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+-- A category collects for at most one live campaign
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaigns_category_id ON campaigns(category_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_campaigns_status_end_date ON campaigns(status, end_date);
+CREATE INDEX IF NOT EXISTS idx_campaigns_deleted_at ON campaigns(deleted_at);
@@ -0,0 +1,4 @@
+-- Link donations to donor accounts. Anonymous donations keep a NULL user_id.
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS user_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_donations_user_id ON donations(user_id);
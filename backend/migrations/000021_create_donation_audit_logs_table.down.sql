@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS donation_audit_logs;
+DROP FUNCTION IF EXISTS reject_donation_audit_log_change();
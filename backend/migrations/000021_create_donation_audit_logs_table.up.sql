@@ -0,0 +1,31 @@
+-- Create donation audit logs table. The trail is append-only: rows are never
+-- updated or deleted, and actor_id is not a foreign key so removing a user
+-- does not rewrite history.
+CREATE TABLE IF NOT EXISTS donation_audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    donation_id INTEGER NOT NULL,
+    action VARCHAR(10) NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    actor_id INTEGER,
+    ip VARCHAR(45),
+    before JSONB,
+    after JSONB,
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_donation_audit_logs_donation_id ON donation_audit_logs(donation_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_donation_audit_logs_actor_id ON donation_audit_logs(actor_id);
+
+CREATE OR REPLACE FUNCTION reject_donation_audit_log_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'donation_audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER donation_audit_logs_append_only
+    BEFORE UPDATE OR DELETE ON donation_audit_logs
+    FOR EACH ROW EXECUTE FUNCTION reject_donation_audit_log_change();
+
+CREATE TRIGGER donation_audit_logs_no_truncate
+    BEFORE TRUNCATE ON donation_audit_logs
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_donation_audit_log_change();
@@ -0,0 +1,94 @@
+-- Create permissions table. Codes are resource:action, e.g. donation:write.
+CREATE TABLE IF NOT EXISTS permissions (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+-- Create roles table. System roles cannot be deleted.
+CREATE TABLE IF NOT EXISTS roles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    is_system BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission_id INTEGER NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, role_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_roles_role_id ON user_roles(role_id);
+
+INSERT INTO permissions (code, description) VALUES
+    ('announcement:read', 'Lihat pengumuman'),
+    ('announcement:write', 'Buat dan ubah pengumuman'),
+    ('announcement:delete', 'Hapus pengumuman'),
+    ('event:write', 'Buat dan ubah event'),
+    ('event:delete', 'Hapus event'),
+    ('gallery:write', 'Tambah item galeri'),
+    ('gallery:delete', 'Hapus item galeri'),
+    ('banner:write', 'Buat dan ubah banner'),
+    ('banner:delete', 'Hapus banner'),
+    ('kajian:write', 'Sinkronisasi kajian dari YouTube'),
+    ('kajian:delete', 'Hapus kajian'),
+    ('upload:write', 'Upload file'),
+    ('about:read', 'Lihat halaman about'),
+    ('about:write', 'Ubah halaman about'),
+    ('donation:read', 'Lihat, laporan, dan ekspor donasi'),
+    ('donation:write', 'Buat dan ubah donasi serta kategori donasi'),
+    ('donation:delete', 'Hapus donasi dan kategori donasi'),
+    ('campaign:read', 'Lihat kampanye'),
+    ('campaign:write', 'Buat dan ubah kampanye'),
+    ('campaign:delete', 'Hapus kampanye'),
+    ('pledge:read', 'Lihat komitmen donasi'),
+    ('pledge:write', 'Buat dan ubah komitmen donasi'),
+    ('pledge:delete', 'Hapus komitmen donasi'),
+    ('ledger:read', 'Lihat buku kas'),
+    ('ledger:write', 'Buat dan ubah akun, kategori, dan entri kas'),
+    ('ledger:delete', 'Hapus akun, kategori, dan entri kas'),
+    ('zakat:read', 'Lihat data zakat'),
+    ('zakat:write', 'Catat zakat dan ubah pengaturan zakat'),
+    ('zakat:delete', 'Hapus muzakki dan pembayaran zakat'),
+    ('mustahik:read', 'Lihat mustahik dan penyaluran'),
+    ('mustahik:write', 'Kelola mustahik dan penyaluran'),
+    ('mustahik:delete', 'Hapus mustahik dan penyaluran'),
+    ('audit:read', 'Lihat audit log'),
+    ('role:read', 'Lihat role dan permission'),
+    ('role:write', 'Buat dan ubah role'),
+    ('role:delete', 'Hapus role'),
+    ('user:read', 'Lihat pengguna dan role-nya'),
+    ('user:write', 'Kelola pengguna dan role-nya')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO roles (name, description, is_system) VALUES
+    ('admin', 'Akses penuh ke seluruh fitur', TRUE),
+    ('treasurer', 'Bendahara: mengelola donasi, kampanye, dan komitmen donasi', FALSE),
+    ('media', 'Tim media: mengelola banner, galeri, dan kajian', FALSE),
+    ('secretary', 'Sekretaris: mengelola pengumuman dan event', FALSE)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r
+JOIN permissions p ON
+    (r.name = 'admin')
+    OR (r.name = 'treasurer' AND (split_part(p.code, ':', 1) IN ('donation', 'campaign', 'pledge') OR p.code = 'upload:write'))
+    OR (r.name = 'media' AND (split_part(p.code, ':', 1) IN ('banner', 'gallery', 'kajian') OR p.code = 'upload:write'))
+    OR (r.name = 'secretary' AND (split_part(p.code, ':', 1) IN ('announcement', 'event') OR p.code = 'upload:write'))
+ON CONFLICT DO NOTHING;
+
+-- Existing administrators keep full access
+INSERT INTO user_roles (user_id, role_id)
+SELECT u.id, r.id FROM users u
+JOIN roles r ON r.name = 'admin'
+WHERE u.role = 'admin' AND u.deleted_at IS NULL
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+INSERT INTO permissions (code, description) VALUES
+    ('user:delete', 'Hapus pengguna')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r
+JOIN permissions p ON p.code = 'user:delete'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
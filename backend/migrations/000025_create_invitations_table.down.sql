@@ -0,0 +1,2 @@
+DELETE FROM permissions WHERE code IN ('invitation:read', 'invitation:write');
+DROP TABLE IF EXISTS invitations;
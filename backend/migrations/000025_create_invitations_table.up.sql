@@ -0,0 +1,26 @@
+-- Create invitations table. Only a SHA-256 hash of each signed invite token is stored.
+CREATE TABLE IF NOT EXISTS invitations (
+    id SERIAL PRIMARY KEY,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    used_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_invitations_created_at ON invitations(created_at);
+
+INSERT INTO permissions (code, description) VALUES
+    ('invitation:read', 'Lihat undangan registrasi'),
+    ('invitation:write', 'Buat dan batalkan undangan registrasi')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r
+JOIN permissions p ON p.code IN ('invitation:read', 'invitation:write')
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,11 @@
+-- Raw tokens cannot be recovered from their hashes; every session has to
+-- log in again
+DELETE FROM refresh_tokens;
+
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+DROP INDEX IF EXISTS idx_refresh_tokens_token_hash;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS family_id;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS token_hash;
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS token VARCHAR(500) NOT NULL UNIQUE;
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_token ON refresh_tokens(token);
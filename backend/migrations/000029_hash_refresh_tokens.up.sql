@@ -0,0 +1,18 @@
+-- Refresh tokens become opaque random values of which only a SHA-256 hash
+-- is stored. Tokens rotated from one login share a family so that reusing
+-- a rotated token can revoke the whole session.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS token_hash CHAR(64);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS family_id VARCHAR(64);
+
+-- Existing tokens keep working; each becomes its own family
+UPDATE refresh_tokens SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex') WHERE token_hash IS NULL;
+UPDATE refresh_tokens SET family_id = md5(random()::text || id::text) WHERE family_id IS NULL;
+
+ALTER TABLE refresh_tokens ALTER COLUMN token_hash SET NOT NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_refresh_tokens_token;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS token;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_user_active;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS signed_in_at;
@@ -0,0 +1,12 @@
+-- When the login that started a refresh token family happened. Rotated
+-- tokens copy it so sessions keep their sign-in time. created_at holds UTC
+-- wall clock times, so the backfill reads it as UTC.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS signed_in_at TIMESTAMPTZ;
+
+UPDATE refresh_tokens rt SET signed_in_at = first.created_at AT TIME ZONE 'UTC'
+FROM (SELECT family_id, MIN(created_at) AS created_at FROM refresh_tokens GROUP BY family_id) first
+WHERE rt.family_id = first.family_id AND rt.signed_in_at IS NULL;
+
+ALTER TABLE refresh_tokens ALTER COLUMN signed_in_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_active ON refresh_tokens(user_id, expires_at) WHERE is_revoked = false;
@@ -0,0 +1,2 @@
+DELETE FROM permissions WHERE code = 'job:read';
+DROP TABLE IF EXISTS job_runs;
@@ -0,0 +1,27 @@
+-- History of background job runs. A run is inserted as running when it starts
+-- and finished with its outcome; runs left running belong to a process that
+-- stopped mid-run.
+CREATE TABLE IF NOT EXISTS job_runs (
+    id BIGSERIAL PRIMARY KEY,
+    job_name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    instance VARCHAR(255) NOT NULL DEFAULT '',
+    scheduled_at TIMESTAMPTZ NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ,
+    duration_ms BIGINT,
+    error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_name_started_at ON job_runs(job_name, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_job_runs_started_at ON job_runs(started_at);
+
+INSERT INTO permissions (code, description) VALUES
+    ('job:read', 'Lihat jadwal dan riwayat background job')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r
+JOIN permissions p ON p.code = 'job:read'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
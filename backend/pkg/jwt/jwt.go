@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -15,18 +17,23 @@ var (
 
 // Claims represents JWT claims
 type Claims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	UserID    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	RoleIDs   []uint `json:"role_ids"`       // RBAC roles, resolved to permissions on each request
+	Type      string `json:"type,omitempty"` // Empty for access tokens; set on every other token
+	SessionID string `json:"sid,omitempty"`  // Refresh token family the access token was issued with
 	jwt.RegisteredClaims
 }
 
-// GenerateAccessToken generates a new access token
-func GenerateAccessToken(userID uint, username, role string) (string, error) {
+// GenerateAccessToken generates a new access token for a session
+func GenerateAccessToken(userID uint, username, role string, roleIDs []uint, sessionID string) (string, error) {
 	claims := &Claims{
-		UserID:   userID,
-		Username: username,
-		Role:     role,
+		UserID:    userID,
+		Username:  username,
+		Role:      role,
+		RoleIDs:   roleIDs,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(config.AppConfig.JWT.AccessExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -45,23 +52,116 @@ func GenerateAccessToken(userID uint, username, role string) (string, error) {
 	return tokenString, nil
 }
 
-// GenerateRefreshToken generates a new refresh token (just a random string, stored in DB)
-func GenerateRefreshToken() (string, error) {
-	// Generate a secure random token
-	// In production, use crypto/rand for better security
-	token := jwt.New(jwt.SigningMethodHS256)
-	claims := token.Claims.(jwt.MapClaims)
-	claims["type"] = "refresh"
-	claims["exp"] = time.Now().Add(config.AppConfig.JWT.RefreshExpiry).Unix()
-	claims["iat"] = time.Now().Unix()
-	claims["jti"] = time.Now().UnixNano() // Unique token ID
+// InviteClaims represents the claims of a registration invite token
+type InviteClaims struct {
+	Type   string `json:"type"`
+	RoleID uint   `json:"role_id"`
+	jwt.RegisteredClaims
+}
 
-	tokenString, err := token.SignedString([]byte(config.AppConfig.JWT.Secret))
-	if err != nil {
+// inviteTokenType marks invite tokens so other tokens signed with the same
+// secret cannot be used as invitations
+const inviteTokenType = "invite"
+
+// GenerateInviteToken generates a signed registration invite token. A random
+// ID makes every token unique; single use is enforced by the stored invitation.
+func GenerateInviteToken(roleID uint, expiresAt time.Time) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
 		return "", err
 	}
 
-	return tokenString, nil
+	now := time.Now()
+	claims := &InviteClaims{
+		Type:   inviteTokenType,
+		RoleID: roleID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        hex.EncodeToString(nonce),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "madr-backend",
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(config.AppConfig.JWT.Secret))
+}
+
+// ValidateInviteToken validates and parses a registration invite token
+func ValidateInviteToken(tokenString string) (*InviteClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &InviteClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(config.AppConfig.JWT.Secret), nil
+	})
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*InviteClaims)
+	if !ok || !token.Valid || claims.Type != inviteTokenType {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// MFAClaims represents the claims of a login challenge token, issued after
+// the password check when a second factor is still needed
+type MFAClaims struct {
+	Type   string `json:"type"`
+	UserID uint   `json:"user_id"`
+	Setup  bool   `json:"setup"` // The user must enroll an authenticator first
+	jwt.RegisteredClaims
+}
+
+// mfaTokenType marks login challenge tokens so they cannot be used as access
+// tokens and vice versa
+const mfaTokenType = "mfa"
+
+// GenerateMFAToken generates a signed login challenge token
+func GenerateMFAToken(userID uint, setup bool, expiresAt time.Time) (string, error) {
+	now := time.Now()
+	claims := &MFAClaims{
+		Type:   mfaTokenType,
+		UserID: userID,
+		Setup:  setup,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "madr-backend",
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(config.AppConfig.JWT.Secret))
+}
+
+// ValidateMFAToken validates and parses a login challenge token
+func ValidateMFAToken(tokenString string) (*MFAClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &MFAClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(config.AppConfig.JWT.Secret), nil
+	})
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*MFAClaims)
+	if !ok || !token.Valid || claims.Type != mfaTokenType {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
 }
 
 // ValidateToken validates and parses a JWT token
@@ -81,7 +181,8 @@ func ValidateToken(tokenString string) (*Claims, error) {
 		return nil, ErrInvalidToken
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+	// Invite and MFA tokens share the secret but are not access tokens
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && claims.Type == "" {
 		return claims, nil
 	}
 
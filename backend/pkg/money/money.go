@@ -0,0 +1,246 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact rupiah amount stored as an integer number of sen
+// (1/100 rupiah). It maps to DECIMAL(15,2) columns without passing through
+// float64, so sums and reports never drift.
+type Money int64
+
+// Scale is the number of minor units per rupiah
+const Scale = 100
+
+var (
+	ErrInvalidAmount = errors.New("invalid amount")
+	ErrOverflow      = errors.New("amount out of range")
+)
+
+// EncodeAsString makes MarshalJSON emit amounts as JSON strings ("50000.00")
+// instead of JSON numbers, for clients that parse numbers as floats. The
+// server sets it once at startup from configuration.
+var EncodeAsString bool
+
+// FromRupiah creates an amount from whole rupiah
+func FromRupiah(rupiah int64) Money {
+	return Money(rupiah * Scale)
+}
+
+// FromSen creates an amount from minor units
+func FromSen(sen int64) Money {
+	return Money(sen)
+}
+
+// Parse parses a decimal string with at most two fractional digits, such as
+// "50000", "-12.5" or "1250000.75". Exponents and thousands separators are rejected.
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("%w: empty", ErrInvalidAmount)
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" || !isDigits(whole) || (hasFrac && (frac == "" || !isDigits(frac))) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("%w: more than two decimal places", ErrInvalidAmount)
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/Scale {
+		return 0, ErrOverflow
+	}
+
+	var sen int64
+	if frac != "" {
+		sen, _ = strconv.ParseInt((frac + "0")[:2], 10, 64)
+	}
+
+	value := units*Scale + sen
+	if value < 0 {
+		return 0, ErrOverflow
+	}
+	if negative {
+		value = -value
+	}
+	return Money(value), nil
+}
+
+// MustParse is like Parse but panics on error; intended for constants and tests
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Sen returns the amount in minor units
+func (m Money) Sen() int64 {
+	return int64(m)
+}
+
+// Rupiah returns the whole rupiah part, truncated toward zero
+func (m Money) Rupiah() int64 {
+	return int64(m) / Scale
+}
+
+// IsWhole reports whether the amount has no sen part
+func (m Money) IsWhole() bool {
+	return int64(m)%Scale == 0
+}
+
+// IsPositive reports whether the amount is greater than zero
+func (m Money) IsPositive() bool {
+	return m > 0
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) Money {
+	return m + other
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) Money {
+	return m - other
+}
+
+// Mul returns m multiplied by an integer factor
+func (m Money) Mul(factor int64) Money {
+	return m * Money(factor)
+}
+
+// MulRatio returns m * num / den rounded half away from zero to the nearest
+// sen, e.g. m.MulRatio(25, 1000) for 2.5%. den must be positive.
+func (m Money) MulRatio(num, den int64) Money {
+	product := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(num))
+	quo, rem := new(big.Int).QuoRem(product, big.NewInt(den), new(big.Int))
+	// Round when twice the remainder reaches den
+	twice := new(big.Int).Lsh(new(big.Int).Abs(rem), 1)
+	if twice.Cmp(big.NewInt(den)) >= 0 {
+		if product.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return Money(quo.Int64())
+}
+
+// Sum adds up amounts exactly
+func Sum(amounts ...Money) Money {
+	var total Money
+	for _, a := range amounts {
+		total += a
+	}
+	return total
+}
+
+// String formats the amount with exactly two decimals, e.g. "50000.00"
+func (m Money) String() string {
+	sen := int64(m)
+	sign := ""
+	if sen < 0 {
+		sign = "-"
+		sen = -sen
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, sen/Scale, sen%Scale)
+}
+
+// Compact formats the amount without trailing zero decimals, e.g. "50000" or "12500.5"
+func (m Money) Compact() string {
+	s := m.String()
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// MarshalJSON encodes the amount as an exact decimal number, or as a string
+// when EncodeAsString is set
+func (m Money) MarshalJSON() ([]byte, error) {
+	if EncodeAsString {
+		return []byte(`"` + m.String() + `"`), nil
+	}
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts both JSON numbers and decimal strings
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value stores the amount as a decimal string so the database never sees a float
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan reads DECIMAL/NUMERIC columns, which drivers return as text
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case int64:
+		*m = FromRupiah(v)
+		return nil
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidAmount, src)
+	}
+}
+
+func (m *Money) scanString(s string) error {
+	// NUMERIC columns without a scale may carry extra zero decimals
+	if whole, frac, ok := strings.Cut(s, "."); ok && len(frac) > 2 {
+		if strings.TrimRight(frac[2:], "0") != "" {
+			return fmt.Errorf("%w: %q has sub-sen precision", ErrInvalidAmount, s)
+		}
+		s = whole + "." + frac[:2]
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
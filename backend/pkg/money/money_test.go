@@ -0,0 +1,167 @@
+package money
+
+import (
+	"encoding/json"
+	"math/big"
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParse tests accepted and rejected decimal strings
+func TestParse(t *testing.T) {
+	valid := map[string]Money{
+		"0":                    0,
+		"50000":                5000000,
+		"50000.5":              5000050,
+		"50000.05":             5000005,
+		"-12.30":               -1230,
+		"+7":                   700,
+		" 1250000.75 ":         125000075,
+		"0.01":                 1,
+		"92233720368547758.07": Money(9223372036854775807),
+	}
+	for s, expected := range valid {
+		m, err := Parse(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, m, s)
+	}
+
+	for _, s := range []string{"", "-", ".5", "5.", "1.234", "1e5", "1,000", "abc", "--5", "92233720368547758.08"} {
+		_, err := Parse(s)
+		assert.Error(t, err, s)
+	}
+}
+
+// TestString tests formatting
+func TestString(t *testing.T) {
+	assert.Equal(t, "50000.00", FromRupiah(50000).String())
+	assert.Equal(t, "-0.05", FromSen(-5).String())
+	assert.Equal(t, "50000", FromRupiah(50000).Compact())
+	assert.Equal(t, "12500.5", MustParse("12500.50").Compact())
+	assert.Equal(t, "0", Money(0).Compact())
+}
+
+// TestJSON tests number and string encodings
+func TestJSON(t *testing.T) {
+	type payload struct {
+		Amount Money `json:"amount"`
+	}
+
+	data, err := json.Marshal(payload{Amount: MustParse("100.10")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":100.10}`, string(data))
+
+	EncodeAsString = true
+	t.Cleanup(func() { EncodeAsString = false })
+	data, err = json.Marshal(payload{Amount: MustParse("100.10")})
+	require.NoError(t, err)
+	assert.Equal(t, `{"amount":"100.10"}`, string(data))
+
+	var p payload
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":"0.30"}`), &p))
+	assert.Equal(t, FromSen(30), p.Amount)
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":0.1}`), &p))
+	assert.Equal(t, FromSen(10), p.Amount)
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":0.001}`), &p))
+}
+
+// TestScan tests reading database values
+func TestScan(t *testing.T) {
+	var m Money
+	require.NoError(t, m.Scan([]byte("1250000.75")))
+	assert.Equal(t, FromSen(125000075), m)
+	require.NoError(t, m.Scan("10.5000"))
+	assert.Equal(t, FromSen(1050), m)
+	require.NoError(t, m.Scan(int64(3)))
+	assert.Equal(t, FromRupiah(3), m)
+	require.NoError(t, m.Scan(nil))
+	assert.Equal(t, Money(0), m)
+	assert.Error(t, m.Scan("10.005"))
+	assert.Error(t, m.Scan(1.5))
+
+	v, err := FromSen(1).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "0.01", v)
+}
+
+// amountRange keeps generated amounts within DECIMAL(15,2)
+const amountRange = 1_000_000_000_000_00
+
+func randomAmount(r *rand.Rand) Money {
+	return Money(r.Int63n(2*amountRange) - amountRange)
+}
+
+// TestProperty_RoundTrip tests that String/Parse and JSON are lossless
+func TestProperty_RoundTrip(t *testing.T) {
+	property := func(seed int64) bool {
+		m := randomAmount(rand.New(rand.NewSource(seed)))
+
+		parsed, err := Parse(m.String())
+		if err != nil || parsed != m {
+			return false
+		}
+
+		var scanned Money
+		if err := scanned.Scan([]byte(m.String())); err != nil || scanned != m {
+			return false
+		}
+
+		data, err := json.Marshal(m)
+		if err != nil {
+			return false
+		}
+		var decoded Money
+		return json.Unmarshal(data, &decoded) == nil && decoded == m
+	}
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 2000}))
+}
+
+// TestProperty_SumIsExact tests that summing parsed row amounts equals the
+// exact rational sum of their decimal strings, as a SUM() over DECIMAL would
+func TestProperty_SumIsExact(t *testing.T) {
+	property := func(seed int64, n uint8) bool {
+		r := rand.New(rand.NewSource(seed))
+		rows := make([]Money, int(n)+1)
+		exact := new(big.Rat)
+		for i := range rows {
+			rows[i] = Money(r.Int63n(amountRange))
+			value, ok := new(big.Rat).SetString(rows[i].String())
+			if !ok {
+				return false
+			}
+			exact.Add(exact, value)
+		}
+
+		expected, err := Parse(exact.FloatString(2))
+		return err == nil && Sum(rows...) == expected
+	}
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 500}))
+}
+
+// TestSum_NoFloatDrift tests the classic 0.1 + 0.2 case that float64 gets wrong
+func TestSum_NoFloatDrift(t *testing.T) {
+	total := Sum(MustParse("0.10"), MustParse("0.20"))
+	assert.Equal(t, MustParse("0.30"), total)
+
+	var float float64
+	var exact Money
+	for i := 0; i < 1000; i++ {
+		float += 0.1
+		exact = exact.Add(MustParse("0.1"))
+	}
+	assert.NotEqual(t, 100.0, float)
+	assert.Equal(t, FromRupiah(100), exact)
+}
+
+// TestMulRatio tests percentage and fractional quantities with half-up rounding
+func TestMulRatio(t *testing.T) {
+	assert.Equal(t, MustParse("2500.00"), FromRupiah(100000).MulRatio(25, 1000))
+	assert.Equal(t, MustParse("0.03"), MustParse("1.00").MulRatio(25, 1000))   // 0.025 rounds up
+	assert.Equal(t, MustParse("-0.03"), MustParse("-1.00").MulRatio(25, 1000)) // away from zero
+	assert.Equal(t, MustParse("0.02"), MustParse("0.99").MulRatio(25, 1000))   // 0.02475
+	assert.Equal(t, FromRupiah(127500000), FromRupiah(1500000).MulRatio(85000, 1000))
+}
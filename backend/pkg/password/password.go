@@ -0,0 +1,56 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MinLength is the minimum number of characters in a password
+	MinLength = 8
+	// MaxBytes is the longest password bcrypt hashes in full
+	MaxBytes = 72
+)
+
+// Validate checks a new password against the password policy. Personal
+// values such as the username or email must not appear in the password.
+func Validate(password string, personal ...string) error {
+	if len([]rune(password)) < MinLength {
+		return errors.New("password must be at least 8 characters")
+	}
+	if len(password) > MaxBytes {
+		return errors.New("password must be at most 72 bytes")
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if !hasLetter || !hasDigit {
+		return errors.New("password must contain letters and numbers")
+	}
+
+	lower := strings.ToLower(password)
+	for _, value := range personal {
+		// Only the local part of an email is something people reuse
+		if at := strings.Index(value, "@"); at >= 0 {
+			value = value[:at]
+		}
+		value = strings.ToLower(strings.TrimSpace(value))
+		if len(value) >= 3 && strings.Contains(lower, value) {
+			return errors.New("password must not contain your username or email")
+		}
+	}
+	return nil
+}
+
+// IsPolicyError reports whether err was returned by Validate
+func IsPolicyError(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "password must ")
+}
@@ -0,0 +1,40 @@
+package password
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidate tests the password policy
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		wantErr  string
+	}{
+		{name: "valid", password: "kurma-ajwa7"},
+		{name: "unicode letters", password: "سلام123456"},
+		{name: "too short", password: "abc1234", wantErr: "password must be at least 8 characters"},
+		{name: "too long", password: string(make([]byte, 73)), wantErr: "password must be at most 72 bytes"},
+		{name: "letters only", password: "kurmaajwa", wantErr: "password must contain letters and numbers"},
+		{name: "digits only", password: "12345678", wantErr: "password must contain letters and numbers"},
+		{name: "contains username", password: "Jamaah2024", wantErr: "password must not contain your username or email"},
+		{name: "contains email local part", password: "ahmad.f99", wantErr: "password must not contain your username or email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.password, "jamaah", "ahmad.f@example.com")
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+			assert.True(t, IsPolicyError(err))
+		})
+	}
+
+	assert.False(t, IsPolicyError(errors.New("invalid current password")))
+}
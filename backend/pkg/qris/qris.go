@@ -0,0 +1,285 @@
+package qris
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/madr/backend/pkg/money"
+)
+
+// EMVCo merchant-presented mode tags used by QRIS
+const (
+	TagPayloadFormat     = "00"
+	TagInitiationMethod  = "01"
+	TagMerchantCategory  = "52"
+	TagCurrency          = "53"
+	TagAmount            = "54"
+	TagCountry           = "58"
+	TagMerchantName      = "59"
+	TagMerchantCity      = "60"
+	TagPostalCode        = "61"
+	TagAdditionalData    = "62"
+	TagCRC               = "63"
+	SubTagBillNumber     = "01"
+	SubTagReferenceLabel = "05"
+	SubTagTerminalLabel  = "07"
+	InitiationStatic     = "11"
+	InitiationDynamic    = "12"
+	maxFieldLength       = 99
+	maxReferenceLength   = 25
+	maxAmountFieldLength = 13
+	crcFieldPrefix       = TagCRC + "04"
+	crcFieldLength       = 8
+)
+
+var (
+	ErrInvalidFormat = errors.New("invalid QRIS format")
+	ErrInvalidCRC    = errors.New("invalid QRIS checksum")
+)
+
+// Field is a single ID-length-value data object
+type Field struct {
+	ID    string
+	Value string
+}
+
+// Payload is a decoded QRIS payload with fields in their original order
+type Payload struct {
+	Fields []Field
+}
+
+// Decode parses a QRIS string and validates its CRC
+func Decode(s string) (*Payload, error) {
+	if len(s) < crcFieldLength || s[len(s)-crcFieldLength:len(s)-4] != crcFieldPrefix {
+		return nil, fmt.Errorf("%w: missing CRC field", ErrInvalidFormat)
+	}
+
+	if expected := CRC16(s[:len(s)-4]); !strings.EqualFold(expected, s[len(s)-4:]) {
+		return nil, ErrInvalidCRC
+	}
+
+	fields, err := ParseFields(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Payload{Fields: fields}, nil
+}
+
+// ParseFields parses a sequence of ID-length-value data objects.
+// Lengths count characters, so multi-byte UTF-8 values are supported.
+func ParseFields(s string) ([]Field, error) {
+	var fields []Field
+	for len(s) > 0 {
+		if len(s) < 4 {
+			return nil, fmt.Errorf("%w: truncated field header", ErrInvalidFormat)
+		}
+		id := s[:2]
+		length, err := strconv.Atoi(s[2:4])
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid length for field %s", ErrInvalidFormat, id)
+		}
+		s = s[4:]
+
+		end := 0
+		for i := 0; i < length; i++ {
+			if end >= len(s) {
+				return nil, fmt.Errorf("%w: field %s exceeds payload", ErrInvalidFormat, id)
+			}
+			_, size := utf8.DecodeRuneInString(s[end:])
+			end += size
+		}
+
+		fields = append(fields, Field{ID: id, Value: s[:end]})
+		s = s[end:]
+	}
+	return fields, nil
+}
+
+// EncodeFields serializes data objects in the given order
+func EncodeFields(fields []Field) (string, error) {
+	var b strings.Builder
+	for _, f := range fields {
+		length := utf8.RuneCountInString(f.Value)
+		if length > maxFieldLength {
+			return "", fmt.Errorf("%w: field %s is too long", ErrInvalidFormat, f.ID)
+		}
+		fmt.Fprintf(&b, "%s%02d%s", f.ID, length, f.Value)
+	}
+	return b.String(), nil
+}
+
+// Get returns the value of a top-level field
+func (p *Payload) Get(id string) (string, bool) {
+	for _, f := range p.Fields {
+		if f.ID == id {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// Set replaces a top-level field, or inserts it before the first field
+// with a higher ID so the CRC field stays last
+func (p *Payload) Set(id, value string) {
+	p.Fields = setField(p.Fields, id, value)
+}
+
+func setField(fields []Field, id, value string) []Field {
+	for i := range fields {
+		if fields[i].ID == id {
+			fields[i].Value = value
+			return fields
+		}
+	}
+	pos := len(fields)
+	for i := range fields {
+		if fields[i].ID > id {
+			pos = i
+			break
+		}
+	}
+	fields = append(fields, Field{})
+	copy(fields[pos+1:], fields[pos:])
+	fields[pos] = Field{ID: id, Value: value}
+	return fields
+}
+
+// Remove deletes a top-level field
+func (p *Payload) Remove(id string) {
+	fields := p.Fields[:0]
+	for _, f := range p.Fields {
+		if f.ID != id {
+			fields = append(fields, f)
+		}
+	}
+	p.Fields = fields
+}
+
+// Encode serializes the payload in field order and appends a fresh CRC
+func (p *Payload) Encode() (string, error) {
+	fields := make([]Field, 0, len(p.Fields))
+	for _, f := range p.Fields {
+		if f.ID != TagCRC {
+			fields = append(fields, f)
+		}
+	}
+
+	body, err := EncodeFields(fields)
+	if err != nil {
+		return "", err
+	}
+
+	body += crcFieldPrefix
+	return body + CRC16(body), nil
+}
+
+// IsDynamic reports whether the payload uses the dynamic initiation method
+func (p *Payload) IsDynamic() bool {
+	method, _ := p.Get(TagInitiationMethod)
+	return method == InitiationDynamic
+}
+
+// Amount returns the transaction amount in tag 54, if present
+func (p *Payload) Amount() (money.Money, bool) {
+	v, ok := p.Get(TagAmount)
+	if !ok {
+		return 0, false
+	}
+	amount, err := money.Parse(v)
+	if err != nil {
+		return 0, false
+	}
+	return amount, true
+}
+
+// ReferenceLabel returns the reference label (tag 62 sub-tag 05), if present
+func (p *Payload) ReferenceLabel() (string, bool) {
+	v, ok := p.Get(TagAdditionalData)
+	if !ok {
+		return "", false
+	}
+	subFields, err := ParseFields(v)
+	if err != nil {
+		return "", false
+	}
+	for _, f := range subFields {
+		if f.ID == SubTagReferenceLabel {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// MerchantName returns the merchant name (tag 59)
+func (p *Payload) MerchantName() string {
+	v, _ := p.Get(TagMerchantName)
+	return v
+}
+
+// Dynamic converts a static merchant QRIS into a dynamic one carrying
+// the amount and a reference label used to match the payment later.
+func Dynamic(static string, amount money.Money, reference string) (string, error) {
+	p, err := Decode(static)
+	if err != nil {
+		return "", err
+	}
+
+	if !amount.IsPositive() {
+		return "", fmt.Errorf("%w: amount must be positive", ErrInvalidFormat)
+	}
+	amountStr := FormatAmount(amount)
+	if len(amountStr) > maxAmountFieldLength {
+		return "", fmt.Errorf("%w: amount is too large", ErrInvalidFormat)
+	}
+	if len(reference) > maxReferenceLength {
+		return "", fmt.Errorf("%w: reference label is too long", ErrInvalidFormat)
+	}
+
+	p.Set(TagInitiationMethod, InitiationDynamic)
+	p.Set(TagAmount, amountStr)
+
+	if reference != "" {
+		var subFields []Field
+		if existing, ok := p.Get(TagAdditionalData); ok {
+			if subFields, err = ParseFields(existing); err != nil {
+				return "", err
+			}
+		}
+		subFields = setField(subFields, SubTagReferenceLabel, reference)
+		additional, err := EncodeFields(subFields)
+		if err != nil {
+			return "", err
+		}
+		p.Set(TagAdditionalData, additional)
+	}
+
+	return p.Encode()
+}
+
+// FormatAmount formats an amount for tag 54, omitting decimals for whole rupiah
+func FormatAmount(amount money.Money) string {
+	if amount.IsWhole() {
+		return strconv.FormatInt(amount.Rupiah(), 10)
+	}
+	return amount.String()
+}
+
+// CRC16 computes the CRC-16/CCITT-FALSE checksum as 4 uppercase hex digits
+func CRC16(data string) string {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return fmt.Sprintf("%04X", crc)
+}
@@ -0,0 +1,111 @@
+package qris
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/madr/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// emvcoSample is the merchant-presented example from the EMVCo QR specification
+const emvcoSample = "00020101021229300012D156000000000510A93FO3230Q31280012D15600000001030812345678520441115802CN5914BEST TRANSPORT6007BEIJING64200002ZH0104最佳运输0202北京540523.7253031565502016233030412340603***0708A60086670902ME91320016A0112233449988770708123456786304A13A"
+
+// staticQRIS is a static QRIS as printed by an acquirer for a mosque merchant
+const staticQRIS = "00020101021126660014ID.CO.QRIS.WWW01189360091100123456780215ID10200012345670303UMI51440014ID.CO.QRIS.WWW0215ID10200012345670303UMI5204866153033605802ID5916MASJID AL IKHLAS6015JAKARTA SELATAN61051234562070703A016304A1EC"
+
+// dynamicQRIS is staticQRIS with amount 50000 and a donation reference label
+const dynamicQRIS = "00020101021226660014ID.CO.QRIS.WWW01189360091100123456780215ID10200012345670303UMI51440014ID.CO.QRIS.WWW0215ID10200012345670303UMI5204866153033605405500005802ID5916MASJID AL IKHLAS6015JAKARTA SELATAN61051234562360525DON-20250115-3F2A9C1B7D4E0703A016304C84B"
+
+// TestCRC16 tests the CRC-16/CCITT-FALSE check value
+func TestCRC16(t *testing.T) {
+	assert.Equal(t, "29B1", CRC16("123456789"))
+}
+
+// TestDecode_RoundTrip tests that decoding and re-encoding known payloads is lossless
+func TestDecode_RoundTrip(t *testing.T) {
+	for _, s := range []string{emvcoSample, staticQRIS, dynamicQRIS} {
+		p, err := Decode(s)
+		require.NoError(t, err)
+
+		encoded, err := p.Encode()
+		require.NoError(t, err)
+		assert.Equal(t, s, encoded)
+	}
+}
+
+// TestDecode_Fields tests field access on a decoded EMVCo payload
+func TestDecode_Fields(t *testing.T) {
+	p, err := Decode(emvcoSample)
+	require.NoError(t, err)
+
+	assert.True(t, p.IsDynamic())
+	assert.Equal(t, "BEST TRANSPORT", p.MerchantName())
+
+	amount, ok := p.Amount()
+	assert.True(t, ok)
+	assert.Equal(t, money.MustParse("23.72"), amount)
+
+	multiLang, ok := p.Get("64")
+	assert.True(t, ok)
+	fields, err := ParseFields(multiLang)
+	require.NoError(t, err)
+	assert.Equal(t, "最佳运输", fields[1].Value)
+}
+
+// TestDecode_InvalidCRC tests that a tampered payload is rejected
+func TestDecode_InvalidCRC(t *testing.T) {
+	tampered := staticQRIS[:len(staticQRIS)-4] + "0000"
+	_, err := Decode(tampered)
+	assert.ErrorIs(t, err, ErrInvalidCRC)
+
+	_, err = Decode("000201")
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+}
+
+// TestDynamic tests converting a static QRIS to a dynamic one
+func TestDynamic(t *testing.T) {
+	d, err := Dynamic(staticQRIS, money.FromRupiah(50000), "DON-20250115-3F2A9C1B7D4E")
+	require.NoError(t, err)
+	assert.Equal(t, dynamicQRIS, d)
+
+	p, err := Decode(d)
+	require.NoError(t, err)
+	assert.True(t, p.IsDynamic())
+
+	amount, ok := p.Amount()
+	assert.True(t, ok)
+	assert.Equal(t, money.FromRupiah(50000), amount)
+
+	ref, ok := p.ReferenceLabel()
+	assert.True(t, ok)
+	assert.Equal(t, "DON-20250115-3F2A9C1B7D4E", ref)
+}
+
+// TestDynamic_Validation tests amount and reference limits
+func TestDynamic_Validation(t *testing.T) {
+	_, err := Dynamic(staticQRIS, 0, "REF")
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+
+	_, err = Dynamic(staticQRIS, money.FromRupiah(1000), "THIS-REFERENCE-IS-WAY-TOO-LONG")
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+}
+
+// TestFormatAmount tests amount formatting for tag 54
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "50000", FormatAmount(money.FromRupiah(50000)))
+	assert.Equal(t, "12500.50", FormatAmount(money.MustParse("12500.5")))
+}
+
+// TestRender tests PNG and SVG output
+func TestRender(t *testing.T) {
+	png, err := PNG(dynamicQRIS, 256)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(png, []byte("\x89PNG")))
+
+	svg, err := SVG(dynamicQRIS, 256)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(svg, []byte("<svg")))
+	assert.True(t, bytes.HasSuffix(svg, []byte("</svg>")))
+}
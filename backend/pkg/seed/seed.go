@@ -2,8 +2,10 @@ package seed
 
 import (
 	donationCategoryDomain "github.com/madr/backend/internal/domain/donationcategory"
+	roleDomain "github.com/madr/backend/internal/domain/role"
 	"github.com/madr/backend/internal/domain/user"
 	donationCategoryRepo "github.com/madr/backend/internal/repository/donationcategory"
+	roleRepo "github.com/madr/backend/internal/repository/role"
 	userRepo "github.com/madr/backend/internal/repository/user"
 	"github.com/madr/backend/pkg/bcrypt"
 	"github.com/madr/backend/pkg/logger"
@@ -42,6 +44,16 @@ func SeedDefaultAdmin() error {
 		return err
 	}
 
+	// Grant the admin system role created by the RBAC migration
+	roles := roleRepo.NewRepository()
+	adminRole, err := roles.GetByName(roleDomain.AdminRoleName)
+	if err != nil {
+		return err
+	}
+	if err := roles.SetUserRoles(adminUser.ID, []uint{adminRole.ID}); err != nil {
+		return err
+	}
+
 	logger.Info().
 		Str("username", "admin").
 		Str("email", "admin@madr.local").
@@ -0,0 +1,76 @@
+package terbilang
+
+import (
+	"strings"
+
+	"github.com/madr/backend/pkg/money"
+)
+
+var units = []string{
+	"", "satu", "dua", "tiga", "empat", "lima",
+	"enam", "tujuh", "delapan", "sembilan", "sepuluh", "sebelas",
+}
+
+var scales = []struct {
+	value int64
+	name  string
+}{
+	{1_000_000_000_000, "triliun"},
+	{1_000_000_000, "miliar"},
+	{1_000_000, "juta"},
+}
+
+// Number spells out a non-negative integer in Bahasa Indonesia,
+// e.g. 1250 becomes "seribu dua ratus lima puluh"
+func Number(n int64) string {
+	if n == 0 {
+		return "nol"
+	}
+	if n < 0 {
+		return "minus " + Number(-n)
+	}
+	return strings.TrimSpace(spell(n))
+}
+
+// Rupiah spells out an amount in rupiah, including sen when present,
+// e.g. 150000.50 becomes "seratus lima puluh ribu rupiah lima puluh sen"
+func Rupiah(amount money.Money) string {
+	words := Number(amount.Rupiah()) + " rupiah"
+	if sen := amount.Sen() % money.Scale; sen != 0 {
+		words += " " + Number(sen) + " sen"
+	}
+	return words
+}
+
+func spell(n int64) string {
+	switch {
+	case n < 12:
+		return units[n]
+	case n < 20:
+		return units[n-10] + " belas"
+	case n < 100:
+		return join(units[n/10]+" puluh", spell(n%10))
+	case n < 200:
+		return join("seratus", spell(n-100))
+	case n < 1000:
+		return join(units[n/100]+" ratus", spell(n%100))
+	case n < 2000:
+		return join("seribu", spell(n-1000))
+	case n < 1_000_000:
+		return join(spell(n/1000)+" ribu", spell(n%1000))
+	}
+
+	for _, s := range scales {
+		if n >= s.value {
+			return join(spell(n/s.value)+" "+s.name, spell(n%s.value))
+		}
+	}
+	return ""
+}
+
+func join(head, tail string) string {
+	if tail == "" {
+		return head
+	}
+	return head + " " + tail
+}
@@ -0,0 +1,43 @@
+package terbilang
+
+import (
+	"testing"
+
+	"github.com/madr/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNumber tests spelling of the irregular and scale boundaries
+func TestNumber(t *testing.T) {
+	cases := map[int64]string{
+		0:             "nol",
+		1:             "satu",
+		10:            "sepuluh",
+		11:            "sebelas",
+		15:            "lima belas",
+		21:            "dua puluh satu",
+		100:           "seratus",
+		115:           "seratus lima belas",
+		250:           "dua ratus lima puluh",
+		1000:          "seribu",
+		1001:          "seribu satu",
+		2500:          "dua ribu lima ratus",
+		100000:        "seratus ribu",
+		111111:        "seratus sebelas ribu seratus sebelas",
+		1000000:       "satu juta",
+		2750000:       "dua juta tujuh ratus lima puluh ribu",
+		1000000000:    "satu miliar",
+		1500000000000: "satu triliun lima ratus miliar",
+		-5:            "minus lima",
+	}
+	for n, expected := range cases {
+		assert.Equal(t, expected, Number(n), "n=%d", n)
+	}
+}
+
+// TestRupiah tests rupiah amounts with and without sen
+func TestRupiah(t *testing.T) {
+	assert.Equal(t, "lima puluh ribu rupiah", Rupiah(money.FromRupiah(50000)))
+	assert.Equal(t, "seratus lima puluh ribu rupiah lima puluh sen", Rupiah(money.MustParse("150000.50")))
+	assert.Equal(t, "satu juta rupiah satu sen", Rupiah(money.MustParse("1000000.01")))
+}
@@ -0,0 +1,81 @@
+package totp
+
+import (
+	"bytes"
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA-1 key from RFC 6238 appendix B
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+// TestCode tests the RFC 6238 SHA-1 test vectors, truncated to 6 digits
+func TestCode(t *testing.T) {
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range vectors {
+		code, err := Code(rfcSecret, Step(time.Unix(unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, want, code, "time %d", unix)
+	}
+
+	_, err := Code("not base32!", 1)
+	assert.EqualError(t, err, "invalid secret")
+}
+
+// TestValidate tests clock skew and the returned step
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, err := Code(rfcSecret, Step(now))
+	require.NoError(t, err)
+
+	step, ok := Validate(rfcSecret, code, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now), step)
+
+	step, ok = Validate(rfcSecret, code[:3]+" "+code[3:], now.Add(Period), 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now), step)
+
+	_, ok = Validate(rfcSecret, code, now.Add(2*Period), 1)
+	assert.False(t, ok)
+	_, ok = Validate(rfcSecret, "12345", now, 1)
+	assert.False(t, ok)
+}
+
+// TestGenerateSecret tests that secrets decode to 160-bit keys and work with Code
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+	_, err = Code(secret, 1)
+	assert.NoError(t, err)
+}
+
+// TestURI tests the otpauth key URI and its QR code
+func TestURI(t *testing.T) {
+	uri := URI("MADR", "admin@madr.local", "JBSWY3DPEHPK3PXP")
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/MADR:admin@madr.local", parsed.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", parsed.Query().Get("secret"))
+	assert.Equal(t, "MADR", parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+
+	png, err := QRCode(uri, 256)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(png, []byte("\x89PNG")))
+}
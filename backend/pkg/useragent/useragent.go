@@ -0,0 +1,126 @@
+package useragent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Device types reported by Parse
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+)
+
+// Info is what a User-Agent header tells about the client
+type Info struct {
+	Browser string `json:"browser"` // Name and major version, e.g. "Chrome 120"
+	OS      string `json:"os"`      // Name and version when known, e.g. "Android 14"
+	Device  string `json:"device"`  // One of the Device constants
+}
+
+// browser is matched in order, so more specific products come before the
+// engines and browsers they are built on
+type browser struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+var browsers = []browser{
+	{"Edge", regexp.MustCompile(`Edg(?:e|A|iOS)?/(\d+)`)},
+	{"Opera", regexp.MustCompile(`(?:OPR|Opera)/(\d+)`)},
+	{"Samsung Internet", regexp.MustCompile(`SamsungBrowser/(\d+)`)},
+	{"Firefox", regexp.MustCompile(`(?:Firefox|FxiOS)/(\d+)`)},
+	{"Chrome", regexp.MustCompile(`(?:Chrome|CriOS)/(\d+)`)},
+	{"Safari", regexp.MustCompile(`Version/(\d+)[.\d]* (?:Mobile/\S+ )?Safari/`)},
+	{"curl", regexp.MustCompile(`^curl/(\d+)`)},
+	{"Postman", regexp.MustCompile(`PostmanRuntime/(\d+)`)},
+}
+
+var (
+	androidVersion = regexp.MustCompile(`Android (\d+(?:\.\d+)?)`)
+	iosVersion     = regexp.MustCompile(`OS (\d+)(?:_(\d+))?(?:_\d+)? like Mac OS X`)
+	macVersion     = regexp.MustCompile(`Mac OS X (\d+)[_.](\d+)`)
+	windowsVersion = regexp.MustCompile(`Windows NT (\d+\.\d+)`)
+	botPattern     = regexp.MustCompile(`(?i)bot|crawler|spider|slurp`)
+)
+
+// windowsNames maps Windows NT versions to their marketing names. Windows
+// 11 still reports NT 10.0.
+var windowsNames = map[string]string{
+	"10.0": "Windows 10",
+	"6.3":  "Windows 8.1",
+	"6.2":  "Windows 8",
+	"6.1":  "Windows 7",
+}
+
+// Parse extracts the browser, operating system and device type from a
+// User-Agent header. Parts it does not recognize are left empty.
+func Parse(ua string) Info {
+	ua = strings.TrimSpace(ua)
+	if ua == "" {
+		return Info{Device: DeviceUnknown}
+	}
+
+	info := Info{OS: parseOS(ua)}
+	for _, b := range browsers {
+		if match := b.pattern.FindStringSubmatch(ua); match != nil {
+			info.Browser = b.name + " " + match[1]
+			break
+		}
+	}
+	info.Device = parseDevice(ua, info.OS)
+	return info
+}
+
+func parseOS(ua string) string {
+	switch {
+	case strings.Contains(ua, "Android"):
+		if match := androidVersion.FindStringSubmatch(ua); match != nil {
+			return "Android " + match[1]
+		}
+		return "Android"
+	case strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPad"), strings.Contains(ua, "iPod"):
+		if match := iosVersion.FindStringSubmatch(ua); match != nil {
+			if match[2] != "" && match[2] != "0" {
+				return "iOS " + match[1] + "." + match[2]
+			}
+			return "iOS " + match[1]
+		}
+		return "iOS"
+	case strings.Contains(ua, "Windows"):
+		if match := windowsVersion.FindStringSubmatch(ua); match != nil {
+			if name, ok := windowsNames[match[1]]; ok {
+				return name
+			}
+		}
+		return "Windows"
+	case strings.Contains(ua, "Mac OS X"):
+		if match := macVersion.FindStringSubmatch(ua); match != nil {
+			return "macOS " + match[1] + "." + match[2]
+		}
+		return "macOS"
+	case strings.Contains(ua, "CrOS"):
+		return "ChromeOS"
+	case strings.Contains(ua, "Linux"):
+		return "Linux"
+	}
+	return ""
+}
+
+func parseDevice(ua, os string) string {
+	switch {
+	case botPattern.MatchString(ua):
+		return DeviceBot
+	case strings.Contains(ua, "iPad"), strings.Contains(ua, "Tablet"),
+		strings.HasPrefix(os, "Android") && !strings.Contains(ua, "Mobile"):
+		return DeviceTablet
+	case strings.Contains(ua, "Mobile"), strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPod"):
+		return DeviceMobile
+	case os != "":
+		return DeviceDesktop
+	}
+	return DeviceUnknown
+}
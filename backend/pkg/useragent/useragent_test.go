@@ -0,0 +1,58 @@
+package useragent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParse tests common browsers, operating systems and devices
+func TestParse(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want Info
+	}{
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			Info{Browser: "Chrome 120", OS: "Windows 10", Device: DeviceDesktop},
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			Info{Browser: "Edge 120", OS: "Windows 10", Device: DeviceDesktop},
+		},
+		{
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+			Info{Browser: "Safari 17", OS: "macOS 10.15", Device: DeviceDesktop},
+		},
+		{
+			"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			Info{Browser: "Firefox 121", OS: "Linux", Device: DeviceDesktop},
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 14; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36",
+			Info{Browser: "Samsung Internet 23", OS: "Android 14", Device: DeviceMobile},
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			Info{Browser: "Chrome 120", OS: "Android 13", Device: DeviceTablet},
+		},
+		{
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1.2 Mobile/15E148 Safari/604.1",
+			Info{Browser: "Safari 17", OS: "iOS 17.1", Device: DeviceMobile},
+		},
+		{
+			"Mozilla/5.0 (iPad; CPU OS 16_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Mobile/15E148 Safari/604.1",
+			Info{Browser: "Chrome 120", OS: "iOS 16", Device: DeviceTablet},
+		},
+		{
+			"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			Info{Device: DeviceBot},
+		},
+		{"curl/8.4.0", Info{Browser: "curl 8", Device: DeviceUnknown}},
+		{"", Info{Device: DeviceUnknown}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Parse(tt.ua), tt.ua)
+	}
+}
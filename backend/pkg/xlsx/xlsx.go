@@ -0,0 +1,258 @@
+// Package xlsx writes Office Open XML spreadsheets row by row. Rows are
+// streamed straight into the zip archive, so memory use does not grow with
+// the number of rows.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Cell styles defined in styles.xml
+const (
+	styleDefault = 0
+	styleAmount  = 1
+	styleHeader  = 2
+)
+
+// maxSheetName is the longest sheet name Excel accepts
+const maxSheetName = 31
+
+var (
+	ErrClosed           = errors.New("xlsx: writer is closed")
+	ErrNoSheet          = errors.New("xlsx: no sheet started")
+	ErrInvalidSheetName = errors.New("xlsx: invalid sheet name")
+)
+
+// Cell is a single spreadsheet value
+type Cell struct {
+	value   string
+	numeric bool
+	style   int
+}
+
+// String returns a text cell
+func String(s string) Cell {
+	return Cell{value: s}
+}
+
+// Header returns a bold text cell
+func Header(s string) Cell {
+	return Cell{value: s, style: styleHeader}
+}
+
+// Int returns a whole number cell
+func Int(n int64) Cell {
+	return Cell{value: strconv.FormatInt(n, 10), numeric: true}
+}
+
+// Amount returns a decimal cell shown with thousand separators and two
+// decimals. The value must be a plain decimal such as "12500.75".
+func Amount(decimal string) Cell {
+	return Cell{value: decimal, numeric: true, style: styleAmount}
+}
+
+// Writer streams a workbook into w
+type Writer struct {
+	zw     *zip.Writer
+	sheets []string
+	sheet  io.Writer
+	row    int
+	closed bool
+}
+
+// NewWriter creates a workbook writer. Close must be called to finish the file.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{zw: zip.NewWriter(w)}
+}
+
+// NewSheet finishes the current sheet and starts a new one. Widths optionally
+// set the width of the first columns, in characters.
+func (w *Writer) NewSheet(name string, widths ...float64) error {
+	if w.closed {
+		return ErrClosed
+	}
+	if name == "" || len([]rune(name)) > maxSheetName || strings.ContainsAny(name, `[]:*?/\`) {
+		return ErrInvalidSheetName
+	}
+	for _, existing := range w.sheets {
+		if strings.EqualFold(existing, name) {
+			return ErrInvalidSheetName
+		}
+	}
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+
+	w.sheets = append(w.sheets, name)
+	sheet, err := w.zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.sheets)))
+	if err != nil {
+		return err
+	}
+	w.sheet = sheet
+	w.row = 0
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(widths) > 0 {
+		b.WriteString("<cols>")
+		for i, width := range widths {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%s" customWidth="1"/>`, i+1, i+1, strconv.FormatFloat(width, 'f', -1, 64))
+		}
+		b.WriteString("</cols>")
+	}
+	b.WriteString("<sheetData>")
+	_, err = io.WriteString(w.sheet, b.String())
+	return err
+}
+
+// WriteRow appends a row to the current sheet
+func (w *Writer) WriteRow(cells ...Cell) error {
+	if w.closed {
+		return ErrClosed
+	}
+	if w.sheet == nil {
+		return ErrNoSheet
+	}
+
+	w.row++
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, w.row)
+	for i, cell := range cells {
+		ref := columnName(i) + strconv.Itoa(w.row)
+		style := ""
+		if cell.style != styleDefault {
+			style = fmt.Sprintf(` s="%d"`, cell.style)
+		}
+		if cell.numeric {
+			fmt.Fprintf(&b, `<c r="%s"%s><v>%s</v></c>`, ref, style, cell.value)
+			continue
+		}
+		fmt.Fprintf(&b, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">`, ref, style)
+		if err := xml.EscapeText(&b, []byte(cell.value)); err != nil {
+			return err
+		}
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString("</row>")
+
+	_, err := io.WriteString(w.sheet, b.String())
+	return err
+}
+
+// Close finishes the last sheet and writes the workbook parts
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrClosed
+	}
+	if len(w.sheets) == 0 {
+		return ErrNoSheet
+	}
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+	w.closed = true
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", w.contentTypes()},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", w.workbook()},
+		{"xl/_rels/workbook.xml.rels", w.workbookRels()},
+		{"xl/styles.xml", styles},
+	}
+	for _, part := range parts {
+		f, err := w.zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+	return w.zw.Close()
+}
+
+func (w *Writer) endSheet() error {
+	if w.sheet == nil {
+		return nil
+	}
+	_, err := io.WriteString(w.sheet, "</sheetData></worksheet>")
+	w.sheet = nil
+	return err
+}
+
+func (w *Writer) contentTypes() string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range w.sheets {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func (w *Writer) workbook() string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, name := range w.sheets {
+		b.WriteString(`<sheet name="`)
+		_ = xml.EscapeText(&b, []byte(name))
+		fmt.Fprintf(&b, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func (w *Writer) workbookRels() string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range w.sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(w.sheets)+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// columnName converts a zero-based column index to its letters, e.g. 27 -> "AB"
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+const rootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// styles defines the default, amount (#,##0.00) and bold header cell formats
+const styles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`</cellXfs>` +
+	`</styleSheet>`
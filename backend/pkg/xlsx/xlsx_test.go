@@ -0,0 +1,77 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readPart(t *testing.T, archive []byte, name string) string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+	f, err := zr.Open(name)
+	require.NoError(t, err, name)
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	return string(content)
+}
+
+// TestWriter_WritesSheetsAndCells tests the workbook parts and cell encoding
+func TestWriter_WritesSheetsAndCells(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	require.NoError(t, w.NewSheet("Donasi", 12, 30))
+	require.NoError(t, w.WriteRow(Header("Donatur"), Header("Nominal")))
+	require.NoError(t, w.WriteRow(String("Ahmad & <Keluarga>"), Amount("12500.75")))
+	require.NoError(t, w.NewSheet("Ringkasan"))
+	require.NoError(t, w.WriteRow(String("Total"), Int(42)))
+	require.NoError(t, w.Close())
+
+	workbook := readPart(t, buf.Bytes(), "xl/workbook.xml")
+	assert.Contains(t, workbook, `<sheet name="Donasi" sheetId="1" r:id="rId1"/>`)
+	assert.Contains(t, workbook, `<sheet name="Ringkasan" sheetId="2" r:id="rId2"/>`)
+
+	sheet := readPart(t, buf.Bytes(), "xl/worksheets/sheet1.xml")
+	assert.Contains(t, sheet, `<col min="2" max="2" width="30" customWidth="1"/>`)
+	assert.Contains(t, sheet, `<c r="A1" s="2" t="inlineStr"><is><t xml:space="preserve">Donatur</t></is></c>`)
+	assert.Contains(t, sheet, `Ahmad &amp; &lt;Keluarga&gt;`)
+	assert.Contains(t, sheet, `<c r="B2" s="1"><v>12500.75</v></c>`)
+	assert.True(t, strings.HasSuffix(sheet, "</sheetData></worksheet>"))
+
+	summary := readPart(t, buf.Bytes(), "xl/worksheets/sheet2.xml")
+	assert.Contains(t, summary, `<c r="B1"><v>42</v></c>`)
+
+	types := readPart(t, buf.Bytes(), "[Content_Types].xml")
+	assert.Contains(t, types, `/xl/worksheets/sheet2.xml`)
+}
+
+// TestWriter_Errors tests misuse of the writer
+func TestWriter_Errors(t *testing.T) {
+	w := NewWriter(io.Discard)
+	assert.ErrorIs(t, w.WriteRow(String("x")), ErrNoSheet)
+	assert.ErrorIs(t, w.Close(), ErrNoSheet)
+	assert.ErrorIs(t, w.NewSheet("a/b"), ErrInvalidSheetName)
+	assert.ErrorIs(t, w.NewSheet(strings.Repeat("x", 32)), ErrInvalidSheetName)
+
+	require.NoError(t, w.NewSheet("Data"))
+	assert.ErrorIs(t, w.NewSheet("data"), ErrInvalidSheetName)
+	require.NoError(t, w.Close())
+	assert.ErrorIs(t, w.WriteRow(String("x")), ErrClosed)
+}
+
+// TestColumnName tests conversion of column indexes to letters
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AB", columnName(27))
+	assert.Equal(t, "BA", columnName(52))
+}